@@ -0,0 +1,14 @@
+/**
+ * Description：
+ * FileName：captcha.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 16:02:31
+ * Remark：
+ */
+
+package config
+
+type CaptchaConfig struct {
+	Enabled          bool `yaml:"enabled" json:"enabled"`                   // 是否启用登录、注册验证码
+	FailureThreshold int  `yaml:"failureThreshold" json:"failureThreshold"` // 登录失败多少次后需要验证码，0 表示始终需要
+}
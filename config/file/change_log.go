@@ -0,0 +1,15 @@
+/**
+ * Description：
+ * FileName：change_log.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 15:52:30
+ * Remark：
+ */
+
+package config
+
+// ChangeLogConfig 数据变更审计，在默认规则基础上追加
+type ChangeLogConfig struct {
+	ExcludeTables []string `yaml:"excludeTables" json:"excludeTables"` // 不记录的表，支持 * 后缀通配
+	ExcludeFields []string `yaml:"excludeFields" json:"excludeFields"` // 不记录的字段(列名)，如 password
+}
@@ -0,0 +1,17 @@
+/**
+ * Description：
+ * FileName：lockout.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 16:48:22
+ * Remark：
+ */
+
+package config
+
+type LockoutConfig struct {
+	Threshold       int `yaml:"threshold" json:"threshold"`             // 同一用户名连续登录失败多少次后锁定，0 表示不锁定
+	IpThreshold     int `yaml:"ipThreshold" json:"ipThreshold"`         // 同一IP连续登录失败多少次后锁定，0 表示不锁定
+	Window          int `yaml:"window" json:"window"`                   // 失败次数统计窗口(秒)，默认 900
+	LockDuration    int `yaml:"lockDuration" json:"lockDuration"`       // 首次锁定时长(秒)，之后每次失败翻倍，默认 60
+	MaxLockDuration int `yaml:"maxLockDuration" json:"maxLockDuration"` // 最长锁定时长(秒)，默认 3600
+}
@@ -0,0 +1,28 @@
+/**
+ * Description：
+ * FileName：log_mask.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 14:18:05
+ * Remark：
+ */
+
+package config
+
+// LogMaskConfig 操作日志脱敏，Keys、Paths、Headers 在默认规则基础上追加
+type LogMaskConfig struct {
+	Keys            []string             `yaml:"keys" json:"keys"`                       // 敏感字段名(JSON、查询参数、请求头)，支持 * 通配，不区分大小写
+	Paths           []string             `yaml:"paths" json:"paths"`                     // 敏感 JSON 路径，如 data.token、data.list.*.mobile
+	Headers         []string             `yaml:"headers" json:"headers"`                 // 敏感请求头
+	Mask            string               `yaml:"mask" json:"mask"`                       // 脱敏后的值，默认 ******
+	MaxResponseSize int                  `yaml:"maxResponseSize" json:"maxResponseSize"` // 响应体最大记录字节数，默认 4096
+	Routes          []LogMaskRouteConfig `yaml:"routes" json:"routes"`                   // 按接口覆盖记录策略
+}
+
+// LogMaskRouteConfig 按接口覆盖记录策略
+type LogMaskRouteConfig struct {
+	Method           string `yaml:"method" json:"method"`                     // 请求方式，为空匹配全部
+	Path             string `yaml:"path" json:"path"`                         // 接口地址，需为完整路由，可省略 /dev-api、/v1 前缀，支持 :id、* 参数段
+	SkipRequestBody  bool   `yaml:"skipRequestBody" json:"skipRequestBody"`   // 不记录请求体
+	SkipResponseBody bool   `yaml:"skipResponseBody" json:"skipResponseBody"` // 不记录响应体
+	MaxResponseSize  int    `yaml:"maxResponseSize" json:"maxResponseSize"`   // 响应体最大记录字节数，大于 0 时覆盖全局配置
+}
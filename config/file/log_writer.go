@@ -0,0 +1,19 @@
+/**
+ * Description：
+ * FileName：log_writer.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 10:36:20
+ * Remark：
+ */
+
+package config
+
+// LogWriterConfig 操作日志、缓存日志异步批量写入
+type LogWriterConfig struct {
+	QueueSize     int    `yaml:"queueSize" json:"queueSize"`         // 队列容量，默认 10000
+	BatchSize     int    `yaml:"batchSize" json:"batchSize"`         // 每批写入条数，默认 200
+	FlushInterval int    `yaml:"flushInterval" json:"flushInterval"` // 刷新间隔(毫秒)，默认 1000
+	Overflow      string `yaml:"overflow" json:"overflow"`           // 队列已满或写入失败时的处理: drop-丢弃(默认) spill-写入本地文件
+	SpillDir      string `yaml:"spillDir" json:"spillDir"`           // 溢出文件目录，默认 tmp/spill
+	DrainTimeout  int    `yaml:"drainTimeout" json:"drainTimeout"`   // 停止服务时等待队列写完的时长(秒)，默认 10
+}
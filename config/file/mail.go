@@ -0,0 +1,18 @@
+/**
+ * Description：
+ * FileName：mail.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 02:24:48
+ * Remark：
+ */
+
+package config
+
+type MailConfig struct {
+	Host     string `yaml:"host" json:"host"`         // SMTP 服务器地址，为空时不发送邮件
+	Port     int    `yaml:"port" json:"port"`         // SMTP 端口
+	Username string `yaml:"username" json:"username"` // 认证用户名，为空时不认证
+	Password string `yaml:"password" json:"password"` // 认证密码
+	From     string `yaml:"from" json:"from"`         // 发件人，如 "Carefuly <noreply@example.com>"
+	SSL      bool   `yaml:"ssl" json:"ssl"`           // 是否使用隐式 TLS(如 465 端口)，否则在服务器支持时使用 STARTTLS
+}
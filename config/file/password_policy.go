@@ -0,0 +1,22 @@
+/**
+ * Description：
+ * FileName：password_policy.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 01:15:20
+ * Remark：
+ */
+
+package config
+
+type PasswordPolicyConfig struct {
+	MinLength        int  `yaml:"minLength" json:"minLength"`               // 最小长度，默认 6
+	MaxLength        int  `yaml:"maxLength" json:"maxLength"`               // 最大长度，默认且不超过 72
+	RequireUpper     bool `yaml:"requireUpper" json:"requireUpper"`         // 必须包含大写字母
+	RequireLower     bool `yaml:"requireLower" json:"requireLower"`         // 必须包含小写字母
+	RequireDigit     bool `yaml:"requireDigit" json:"requireDigit"`         // 必须包含数字
+	RequireSymbol    bool `yaml:"requireSymbol" json:"requireSymbol"`       // 必须包含特殊字符
+	DisallowUsername bool `yaml:"disallowUsername" json:"disallowUsername"` // 不能包含用户名
+	HistoryCount     int  `yaml:"historyCount" json:"historyCount"`         // 不能与最近 N 次使用过的密码相同(含当前密码)，0 表示不限制
+	MaxAgeDays       int  `yaml:"maxAgeDays" json:"maxAgeDays"`             // 密码最长使用天数，到期后登录时要求修改，0 表示不限制
+	ChangeOnAdminSet bool `yaml:"changeOnAdminSet" json:"changeOnAdminSet"` // 管理员新增用户或重置密码后，要求用户首次登录时修改密码
+}
@@ -0,0 +1,36 @@
+/**
+ * Description：
+ * FileName：sso.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 06:34:08
+ * Remark：
+ */
+
+package config
+
+type SSOConfig struct {
+	Providers []OIDCProviderConfig `yaml:"providers" json:"providers"` // 身份提供方，未配置时不启用单点登录
+}
+
+// OIDCProviderConfig OpenID Connect 身份提供方
+type OIDCProviderConfig struct {
+	Name         string   `yaml:"name" json:"name"`                 // 标识，登录时指定，如 corp
+	DisplayName  string   `yaml:"displayName" json:"displayName"`   // 登录页显示名称
+	Issuer       string   `yaml:"issuer" json:"issuer"`             // 颁发者地址
+	ClientId     string   `yaml:"clientId" json:"clientId"`         // 客户端ID
+	ClientSecret string   `yaml:"clientSecret" json:"clientSecret"` // 客户端密钥，公共客户端留空
+	RedirectUrl  string   `yaml:"redirectUrl" json:"redirectUrl"`   // 回调地址(前端页面)，需与身份提供方登记的一致
+	Scopes       []string `yaml:"scopes" json:"scopes"`             // 授权范围，默认 openid profile email
+	Timeout      int      `yaml:"timeout" json:"timeout"`           // 请求超时(秒)，默认 10
+
+	UsernameClaim string `yaml:"usernameClaim" json:"usernameClaim"` // 用户名声明，默认 preferred_username
+	EmailClaim    string `yaml:"emailClaim" json:"emailClaim"`       // 邮箱声明，默认 email
+	NameClaim     string `yaml:"nameClaim" json:"nameClaim"`         // 姓名声明，默认 name
+	// MatchBy 首次登录时按 email 关联已有用户，为空时不关联；要求 email_verified 为 true
+	MatchBy string `yaml:"matchBy" json:"matchBy"`
+
+	AutoCreate     bool     `yaml:"autoCreate" json:"autoCreate"`         // 未关联到用户时是否自动创建
+	UserType       int      `yaml:"userType" json:"userType"`             // 自动创建的用户类型，默认 1-后台用户
+	DefaultDeptId  string   `yaml:"defaultDeptId" json:"defaultDeptId"`   // 自动创建的用户所属部门
+	DefaultRoleIds []string `yaml:"defaultRoleIds" json:"defaultRoleIds"` // 自动创建的用户分配的角色
+}
@@ -9,6 +9,21 @@
 package config
 
 type TokenConfig struct {
-	Secret string `yaml:"secret" json:"secret"`
-	Expire int    `yaml:"expire" json:"expire"`
+	Secret        string           `yaml:"secret" json:"secret"`               // HS256共享密钥，配置非对称密钥后仅用于校验旧令牌，可在旧令牌过期后移除
+	AccessExpire  int              `yaml:"accessExpire" json:"accessExpire"`   // 访问令牌有效期(分钟)，默认 15
+	RefreshExpire int              `yaml:"refreshExpire" json:"refreshExpire"` // 刷新令牌有效期(小时)，默认 168
+	SingleSession bool             `yaml:"singleSession" json:"singleSession"` // 单会话模式，登录时吊销该用户的其他会话
+	SigningKid    string           `yaml:"signingKid" json:"signingKid"`       // 当前签名密钥kid，为空时使用第一个密钥
+	Keys          []TokenKeyConfig `yaml:"keys" json:"keys"`                   // 非对称签名密钥，轮换时保留旧密钥用于校验未过期令牌
+	BindUserAgent string           `yaml:"bindUserAgent" json:"bindUserAgent"` // 令牌与签发时的User-Agent绑定 off/warn/reject，默认 off
+	BindIp        string           `yaml:"bindIp" json:"bindIp"`               // 令牌与签发时的IP绑定 off/warn/reject，默认 off
+
+	ImpersonateExpire int `yaml:"impersonateExpire" json:"impersonateExpire"` // 模拟登录令牌最长有效期(分钟)，默认 30
+}
+
+type TokenKeyConfig struct {
+	Kid        string `yaml:"kid" json:"kid"`               // 密钥ID
+	Algorithm  string `yaml:"algorithm" json:"algorithm"`   // 签名算法 RS256/ES256
+	PrivateKey string `yaml:"privateKey" json:"privateKey"` // 私钥PEM文件路径，为空时仅用于校验
+	PublicKey  string `yaml:"publicKey" json:"publicKey"`   // 公钥PEM文件路径，为空时由私钥推导
 }
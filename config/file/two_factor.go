@@ -0,0 +1,16 @@
+/**
+ * Description：
+ * FileName：two_factor.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 21:45:26
+ * Remark：
+ */
+
+package config
+
+type TwoFactorConfig struct {
+	Issuer           string `yaml:"issuer" json:"issuer"`                     // 认证器应用中显示的签发方，默认 Carefuly
+	RequireAdminUser bool   `yaml:"requireAdminUser" json:"requireAdminUser"` // 后台用户是否必须启用双因素认证
+	ChallengeExpire  int    `yaml:"challengeExpire" json:"challengeExpire"`   // 登录二次验证有效期(秒)，默认 300
+	MaxAttempts      int    `yaml:"maxAttempts" json:"maxAttempts"`           // 登录二次验证最多尝试次数，默认 5
+}
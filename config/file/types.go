@@ -9,22 +9,50 @@
 package config
 
 import (
+	"github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/logger"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/batchwriter"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/jwt"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/masking"
 	ut "github.com/go-playground/universal-translator"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 )
 
 type Config struct {
-	ServerConfig   `yaml:"server" json:"server"`
-	DatabaseConfig map[string]DatabaseConfig `yaml:"database" json:"database"`
-	CacheConfig    `yaml:"cache" json:"cache"`
-	TokenConfig    `yaml:"token" json:"token"`
+	ServerConfig    `yaml:"server" json:"server"`
+	DatabaseConfig  map[string]DatabaseConfig `yaml:"database" json:"database"`
+	CacheConfig     `yaml:"cache" json:"cache"`
+	TokenConfig     `yaml:"token" json:"token"`
+	CaptchaConfig   `yaml:"captcha" json:"captcha"`
+	LockoutConfig   `yaml:"lockout" json:"lockout"`
+	TwoFactorConfig `yaml:"twoFactor" json:"twoFactor"`
+
+	PasswordPolicyConfig `yaml:"passwordPolicy" json:"passwordPolicy"`
+	MailConfig           `yaml:"mail" json:"mail"`
+	SSOConfig            `yaml:"sso" json:"sso"`
+	LogWriterConfig      `yaml:"logWriter" json:"logWriter"`
+	LogMaskConfig        `yaml:"logMask" json:"logMask"`
+	ChangeLogConfig      `yaml:"changeLog" json:"changeLog"`
 }
 
 type RelyConfig struct {
-	Logger *zap.Logger
-	Db     DatabasesPool
-	Redis  redis.Cmdable
-	Trans  ut.Translator
-	Token  TokenConfig
+	Logger    *zap.Logger
+	Db        DatabasesPool
+	Redis     redis.Cmdable
+	Trans     ut.Translator
+	Token     TokenConfig
+	Keys      *jwt.KeySet
+	Captcha   CaptchaConfig
+	Lockout   LockoutConfig
+	TwoFactor TwoFactorConfig
+	Password  PasswordPolicyConfig
+	Mail      MailConfig
+	SSO       SSOConfig
+	LogWriter LogWriterConfig
+	// OperateLogs 操作日志写入队列，由 ioc.InitLogWriters 创建
+	OperateLogs *batchwriter.Writer[logger.OperateLogger]
+	// CacheLogs 缓存日志写入队列，由 ioc.InitLogWriters 创建
+	CacheLogs *batchwriter.Writer[logger.CacheLogger]
+	// Masker 操作日志脱敏，由 ioc.InitMasker 创建
+	Masker *masking.Masker
 }
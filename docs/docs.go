@@ -7255,10 +7255,6 @@ const docTemplate = `{
         "third.CaptchaResponse": {
             "type": "object",
             "properties": {
-                "code": {
-                    "description": "验证码",
-                    "type": "string"
-                },
                 "id": {
                     "description": "验证码Id",
                     "type": "string"
@@ -0,0 +1,19 @@
+/**
+ * Description：
+ * FileName：audit.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 17:01:36
+ * Remark：
+ */
+
+package logger
+
+import (
+	"github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/logger"
+)
+
+type AuditLog struct {
+	logger.AuditLogger
+	CreateTime string `json:"createTime"` // 创建时间
+	UpdateTime string `json:"updateTime"` // 更新时间
+}
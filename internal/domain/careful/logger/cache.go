@@ -0,0 +1,94 @@
+/**
+ * Description：
+ * FileName：cache.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 09:14:22
+ * Remark：
+ */
+
+package logger
+
+import (
+	"github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/logger"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/filters"
+	"gorm.io/gorm"
+)
+
+type CacheLog struct {
+	logger.CacheLogger
+	CreateTime string `json:"createTime"` // 创建时间
+	UpdateTime string `json:"updateTime"` // 更新时间
+}
+
+type CacheLogFilter struct {
+	filters.Pagination
+	Key       string `json:"key"`       // 缓存key键前缀
+	Operation string `json:"operation"` // 缓存操作
+	Result    string `json:"result"`    // 缓存结果
+	Username  string `json:"username"`  // 缓存用户名
+	Path      string `json:"path"`      // 请求地址前缀
+	HasError  *bool  `json:"hasError"`  // 是否异常
+	StartTime string `json:"startTime"` // 开始时间
+	EndTime   string `json:"endTime"`   // 结束时间
+}
+
+func (f *CacheLogFilter) Apply(query *gorm.DB) *gorm.DB {
+	return f.Where(query.Order("create_time DESC"))
+}
+
+// Where 仅添加查询条件，用于不能按创建时间排序的分组统计
+func (f *CacheLogFilter) Where(query *gorm.DB) *gorm.DB {
+	if f.Key != "" {
+		query = query.Where("cacheKey LIKE ?", f.Key+"%")
+	}
+	if f.Operation != "" {
+		query = query.Where("cacheOperation = ?", f.Operation)
+	}
+	if f.Result != "" {
+		query = query.Where("cacheResult = ?", f.Result)
+	}
+	if f.Username != "" {
+		query = query.Where("cacheUsername LIKE ?", "%"+f.Username+"%")
+	}
+	if f.Path != "" {
+		query = query.Where("cachePath LIKE ?", f.Path+"%")
+	}
+	if f.HasError != nil {
+		if *f.HasError {
+			query = query.Where("cacheError <> ''")
+		} else {
+			query = query.Where("(cacheError = '' OR cacheError IS NULL)")
+		}
+	}
+	if f.StartTime != "" {
+		query = query.Where("create_time >= ?", f.StartTime)
+	}
+	if f.EndTime != "" {
+		query = query.Where("create_time <= ?", f.EndTime)
+	}
+	return query
+}
+
+// CacheStatGroup 按缓存key键前缀、操作及结果分组的计数
+type CacheStatGroup struct {
+	logger.CacheStatGroup
+}
+
+// CacheStats 按缓存key键前缀聚合的统计
+// 命中率等比例按读取次数计算，耗时分位数按最近的部分记录计算，包含全部操作
+type CacheStats struct {
+	Prefix        string  `json:"prefix"`        // 缓存key键前缀
+	Total         int64   `json:"total"`         // 操作总数
+	Gets          int64   `json:"gets"`          // 读取次数
+	Hits          int64   `json:"hits"`          // 命中次数
+	Misses        int64   `json:"misses"`        // 未命中次数
+	NotFound      int64   `json:"notFound"`      // 命中防穿透标记次数
+	Sets          int64   `json:"sets"`          // 写入次数(含防穿透标记)
+	Dels          int64   `json:"dels"`          // 删除次数
+	Errors        int64   `json:"errors"`        // 异常次数
+	HitRatio      float64 `json:"hitRatio"`      // 命中率
+	MissRatio     float64 `json:"missRatio"`     // 未命中率
+	NotFoundRatio float64 `json:"notFoundRatio"` // 防穿透标记命中率
+	P50           float64 `json:"p50"`           // 耗时中位数(毫秒)
+	P95           float64 `json:"p95"`           // 耗时 95 分位(毫秒)
+}
@@ -0,0 +1,58 @@
+/**
+ * Description：
+ * FileName：change.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 15:58:04
+ * Remark：
+ */
+
+package logger
+
+import (
+	"github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/logger"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/filters"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/changelog"
+	"gorm.io/gorm"
+)
+
+type ChangeLog struct {
+	logger.ChangeLogger
+	Changes    []changelog.Change `json:"changes"`    // 字段差异
+	OldValues  map[string]any     `json:"oldValues"`  // 变更前的数据，新增时为空
+	NewValues  map[string]any     `json:"newValues"`  // 变更后的数据，删除时为空
+	CreateTime string             `json:"createTime"` // 创建时间
+	UpdateTime string             `json:"updateTime"` // 更新时间
+}
+
+type ChangeLogFilter struct {
+	filters.Pagination
+	EntityTable string `json:"entityTable"` // 数据表
+	EntityId    string `json:"entityId"`    // 数据ID
+	Action      string `json:"action"`      // 变更类型
+	Operator    string `json:"operator"`    // 操作人
+	StartTime   string `json:"startTime"`   // 开始时间
+	EndTime     string `json:"endTime"`     // 结束时间
+}
+
+func (f *ChangeLogFilter) Apply(query *gorm.DB) *gorm.DB {
+	query = query.Order("create_time DESC")
+	if f.EntityTable != "" {
+		query = query.Where("entityTable = ?", f.EntityTable)
+	}
+	if f.EntityId != "" {
+		query = query.Where("entityId = ?", f.EntityId)
+	}
+	if f.Action != "" {
+		query = query.Where("action = ?", f.Action)
+	}
+	if f.Operator != "" {
+		query = query.Where("operator LIKE ?", "%"+f.Operator+"%")
+	}
+	if f.StartTime != "" {
+		query = query.Where("create_time >= ?", f.StartTime)
+	}
+	if f.EndTime != "" {
+		query = query.Where("create_time <= ?", f.EndTime)
+	}
+	return query
+}
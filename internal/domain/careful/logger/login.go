@@ -0,0 +1,56 @@
+/**
+ * Description：
+ * FileName：login.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 17:45:33
+ * Remark：
+ */
+
+package logger
+
+import (
+	"github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/logger"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/filters"
+	"gorm.io/gorm"
+)
+
+type LoginLog struct {
+	logger.LoginLogger
+	CreateTime string `json:"createTime"` // 创建时间
+	UpdateTime string `json:"updateTime"` // 更新时间
+}
+
+type LoginLogFilter struct {
+	filters.Pagination
+	Username  string `json:"username"`  // 用户名
+	RequestIp string `json:"requestIp"` // 请求IP地址
+	Action    string `json:"action"`    // 登录行为
+	Status    *bool  `json:"status"`    // 结果
+	StartTime string `json:"startTime"` // 开始时间
+	EndTime   string `json:"endTime"`   // 结束时间
+}
+
+func (f *LoginLogFilter) Apply(query *gorm.DB) *gorm.DB {
+	query = query.Order("create_time DESC")
+
+	if f.Username != "" {
+		query = query.Where("username LIKE ?", "%"+f.Username+"%")
+	}
+	if f.RequestIp != "" {
+		query = query.Where("requestIp LIKE ?", "%"+f.RequestIp+"%")
+	}
+	if f.Action != "" {
+		query = query.Where("action = ?", f.Action)
+	}
+	if f.Status != nil {
+		query = query.Where("status = ?", *f.Status)
+	}
+	if f.StartTime != "" {
+		query = query.Where("create_time >= ?", f.StartTime)
+	}
+	if f.EndTime != "" {
+		query = query.Where("create_time <= ?", f.EndTime)
+	}
+
+	return query
+}
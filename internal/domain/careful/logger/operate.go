@@ -0,0 +1,62 @@
+/**
+ * Description：
+ * FileName：operate.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 08:20:14
+ * Remark：
+ */
+
+package logger
+
+import (
+	"github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/logger"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/filters"
+	"gorm.io/gorm"
+)
+
+type OperateLog struct {
+	logger.OperateLogger
+	CreateTime string `json:"createTime"` // 创建时间
+	UpdateTime string `json:"updateTime"` // 更新时间
+}
+
+type OperateLogFilter struct {
+	filters.Pagination
+	Username    string `json:"username"`    // 请求用户名
+	Method      string `json:"method"`      // 请求方式
+	Path        string `json:"path"`        // 请求地址前缀
+	Status      *int   `json:"status"`      // 响应状态码
+	RequestCode *int   `json:"requestCode"` // 自定义状态码
+	RequestIp   string `json:"requestIp"`   // 请求IP地址
+	StartTime   string `json:"startTime"`   // 开始时间
+	EndTime     string `json:"endTime"`     // 结束时间
+}
+
+func (f *OperateLogFilter) Apply(query *gorm.DB) *gorm.DB {
+	query = query.Order("create_time DESC")
+	if f.Username != "" {
+		query = query.Where("requestUsername LIKE ?", "%"+f.Username+"%")
+	}
+	if f.Method != "" {
+		query = query.Where("requestMethod = ?", f.Method)
+	}
+	if f.Path != "" {
+		query = query.Where("requestPath LIKE ?", f.Path+"%")
+	}
+	if f.Status != nil {
+		query = query.Where("requestStatus = ?", *f.Status)
+	}
+	if f.RequestCode != nil {
+		query = query.Where("requestCode = ?", *f.RequestCode)
+	}
+	if f.RequestIp != "" {
+		query = query.Where("requestIp LIKE ?", "%"+f.RequestIp+"%")
+	}
+	if f.StartTime != "" {
+		query = query.Where("create_time >= ?", f.StartTime)
+	}
+	if f.EndTime != "" {
+		query = query.Where("create_time <= ?", f.EndTime)
+	}
+	return query
+}
@@ -0,0 +1,29 @@
+/**
+ * Description：
+ * FileName：api_key.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 23:16:05
+ * Remark：
+ */
+
+package system
+
+import (
+	"github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/system"
+)
+
+type ApiKey struct {
+	system.ApiKey
+	Scopes       []string `json:"scopes"`       // 授权范围(按钮权限值)
+	Expired      bool     `json:"expired"`      // 是否已过期
+	ExpireTime   string   `json:"expireTime"`   // 过期时间
+	LastUsedTime string   `json:"lastUsedTime"` // 最近使用时间
+	CreateTime   string   `json:"createTime"`   // 创建时间
+	UpdateTime   string   `json:"updateTime"`   // 更新时间
+}
+
+// ApiKeyCreated 新建的个人访问令牌
+type ApiKeyCreated struct {
+	ApiKey
+	Key string `json:"key"` // 令牌明文，仅展示一次
+}
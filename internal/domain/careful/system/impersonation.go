@@ -0,0 +1,17 @@
+/**
+ * Description：
+ * FileName：impersonation.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 04:36:12
+ * Remark：
+ */
+
+package system
+
+// Impersonation 模拟登录令牌
+type Impersonation struct {
+	Token      string `json:"token"`      // 以目标用户身份访问的令牌，不可刷新
+	Expire     int    `json:"expire"`     // 有效期(秒)
+	ExpireTime string `json:"expireTime"` // 过期时间
+	User       User   `json:"user"`       // 被模拟的用户
+}
@@ -0,0 +1,90 @@
+/**
+ * Description：
+ * FileName：permission.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 09:40:12
+ * Remark：
+ */
+
+package system
+
+import (
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/constants/careful/system/menu"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/filters"
+)
+
+// ApiPermission 接口权限
+type ApiPermission struct {
+	Code   string           `json:"code"`   // 权限值
+	Api    string           `json:"api"`    // 接口地址
+	Method menu.MethodConst `json:"method"` // 请求方式
+	MenuId string           `json:"menuId"` // 关联菜单
+}
+
+// UserPermission 用户权限集合
+type UserPermission struct {
+	UserId     string            `json:"userId"`     // 用户ID
+	SuperAdmin bool              `json:"superAdmin"` // 是否超级管理员
+	RoleIds    []string          `json:"roleIds"`    // 已启用的角色ID
+	RoleCodes  []string          `json:"roleCodes"`  // 已启用的角色编码
+	Apis       []ApiPermission   `json:"apis"`       // 接口权限
+	MenuIds    []string          `json:"menuIds"`    // 已授权的菜单ID
+	DataScope  filters.DataScope `json:"dataScope"`  // 数据权限范围
+	// Columns 已授权查看的列(菜单ID -> 字段名)
+	Columns map[string][]string `json:"columns"`
+	// HiddenColumns 无权限查看的列(菜单ID -> 字段名)
+	HiddenColumns map[string][]string `json:"hiddenColumns"`
+}
+
+// Narrow 将接口权限收窄至指定的按钮权限值，codes 为空时不做限制
+func (p UserPermission) Narrow(codes []string) UserPermission {
+	if len(codes) == 0 {
+		return p
+	}
+
+	allowed := make(map[string]bool, len(codes))
+	for _, code := range codes {
+		allowed[code] = true
+	}
+	apis := make([]ApiPermission, 0, len(codes))
+	for _, api := range p.Apis {
+		if allowed[api.Code] {
+			apis = append(apis, api)
+		}
+	}
+
+	// 超级管理员的接口权限同样受限，数据权限范围保持不变
+	p.SuperAdmin = false
+	p.Apis = apis
+	return p
+}
+
+// PermissionInfo 当前用户权限信息
+type PermissionInfo struct {
+	SuperAdmin bool                `json:"superAdmin"` // 是否超级管理员
+	Roles      []string            `json:"roles"`      // 角色编码
+	Buttons    []string            `json:"buttons"`    // 按钮权限值
+	Columns    map[string][]string `json:"columns"`    // 可查看的列(菜单ID -> 字段名)
+}
+
+// RouteMeta 前端路由元信息
+type RouteMeta struct {
+	Icon        string `json:"icon"`        // 菜单图标
+	Title       string `json:"title"`       // 菜单标题
+	IsHide      bool   `json:"isHide"`      // 是否隐藏
+	IsLink      string `json:"isLink"`      // 外链地址
+	IsKeepAlive bool   `json:"isKeepAlive"` // 是否页面缓存
+	IsFull      bool   `json:"isFull"`      // 是否全屏
+	IsAffix     bool   `json:"isAffix"`     // 是否固定标签
+}
+
+// Route 前端动态路由
+type Route struct {
+	Id        string    `json:"id"`        // 菜单ID
+	Path      string    `json:"path"`      // 路由地址
+	Name      string    `json:"name"`      // 组件名称
+	Component string    `json:"component"` // 组件地址
+	Redirect  string    `json:"redirect"`  // 重定向地址
+	Meta      RouteMeta `json:"meta"`      // 元信息
+	Children  []*Route  `json:"children"`  // 子路由
+}
@@ -0,0 +1,45 @@
+/**
+ * Description：
+ * FileName：session.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 18:41:05
+ * Remark：
+ */
+
+package system
+
+import (
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/filters"
+)
+
+// Session 在线会话
+type Session struct {
+	Jti            string `json:"jti"`            // 会话ID(令牌jti)
+	UserId         string `json:"userId"`         // 用户ID
+	Username       string `json:"username"`       // 用户名
+	DeptId         string `json:"deptId"`         // 所属部门
+	RequestIp      string `json:"requestIp"`      // 登录IP地址
+	RequestOs      string `json:"requestOs"`      // 操作系统
+	RequestBrowser string `json:"requestBrowser"` // 浏览器
+	UserAgent      string `json:"userAgent"`      // 用户代理
+	Family         string `json:"family"`         // 刷新令牌族
+	LoginTime      string `json:"loginTime"`      // 登录时间
+	ExpireTime     string `json:"expireTime"`     // 过期时间
+
+	Suspicious        bool   `json:"suspicious"`        // 是否检测到客户端与签发时不一致
+	MismatchReason    string `json:"mismatchReason"`    // 不一致项
+	MismatchIp        string `json:"mismatchIp"`        // 最近一次不一致请求的IP地址
+	MismatchUserAgent string `json:"mismatchUserAgent"` // 最近一次不一致请求的用户代理
+	MismatchTime      string `json:"mismatchTime"`      // 最近一次检测到不一致的时间
+
+	ActorId   string `json:"actorId,omitempty"`   // 模拟登录时发起模拟的管理员ID
+	ActorName string `json:"actorName,omitempty"` // 模拟登录时发起模拟的管理员用户名
+}
+
+// SessionFilter 在线会话过滤条件
+type SessionFilter struct {
+	filters.Pagination
+	Username   string `json:"username"`   // 用户名
+	RequestIp  string `json:"requestIp"`  // 登录IP地址
+	Suspicious bool   `json:"suspicious"` // 仅查询客户端不一致的会话
+}
@@ -16,8 +16,9 @@ import (
 
 type User struct {
 	system.User
-	CreateTime string `json:"createTime"` // 创建时间
-	UpdateTime string `json:"updateTime"` // 更新时间
+	PasswordUpdateTime string `json:"passwordUpdateTime"` // 密码修改时间
+	CreateTime         string `json:"createTime"`         // 创建时间
+	UpdateTime         string `json:"updateTime"`         // 更新时间
 }
 
 type UserFilter struct {
@@ -0,0 +1,49 @@
+/**
+ * Description：
+ * FileName：user_identity.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 06:44:52
+ * Remark：
+ */
+
+package system
+
+import (
+	"github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/system"
+)
+
+type UserIdentity struct {
+	system.UserIdentity
+	LastLoginTime string `json:"lastLoginTime"` // 最近登录时间
+	CreateTime    string `json:"createTime"`    // 创建时间
+}
+
+// ExternalIdentity 身份提供方认证后返回的用户信息
+type ExternalIdentity struct {
+	Provider      string         `json:"provider"`      // 身份提供方
+	Subject       string         `json:"subject"`       // 第三方用户标识
+	Username      string         `json:"username"`      // 用户名
+	Email         string         `json:"email"`         // 邮箱
+	EmailVerified bool           `json:"emailVerified"` // 邮箱是否已验证
+	Name          string         `json:"name"`          // 姓名
+	Claims        map[string]any `json:"-"`             // 原始声明
+}
+
+// SSOState 单点登录授权请求，回调时校验
+type SSOState struct {
+	Provider string `json:"provider"` // 身份提供方
+	Verifier string `json:"verifier"` // PKCE verifier
+	Nonce    string `json:"nonce"`    // ID令牌 nonce
+}
+
+// SSOProvider 登录页展示的身份提供方
+type SSOProvider struct {
+	Name        string `json:"name"`        // 标识
+	DisplayName string `json:"displayName"` // 显示名称
+}
+
+// SSOAuthorization 单点登录授权地址
+type SSOAuthorization struct {
+	AuthUrl string `json:"authUrl"` // 跳转至身份提供方的授权地址
+	State   string `json:"state"`   // 回调时原样提交
+}
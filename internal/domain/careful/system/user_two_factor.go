@@ -0,0 +1,33 @@
+/**
+ * Description：
+ * FileName：user_two_factor.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 21:55:37
+ * Remark：
+ */
+
+package system
+
+import (
+	"github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/system"
+)
+
+type UserTwoFactor struct {
+	system.UserTwoFactor
+	CreateTime string `json:"createTime"` // 创建时间
+	UpdateTime string `json:"updateTime"` // 更新时间
+}
+
+// TwoFactorSetup 双因素认证绑定信息
+type TwoFactorSetup struct {
+	Secret string `json:"secret"` // TOTP密钥，无法扫码时手动输入
+	Uri    string `json:"uri"`    // otpauth地址
+	QrCode string `json:"qrCode"` // 二维码(Base64 PNG)
+}
+
+// TwoFactorStatus 双因素认证状态
+type TwoFactorStatus struct {
+	Enabled       bool `json:"enabled"`       // 是否已启用
+	Required      bool `json:"required"`      // 是否要求启用
+	RecoveryCodes int  `json:"recoveryCodes"` // 剩余恢复码数量
+}
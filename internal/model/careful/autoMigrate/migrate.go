@@ -10,6 +10,7 @@ package autoMigrate
 
 import (
 	"github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/logger"
+	"github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/system"
 	"gorm.io/gorm"
 )
 
@@ -17,6 +18,8 @@ func AutoMigrate(db *gorm.DB) {
 	// initSystem(db)
 	// initTools(db)
 	initLogger(db)
+	initAuth(db)
+	initData(db)
 }
 
 func initSystem(db *gorm.DB) {
@@ -38,4 +41,21 @@ func initTools(db *gorm.DB) {
 func initLogger(db *gorm.DB) {
 	// logger.NewOperateLogger().AutoMigrate(db)
 	logger.NewCacheLogger().AutoMigrate(db)
+	logger.NewAuditLogger().AutoMigrate(db)
+	logger.NewLoginLogger().AutoMigrate(db)
+	logger.NewChangeLogger().AutoMigrate(db)
+}
+
+func initAuth(db *gorm.DB) {
+	system.NewUserTwoFactor().AutoMigrate(db)
+	system.NewApiKey().AutoMigrate(db)
+	system.NewUserPasswordHistory().AutoMigrate(db)
+	system.NewUserIdentity().AutoMigrate(db)
+}
+
+// initData 数据迁移，需保证可重复执行
+func initData(db *gorm.DB) {
+	system.NewUser().ClearPasswordStr(db)
+	system.NewRole().AddRequireTwoFA(db)
+	system.NewUser().AddPasswordPolicyColumns(db)
 }
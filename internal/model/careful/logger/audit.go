@@ -0,0 +1,42 @@
+/**
+ * Description：
+ * FileName：audit.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 16:58:10
+ * Remark：
+ */
+
+package logger
+
+import (
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/constants/careful/logger/audit"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/models"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// AuditLogger 安全审计日志表
+type AuditLogger struct {
+	models.CoreModels
+	Event      audit.EventConst `gorm:"type:varchar(40);index;column:event;comment:审计事件" json:"event"`          // 审计事件
+	Username   string           `gorm:"type:varchar(50);index;column:username;comment:事件关联用户名" json:"username"` // 事件关联用户名
+	RequestIp  string           `gorm:"type:varchar(64);column:requestIp;comment:事件关联IP" json:"requestIp"`      // 事件关联IP
+	OperatorId string           `gorm:"type:varchar(100);column:operatorId;comment:操作人ID" json:"operatorId"`    // 操作人ID
+	Operator   string           `gorm:"type:varchar(50);column:operator;comment:操作人" json:"operator"`           // 操作人
+	Detail     string           `gorm:"type:text;column:detail;comment:事件详情" json:"detail"`                     // 事件详情
+}
+
+func NewAuditLogger() *AuditLogger {
+	return &AuditLogger{}
+}
+
+func (l *AuditLogger) TableName() string {
+	return "careful_logger_audit_log"
+}
+
+func (l *AuditLogger) AutoMigrate(db *gorm.DB) {
+	err := db.Set("gorm:table_options", "ENGINE=InnoDB,COMMENT='安全审计日志表'").AutoMigrate(&AuditLogger{})
+	if err != nil {
+		zap.L().Error("AuditLogger表模型迁移失败", zap.Error(err))
+	}
+}
@@ -9,6 +9,7 @@
 package logger
 
 import (
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/constants/careful/logger/cache"
 	"github.com/carefuly/carefuly-admin-go-gin/pkg/models"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
@@ -26,6 +27,18 @@ type CacheLogger struct {
 	CacheKey      string `gorm:"type:varchar(255);column:cacheKey;comment:缓存key键" json:"cacheKey"`         // 缓存请求地址
 	CacheValue    string `gorm:"type:text;column:cacheValue;comment:缓存value值" json:"cacheValue"`           // 缓存value值
 	CacheError    string `gorm:"type:varchar(255);column:cacheError;comment:缓存Error错误" json:"cacheError"`  // 缓存Error错误
+
+	CacheOperation cache.OperationConst `gorm:"type:varchar(20);index;column:cacheOperation;comment:缓存操作" json:"cacheOperation"` // 缓存操作
+	CacheResult    cache.ResultConst    `gorm:"type:varchar(20);index;column:cacheResult;comment:缓存结果" json:"cacheResult"`       // 缓存结果
+}
+
+// CacheStatGroup 缓存日志分组统计结果，不对应数据表
+type CacheStatGroup struct {
+	Prefix         string               `gorm:"column:prefix" json:"prefix"`                 // 缓存key键前缀
+	CacheOperation cache.OperationConst `gorm:"column:cacheOperation" json:"cacheOperation"` // 缓存操作
+	CacheResult    cache.ResultConst    `gorm:"column:cacheResult" json:"cacheResult"`       // 缓存结果
+	Total          int64                `gorm:"column:total" json:"total"`                   // 操作次数
+	Errors         int64                `gorm:"column:errors" json:"errors"`                 // 异常次数
 }
 
 func NewCacheLogger() *CacheLogger {
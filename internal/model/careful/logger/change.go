@@ -0,0 +1,44 @@
+/**
+ * Description：
+ * FileName：change.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 15:40:12
+ * Remark：
+ */
+
+package logger
+
+import (
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/models"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ChangeLogger 数据变更日志表
+type ChangeLogger struct {
+	models.CoreModels
+	EntityTable   string `gorm:"type:varchar(64);index:idx_entity;column:entityTable;comment:数据表" json:"entityTable"` // 数据表
+	EntityId      string `gorm:"type:varchar(100);index:idx_entity;column:entityId;comment:数据ID" json:"entityId"`     // 数据ID
+	EntityVersion int    `gorm:"type:int;column:entityVersion;comment:变更后的数据版本号" json:"entityVersion"`                // 变更后的数据版本号
+	Action        string `gorm:"type:varchar(20);index;column:action;comment:变更类型" json:"action"`                     // 变更类型
+	OperatorId    string `gorm:"type:varchar(100);column:operatorId;comment:操作人ID" json:"operatorId"`                 // 操作人ID
+	Operator      string `gorm:"type:varchar(50);index;column:operator;comment:操作人" json:"operator"`                  // 操作人
+	Changes       string `gorm:"type:longtext;column:changes;comment:字段差异" json:"-"`                                  // 字段差异
+	OldValues     string `gorm:"type:longtext;column:oldValues;comment:变更前的数据" json:"-"`                              // 变更前的数据
+	NewValues     string `gorm:"type:longtext;column:newValues;comment:变更后的数据" json:"-"`                              // 变更后的数据
+}
+
+func NewChangeLogger() *ChangeLogger {
+	return &ChangeLogger{}
+}
+
+func (l *ChangeLogger) TableName() string {
+	return "careful_logger_change_log"
+}
+
+func (l *ChangeLogger) AutoMigrate(db *gorm.DB) {
+	err := db.Set("gorm:table_options", "ENGINE=InnoDB,COMMENT='数据变更日志表'").AutoMigrate(&ChangeLogger{})
+	if err != nil {
+		zap.L().Error("ChangeLogger表模型迁移失败", zap.Error(err))
+	}
+}
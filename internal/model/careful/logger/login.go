@@ -0,0 +1,45 @@
+/**
+ * Description：
+ * FileName：login.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 17:42:05
+ * Remark：
+ */
+
+package logger
+
+import (
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/constants/careful/logger/login"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/models"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// LoginLogger 登录日志表
+type LoginLogger struct {
+	models.CoreModels
+	UserId         string            `gorm:"type:varchar(100);index;column:userId;comment:用户ID" json:"userId"`            // 用户ID
+	Username       string            `gorm:"type:varchar(50);index;column:username;comment:用户名" json:"username"`          // 用户名
+	Action         login.ActionConst `gorm:"type:varchar(20);index;column:action;comment:登录行为" json:"action"`             // 登录行为
+	Status         bool              `gorm:"type:boolean;index;column:status;comment:结果【true-成功 false-失败】" json:"status"` // 结果
+	Message        string            `gorm:"type:varchar(255);column:message;comment:失败原因" json:"message"`                // 失败原因
+	RequestIp      string            `gorm:"type:varchar(64);index;column:requestIp;comment:请求IP地址" json:"requestIp"`     // 请求IP地址
+	RequestOs      string            `gorm:"type:varchar(40);column:requestOs;comment:操作系统" json:"requestOs"`             // 操作系统
+	RequestBrowser string            `gorm:"type:varchar(64);column:requestBrowser;comment:浏览器" json:"requestBrowser"`    // 浏览器
+	UserAgent      string            `gorm:"type:varchar(255);column:userAgent;comment:用户代理" json:"userAgent"`            // 用户代理
+}
+
+func NewLoginLogger() *LoginLogger {
+	return &LoginLogger{}
+}
+
+func (l *LoginLogger) TableName() string {
+	return "careful_logger_login_log"
+}
+
+func (l *LoginLogger) AutoMigrate(db *gorm.DB) {
+	err := db.Set("gorm:table_options", "ENGINE=InnoDB,COMMENT='登录日志表'").AutoMigrate(&LoginLogger{})
+	if err != nil {
+		zap.L().Error("LoginLogger表模型迁移失败", zap.Error(err))
+	}
+}
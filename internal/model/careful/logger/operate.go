@@ -9,17 +9,17 @@
 package logger
 
 import (
-	"context"
 	"github.com/carefuly/carefuly-admin-go-gin/pkg/models"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 )
 
 // OperateLogger 操作日志表
 type OperateLogger struct {
 	models.CoreModels
 	RequestUsername string `gorm:"type:varchar(40);column:requestUsername;comment:请求用户名" json:"requestUsername"` // 请求用户名
+	ActorId         string `gorm:"type:varchar(100);column:actorId;comment:实际操作人ID" json:"actorId"`              // 实际操作人ID，模拟登录时为发起模拟的管理员
+	ActorUsername   string `gorm:"type:varchar(40);column:actorUsername;comment:实际操作人" json:"actorUsername"`     // 实际操作人
 	RequestTime     string `gorm:"type:varchar(40);column:requestTime;comment:请求耗时" json:"requestTime"`          // 请求耗时
 	RequestStatus   int    `gorm:"type:int;column:requestStatus;comment:响应状态码" json:"requestStatus"`             // 响应状态码
 	RequestMethod   string `gorm:"type:varchar(8);column:requestMethod;comment:请求方式" json:"requestMethod"`       // 请求方式
@@ -50,17 +50,3 @@ func (l *OperateLogger) AutoMigrate(db *gorm.DB) {
 		zap.L().Error("OperateLogger表模型迁移失败", zap.Error(err))
 	}
 }
-
-func (l *OperateLogger) Insert(ctx context.Context, db *gorm.DB, op OperateLogger) {
-	currentLogger := db.Config.Logger
-	// 临时禁用日志
-	db.Config.Logger = logger.Default.LogMode(logger.Silent)
-
-	err := db.WithContext(ctx).Create(&op).Error
-	if err != nil {
-		zap.L().Error("日志记录异常", zap.String("err", err.Error()))
-	}
-
-	// 恢复日志级别
-	db.Config.Logger = currentLogger
-}
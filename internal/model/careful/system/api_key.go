@@ -0,0 +1,45 @@
+/**
+ * Description：
+ * FileName：api_key.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 23:12:40
+ * Remark：
+ */
+
+package system
+
+import (
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/models"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"time"
+)
+
+// ApiKey 个人访问令牌表
+type ApiKey struct {
+	models.CoreModels
+	UserId       string     `gorm:"type:varchar(100);not null;index;column:user_id;comment:所属用户" json:"userId"`               // 所属用户
+	Name         string     `gorm:"type:varchar(64);not null;column:name;comment:名称" json:"name"`                             // 名称
+	Prefix       string     `gorm:"type:varchar(32);not null;column:prefix;comment:令牌前缀" json:"prefix"`                       // 令牌前缀，用于识别
+	KeyHash      string     `gorm:"type:varchar(64);not null;uniqueIndex;column:key_hash;comment:令牌摘要(SHA-256)" json:"-"`     // 令牌摘要
+	Scopes       string     `gorm:"type:text;column:scopes;comment:授权范围(按钮权限值，JSON数组，为空表示继承用户全部权限)" json:"-"`                 // 授权范围
+	ExpireTime   *time.Time `gorm:"column:expire_time;comment:过期时间" json:"-"`                                                 // 过期时间
+	LastUsedTime *time.Time `gorm:"column:last_used_time;comment:最近使用时间" json:"-"`                                            // 最近使用时间
+	LastUsedIp   string     `gorm:"type:varchar(64);column:last_used_ip;comment:最近使用IP" json:"lastUsedIp"`                    // 最近使用IP
+	Revoked      bool       `gorm:"type:boolean;index:idx_revoked;default:false;column:revoked;comment:是否已吊销" json:"revoked"` // 是否已吊销
+}
+
+func NewApiKey() *ApiKey {
+	return &ApiKey{}
+}
+
+func (a *ApiKey) TableName() string {
+	return "careful_system_api_key"
+}
+
+func (a *ApiKey) AutoMigrate(db *gorm.DB) {
+	err := db.Set("gorm:table_options", "ENGINE=InnoDB,COMMENT='个人访问令牌表'").AutoMigrate(&ApiKey{})
+	if err != nil {
+		zap.L().Error("ApiKey表模型迁移失败", zap.Error(err))
+	}
+}
@@ -23,6 +23,7 @@ type Role struct {
 	Name          string              `gorm:"type:varchar(64);not null;index:idx_name;column:name;comment:角色名称" json:"name"`                       // 角色名称
 	Code          string              `gorm:"type:varchar(64);not null;uniqueIndex;column:code;comment:角色编码" json:"code"`                          // 角色编码
 	DataRange     role.DataRangeConst `gorm:"type:tinyint;default:1;column:data_range;comment:数据权限范围" json:"data_range"`                           // 数据权限范围
+	RequireTwoFA  bool                `gorm:"type:boolean;default:false;column:require_two_fa;comment:是否要求双因素认证" json:"require_two_fa"`            // 是否要求双因素认证
 	DeptIDs       []string            `gorm:"-" json:"dept_ids"`                                                                                   // 忽略GORM处理，只用于接收参数
 	MenuIDs       []string            `gorm:"-" json:"menu_ids"`                                                                                   // 忽略GORM处理
 	MenuButtonIDs []string            `gorm:"-" json:"menu_button_ids"`                                                                            // 忽略GORM处理
@@ -66,3 +67,13 @@ func migrateManyToManyTable(db *gorm.DB, tableName string, comment string) {
 		zap.L().Error(fmt.Sprintf("%s表备注设置失败", tableName), zap.Error(err))
 	}
 }
+
+// AddRequireTwoFA 为已有角色表补充 require_two_fa 列
+func (r *Role) AddRequireTwoFA(db *gorm.DB) {
+	if !db.Migrator().HasTable(r.TableName()) || db.Migrator().HasColumn(&Role{}, "RequireTwoFA") {
+		return
+	}
+	if err := db.Migrator().AddColumn(&Role{}, "RequireTwoFA"); err != nil {
+		zap.L().Error("require_two_fa列新增失败", zap.Error(err))
+	}
+}
@@ -14,27 +14,30 @@ import (
 	"github.com/carefuly/carefuly-admin-go-gin/pkg/models"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"time"
 )
 
 // User 用户表
 type User struct {
 	models.CoreModels
-	Status      bool             `gorm:"type:boolean;index:idx_status;default:true;column:status;comment:状态【true-启用 false-停用】" json:"status"` // 状态
-	Username    string           `gorm:"type:varchar(50);not null;unique;column:username;comment:用户名" json:"username"`                        // 用户名
-	Password    string           `gorm:"type:varchar(255);not null;column:password;comment:密码" json:"-"`                                      // 密码
-	PasswordStr string           `gorm:"type:varchar(255);not null;column:password_str;comment:明文密码" json:"-"`                                // 明文密码
-	UserType    user.TypeConst   `gorm:"type:tinyint;default:1;column:user_type;comment:用户类型" json:"userType"`                                // 用户类型
-	Name        string           `gorm:"type:varchar(50);index:idx_search;column:name;comment:姓名" json:"name"`                                // 姓名
-	Gender      user.GenderConst `gorm:"type:tinyint;default:1;column:gender;comment:性别" json:"gender"`                                       // 性别
-	Email       string           `gorm:"type:varchar(50);index:idx_search;column:email;comment:邮箱" json:"email"`                              // 邮箱
-	Mobile      string           `gorm:"type:varchar(20);index:idx_search;column:mobile;comment:电话" json:"mobile"`                            // 电话
-	Avatar      string           `gorm:"type:text;column:avatar;comment:头像" json:"avatar"`                                                    // 头像
-	PostIDs     []string         `gorm:"-" json:"post_ids"`                                                                                   // 忽略GORM处理
-	RoleIDs     []string         `gorm:"-" json:"role_ids"`                                                                                   // 忽略GORM处理
-	DeptId      string           `gorm:"type:varchar(100);index;column:dept_id;comment:部门ID" json:"dept_id"`                                  // 部门ID
-	Dept        *Dept            `gorm:"foreignKey:DeptId;constraint:OnUpdate:CASCADE,OnDelete:SET NULL" json:"dept"`                         // 部门
-	Post        []*Post          `gorm:"many2many:careful_system_users_post;"`                                                                // 关联岗位
-	Role        []*Role          `gorm:"many2many:careful_system_users_role;"`                                                                // 关联角色
+	Status   bool             `gorm:"type:boolean;index:idx_status;default:true;column:status;comment:状态【true-启用 false-停用】" json:"status"` // 状态
+	Username string           `gorm:"type:varchar(50);not null;unique;column:username;comment:用户名" json:"username"`                        // 用户名
+	Password string           `gorm:"type:varchar(255);not null;column:password;comment:密码" json:"-"`                                      // 密码
+	UserType user.TypeConst   `gorm:"type:tinyint;default:1;column:user_type;comment:用户类型" json:"userType"`                                // 用户类型
+	Name     string           `gorm:"type:varchar(50);index:idx_search;column:name;comment:姓名" json:"name"`                                // 姓名
+	Gender   user.GenderConst `gorm:"type:tinyint;default:1;column:gender;comment:性别" json:"gender"`                                       // 性别
+	Email    string           `gorm:"type:varchar(50);index:idx_search;column:email;comment:邮箱" json:"email"`                              // 邮箱
+	Mobile   string           `gorm:"type:varchar(20);index:idx_search;column:mobile;comment:电话" json:"mobile"`                            // 电话
+	Avatar   string           `gorm:"type:text;column:avatar;comment:头像" json:"avatar"`                                                    // 头像
+	PostIDs  []string         `gorm:"-" json:"post_ids"`                                                                                   // 忽略GORM处理
+	RoleIDs  []string         `gorm:"-" json:"role_ids"`                                                                                   // 忽略GORM处理
+	DeptId   string           `gorm:"type:varchar(100);index;column:dept_id;comment:部门ID" json:"dept_id"`                                  // 部门ID
+	Dept     *Dept            `gorm:"foreignKey:DeptId;constraint:OnUpdate:CASCADE,OnDelete:SET NULL" json:"dept"`                         // 部门
+	Post     []*Post          `gorm:"many2many:careful_system_users_post;"`                                                                // 关联岗位
+	Role     []*Role          `gorm:"many2many:careful_system_users_role;"`                                                                // 关联角色
+
+	PasswordUpdateTime     *time.Time `gorm:"autoCreateTime;column:password_update_time;comment:密码修改时间" json:"-"`                                       // 密码修改时间
+	PasswordChangeRequired bool       `gorm:"type:boolean;default:false;column:password_change_required;comment:是否需修改密码" json:"passwordChangeRequired"` // 是否需修改密码
 }
 
 func NewUser() *User {
@@ -56,6 +59,47 @@ func (u *User) AutoMigrate(db *gorm.DB) {
 	migrateManyToManyTable(db, "careful_system_users_role", "用户-关联角色表")
 }
 
+// ClearPasswordStr 清空历史遗留的明文密码列
+// password_str 已不再写入，保留列并设置默认值以兼容旧表结构
+func (u *User) ClearPasswordStr(db *gorm.DB) {
+	if !db.Migrator().HasTable(u.TableName()) || !db.Migrator().HasColumn(&User{}, "password_str") {
+		return
+	}
+
+	err := db.Exec(fmt.Sprintf(
+		"ALTER TABLE %s MODIFY COLUMN password_str varchar(255) NOT NULL DEFAULT '' COMMENT '明文密码(已废弃)'",
+		u.TableName(),
+	)).Error
+	if err != nil {
+		zap.L().Error("password_str列修改失败", zap.Error(err))
+		return
+	}
+
+	result := db.Exec(fmt.Sprintf("UPDATE %s SET password_str = '' WHERE password_str <> ''", u.TableName()))
+	if result.Error != nil {
+		zap.L().Error("明文密码清理失败", zap.Error(result.Error))
+		return
+	}
+	if result.RowsAffected > 0 {
+		zap.L().Info("明文密码清理完成", zap.Int64("rows", result.RowsAffected))
+	}
+}
+
+// AddPasswordPolicyColumns 为已有用户表补充密码策略相关列
+func (u *User) AddPasswordPolicyColumns(db *gorm.DB) {
+	if !db.Migrator().HasTable(u.TableName()) {
+		return
+	}
+	for _, field := range []string{"PasswordUpdateTime", "PasswordChangeRequired"} {
+		if db.Migrator().HasColumn(&User{}, field) {
+			continue
+		}
+		if err := db.Migrator().AddColumn(&User{}, field); err != nil {
+			zap.L().Error("用户表密码策略列新增失败", zap.String("field", field), zap.Error(err))
+		}
+	}
+}
+
 // 迁移many2many中间表并设置表备注
 func (u *User) migrateManyToManyTable(db *gorm.DB, tableName string, comment string) {
 	err := db.Exec(fmt.Sprintf(
@@ -0,0 +1,41 @@
+/**
+ * Description：
+ * FileName：user_identity.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 06:40:31
+ * Remark：
+ */
+
+package system
+
+import (
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/models"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"time"
+)
+
+// UserIdentity 用户第三方身份关联表
+type UserIdentity struct {
+	models.CoreModels
+	UserId        string     `gorm:"type:varchar(100);not null;index;column:user_id;comment:用户ID" json:"userId"`                                    // 用户ID
+	Provider      string     `gorm:"type:varchar(50);not null;uniqueIndex:uk_provider_subject;column:provider;comment:身份提供方" json:"provider"`       // 身份提供方
+	Subject       string     `gorm:"type:varchar(255);not null;uniqueIndex:uk_provider_subject;column:subject;comment:第三方用户标识(sub)" json:"subject"` // 第三方用户标识
+	Email         string     `gorm:"type:varchar(100);column:email;comment:第三方邮箱" json:"email"`                                                     // 第三方邮箱
+	LastLoginTime *time.Time `gorm:"column:last_login_time;comment:最近登录时间" json:"-"`                                                                // 最近登录时间
+}
+
+func NewUserIdentity() *UserIdentity {
+	return &UserIdentity{}
+}
+
+func (u *UserIdentity) TableName() string {
+	return "careful_system_user_identity"
+}
+
+func (u *UserIdentity) AutoMigrate(db *gorm.DB) {
+	err := db.Set("gorm:table_options", "ENGINE=InnoDB,COMMENT='用户第三方身份关联表'").AutoMigrate(&UserIdentity{})
+	if err != nil {
+		zap.L().Error("UserIdentity表模型迁移失败", zap.Error(err))
+	}
+}
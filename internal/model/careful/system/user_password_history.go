@@ -0,0 +1,37 @@
+/**
+ * Description：
+ * FileName：user_password_history.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 01:24:47
+ * Remark：
+ */
+
+package system
+
+import (
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/models"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// UserPasswordHistory 用户历史密码表
+type UserPasswordHistory struct {
+	models.CoreModels
+	UserId   string `gorm:"type:varchar(100);not null;index;column:user_id;comment:用户ID" json:"userId"` // 用户ID
+	Password string `gorm:"type:varchar(255);not null;column:password;comment:历史密码(密文)" json:"-"`       // 历史密码
+}
+
+func NewUserPasswordHistory() *UserPasswordHistory {
+	return &UserPasswordHistory{}
+}
+
+func (u *UserPasswordHistory) TableName() string {
+	return "careful_system_user_password_history"
+}
+
+func (u *UserPasswordHistory) AutoMigrate(db *gorm.DB) {
+	err := db.Set("gorm:table_options", "ENGINE=InnoDB,COMMENT='用户历史密码表'").AutoMigrate(&UserPasswordHistory{})
+	if err != nil {
+		zap.L().Error("UserPasswordHistory表模型迁移失败", zap.Error(err))
+	}
+}
@@ -0,0 +1,40 @@
+/**
+ * Description：
+ * FileName：user_two_factor.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 21:49:03
+ * Remark：
+ */
+
+package system
+
+import (
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/models"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// UserTwoFactor 用户双因素认证表
+type UserTwoFactor struct {
+	models.CoreModels
+	UserId        string `gorm:"type:varchar(100);not null;uniqueIndex;column:user_id;comment:用户ID" json:"userId"` // 用户ID
+	Secret        string `gorm:"type:varchar(64);not null;column:secret;comment:TOTP密钥" json:"-"`                  // TOTP密钥
+	Enabled       bool   `gorm:"type:boolean;default:false;column:enabled;comment:是否已启用" json:"enabled"`           // 是否已启用
+	LastCounter   int64  `gorm:"type:bigint;default:0;column:last_counter;comment:最近使用的时间步" json:"-"`              // 最近使用的时间步，防止验证码重放
+	RecoveryCodes string `gorm:"type:text;column:recovery_codes;comment:恢复码(SHA-256摘要，JSON数组)" json:"-"`           // 恢复码摘要
+}
+
+func NewUserTwoFactor() *UserTwoFactor {
+	return &UserTwoFactor{}
+}
+
+func (u *UserTwoFactor) TableName() string {
+	return "careful_system_user_two_factor"
+}
+
+func (u *UserTwoFactor) AutoMigrate(db *gorm.DB) {
+	err := db.Set("gorm:table_options", "ENGINE=InnoDB,COMMENT='用户双因素认证表'").AutoMigrate(&UserTwoFactor{})
+	if err != nil {
+		zap.L().Error("UserTwoFactor表模型迁移失败", zap.Error(err))
+	}
+}
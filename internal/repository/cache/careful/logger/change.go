@@ -0,0 +1,54 @@
+/**
+ * Description：
+ * FileName：change.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 21:48:05
+ * Remark：
+ */
+
+package logger
+
+import (
+	"context"
+	"fmt"
+	modelSystem "github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/system"
+	modelTools "github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/tools"
+	"github.com/redis/go-redis/v9"
+)
+
+// entityKeys 数据表对应的实体缓存键，与各实体缓存的 key 保持一致
+var entityKeys = map[string]string{
+	new(modelSystem.User).TableName():       "careful:system:user:info:%s",
+	new(modelSystem.Dept).TableName():       "careful:system:dept:info:%s",
+	new(modelSystem.Role).TableName():       "careful:system:role:info:%s",
+	new(modelSystem.Post).TableName():       "careful:system:post:info:%s",
+	new(modelSystem.Menu).TableName():       "careful:system:menu:info:%s",
+	new(modelSystem.MenuButton).TableName(): "careful:system:menu_button:info:%s",
+	new(modelSystem.MenuColumn).TableName(): "careful:system:menu_column:info:%s",
+	new(modelTools.Dict).TableName():        "careful:tools:dict:info:%s",
+	new(modelTools.DictType).TableName():    "careful:tools:dict_type:info:%s",
+	new(modelTools.Bucket).TableName():      "careful:tools:bucket:info:%s",
+}
+
+type ChangeLogCache interface {
+	// DelEntity 删除数据对应的实体缓存，没有实体缓存的表忽略
+	DelEntity(ctx context.Context, table, entityId string) error
+}
+
+type RedisChangeLogCache struct {
+	cmd redis.Cmdable
+}
+
+func NewRedisChangeLogCache(cmd redis.Cmdable) ChangeLogCache {
+	return &RedisChangeLogCache{
+		cmd: cmd,
+	}
+}
+
+func (c *RedisChangeLogCache) DelEntity(ctx context.Context, table, entityId string) error {
+	format, ok := entityKeys[table]
+	if !ok {
+		return nil
+	}
+	return c.cmd.Del(ctx, fmt.Sprintf(format, entityId)).Err()
+}
@@ -0,0 +1,39 @@
+/**
+ * Description：
+ * FileName：api_key.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 23:24:12
+ * Remark：
+ */
+
+package system
+
+import (
+	"context"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"time"
+)
+
+type ApiKeyCache interface {
+	// TryTouch 获取记录最近使用信息的机会，间隔内仅首次返回 true
+	TryTouch(ctx context.Context, id string, interval time.Duration) (bool, error)
+}
+
+type RedisApiKeyCache struct {
+	cmd redis.Cmdable
+}
+
+func NewRedisApiKeyCache(cmd redis.Cmdable) ApiKeyCache {
+	return &RedisApiKeyCache{
+		cmd: cmd,
+	}
+}
+
+func (c *RedisApiKeyCache) TryTouch(ctx context.Context, id string, interval time.Duration) (bool, error) {
+	return c.cmd.SetNX(ctx, c.key(id), 1, interval).Result()
+}
+
+func (c *RedisApiKeyCache) key(id string) string {
+	return fmt.Sprintf("careful:system:api_key:touch:%s", id)
+}
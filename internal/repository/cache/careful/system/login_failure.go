@@ -0,0 +1,135 @@
+/**
+ * Description：
+ * FileName：login_failure.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 16:10:12
+ * Remark：
+ */
+
+package system
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"time"
+)
+
+//go:embed lua/login_failure.lua
+var luaLoginFailure string
+
+// LoginFailureRule 登录失败锁定规则
+type LoginFailureRule struct {
+	Window          time.Duration // 失败次数统计窗口
+	UserThreshold   int           // 用户名锁定阈值，0 表示不锁定
+	IpThreshold     int           // IP锁定阈值，0 表示不锁定
+	LockDuration    time.Duration // 首次锁定时长
+	MaxLockDuration time.Duration // 最长锁定时长
+}
+
+// LoginFailureResult 登录失败记录结果
+type LoginFailureResult struct {
+	UserFailures int64         // 用户名失败次数
+	UserLock     time.Duration // 用户名本次锁定时长，0 表示未锁定
+	IpFailures   int64         // IP失败次数
+	IpLock       time.Duration // IP本次锁定时长，0 表示未锁定
+}
+
+type LoginFailureCache interface {
+	Get(ctx context.Context, username string) (int64, error)
+	Incr(ctx context.Context, username, ip string, rule LoginFailureRule) (LoginFailureResult, error)
+	LockTTL(ctx context.Context, username, ip string) (time.Duration, time.Duration, error)
+	Del(ctx context.Context, username string) error
+	Unlock(ctx context.Context, username string) error
+	UnlockIp(ctx context.Context, ip string) error
+}
+
+type RedisLoginFailureCache struct {
+	cmd redis.Cmdable
+}
+
+func NewRedisLoginFailureCache(cmd redis.Cmdable) LoginFailureCache {
+	return &RedisLoginFailureCache{
+		cmd: cmd,
+	}
+}
+
+// Get 获取登录失败次数
+func (c *RedisLoginFailureCache) Get(ctx context.Context, username string) (int64, error) {
+	cnt, err := c.cmd.Get(ctx, c.userKey(username)).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	return cnt, err
+}
+
+// Incr 原子记录用户名及IP的登录失败次数，达到阈值时锁定
+func (c *RedisLoginFailureCache) Incr(ctx context.Context, username, ip string, rule LoginFailureRule) (LoginFailureResult, error) {
+	keys := []string{c.userKey(username), c.userLockKey(username), c.ipKey(ip), c.ipLockKey(ip)}
+	res, err := c.cmd.Eval(ctx, luaLoginFailure, keys,
+		int64(rule.Window.Seconds()),
+		rule.UserThreshold,
+		rule.IpThreshold,
+		int64(rule.LockDuration.Seconds()),
+		int64(rule.MaxLockDuration.Seconds()),
+	).Int64Slice()
+	if err != nil {
+		// 调用 redis 出了问题
+		return LoginFailureResult{}, err
+	}
+	if len(res) != 4 {
+		return LoginFailureResult{}, fmt.Errorf("登录失败计数返回值异常: %v", res)
+	}
+
+	return LoginFailureResult{
+		UserFailures: res[0],
+		UserLock:     time.Duration(res[1]) * time.Second,
+		IpFailures:   res[2],
+		IpLock:       time.Duration(res[3]) * time.Second,
+	}, nil
+}
+
+// LockTTL 获取用户名及IP的剩余锁定时长
+func (c *RedisLoginFailureCache) LockTTL(ctx context.Context, username, ip string) (time.Duration, time.Duration, error) {
+	pipe := c.cmd.Pipeline()
+	userTTL := pipe.TTL(ctx, c.userLockKey(username))
+	ipTTL := pipe.TTL(ctx, c.ipLockKey(ip))
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, 0, err
+	}
+	// key 不存在时返回负数
+	return max(userTTL.Val(), 0), max(ipTTL.Val(), 0), nil
+}
+
+// Del 登录成功后清除用户名失败次数
+func (c *RedisLoginFailureCache) Del(ctx context.Context, username string) error {
+	return c.cmd.Del(ctx, c.userKey(username)).Err()
+}
+
+// Unlock 解锁用户名
+func (c *RedisLoginFailureCache) Unlock(ctx context.Context, username string) error {
+	return c.cmd.Del(ctx, c.userKey(username), c.userLockKey(username)).Err()
+}
+
+// UnlockIp 解锁IP
+func (c *RedisLoginFailureCache) UnlockIp(ctx context.Context, ip string) error {
+	return c.cmd.Del(ctx, c.ipKey(ip), c.ipLockKey(ip)).Err()
+}
+
+func (c *RedisLoginFailureCache) userKey(username string) string {
+	return fmt.Sprintf("careful:system:login:failure:user:%s", username)
+}
+
+func (c *RedisLoginFailureCache) userLockKey(username string) string {
+	return fmt.Sprintf("careful:system:login:lock:user:%s", username)
+}
+
+func (c *RedisLoginFailureCache) ipKey(ip string) string {
+	return fmt.Sprintf("careful:system:login:failure:ip:%s", ip)
+}
+
+func (c *RedisLoginFailureCache) ipLockKey(ip string) string {
+	return fmt.Sprintf("careful:system:login:lock:ip:%s", ip)
+}
@@ -0,0 +1,43 @@
+-- 用户名维度
+local userCntKey = KEYS[1]
+local userLockKey = KEYS[2]
+-- IP维度
+local ipCntKey = KEYS[3]
+local ipLockKey = KEYS[4]
+
+local window = tonumber(ARGV[1])          -- 失败次数统计窗口(秒)
+local userThreshold = tonumber(ARGV[2])   -- 用户名锁定阈值，0 表示不锁定
+local ipThreshold = tonumber(ARGV[3])     -- IP锁定阈值，0 表示不锁定
+local lockDuration = tonumber(ARGV[4])    -- 首次锁定时长(秒)
+local maxLockDuration = tonumber(ARGV[5]) -- 最长锁定时长(秒)
+
+-- 记录一次失败，达到阈值后锁定，超出阈值的每次失败锁定时长翻倍
+local function fail(cntKey, lockKey, threshold)
+    local cnt = redis.call("incr", cntKey)
+    redis.call("expire", cntKey, window)
+
+    if threshold <= 0 or cnt < threshold then
+        return cnt, 0
+    end
+
+    local ttl = lockDuration
+    for _ = 1, cnt - threshold do
+        ttl = ttl * 2
+        if ttl >= maxLockDuration then
+            break
+        end
+    end
+    if ttl > maxLockDuration then
+        ttl = maxLockDuration
+    end
+
+    redis.call("set", lockKey, 1, "EX", ttl)
+    -- 锁定期间保留失败次数，解锁后再次失败继续翻倍
+    redis.call("expire", cntKey, ttl + window)
+    return cnt, ttl
+end
+
+local userCnt, userTtl = fail(userCntKey, userLockKey, userThreshold)
+local ipCnt, ipTtl = fail(ipCntKey, ipLockKey, ipThreshold)
+
+return { userCnt, userTtl, ipCnt, ipTtl }
@@ -0,0 +1,22 @@
+-- 邮箱维度：发送间隔内只能发送一次
+local emailKey = KEYS[1]
+-- IP维度：统计窗口内的发送次数
+local ipKey = KEYS[2]
+
+local emailInterval = tonumber(ARGV[1]) -- 邮箱发送间隔(秒)
+local ipLimit = tonumber(ARGV[2])       -- IP发送次数上限，0 表示不限制
+local ipWindow = tonumber(ARGV[3])      -- IP统计窗口(秒)
+
+if redis.call('EXISTS', emailKey) == 1 then
+    return 0
+end
+local ipCnt = tonumber(redis.call('GET', ipKey) or '0')
+if ipLimit > 0 and ipCnt >= ipLimit then
+    return 0
+end
+
+redis.call('SET', emailKey, '1', 'EX', emailInterval)
+if redis.call('INCR', ipKey) == 1 then
+    redis.call('EXPIRE', ipKey, ipWindow)
+end
+return 1
@@ -0,0 +1,64 @@
+/**
+ * Description：
+ * FileName：password_reset_limit.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 18:52:06
+ * Remark：
+ */
+
+package system
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"time"
+)
+
+//go:embed lua/password_reset_limit.lua
+var luaPasswordResetLimit string
+
+// PasswordResetLimitRule 找回密码验证码发送频率限制
+type PasswordResetLimitRule struct {
+	EmailInterval time.Duration // 同一邮箱的发送间隔
+	IpLimit       int           // 同一IP在统计窗口内的发送次数上限，0 表示不限制
+	IpWindow      time.Duration // IP发送次数统计窗口
+}
+
+type PasswordResetLimitCache interface {
+	// Acquire 占用一次发送次数，超出限制时返回 false
+	Acquire(ctx context.Context, email, ip string, rule PasswordResetLimitRule) (bool, error)
+}
+
+type RedisPasswordResetLimitCache struct {
+	cmd redis.Cmdable
+}
+
+func NewRedisPasswordResetLimitCache(cmd redis.Cmdable) PasswordResetLimitCache {
+	return &RedisPasswordResetLimitCache{
+		cmd: cmd,
+	}
+}
+
+// Acquire 原子校验并记录邮箱及IP的发送次数
+func (c *RedisPasswordResetLimitCache) Acquire(ctx context.Context, email, ip string, rule PasswordResetLimitRule) (bool, error) {
+	res, err := c.cmd.Eval(ctx, luaPasswordResetLimit, []string{c.emailKey(email), c.ipKey(ip)},
+		int64(rule.EmailInterval.Seconds()),
+		rule.IpLimit,
+		int64(rule.IpWindow.Seconds()),
+	).Int()
+	if err != nil {
+		// 调用 redis 出了问题
+		return false, err
+	}
+	return res == 1, nil
+}
+
+func (c *RedisPasswordResetLimitCache) emailKey(email string) string {
+	return fmt.Sprintf("careful:system:password_reset:email:%s", email)
+}
+
+func (c *RedisPasswordResetLimitCache) ipKey(ip string) string {
+	return fmt.Sprintf("careful:system:password_reset:ip:%s", ip)
+}
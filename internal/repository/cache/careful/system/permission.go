@@ -0,0 +1,121 @@
+/**
+ * Description：
+ * FileName：permission.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 09:52:18
+ * Remark：
+ */
+
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	domainSystem "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/system"
+	"github.com/redis/go-redis/v9"
+	"time"
+)
+
+var ErrPermissionNotExist = redis.Nil
+
+type PermissionCache interface {
+	Get(ctx context.Context, userId string) (*domainSystem.UserPermission, error)
+	Set(ctx context.Context, domain domainSystem.UserPermission) error
+	GetRoutes(ctx context.Context, userId string) ([]*domainSystem.Route, error)
+	SetRoutes(ctx context.Context, userId string, routes []*domainSystem.Route) error
+	Del(ctx context.Context, userIds ...string) error
+	DelAll(ctx context.Context) error
+}
+
+type RedisPermissionCache struct {
+	cmd        redis.Cmdable
+	expiration time.Duration
+}
+
+func NewRedisPermissionCache(cmd redis.Cmdable) PermissionCache {
+	return &RedisPermissionCache{
+		cmd:        cmd,
+		expiration: time.Minute * 30,
+	}
+}
+
+func (c *RedisPermissionCache) Get(ctx context.Context, userId string) (*domainSystem.UserPermission, error) {
+	key := c.key(userId)
+
+	data, err := c.cmd.Get(ctx, key).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrPermissionNotExist
+		}
+		return nil, err
+	}
+
+	var doMain domainSystem.UserPermission
+	err = json.Unmarshal([]byte(data), &doMain)
+	return &doMain, err
+}
+
+func (c *RedisPermissionCache) Set(ctx context.Context, domain domainSystem.UserPermission) error {
+	key := c.key(domain.UserId)
+	data, err := json.Marshal(domain)
+	if err != nil {
+		return err
+	}
+	return c.cmd.Set(ctx, key, data, c.expiration).Err()
+}
+
+func (c *RedisPermissionCache) GetRoutes(ctx context.Context, userId string) ([]*domainSystem.Route, error) {
+	key := c.routesKey(userId)
+
+	data, err := c.cmd.Get(ctx, key).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrPermissionNotExist
+		}
+		return nil, err
+	}
+
+	var routes []*domainSystem.Route
+	err = json.Unmarshal([]byte(data), &routes)
+	return routes, err
+}
+
+func (c *RedisPermissionCache) SetRoutes(ctx context.Context, userId string, routes []*domainSystem.Route) error {
+	key := c.routesKey(userId)
+	data, err := json.Marshal(routes)
+	if err != nil {
+		return err
+	}
+	return c.cmd.Set(ctx, key, data, c.expiration).Err()
+}
+
+func (c *RedisPermissionCache) Del(ctx context.Context, userIds ...string) error {
+	if len(userIds) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(userIds)*2)
+	for _, id := range userIds {
+		keys = append(keys, c.key(id), c.routesKey(id))
+	}
+	return c.cmd.Del(ctx, keys...).Err()
+}
+
+func (c *RedisPermissionCache) DelAll(ctx context.Context) error {
+	iter := c.cmd.Scan(ctx, 0, "careful:system:permission:*", 100).Iterator()
+	for iter.Next(ctx) {
+		if err := c.cmd.Del(ctx, iter.Val()).Err(); err != nil {
+			return err
+		}
+	}
+	return iter.Err()
+}
+
+func (c *RedisPermissionCache) key(userId string) string {
+	return fmt.Sprintf("careful:system:permission:user:%s", userId)
+}
+
+func (c *RedisPermissionCache) routesKey(userId string) string {
+	return fmt.Sprintf("careful:system:permission:routes:%s", userId)
+}
@@ -0,0 +1,169 @@
+/**
+ * Description：
+ * FileName：session.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 18:46:37
+ * Remark：
+ */
+
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	domainSystem "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/system"
+	"github.com/redis/go-redis/v9"
+	"strconv"
+	"time"
+)
+
+var ErrSessionNotExist = redis.Nil
+
+type SessionCache interface {
+	Set(ctx context.Context, domain domainSystem.Session, expiration time.Duration) error
+	// Update 更新未过期的会话，保留剩余有效期
+	Update(ctx context.Context, domain domainSystem.Session) error
+	Get(ctx context.Context, jti string) (*domainSystem.Session, error)
+	TTL(ctx context.Context, jti string) (time.Duration, error)
+	GetUserJtis(ctx context.Context, userId string) ([]string, error)
+	GetAll(ctx context.Context) ([]domainSystem.Session, error)
+	Del(ctx context.Context, userId, jti string) error
+}
+
+type RedisSessionCache struct {
+	cmd redis.Cmdable
+}
+
+func NewRedisSessionCache(cmd redis.Cmdable) SessionCache {
+	return &RedisSessionCache{
+		cmd: cmd,
+	}
+}
+
+// Set 记录会话，同时维护用户会话集合及全局索引(按过期时间排序)
+func (c *RedisSessionCache) Set(ctx context.Context, domain domainSystem.Session, expiration time.Duration) error {
+	data, err := json.Marshal(domain)
+	if err != nil {
+		return err
+	}
+
+	userKey := c.userKey(domain.UserId)
+	pipe := c.cmd.TxPipeline()
+	pipe.Set(ctx, c.key(domain.Jti), data, expiration)
+	pipe.SAdd(ctx, userKey, domain.Jti)
+	pipe.Expire(ctx, userKey, expiration)
+	pipe.ZAdd(ctx, c.indexKey(), redis.Z{
+		Score:  float64(time.Now().Add(expiration).Unix()),
+		Member: domain.Jti,
+	})
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+func (c *RedisSessionCache) Update(ctx context.Context, domain domainSystem.Session) error {
+	data, err := json.Marshal(domain)
+	if err != nil {
+		return err
+	}
+
+	err = c.cmd.SetArgs(ctx, c.key(domain.Jti), data, redis.SetArgs{
+		Mode:    "XX",
+		KeepTTL: true,
+	}).Err()
+	if errors.Is(err, redis.Nil) {
+		return ErrSessionNotExist
+	}
+	return err
+}
+
+func (c *RedisSessionCache) Get(ctx context.Context, jti string) (*domainSystem.Session, error) {
+	data, err := c.cmd.Get(ctx, c.key(jti)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrSessionNotExist
+		}
+		return nil, err
+	}
+
+	var doMain domainSystem.Session
+	err = json.Unmarshal([]byte(data), &doMain)
+	return &doMain, err
+}
+
+// TTL 会话剩余有效期
+func (c *RedisSessionCache) TTL(ctx context.Context, jti string) (time.Duration, error) {
+	ttl, err := c.cmd.TTL(ctx, c.key(jti)).Result()
+	if err != nil {
+		return 0, err
+	}
+	if ttl < 0 {
+		return 0, ErrSessionNotExist
+	}
+	return ttl, nil
+}
+
+func (c *RedisSessionCache) GetUserJtis(ctx context.Context, userId string) ([]string, error) {
+	return c.cmd.SMembers(ctx, c.userKey(userId)).Result()
+}
+
+// GetAll 获取全部未过期的会话
+func (c *RedisSessionCache) GetAll(ctx context.Context) ([]domainSystem.Session, error) {
+	indexKey := c.indexKey()
+
+	// 清理已过期的索引
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	if err := c.cmd.ZRemRangeByScore(ctx, indexKey, "-inf", now).Err(); err != nil {
+		return nil, err
+	}
+
+	jtis, err := c.cmd.ZRange(ctx, indexKey, 0, -1).Result()
+	if err != nil || len(jtis) == 0 {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(jtis))
+	for _, jti := range jtis {
+		keys = append(keys, c.key(jti))
+	}
+	values, err := c.cmd.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]domainSystem.Session, 0, len(values))
+	for _, value := range values {
+		data, ok := value.(string)
+		if !ok {
+			continue
+		}
+		var doMain domainSystem.Session
+		if err := json.Unmarshal([]byte(data), &doMain); err != nil {
+			continue
+		}
+		list = append(list, doMain)
+	}
+	return list, nil
+}
+
+func (c *RedisSessionCache) Del(ctx context.Context, userId, jti string) error {
+	pipe := c.cmd.TxPipeline()
+	pipe.Del(ctx, c.key(jti))
+	pipe.SRem(ctx, c.userKey(userId), jti)
+	pipe.ZRem(ctx, c.indexKey(), jti)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (c *RedisSessionCache) key(jti string) string {
+	return fmt.Sprintf("careful:system:session:jti:%s", jti)
+}
+
+func (c *RedisSessionCache) userKey(userId string) string {
+	return fmt.Sprintf("careful:system:session:user:%s", userId)
+}
+
+func (c *RedisSessionCache) indexKey() string {
+	return "careful:system:session:index"
+}
@@ -0,0 +1,63 @@
+/**
+ * Description：
+ * FileName：sso_state.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 06:53:40
+ * Remark：
+ */
+
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	domainSystem "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/system"
+	"github.com/redis/go-redis/v9"
+	"time"
+)
+
+var ErrSSOStateNotExist = redis.Nil
+
+type SSOStateCache interface {
+	Set(ctx context.Context, state string, domain domainSystem.SSOState, expiration time.Duration) error
+	// Take 获取并删除，保证 state 只能使用一次
+	Take(ctx context.Context, state string) (domainSystem.SSOState, error)
+}
+
+type RedisSSOStateCache struct {
+	cmd redis.Cmdable
+}
+
+func NewRedisSSOStateCache(cmd redis.Cmdable) SSOStateCache {
+	return &RedisSSOStateCache{
+		cmd: cmd,
+	}
+}
+
+func (c *RedisSSOStateCache) Set(ctx context.Context, state string, domain domainSystem.SSOState, expiration time.Duration) error {
+	data, err := json.Marshal(domain)
+	if err != nil {
+		return err
+	}
+	return c.cmd.Set(ctx, c.key(state), data, expiration).Err()
+}
+
+func (c *RedisSSOStateCache) Take(ctx context.Context, state string) (domainSystem.SSOState, error) {
+	data, err := c.cmd.GetDel(ctx, c.key(state)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return domainSystem.SSOState{}, ErrSSOStateNotExist
+		}
+		return domainSystem.SSOState{}, err
+	}
+
+	var domain domainSystem.SSOState
+	err = json.Unmarshal(data, &domain)
+	return domain, err
+}
+
+func (c *RedisSSOStateCache) key(state string) string {
+	return fmt.Sprintf("careful:system:sso:state:%s", state)
+}
@@ -0,0 +1,81 @@
+/**
+ * Description：
+ * FileName：two_factor_challenge.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 22:09:44
+ * Remark：
+ */
+
+package system
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"time"
+)
+
+var ErrTwoFactorChallengeNotExist = redis.Nil
+
+// incrAttemptsScript 仅对未过期的挑战增加尝试次数，避免生成无过期时间的键
+var incrAttemptsScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+    return -1
+end
+return redis.call('HINCRBY', KEYS[1], 'attempts', 1)
+`)
+
+type TwoFactorChallengeCache interface {
+	Set(ctx context.Context, token, userId string, expiration time.Duration) error
+	Get(ctx context.Context, token string) (string, error)
+	IncrAttempts(ctx context.Context, token string) (int64, error)
+	Del(ctx context.Context, token string) error
+}
+
+type RedisTwoFactorChallengeCache struct {
+	cmd redis.Cmdable
+}
+
+func NewRedisTwoFactorChallengeCache(cmd redis.Cmdable) TwoFactorChallengeCache {
+	return &RedisTwoFactorChallengeCache{
+		cmd: cmd,
+	}
+}
+
+func (c *RedisTwoFactorChallengeCache) Set(ctx context.Context, token, userId string, expiration time.Duration) error {
+	key := c.key(token)
+	pipe := c.cmd.TxPipeline()
+	pipe.HSet(ctx, key, "userId", userId, "attempts", 0)
+	pipe.Expire(ctx, key, expiration)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (c *RedisTwoFactorChallengeCache) Get(ctx context.Context, token string) (string, error) {
+	userId, err := c.cmd.HGet(ctx, c.key(token), "userId").Result()
+	if errors.Is(err, redis.Nil) {
+		return "", ErrTwoFactorChallengeNotExist
+	}
+	return userId, err
+}
+
+// IncrAttempts 增加尝试次数，返回增加后的次数
+func (c *RedisTwoFactorChallengeCache) IncrAttempts(ctx context.Context, token string) (int64, error) {
+	attempts, err := incrAttemptsScript.Run(ctx, c.cmd, []string{c.key(token)}).Int64()
+	if err != nil {
+		return 0, err
+	}
+	if attempts < 0 {
+		return 0, ErrTwoFactorChallengeNotExist
+	}
+	return attempts, nil
+}
+
+func (c *RedisTwoFactorChallengeCache) Del(ctx context.Context, token string) error {
+	return c.cmd.Del(ctx, c.key(token)).Err()
+}
+
+func (c *RedisTwoFactorChallengeCache) key(token string) string {
+	return fmt.Sprintf("careful:system:two_factor:challenge:%s", token)
+}
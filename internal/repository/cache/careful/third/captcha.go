@@ -14,6 +14,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/redis/go-redis/v9"
+	"time"
 )
 
 var (
@@ -31,6 +32,8 @@ var (
 
 type CaptchaCache interface {
 	Set(ctx context.Context, id, code string, bizType string) error
+	// SetWithExpire 按指定有效期及重新发送间隔保存验证码
+	SetWithExpire(ctx context.Context, id, code string, bizType string, expire, interval time.Duration) error
 	Verify(ctx context.Context, id string, biz string, code string) (bool, error)
 }
 
@@ -45,7 +48,12 @@ func NewCaptchaCache(cmd redis.Cmdable) CaptchaCache {
 }
 
 func (c *captchaCache) Set(ctx context.Context, id, code string, bizType string) error {
-	res, err := c.cmd.Eval(ctx, luaSetCode, []string{c.key(id, bizType)}, code).Int()
+	return c.SetWithExpire(ctx, id, code, bizType, 60*time.Second, 10*time.Second)
+}
+
+func (c *captchaCache) SetWithExpire(ctx context.Context, id, code string, bizType string, expire, interval time.Duration) error {
+	res, err := c.cmd.Eval(ctx, luaSetCode, []string{c.key(id, bizType)}, code,
+		int(expire.Seconds()), int(interval.Seconds())).Int()
 	if err != nil {
 		// 调用 redis 出了问题
 		return err
@@ -2,16 +2,17 @@ local key = KEYS[1]
 local cntKey = key .. ":cnt"
 -- 你准备的存储的验证码
 local val = ARGV[1]
-local expireTime = 60
+-- 有效期及重新发送间隔(秒)，未传时沿用图形验证码的 60 秒有效期、10 秒间隔
+local expireTime = tonumber(ARGV[2]) or 60
+local interval = tonumber(ARGV[3]) or 10
 
 local ttl = tonumber(redis.call("ttl", key))
 if ttl == -1 then
     -- key 存在，但是没有过期时间
     return -2
-elseif ttl == -2 or ttl < 50 then
+elseif ttl == -2 or ttl < expireTime - interval then
     -- 可以发验证码
     redis.call("set", key, val)
-    -- 60 秒
     redis.call("expire", key, expireTime)
     redis.call("set", cntKey, 3)
     redis.call("expire", cntKey, expireTime)
@@ -17,7 +17,9 @@ import (
 	modelLogger "github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/logger"
 	cacheTools "github.com/carefuly/carefuly-admin-go-gin/internal/repository/cache/careful/tools"
 	cacheRecord "github.com/carefuly/carefuly-admin-go-gin/internal/repository/cache/decorator/record"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/constants/careful/logger/cache"
 	"github.com/carefuly/carefuly-admin-go-gin/pkg/models"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/requestUtils"
 	"net/http"
 	"time"
 )
@@ -44,8 +46,8 @@ func (d *BucketCacheLoggingDecorator) Get(ctx context.Context, id string) (*doma
 	// 创建日志条目
 	entry := &modelLogger.CacheLogger{
 		CoreModels: models.CoreModels{
-			Creator:    ctx.Value("userId").(string),
-			Modifier:   ctx.Value("userId").(string),
+			Creator:    requestUtils.GetActorId(ctx),
+			Modifier:   requestUtils.GetActorId(ctx),
 			BelongDept: ctx.Value("deptId").(string),
 		},
 		CacheHost:     request.Host,
@@ -54,26 +56,34 @@ func (d *BucketCacheLoggingDecorator) Get(ctx context.Context, id string) (*doma
 		CacheMethod:   request.Method,
 		CachePath:     request.URL.Path,
 		CacheKey:      d.key(id),
+
+		CacheOperation: cache.OperationConstGet,
 	}
 
 	if err != nil {
 		if errors.Is(err, cacheTools.ErrBucketNotExist) {
 			entry.CacheValue = "not_found"
+			entry.CacheResult = cache.ResultConstMiss
 		} else {
 			entry.CacheError = err.Error()
+			entry.CacheResult = cache.ResultConstError
 		}
 	} else if result != nil {
+		entry.CacheResult = cache.ResultConstHit
 		// 记录值摘要
 		if data, err := json.Marshal(result); err == nil {
 			entry.CacheValue = string(data)
 		}
+	} else {
+		// 命中防穿透标记
+		entry.CacheResult = cache.ResultConstNotFound
 	}
 
 	// 记录执行时间
 	entry.CacheTime = time.Since(start).String()
 
-	// 异步记录日志
-	go d.logger.Log(ctx, entry)
+	// 加入日志写入队列
+	d.logger.Log(ctx, entry)
 
 	return result, err
 }
@@ -94,8 +104,8 @@ func (d *BucketCacheLoggingDecorator) Set(ctx context.Context, domain domainTool
 	// 创建日志条目
 	entry := &modelLogger.CacheLogger{
 		CoreModels: models.CoreModels{
-			Creator:    ctx.Value("userId").(string),
-			Modifier:   ctx.Value("userId").(string),
+			Creator:    requestUtils.GetActorId(ctx),
+			Modifier:   requestUtils.GetActorId(ctx),
 			BelongDept: ctx.Value("deptId").(string),
 		},
 		CacheHost:     request.Host,
@@ -105,17 +115,21 @@ func (d *BucketCacheLoggingDecorator) Set(ctx context.Context, domain domainTool
 		CachePath:     request.URL.Path,
 		CacheKey:      d.key(domain.Id),
 		CacheValue:    valueStr,
+
+		CacheOperation: cache.OperationConstSet,
+		CacheResult:    cache.ResultConstOk,
 	}
 
 	if err != nil {
 		entry.CacheError = err.Error()
+		entry.CacheResult = cache.ResultConstError
 	}
 
 	// 记录执行时间
-	entry.CacheTime += time.Since(start).String()
+	entry.CacheTime = time.Since(start).String()
 
-	// 异步记录日志
-	go d.logger.Log(ctx, entry)
+	// 加入日志写入队列
+	d.logger.Log(ctx, entry)
 
 	return err
 }
@@ -130,8 +144,8 @@ func (d *BucketCacheLoggingDecorator) Del(ctx context.Context, id string) error
 	// 创建日志条目
 	entry := &modelLogger.CacheLogger{
 		CoreModels: models.CoreModels{
-			Creator:    ctx.Value("userId").(string),
-			Modifier:   ctx.Value("userId").(string),
+			Creator:    requestUtils.GetActorId(ctx),
+			Modifier:   requestUtils.GetActorId(ctx),
 			BelongDept: ctx.Value("deptId").(string),
 		},
 		CacheHost:     request.Host,
@@ -140,17 +154,21 @@ func (d *BucketCacheLoggingDecorator) Del(ctx context.Context, id string) error
 		CacheMethod:   request.Method,
 		CachePath:     request.URL.Path,
 		CacheKey:      d.key(id),
+
+		CacheOperation: cache.OperationConstDel,
+		CacheResult:    cache.ResultConstOk,
 	}
 
 	if err != nil {
 		entry.CacheError = err.Error()
+		entry.CacheResult = cache.ResultConstError
 	}
 
 	// 记录执行时间
 	entry.CacheTime = time.Since(start).String()
 
-	// 异步记录日志
-	go d.logger.Log(ctx, entry)
+	// 加入日志写入队列
+	d.logger.Log(ctx, entry)
 
 	return err
 }
@@ -165,8 +183,8 @@ func (d *BucketCacheLoggingDecorator) SetNotFound(ctx context.Context, id string
 	// 创建日志条目
 	entry := &modelLogger.CacheLogger{
 		CoreModels: models.CoreModels{
-			Creator:    ctx.Value("userId").(string),
-			Modifier:   ctx.Value("userId").(string),
+			Creator:    requestUtils.GetActorId(ctx),
+			Modifier:   requestUtils.GetActorId(ctx),
 			BelongDept: ctx.Value("deptId").(string),
 		},
 		CacheHost:     request.Host,
@@ -176,17 +194,21 @@ func (d *BucketCacheLoggingDecorator) SetNotFound(ctx context.Context, id string
 		CachePath:     request.URL.Path,
 		CacheKey:      d.key(id),
 		CacheValue:    "not_found",
+
+		CacheOperation: cache.OperationConstSetNotFound,
+		CacheResult:    cache.ResultConstOk,
 	}
 
 	if err != nil {
 		entry.CacheError = err.Error()
+		entry.CacheResult = cache.ResultConstError
 	}
 
 	// 记录执行时间
-	entry.CacheTime += " | " + time.Since(start).String()
+	entry.CacheTime = time.Since(start).String()
 
-	// 异步记录日志
-	go d.logger.Log(ctx, entry)
+	// 加入日志写入队列
+	d.logger.Log(ctx, entry)
 
 	return err
 }
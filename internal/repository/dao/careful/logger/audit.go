@@ -0,0 +1,34 @@
+/**
+ * Description：
+ * FileName：audit.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 17:03:12
+ * Remark：
+ */
+
+package logger
+
+import (
+	"context"
+	"github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/logger"
+	"gorm.io/gorm"
+)
+
+type AuditLogDAO interface {
+	Insert(ctx context.Context, model logger.AuditLogger) error
+}
+
+type GORMAuditLogDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMAuditLogDAO(db *gorm.DB) AuditLogDAO {
+	return &GORMAuditLogDAO{
+		db: db,
+	}
+}
+
+// Insert 新增
+func (dao *GORMAuditLogDAO) Insert(ctx context.Context, model logger.AuditLogger) error {
+	return dao.db.WithContext(ctx).Create(&model).Error
+}
@@ -0,0 +1,101 @@
+/**
+ * Description：
+ * FileName：cache.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 09:20:03
+ * Remark：
+ */
+
+package logger
+
+import (
+	"context"
+	domainLogger "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/logger"
+	"github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/logger"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/constants/careful/logger/cache"
+	"gorm.io/gorm"
+	gormLogger "gorm.io/gorm/logger"
+)
+
+type CacheLogDAO interface {
+	InsertBatch(ctx context.Context, models []logger.CacheLogger) error
+	FindListPage(ctx context.Context, filter domainLogger.CacheLogFilter) ([]*logger.CacheLogger, int64, error)
+	// FindStatGroups 按缓存key键前缀(去掉最后一段)、操作及结果分组计数
+	FindStatGroups(ctx context.Context, filter domainLogger.CacheLogFilter) ([]*logger.CacheStatGroup, error)
+	// FindTimeSample 查询最近 limit 条记录的缓存key键及耗时
+	FindTimeSample(ctx context.Context, filter domainLogger.CacheLogFilter, limit int) ([]*logger.CacheLogger, error)
+}
+
+// cacheKeyPrefixExpr 缓存key键前缀，如 careful:tools:bucket:info:1 的前缀为 careful:tools:bucket:info，不含 : 时为缓存key键本身
+const cacheKeyPrefixExpr = "CASE WHEN cacheKey LIKE '_%:%' " +
+	"THEN SUBSTRING(cacheKey, 1, CHAR_LENGTH(cacheKey) - CHAR_LENGTH(SUBSTRING_INDEX(cacheKey, ':', -1)) - 1) " +
+	"ELSE cacheKey END"
+
+type GORMCacheLogDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMCacheLogDAO(db *gorm.DB) CacheLogDAO {
+	return &GORMCacheLogDAO{
+		db: db,
+	}
+}
+
+// InsertBatch 批量新增，不输出 SQL 日志
+func (dao *GORMCacheLogDAO) InsertBatch(ctx context.Context, models []logger.CacheLogger) error {
+	return dao.db.Session(&gorm.Session{Logger: gormLogger.Default.LogMode(gormLogger.Silent)}).
+		WithContext(ctx).CreateInBatches(models, len(models)).Error
+}
+
+// FindListPage 分页查询
+func (dao *GORMCacheLogDAO) FindListPage(ctx context.Context, filter domainLogger.CacheLogFilter) ([]*logger.CacheLogger, int64, error) {
+	var total int64
+	var models []*logger.CacheLogger
+
+	query := dao.buildQuery(ctx, filter)
+	err := query.Count(&total).
+		Offset((filter.Page - 1) * filter.PageSize).
+		Limit(filter.PageSize).
+		Find(&models).Error
+
+	return models, total, err
+}
+
+// FindStatGroups 分组统计
+func (dao *GORMCacheLogDAO) FindStatGroups(ctx context.Context, filter domainLogger.CacheLogFilter) ([]*logger.CacheStatGroup, error) {
+	var groups []*logger.CacheStatGroup
+	builder := dao.filter(filter)
+	err := builder.Where(dao.db.WithContext(ctx).Model(&logger.CacheLogger{})).
+		Select(cacheKeyPrefixExpr+" AS prefix, cacheOperation, cacheResult, COUNT(*) AS total, "+
+			"SUM(CASE WHEN cacheError <> '' OR cacheResult = ? THEN 1 ELSE 0 END) AS errors", cache.ResultConstError).
+		Group("prefix").Group("cacheOperation").Group("cacheResult").
+		Scan(&groups).Error
+	return groups, err
+}
+
+// FindTimeSample 查询耗时样本
+func (dao *GORMCacheLogDAO) FindTimeSample(ctx context.Context, filter domainLogger.CacheLogFilter, limit int) ([]*logger.CacheLogger, error) {
+	var models []*logger.CacheLogger
+	query := dao.buildQuery(ctx, filter)
+	err := query.Select("cacheKey", "cacheTime").Limit(limit).Find(&models).Error
+	return models, err
+}
+
+// buildQuery 构建查询条件
+func (dao *GORMCacheLogDAO) buildQuery(ctx context.Context, filter domainLogger.CacheLogFilter) *gorm.DB {
+	return dao.filter(filter).Apply(dao.db.WithContext(ctx).Model(&logger.CacheLogger{}))
+}
+
+// filter 查询条件
+func (dao *GORMCacheLogDAO) filter(filter domainLogger.CacheLogFilter) *domainLogger.CacheLogFilter {
+	return &domainLogger.CacheLogFilter{
+		Key:       filter.Key,
+		Operation: filter.Operation,
+		Result:    filter.Result,
+		Username:  filter.Username,
+		Path:      filter.Path,
+		HasError:  filter.HasError,
+		StartTime: filter.StartTime,
+		EndTime:   filter.EndTime,
+	}
+}
@@ -0,0 +1,183 @@
+/**
+ * Description：
+ * FileName：change.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 16:04:51
+ * Remark：
+ */
+
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	domainLogger "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/logger"
+	"github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/logger"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/changelog"
+	"gorm.io/gorm"
+	"time"
+)
+
+var (
+	ErrChangeLogNotFound = gorm.ErrRecordNotFound
+)
+
+type ChangeLogDAO interface {
+	// Write 写入变更记录，作为 changelog.WriteFunc 在数据变更的事务中调用
+	Write(tx *gorm.DB, entries []changelog.Entry) error
+	FindById(ctx context.Context, id string) (*logger.ChangeLogger, error)
+	FindLatest(ctx context.Context, table, entityId string) (*logger.ChangeLogger, error)
+	FindHistory(ctx context.Context, table, entityId string) ([]*logger.ChangeLogger, error)
+	FindListPage(ctx context.Context, filter domainLogger.ChangeLogFilter) ([]*logger.ChangeLogger, int64, error)
+	// Restore 将数据恢复为 values，记录已删除时以 version 重新创建
+	Restore(ctx context.Context, table, entityId string, values map[string]any, version int) error
+}
+
+type GORMChangeLogDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMChangeLogDAO(db *gorm.DB) ChangeLogDAO {
+	return &GORMChangeLogDAO{
+		db: db,
+	}
+}
+
+// Write 写入变更记录
+func (dao *GORMChangeLogDAO) Write(tx *gorm.DB, entries []changelog.Entry) error {
+	models := make([]logger.ChangeLogger, 0, len(entries))
+	for _, entry := range entries {
+		changes, err := json.Marshal(entry.Changes)
+		if err != nil {
+			return err
+		}
+		oldValues, err := marshalValues(entry.OldValues)
+		if err != nil {
+			return err
+		}
+		newValues, err := marshalValues(entry.NewValues)
+		if err != nil {
+			return err
+		}
+
+		model := logger.ChangeLogger{
+			EntityTable:   entry.Table,
+			EntityId:      entry.RecordId,
+			EntityVersion: entry.Version,
+			Action:        entry.Action,
+			OperatorId:    entry.OperatorId,
+			Operator:      entry.Operator,
+			Changes:       string(changes),
+			OldValues:     oldValues,
+			NewValues:     newValues,
+		}
+		model.Creator = entry.OperatorId
+		model.Modifier = entry.OperatorId
+		models = append(models, model)
+	}
+	return tx.Create(&models).Error
+}
+
+// FindById 根据ID获取详情
+func (dao *GORMChangeLogDAO) FindById(ctx context.Context, id string) (*logger.ChangeLogger, error) {
+	var model logger.ChangeLogger
+	err := dao.db.WithContext(ctx).Where("id = ?", id).First(&model).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &model, ErrChangeLogNotFound
+		}
+		return &model, err
+	}
+	return &model, nil
+}
+
+// FindLatest 获取数据最近一次的变更记录
+func (dao *GORMChangeLogDAO) FindLatest(ctx context.Context, table, entityId string) (*logger.ChangeLogger, error) {
+	var model logger.ChangeLogger
+	err := dao.db.WithContext(ctx).
+		Where("entityTable = ? AND entityId = ?", table, entityId).
+		Order("create_time DESC").
+		Order("entityVersion DESC").
+		First(&model).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &model, ErrChangeLogNotFound
+		}
+		return &model, err
+	}
+	return &model, nil
+}
+
+// FindHistory 获取数据的全部变更记录，最近的在前
+func (dao *GORMChangeLogDAO) FindHistory(ctx context.Context, table, entityId string) ([]*logger.ChangeLogger, error) {
+	var models []*logger.ChangeLogger
+	err := dao.db.WithContext(ctx).
+		Where("entityTable = ? AND entityId = ?", table, entityId).
+		Order("create_time DESC").
+		Order("entityVersion DESC").
+		Find(&models).Error
+	return models, err
+}
+
+// FindListPage 分页查询，列表不返回变更前后的数据，通过详情查看
+func (dao *GORMChangeLogDAO) FindListPage(ctx context.Context, filter domainLogger.ChangeLogFilter) ([]*logger.ChangeLogger, int64, error) {
+	var total int64
+	var models []*logger.ChangeLogger
+
+	query := dao.buildQuery(ctx, filter)
+	err := query.Count(&total).
+		Omit("oldValues", "newValues").
+		Offset((filter.Page - 1) * filter.PageSize).
+		Limit(filter.PageSize).
+		Find(&models).Error
+
+	return models, total, err
+}
+
+// Restore 恢复数据
+// 通过 changelog.WithAction 标记，恢复操作本身记录为 restore 类型的变更
+func (dao *GORMChangeLogDAO) Restore(ctx context.Context, table, entityId string, values map[string]any, version int) error {
+	return dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Table(table).Where("id = ?", entityId).Count(&count).Error; err != nil {
+			return err
+		}
+
+		restore := changelog.WithAction(tx, changelog.ActionRestore).Table(table)
+		if count > 0 {
+			values["version"] = gorm.Expr("version + 1")
+			return restore.Where("id = ?", entityId).Updates(values).Error
+		}
+
+		values["id"] = entityId
+		values["version"] = version
+		values["create_time"] = time.Now()
+		return restore.Create(values).Error
+	})
+}
+
+// buildQuery 构建查询条件
+func (dao *GORMChangeLogDAO) buildQuery(ctx context.Context, filter domainLogger.ChangeLogFilter) *gorm.DB {
+	builder := &domainLogger.ChangeLogFilter{
+		EntityTable: filter.EntityTable,
+		EntityId:    filter.EntityId,
+		Action:      filter.Action,
+		Operator:    filter.Operator,
+		StartTime:   filter.StartTime,
+		EndTime:     filter.EndTime,
+	}
+	return builder.Apply(dao.db.WithContext(ctx).Model(&logger.ChangeLogger{}))
+}
+
+// marshalValues 数据快照序列化，为空时存储空字符串
+func marshalValues(values map[string]any) (string, error) {
+	if values == nil {
+		return "", nil
+	}
+	data, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,90 @@
+/**
+ * Description：
+ * FileName：login.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 17:48:51
+ * Remark：
+ */
+
+package logger
+
+import (
+	"context"
+	domainLogger "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/logger"
+	"github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/logger"
+	"gorm.io/gorm"
+	"time"
+)
+
+type LoginLogDAO interface {
+	Insert(ctx context.Context, model logger.LoginLogger) error
+	BatchDelete(ctx context.Context, ids []string) error
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+
+	FindListPage(ctx context.Context, filter domainLogger.LoginLogFilter) ([]*logger.LoginLogger, int64, error)
+	FindListAll(ctx context.Context, filter domainLogger.LoginLogFilter) ([]*logger.LoginLogger, error)
+}
+
+type GORMLoginLogDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMLoginLogDAO(db *gorm.DB) LoginLogDAO {
+	return &GORMLoginLogDAO{
+		db: db,
+	}
+}
+
+// Insert 新增
+func (dao *GORMLoginLogDAO) Insert(ctx context.Context, model logger.LoginLogger) error {
+	return dao.db.WithContext(ctx).Create(&model).Error
+}
+
+// BatchDelete 批量删除
+func (dao *GORMLoginLogDAO) BatchDelete(ctx context.Context, ids []string) error {
+	return dao.db.WithContext(ctx).Where("id IN ?", ids).Delete(&logger.LoginLogger{}).Error
+}
+
+// DeleteBefore 清理指定时间之前的日志
+func (dao *GORMLoginLogDAO) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := dao.db.WithContext(ctx).Where("create_time < ?", before).Delete(&logger.LoginLogger{})
+	return result.RowsAffected, result.Error
+}
+
+// FindListPage 分页查询
+func (dao *GORMLoginLogDAO) FindListPage(ctx context.Context, filter domainLogger.LoginLogFilter) ([]*logger.LoginLogger, int64, error) {
+	var total int64
+	var models []*logger.LoginLogger
+
+	query := dao.buildQuery(ctx, filter)
+
+	err := query.Count(&total).
+		Offset((filter.Page - 1) * filter.PageSize).
+		Limit(filter.PageSize).
+		Find(&models).Error
+
+	return models, total, err
+}
+
+// FindListAll 查询所有列表
+func (dao *GORMLoginLogDAO) FindListAll(ctx context.Context, filter domainLogger.LoginLogFilter) ([]*logger.LoginLogger, error) {
+	var models []*logger.LoginLogger
+
+	query := dao.buildQuery(ctx, filter)
+
+	err := query.Find(&models).Error
+	return models, err
+}
+
+// buildQuery 构建查询条件
+func (dao *GORMLoginLogDAO) buildQuery(ctx context.Context, filter domainLogger.LoginLogFilter) *gorm.DB {
+	builder := &domainLogger.LoginLogFilter{
+		Username:  filter.Username,
+		RequestIp: filter.RequestIp,
+		Action:    filter.Action,
+		Status:    filter.Status,
+		StartTime: filter.StartTime,
+		EndTime:   filter.EndTime,
+	}
+	return builder.Apply(dao.db.WithContext(ctx).Model(&logger.LoginLogger{}))
+}
@@ -0,0 +1,111 @@
+/**
+ * Description：
+ * FileName：operate.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 08:24:37
+ * Remark：
+ */
+
+package logger
+
+import (
+	"context"
+	"errors"
+	domainLogger "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/logger"
+	"github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/logger"
+	"gorm.io/gorm"
+	gormLogger "gorm.io/gorm/logger"
+	"time"
+)
+
+var (
+	ErrOperateLogNotFound = gorm.ErrRecordNotFound
+)
+
+type OperateLogDAO interface {
+	InsertBatch(ctx context.Context, models []logger.OperateLogger) error
+	BatchDelete(ctx context.Context, ids []string) error
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+	FindById(ctx context.Context, id string) (*logger.OperateLogger, error)
+	FindListPage(ctx context.Context, filter domainLogger.OperateLogFilter) ([]*logger.OperateLogger, int64, error)
+	FindListAll(ctx context.Context, filter domainLogger.OperateLogFilter) ([]*logger.OperateLogger, error)
+}
+
+type GORMOperateLogDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMOperateLogDAO(db *gorm.DB) OperateLogDAO {
+	return &GORMOperateLogDAO{
+		db: db,
+	}
+}
+
+// InsertBatch 批量新增，不输出 SQL 日志
+// 通过 Session 使用独立的日志配置，不修改共享 db 的 Logger
+func (dao *GORMOperateLogDAO) InsertBatch(ctx context.Context, models []logger.OperateLogger) error {
+	return dao.db.Session(&gorm.Session{Logger: gormLogger.Default.LogMode(gormLogger.Silent)}).
+		WithContext(ctx).CreateInBatches(models, len(models)).Error
+}
+
+// BatchDelete 批量删除
+func (dao *GORMOperateLogDAO) BatchDelete(ctx context.Context, ids []string) error {
+	return dao.db.WithContext(ctx).Where("id IN ?", ids).Delete(&logger.OperateLogger{}).Error
+}
+
+// DeleteBefore 清理指定时间之前的日志
+func (dao *GORMOperateLogDAO) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := dao.db.WithContext(ctx).Where("create_time < ?", before).Delete(&logger.OperateLogger{})
+	return result.RowsAffected, result.Error
+}
+
+// FindById 根据ID获取详情
+func (dao *GORMOperateLogDAO) FindById(ctx context.Context, id string) (*logger.OperateLogger, error) {
+	var model logger.OperateLogger
+	err := dao.db.WithContext(ctx).Where("id = ?", id).First(&model).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &model, ErrOperateLogNotFound
+		}
+		return &model, err
+	}
+	return &model, nil
+}
+
+// FindListPage 分页查询，列表不返回请求参数及响应信息，通过详情查看
+func (dao *GORMOperateLogDAO) FindListPage(ctx context.Context, filter domainLogger.OperateLogFilter) ([]*logger.OperateLogger, int64, error) {
+	var total int64
+	var models []*logger.OperateLogger
+
+	query := dao.buildQuery(ctx, filter)
+	err := query.Count(&total).
+		Omit("requestBody", "requestResult").
+		Offset((filter.Page - 1) * filter.PageSize).
+		Limit(filter.PageSize).
+		Find(&models).Error
+
+	return models, total, err
+}
+
+// FindListAll 查询所有列表
+func (dao *GORMOperateLogDAO) FindListAll(ctx context.Context, filter domainLogger.OperateLogFilter) ([]*logger.OperateLogger, error) {
+	var models []*logger.OperateLogger
+	query := dao.buildQuery(ctx, filter)
+	err := query.Find(&models).Error
+	return models, err
+}
+
+// buildQuery 构建查询条件
+func (dao *GORMOperateLogDAO) buildQuery(ctx context.Context, filter domainLogger.OperateLogFilter) *gorm.DB {
+	builder := &domainLogger.OperateLogFilter{
+		Username:    filter.Username,
+		Method:      filter.Method,
+		Path:        filter.Path,
+		Status:      filter.Status,
+		RequestCode: filter.RequestCode,
+		RequestIp:   filter.RequestIp,
+		StartTime:   filter.StartTime,
+		EndTime:     filter.EndTime,
+	}
+	return builder.Apply(dao.db.WithContext(ctx).Model(&logger.OperateLogger{}))
+}
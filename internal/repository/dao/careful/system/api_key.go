@@ -0,0 +1,99 @@
+/**
+ * Description：
+ * FileName：api_key.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 23:19:31
+ * Remark：
+ */
+
+package system
+
+import (
+	"context"
+	"github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/system"
+	"gorm.io/gorm"
+	"time"
+)
+
+var ErrApiKeyNotFound = gorm.ErrRecordNotFound
+
+type ApiKeyDAO interface {
+	Insert(ctx context.Context, model system.ApiKey) error
+	Revoke(ctx context.Context, id, userId, modifier string) (int64, error)
+	UpdateLastUsed(ctx context.Context, id, ip string, usedTime time.Time) error
+
+	FindById(ctx context.Context, id string) (*system.ApiKey, error)
+	FindByHash(ctx context.Context, hash string) (*system.ApiKey, error)
+	FindListByUserId(ctx context.Context, userId string) ([]*system.ApiKey, error)
+	CountActiveByUserId(ctx context.Context, userId string) (int64, error)
+}
+
+type GORMApiKeyDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMApiKeyDAO(db *gorm.DB) ApiKeyDAO {
+	return &GORMApiKeyDAO{
+		db: db,
+	}
+}
+
+// Insert 新增
+func (dao *GORMApiKeyDAO) Insert(ctx context.Context, model system.ApiKey) error {
+	return dao.db.WithContext(ctx).Create(&model).Error
+}
+
+// Revoke 吊销，userId 为空时不限制所属用户
+func (dao *GORMApiKeyDAO) Revoke(ctx context.Context, id, userId, modifier string) (int64, error) {
+	query := dao.db.WithContext(ctx).Model(&system.ApiKey{}).Where("id = ? AND revoked = ?", id, false)
+	if userId != "" {
+		query = query.Where("user_id = ?", userId)
+	}
+	result := query.Updates(map[string]any{
+		"revoked":  true,
+		"modifier": modifier,
+		"version":  gorm.Expr("version + 1"),
+	})
+	return result.RowsAffected, result.Error
+}
+
+// UpdateLastUsed 记录最近使用信息，不更新修改时间
+func (dao *GORMApiKeyDAO) UpdateLastUsed(ctx context.Context, id, ip string, usedTime time.Time) error {
+	return dao.db.WithContext(ctx).Model(&system.ApiKey{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]any{
+			"last_used_time": usedTime,
+			"last_used_ip":   ip,
+		}).Error
+}
+
+// FindById 根据ID查询
+func (dao *GORMApiKeyDAO) FindById(ctx context.Context, id string) (*system.ApiKey, error) {
+	var model system.ApiKey
+	err := dao.db.WithContext(ctx).Where("id = ?", id).First(&model).Error
+	return &model, err
+}
+
+// FindByHash 根据令牌摘要查询
+func (dao *GORMApiKeyDAO) FindByHash(ctx context.Context, hash string) (*system.ApiKey, error) {
+	var model system.ApiKey
+	err := dao.db.WithContext(ctx).Where("key_hash = ?", hash).First(&model).Error
+	return &model, err
+}
+
+// FindListByUserId 查询用户的全部令牌
+func (dao *GORMApiKeyDAO) FindListByUserId(ctx context.Context, userId string) ([]*system.ApiKey, error) {
+	var list []*system.ApiKey
+	err := dao.db.WithContext(ctx).Where("user_id = ?", userId).Order("create_time DESC").Find(&list).Error
+	return list, err
+}
+
+// CountActiveByUserId 统计用户未吊销且未过期的令牌数量
+func (dao *GORMApiKeyDAO) CountActiveByUserId(ctx context.Context, userId string) (int64, error) {
+	var count int64
+	err := dao.db.WithContext(ctx).Model(&system.ApiKey{}).
+		Where("user_id = ? AND revoked = ?", userId, false).
+		Where("expire_time IS NULL OR expire_time > ?", time.Now()).
+		Count(&count).Error
+	return count, err
+}
@@ -103,7 +103,7 @@ func (dao *GORMDeptDAO) Update(ctx context.Context, model system.Dept) error {
 // FindById 根据id获取详情
 func (dao *GORMDeptDAO) FindById(ctx context.Context, id string) (*system.Dept, error) {
 	var model system.Dept
-	err := dao.db.WithContext(ctx).Where("id = ?", id).First(&model).Error
+	err := dao.db.WithContext(ctx).Scopes(filters.WithDataScope(ctx)).Where("id = ?", id).First(&model).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return &model, ErrDeptNotFound
@@ -139,7 +139,7 @@ func (dao *GORMDeptDAO) buildQuery(ctx context.Context, filter domainSystem.Dept
 		Name:   filter.Name,
 		Code:   filter.Code,
 	}
-	return builder.Apply(dao.db.WithContext(ctx).Model(&system.Dept{}))
+	return builder.Apply(dao.db.WithContext(ctx).Model(&system.Dept{}).Scopes(filters.WithDataScope(ctx)))
 }
 
 // CheckExistByIdAndParentId 检查当前id是否存在子节点
@@ -110,7 +110,7 @@ func (dao *GORMMenuDAO) Update(ctx context.Context, model system.Menu) error {
 // FindById 根据id获取详情
 func (dao *GORMMenuDAO) FindById(ctx context.Context, id string) (*system.Menu, error) {
 	var model system.Menu
-	err := dao.db.WithContext(ctx).Where("id = ?", id).First(&model).Error
+	err := dao.db.WithContext(ctx).Scopes(filters.WithDataScope(ctx)).Where("id = ?", id).First(&model).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return &model, ErrMenuNotFound
@@ -145,7 +145,7 @@ func (dao *GORMMenuDAO) buildQuery(ctx context.Context, filter domainSystem.Menu
 		Status: filter.Status,
 		Title:  filter.Title,
 	}
-	return builder.Apply(dao.db.WithContext(ctx).Model(&system.Menu{}))
+	return builder.Apply(dao.db.WithContext(ctx).Model(&system.Menu{}).Scopes(filters.WithDataScope(ctx)))
 }
 
 // CheckExistByIdAndParentId 检查当前id是否存在子节点
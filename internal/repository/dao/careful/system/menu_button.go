@@ -97,7 +97,7 @@ func (dao *GORMMenuButtonDAO) Update(ctx context.Context, model system.MenuButto
 // FindById 根据id获取详情
 func (dao *GORMMenuButtonDAO) FindById(ctx context.Context, id string) (*system.MenuButton, error) {
 	var model system.MenuButton
-	err := dao.db.WithContext(ctx).Where("id = ?", id).
+	err := dao.db.WithContext(ctx).Scopes(filters.WithDataScope(ctx)).Where("id = ?", id).
 		Preload("Menu").
 		First(&model).Error
 	if err != nil {
@@ -165,5 +165,5 @@ func (dao *GORMMenuButtonDAO) buildQuery(ctx context.Context, filter domainSyste
 		Code:   filter.Code,
 		MenuId: filter.MenuId,
 	}
-	return builder.Apply(dao.db.WithContext(ctx).Model(&system.MenuButton{}))
+	return builder.Apply(dao.db.WithContext(ctx).Model(&system.MenuButton{}).Scopes(filters.WithDataScope(ctx)))
 }
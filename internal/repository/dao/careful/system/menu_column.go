@@ -96,7 +96,7 @@ func (dao *GORMMenuColumnDAO) Update(ctx context.Context, model system.MenuColum
 // FindById 根据id获取详情
 func (dao *GORMMenuColumnDAO) FindById(ctx context.Context, id string) (*system.MenuColumn, error) {
 	var model system.MenuColumn
-	err := dao.db.WithContext(ctx).Where("id = ?", id).
+	err := dao.db.WithContext(ctx).Scopes(filters.WithDataScope(ctx)).Where("id = ?", id).
 		Preload("Menu").
 		First(&model).Error
 	if err != nil {
@@ -164,5 +164,5 @@ func (dao *GORMMenuColumnDAO) buildQuery(ctx context.Context, filter domainSyste
 		Field:  filter.Field,
 		MenuId: filter.MenuId,
 	}
-	return builder.Apply(dao.db.WithContext(ctx).Model(&system.MenuColumn{}))
+	return builder.Apply(dao.db.WithContext(ctx).Model(&system.MenuColumn{}).Scopes(filters.WithDataScope(ctx)))
 }
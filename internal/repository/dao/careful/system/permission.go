@@ -0,0 +1,159 @@
+/**
+ * Description：
+ * FileName：permission.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 09:46:37
+ * Remark：
+ */
+
+package system
+
+import (
+	"context"
+	"github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/system"
+	"gorm.io/gorm"
+)
+
+type PermissionDAO interface {
+	FindRolesByUserId(ctx context.Context, userId string) ([]*system.Role, error)
+	FindMenuButtonsByRoleIds(ctx context.Context, roleIds []string) ([]*system.MenuButton, error)
+	FindAllMenuButtons(ctx context.Context) ([]*system.MenuButton, error)
+	FindMenuIdsByRoleIds(ctx context.Context, roleIds []string) ([]string, error)
+	FindUserIdsByRoleIds(ctx context.Context, roleIds []string) ([]string, error)
+	FindDeptIdByUserId(ctx context.Context, userId string) (string, error)
+	FindDeptIdsByRoleIds(ctx context.Context, roleIds []string) ([]string, error)
+	FindDeptTree(ctx context.Context) ([]*system.Dept, error)
+	FindMenuColumns(ctx context.Context) ([]*system.MenuColumn, error)
+	FindMenuColumnIdsByRoleIds(ctx context.Context, roleIds []string) ([]string, error)
+}
+
+type GORMPermissionDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMPermissionDAO(db *gorm.DB) PermissionDAO {
+	return &GORMPermissionDAO{
+		db: db,
+	}
+}
+
+// FindRolesByUserId 获取用户已启用的角色
+func (dao *GORMPermissionDAO) FindRolesByUserId(ctx context.Context, userId string) ([]*system.Role, error) {
+	var models []*system.Role
+	err := dao.db.WithContext(ctx).Model(&system.Role{}).
+		Joins("JOIN careful_system_users_role ON careful_system_users_role.role_id = careful_system_role.id").
+		Where("careful_system_users_role.user_id = ? AND careful_system_role.status = ?", userId, true).
+		Find(&models).Error
+	return models, err
+}
+
+// FindMenuButtonsByRoleIds 获取角色关联的已启用接口按钮
+func (dao *GORMPermissionDAO) FindMenuButtonsByRoleIds(ctx context.Context, roleIds []string) ([]*system.MenuButton, error) {
+	var models []*system.MenuButton
+	if len(roleIds) == 0 {
+		return models, nil
+	}
+	err := dao.db.WithContext(ctx).Model(&system.MenuButton{}).
+		Distinct("careful_system_menu_button.*").
+		Joins("JOIN careful_system_role_menu_button ON careful_system_role_menu_button.menu_button_id = careful_system_menu_button.id").
+		Where("careful_system_role_menu_button.role_id IN ? AND careful_system_menu_button.status = ?", roleIds, true).
+		Find(&models).Error
+	return models, err
+}
+
+// FindAllMenuButtons 获取全部已启用的接口按钮
+func (dao *GORMPermissionDAO) FindAllMenuButtons(ctx context.Context) ([]*system.MenuButton, error) {
+	var models []*system.MenuButton
+	err := dao.db.WithContext(ctx).Model(&system.MenuButton{}).
+		Where("status = ?", true).
+		Find(&models).Error
+	return models, err
+}
+
+// FindMenuIdsByRoleIds 获取角色关联的菜单ID
+func (dao *GORMPermissionDAO) FindMenuIdsByRoleIds(ctx context.Context, roleIds []string) ([]string, error) {
+	var menuIds []string
+	if len(roleIds) == 0 {
+		return menuIds, nil
+	}
+	err := dao.db.WithContext(ctx).
+		Table("careful_system_role_menu").
+		Distinct("menu_id").
+		Where("role_id IN ?", roleIds).
+		Pluck("menu_id", &menuIds).Error
+	return menuIds, err
+}
+
+// FindUserIdsByRoleIds 获取关联指定角色的用户ID
+func (dao *GORMPermissionDAO) FindUserIdsByRoleIds(ctx context.Context, roleIds []string) ([]string, error) {
+	var userIds []string
+	if len(roleIds) == 0 {
+		return userIds, nil
+	}
+	err := dao.db.WithContext(ctx).
+		Table("careful_system_users_role").
+		Distinct("user_id").
+		Where("role_id IN ?", roleIds).
+		Pluck("user_id", &userIds).Error
+	return userIds, err
+}
+
+// FindDeptIdByUserId 获取用户所属部门ID
+func (dao *GORMPermissionDAO) FindDeptIdByUserId(ctx context.Context, userId string) (string, error) {
+	var deptIds []string
+	err := dao.db.WithContext(ctx).Model(&system.User{}).
+		Where("id = ?", userId).
+		Limit(1).
+		Pluck("dept_id", &deptIds).Error
+	if err != nil || len(deptIds) == 0 {
+		return "", err
+	}
+	return deptIds[0], nil
+}
+
+// FindDeptIdsByRoleIds 获取角色自定义数据权限关联的部门ID
+func (dao *GORMPermissionDAO) FindDeptIdsByRoleIds(ctx context.Context, roleIds []string) ([]string, error) {
+	var deptIds []string
+	if len(roleIds) == 0 {
+		return deptIds, nil
+	}
+	err := dao.db.WithContext(ctx).
+		Table("careful_system_role_dept").
+		Distinct("dept_id").
+		Where("role_id IN ?", roleIds).
+		Pluck("dept_id", &deptIds).Error
+	return deptIds, err
+}
+
+// FindDeptTree 获取全部部门的上下级关系
+func (dao *GORMPermissionDAO) FindDeptTree(ctx context.Context) ([]*system.Dept, error) {
+	var models []*system.Dept
+	err := dao.db.WithContext(ctx).Model(&system.Dept{}).
+		Select("id", "parent_id").
+		Find(&models).Error
+	return models, err
+}
+
+// FindMenuColumns 获取全部已启用的菜单数据列
+func (dao *GORMPermissionDAO) FindMenuColumns(ctx context.Context) ([]*system.MenuColumn, error) {
+	var models []*system.MenuColumn
+	err := dao.db.WithContext(ctx).Model(&system.MenuColumn{}).
+		Select("id", "field", "menu_id").
+		Where("status = ?", true).
+		Find(&models).Error
+	return models, err
+}
+
+// FindMenuColumnIdsByRoleIds 获取角色授权的菜单数据列ID
+func (dao *GORMPermissionDAO) FindMenuColumnIdsByRoleIds(ctx context.Context, roleIds []string) ([]string, error) {
+	var columnIds []string
+	if len(roleIds) == 0 {
+		return columnIds, nil
+	}
+	err := dao.db.WithContext(ctx).
+		Table("careful_system_role_menu_column").
+		Distinct("menu_column_id").
+		Where("role_id IN ?", roleIds).
+		Pluck("menu_column_id", &columnIds).Error
+	return columnIds, err
+}
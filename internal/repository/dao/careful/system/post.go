@@ -98,7 +98,7 @@ func (dao *GORMPostDAO) Update(ctx context.Context, model system.Post) error {
 // FindById 根据id获取详情
 func (dao *GORMPostDAO) FindById(ctx context.Context, id string) (*system.Post, error) {
 	var model system.Post
-	err := dao.db.WithContext(ctx).Where("id = ?", id).First(&model).Error
+	err := dao.db.WithContext(ctx).Scopes(filters.WithDataScope(ctx)).Where("id = ?", id).First(&model).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return &model, ErrPostNotFound
@@ -149,7 +149,7 @@ func (dao *GORMPostDAO) buildQuery(ctx context.Context, filter domainSystem.Post
 		Name:   filter.Name,
 		Code:   filter.Code,
 	}
-	return builder.Apply(dao.db.WithContext(ctx).Model(&system.Post{}))
+	return builder.Apply(dao.db.WithContext(ctx).Model(&system.Post{}).Scopes(filters.WithDataScope(ctx)))
 }
 
 // CheckExistByNameAndCode 检查name、code是否同时存在
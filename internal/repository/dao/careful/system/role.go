@@ -86,14 +86,15 @@ func (dao *GORMRoleDAO) Update(ctx context.Context, model system.Role) error {
 	result := dao.db.WithContext(ctx).Model(&model).
 		Where("id = ? AND version = ?", model.Id, model.Version).
 		Updates(map[string]any{
-			"name":       model.Name,
-			"code":       model.Code,
-			"data_range": model.DataRange,
-			"sort":       model.Sort,
-			"status":     model.Status,
-			"version":    gorm.Expr("version + 1"),
-			"modifier":   model.Modifier,
-			"remark":     model.Remark,
+			"name":           model.Name,
+			"code":           model.Code,
+			"data_range":     model.DataRange,
+			"require_two_fa": model.RequireTwoFA,
+			"sort":           model.Sort,
+			"status":         model.Status,
+			"version":        gorm.Expr("version + 1"),
+			"modifier":       model.Modifier,
+			"remark":         model.Remark,
 		})
 
 	// 处理行影响数为0的情况
@@ -200,7 +201,7 @@ func (dao *GORMRoleDAO) updateAssociations(tx *gorm.DB, ctx context.Context, rol
 // FindById 根据id获取详情
 func (dao *GORMRoleDAO) FindById(ctx context.Context, id string) (*system.Role, error) {
 	var model system.Role
-	err := dao.db.WithContext(ctx).
+	err := dao.db.WithContext(ctx).Scopes(filters.WithDataScope(ctx)).
 		Preload("Dept").
 		Preload("Menu").
 		Preload("MenuButton").
@@ -257,7 +258,7 @@ func (dao *GORMRoleDAO) buildQuery(ctx context.Context, filter domainSystem.Role
 		Name:   filter.Name,
 		Code:   filter.Code,
 	}
-	return builder.Apply(dao.db.WithContext(ctx).Model(&system.Role{}))
+	return builder.Apply(dao.db.WithContext(ctx).Model(&system.Role{}).Scopes(filters.WithDataScope(ctx)))
 }
 
 // CheckExistByCode 检查code是否存在
@@ -16,6 +16,7 @@ import (
 	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/filters"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"time"
 )
 
 var (
@@ -28,12 +29,16 @@ var (
 type UserDAO interface {
 	Insert(ctx context.Context, model system.User) error
 	Delete(ctx context.Context, id string) (int64, error)
-	BatchDelete(ctx context.Context, ids []string) error
+	// BatchDelete 批量删除，返回实际删除的用户ID
+	BatchDelete(ctx context.Context, ids []string) ([]string, error)
 	Update(ctx context.Context, model system.User) error
-	UpdatePassword(ctx context.Context, userId string, newPassword, hashedPassword string) error
+	UpdateStatus(ctx context.Context, model system.User) error
+	UpdatePassword(ctx context.Context, userId string, hashedPassword string) error
+	SetPassword(ctx context.Context, userId string, hashedPassword string, changeRequired bool) error
 
 	FindById(ctx context.Context, id string) (*system.User, error)
 	FindByUsername(ctx context.Context, username string) (*system.User, error)
+	FindListByEmail(ctx context.Context, email string) ([]*system.User, error)
 	FindListPage(ctx context.Context, filter domainSystem.UserFilter) ([]*system.User, int64, error)
 	FindListAll(ctx context.Context, filter domainSystem.UserFilter) ([]*system.User, error)
 
@@ -52,21 +57,62 @@ func NewGORMUserDAO(db *gorm.DB) UserDAO {
 
 // Insert 新增
 func (dao *GORMUserDAO) Insert(ctx context.Context, model system.User) error {
-	return dao.db.WithContext(ctx).Create(&model).Error
+	return dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&model).Error; err != nil {
+			return err
+		}
+		return dao.updateAssociations(tx, model)
+	})
 }
 
-// Delete 删除
+// Delete 删除(受数据权限限制)
 func (dao *GORMUserDAO) Delete(ctx context.Context, id string) (int64, error) {
-	result := dao.db.WithContext(ctx).Where("id = ?", id).Delete(&system.User{})
-	return result.RowsAffected, result.Error
+	var rowsAffected int64
+	err := dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ids, err := dao.scopedIds(ctx, tx, []string{id})
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+		if err := dao.deleteAssociations(tx, ids); err != nil {
+			return err
+		}
+		result := tx.Where("id IN ?", ids).Delete(&system.User{})
+		rowsAffected = result.RowsAffected
+		return result.Error
+	})
+	return rowsAffected, err
 }
 
-// BatchDelete 批量删除
-func (dao *GORMUserDAO) BatchDelete(ctx context.Context, ids []string) error {
-	return dao.db.WithContext(ctx).Where("id IN ?", ids).Delete(&system.User{}).Error
+// BatchDelete 批量删除(受数据权限限制，仅删除有权限的用户)
+func (dao *GORMUserDAO) BatchDelete(ctx context.Context, ids []string) ([]string, error) {
+	var scoped []string
+	err := dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		scoped, err = dao.scopedIds(ctx, tx, ids)
+		if err != nil || len(scoped) == 0 {
+			return err
+		}
+		if err := dao.deleteAssociations(tx, scoped); err != nil {
+			return err
+		}
+		return tx.Where("id IN ?", scoped).Delete(&system.User{}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return scoped, nil
 }
 
-// Update 更新
+// scopedIds 过滤出数据权限范围内的用户ID
+func (dao *GORMUserDAO) scopedIds(ctx context.Context, tx *gorm.DB, ids []string) ([]string, error) {
+	var scoped []string
+	err := tx.Model(&system.User{}).Scopes(filters.WithDataScope(ctx, "id")).
+		Where("id IN ?", ids).
+		Pluck("id", &scoped).Error
+	return scoped, err
+}
+
+// Update 更新(受数据权限限制)
 func (dao *GORMUserDAO) Update(ctx context.Context, model system.User) error {
 	// 开启事务
 	tx := dao.db.WithContext(ctx).Begin()
@@ -76,40 +122,35 @@ func (dao *GORMUserDAO) Update(ctx context.Context, model system.User) error {
 		}
 	}()
 
-	result := dao.db.WithContext(ctx).Model(&model).
+	result := tx.Model(&model).Scopes(filters.WithDataScope(ctx, "id")).
 		Where("id = ? AND version = ?", model.Id, model.Version).
 		Updates(map[string]any{
-			"name":     model.Name,
-			"gender":   model.Gender,
-			"email":    model.Email,
-			"mobile":   model.Mobile,
-			"avatar":   model.Avatar,
-			"dept_id":  model.DeptId,
-			"sort":     model.Sort,
-			"status":   model.Status,
-			"version":  gorm.Expr("version + 1"),
-			"modifier": model.Modifier,
-			"remark":   model.Remark,
+			"username":  model.Username,
+			"user_type": model.UserType,
+			"name":      model.Name,
+			"gender":    model.Gender,
+			"email":     model.Email,
+			"mobile":    model.Mobile,
+			"avatar":    model.Avatar,
+			"dept_id":   model.DeptId,
+			"sort":      model.Sort,
+			"status":    model.Status,
+			"version":   gorm.Expr("version + 1"),
+			"modifier":  model.Modifier,
+			"remark":    model.Remark,
 		})
+	if result.Error != nil {
+		tx.Rollback()
+		return result.Error
+	}
 	// 处理行影响数为0的情况
 	if result.RowsAffected == 0 {
-		// 先检查记录是否存在
-		var exists bool
-		dao.db.WithContext(ctx).
-			Model(&system.User{}).
-			Select("1").
-			Where("id = ?", model.Id).
-			Limit(1).
-			Find(&exists)
-
-		if !exists {
-			return ErrUserNotFound
-		}
-		return ErrUserVersionInconsistency
+		tx.Rollback()
+		return dao.notUpdatedError(ctx, model.Id)
 	}
 
 	// 2. 更新关联关系
-	if err := dao.updateAssociations(tx, ctx, model); err != nil {
+	if err := dao.updateAssociations(tx, model); err != nil {
 		tx.Rollback()
 		return err
 	}
@@ -118,49 +159,107 @@ func (dao *GORMUserDAO) Update(ctx context.Context, model system.User) error {
 	return tx.Commit().Error
 }
 
+// UpdateStatus 更新状态(受数据权限限制)
+func (dao *GORMUserDAO) UpdateStatus(ctx context.Context, model system.User) error {
+	result := dao.db.WithContext(ctx).Model(&system.User{}).Scopes(filters.WithDataScope(ctx, "id")).
+		Where("id = ? AND version = ?", model.Id, model.Version).
+		Updates(map[string]any{
+			"status":   model.Status,
+			"version":  gorm.Expr("version + 1"),
+			"modifier": model.Modifier,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return dao.notUpdatedError(ctx, model.Id)
+	}
+	return nil
+}
+
+// notUpdatedError 行影响数为0时区分记录不存在与版本不一致
+// 数据权限范围外的用户视为不存在
+func (dao *GORMUserDAO) notUpdatedError(ctx context.Context, id string) error {
+	var exists bool
+	dao.db.WithContext(ctx).
+		Model(&system.User{}).Scopes(filters.WithDataScope(ctx, "id")).
+		Select("1").
+		Where("id = ?", id).
+		Limit(1).
+		Find(&exists)
+
+	if !exists {
+		return ErrUserNotFound
+	}
+	return ErrUserVersionInconsistency
+}
+
 // updateAssociations 辅助函数：更新所有关联关系
-func (dao *GORMUserDAO) updateAssociations(tx *gorm.DB, ctx context.Context, model system.User) error {
+func (dao *GORMUserDAO) updateAssociations(tx *gorm.DB, model system.User) error {
 	// 更新岗位关联
 	// 删除旧关联
 	if err := tx.Exec("DELETE FROM careful_system_users_post WHERE user_id = ?", model.Id).Error; err != nil {
 		zap.S().Error("删除岗位关联异常：", err)
 		return err
 	}
-	// for _, id := range model.DeptIDs {
-	// 	dept, err := dao.deptDb.FindById(ctx, id)
-	// 	if err != nil {
-	// 		continue
-	// 	}
-	// 	if err := tx.Exec("INSERT INTO careful_system_role_dept (role_id, dept_id) VALUES (?, ?)",
-	// 		role.Id, dept.Id).Error; err != nil {
-	// 		zap.S().Error("更新部门关联异常：", err)
-	// 		return err
-	// 	}
-	// }
+	if len(model.PostIDs) > 0 {
+		// 只关联存在的岗位
+		var postIds []string
+		if err := tx.Model(&system.Post{}).Where("id IN ?", model.PostIDs).Pluck("id", &postIds).Error; err != nil {
+			return err
+		}
+		for _, id := range postIds {
+			if err := tx.Exec("INSERT INTO careful_system_users_post (user_id, post_id) VALUES (?, ?)",
+				model.Id, id).Error; err != nil {
+				zap.S().Error("更新岗位关联异常：", err)
+				return err
+			}
+		}
+	}
 	// 更新角色关联
 	// 删除旧关联
 	if err := tx.Exec("DELETE FROM careful_system_users_role WHERE user_id = ?", model.Id).Error; err != nil {
 		zap.S().Error("删除角色关联异常：", err)
 		return err
 	}
-	// for _, id := range role.MenuIDs {
-	// 	menu, err := dao.menuDb.FindById(ctx, id)
-	// 	if err != nil {
-	// 		continue
-	// 	}
-	// 	if err := tx.Exec("INSERT INTO careful_system_role_menu (role_id, menu_id) VALUES (?, ?)",
-	// 		role.Id, menu.Id).Error; err != nil {
-	// 		zap.S().Error("更新菜单关联异常：", err)
-	// 		return err
-	// 	}
-	// }
+	if len(model.RoleIDs) > 0 {
+		// 只关联存在的角色
+		var roleIds []string
+		if err := tx.Model(&system.Role{}).Where("id IN ?", model.RoleIDs).Pluck("id", &roleIds).Error; err != nil {
+			return err
+		}
+		for _, id := range roleIds {
+			if err := tx.Exec("INSERT INTO careful_system_users_role (user_id, role_id) VALUES (?, ?)",
+				model.Id, id).Error; err != nil {
+				zap.S().Error("更新角色关联异常：", err)
+				return err
+			}
+		}
+	}
 
 	return nil
 }
 
+// deleteAssociations 辅助函数：删除用户的岗位、角色及第三方身份关联
+func (dao *GORMUserDAO) deleteAssociations(tx *gorm.DB, ids []string) error {
+	if err := tx.Exec("DELETE FROM careful_system_users_post WHERE user_id IN ?", ids).Error; err != nil {
+		zap.S().Error("删除岗位关联异常：", err)
+		return err
+	}
+	if err := tx.Exec("DELETE FROM careful_system_users_role WHERE user_id IN ?", ids).Error; err != nil {
+		zap.S().Error("删除角色关联异常：", err)
+		return err
+	}
+	if err := tx.Exec("DELETE FROM careful_system_user_identity WHERE user_id IN ?", ids).Error; err != nil {
+		zap.S().Error("删除第三方身份关联异常：", err)
+		return err
+	}
+	return nil
+}
+
 // UpdatePassword 更新密码
-func (dao *GORMUserDAO) UpdatePassword(ctx context.Context, userId string, newPassword, hashedPassword string) error {
-	result := dao.db.WithContext(ctx).Model(&system.User{}).
+func (dao *GORMUserDAO) UpdatePassword(ctx context.Context, userId string, hashedPassword string) error {
+	result := dao.db.WithContext(ctx).Model(&system.User{}).Scopes(filters.WithDataScope(ctx, "id")).
 		Where("id = ?", userId).
 		Update("password", hashedPassword)
 	if result.RowsAffected == 0 {
@@ -169,11 +268,31 @@ func (dao *GORMUserDAO) UpdatePassword(ctx context.Context, userId string, newPa
 	return result.Error
 }
 
+// SetPassword 设置新密码，同时记录密码修改时间及是否需修改密码
+func (dao *GORMUserDAO) SetPassword(ctx context.Context, userId string, hashedPassword string, changeRequired bool) error {
+	result := dao.db.WithContext(ctx).Model(&system.User{}).Scopes(filters.WithDataScope(ctx, "id")).
+		Where("id = ?", userId).
+		Updates(map[string]any{
+			"password":                 hashedPassword,
+			"password_update_time":     time.Now(),
+			"password_change_required": changeRequired,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
 // FindById 根据id获取详情
 func (dao *GORMUserDAO) FindById(ctx context.Context, id string) (*system.User, error) {
 	var user system.User
-	err := dao.db.WithContext(ctx).
+	err := dao.db.WithContext(ctx).Scopes(filters.WithDataScope(ctx, "id")).
 		Preload("Dept").
+		Preload("Post").
+		Preload("Role").
 		Where("id = ?", id).
 		First(&user).Error
 	if err != nil {
@@ -201,6 +320,15 @@ func (dao *GORMUserDAO) FindByUsername(ctx context.Context, username string) (*s
 	return &user, nil
 }
 
+// FindListByEmail 根据邮箱精确查询，邮箱不唯一时可能返回多个用户
+func (dao *GORMUserDAO) FindListByEmail(ctx context.Context, email string) ([]*system.User, error) {
+	var models []*system.User
+	err := dao.db.WithContext(ctx).
+		Where("email = ?", email).
+		Find(&models).Error
+	return models, err
+}
+
 // FindListPage 分页查询
 func (dao *GORMUserDAO) FindListPage(ctx context.Context, filter domainSystem.UserFilter) ([]*system.User, int64, error) {
 	var total int64
@@ -244,7 +372,7 @@ func (dao *GORMUserDAO) buildQuery(ctx context.Context, filter domainSystem.User
 		Email:    filter.Email,
 		Mobile:   filter.Mobile,
 	}
-	return builder.Apply(dao.db.WithContext(ctx).Model(&system.User{}))
+	return builder.Apply(dao.db.WithContext(ctx).Model(&system.User{}).Scopes(filters.WithDataScope(ctx, "id")))
 }
 
 // CheckExistByUsername 检查用户名是否存在
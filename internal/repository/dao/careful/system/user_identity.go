@@ -0,0 +1,65 @@
+/**
+ * Description：
+ * FileName：user_identity.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 06:49:16
+ * Remark：
+ */
+
+package system
+
+import (
+	"context"
+	"github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/system"
+	"gorm.io/gorm"
+	"time"
+)
+
+var ErrUserIdentityNotFound = gorm.ErrRecordNotFound
+
+type UserIdentityDAO interface {
+	Insert(ctx context.Context, model system.UserIdentity) error
+	UpdateLastLogin(ctx context.Context, id, email string, loginTime time.Time) error
+
+	FindByProviderSubject(ctx context.Context, provider, subject string) (*system.UserIdentity, error)
+	FindListByUserId(ctx context.Context, userId string) ([]*system.UserIdentity, error)
+}
+
+type GORMUserIdentityDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMUserIdentityDAO(db *gorm.DB) UserIdentityDAO {
+	return &GORMUserIdentityDAO{
+		db: db,
+	}
+}
+
+// Insert 新增
+func (dao *GORMUserIdentityDAO) Insert(ctx context.Context, model system.UserIdentity) error {
+	return dao.db.WithContext(ctx).Create(&model).Error
+}
+
+// UpdateLastLogin 记录最近登录时间及第三方邮箱
+func (dao *GORMUserIdentityDAO) UpdateLastLogin(ctx context.Context, id, email string, loginTime time.Time) error {
+	return dao.db.WithContext(ctx).Model(&system.UserIdentity{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"email":           email,
+			"last_login_time": loginTime,
+		}).Error
+}
+
+// FindByProviderSubject 根据身份提供方及第三方用户标识查询
+func (dao *GORMUserIdentityDAO) FindByProviderSubject(ctx context.Context, provider, subject string) (*system.UserIdentity, error) {
+	var model system.UserIdentity
+	err := dao.db.WithContext(ctx).Where("provider = ? AND subject = ?", provider, subject).First(&model).Error
+	return &model, err
+}
+
+// FindListByUserId 查询用户关联的全部第三方身份
+func (dao *GORMUserIdentityDAO) FindListByUserId(ctx context.Context, userId string) ([]*system.UserIdentity, error) {
+	var list []*system.UserIdentity
+	err := dao.db.WithContext(ctx).Where("user_id = ?", userId).Order("create_time").Find(&list).Error
+	return list, err
+}
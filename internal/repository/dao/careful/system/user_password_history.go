@@ -0,0 +1,61 @@
+/**
+ * Description：
+ * FileName：user_password_history.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 01:33:09
+ * Remark：
+ */
+
+package system
+
+import (
+	"context"
+	"github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/system"
+	"gorm.io/gorm"
+)
+
+type UserPasswordHistoryDAO interface {
+	Insert(ctx context.Context, model system.UserPasswordHistory) error
+	DeleteExceptRecent(ctx context.Context, userId string, keep int) error
+
+	FindRecentPasswords(ctx context.Context, userId string, limit int) ([]string, error)
+}
+
+type GORMUserPasswordHistoryDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMUserPasswordHistoryDAO(db *gorm.DB) UserPasswordHistoryDAO {
+	return &GORMUserPasswordHistoryDAO{
+		db: db,
+	}
+}
+
+// Insert 新增
+func (dao *GORMUserPasswordHistoryDAO) Insert(ctx context.Context, model system.UserPasswordHistory) error {
+	return dao.db.WithContext(ctx).Create(&model).Error
+}
+
+// DeleteExceptRecent 仅保留用户最近 keep 条历史密码
+func (dao *GORMUserPasswordHistoryDAO) DeleteExceptRecent(ctx context.Context, userId string, keep int) error {
+	var ids []string
+	err := dao.db.WithContext(ctx).Model(&system.UserPasswordHistory{}).
+		Where("user_id = ?", userId).
+		Order("create_time DESC").
+		Pluck("id", &ids).Error
+	if err != nil || len(ids) <= keep {
+		return err
+	}
+	return dao.db.WithContext(ctx).Where("id IN ?", ids[keep:]).Delete(&system.UserPasswordHistory{}).Error
+}
+
+// FindRecentPasswords 查询用户最近 limit 条历史密码(密文)
+func (dao *GORMUserPasswordHistoryDAO) FindRecentPasswords(ctx context.Context, userId string, limit int) ([]string, error) {
+	var passwords []string
+	err := dao.db.WithContext(ctx).Model(&system.UserPasswordHistory{}).
+		Where("user_id = ?", userId).
+		Order("create_time DESC").
+		Limit(limit).
+		Pluck("password", &passwords).Error
+	return passwords, err
+}
@@ -0,0 +1,98 @@
+/**
+ * Description：
+ * FileName：user_two_factor.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 22:01:18
+ * Remark：
+ */
+
+package system
+
+import (
+	"context"
+	"github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/system"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrUserTwoFactorNotFound = gorm.ErrRecordNotFound
+
+type UserTwoFactorDAO interface {
+	Upsert(ctx context.Context, model system.UserTwoFactor) error
+	Enable(ctx context.Context, userId string, counter int64, recoveryCodes string) (bool, error)
+	UpdateLastCounter(ctx context.Context, userId string, counter int64) (bool, error)
+	UpdateRecoveryCodes(ctx context.Context, userId, oldCodes, newCodes string) (bool, error)
+	DeleteByUserId(ctx context.Context, userId string) error
+
+	FindByUserId(ctx context.Context, userId string) (*system.UserTwoFactor, error)
+	HasRequiredRole(ctx context.Context, userId string) (bool, error)
+}
+
+type GORMUserTwoFactorDAO struct {
+	db *gorm.DB
+}
+
+func NewGORMUserTwoFactorDAO(db *gorm.DB) UserTwoFactorDAO {
+	return &GORMUserTwoFactorDAO{
+		db: db,
+	}
+}
+
+// Upsert 新增或覆盖未启用的绑定信息
+func (dao *GORMUserTwoFactorDAO) Upsert(ctx context.Context, model system.UserTwoFactor) error {
+	return dao.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"secret", "enabled", "last_counter", "recovery_codes", "modifier", "update_time"}),
+	}).Create(&model).Error
+}
+
+// Enable 启用，仅对未启用的记录生效
+func (dao *GORMUserTwoFactorDAO) Enable(ctx context.Context, userId string, counter int64, recoveryCodes string) (bool, error) {
+	result := dao.db.WithContext(ctx).Model(&system.UserTwoFactor{}).
+		Where("user_id = ? AND enabled = ?", userId, false).
+		Updates(map[string]any{
+			"enabled":        true,
+			"last_counter":   counter,
+			"recovery_codes": recoveryCodes,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// UpdateLastCounter 记录已使用的时间步，时间步不大于已记录值时返回 false(验证码重放)
+func (dao *GORMUserTwoFactorDAO) UpdateLastCounter(ctx context.Context, userId string, counter int64) (bool, error) {
+	result := dao.db.WithContext(ctx).Model(&system.UserTwoFactor{}).
+		Where("user_id = ? AND last_counter < ?", userId, counter).
+		Update("last_counter", counter)
+	return result.RowsAffected > 0, result.Error
+}
+
+// UpdateRecoveryCodes 替换恢复码，恢复码已被并发修改时返回 false
+func (dao *GORMUserTwoFactorDAO) UpdateRecoveryCodes(ctx context.Context, userId, oldCodes, newCodes string) (bool, error) {
+	result := dao.db.WithContext(ctx).Model(&system.UserTwoFactor{}).
+		Where("user_id = ? AND recovery_codes = ?", userId, oldCodes).
+		Update("recovery_codes", newCodes)
+	return result.RowsAffected > 0, result.Error
+}
+
+// DeleteByUserId 删除
+func (dao *GORMUserTwoFactorDAO) DeleteByUserId(ctx context.Context, userId string) error {
+	return dao.db.WithContext(ctx).Where("user_id = ?", userId).Delete(&system.UserTwoFactor{}).Error
+}
+
+// FindByUserId 根据用户ID查询
+func (dao *GORMUserTwoFactorDAO) FindByUserId(ctx context.Context, userId string) (*system.UserTwoFactor, error) {
+	var model system.UserTwoFactor
+	err := dao.db.WithContext(ctx).Where("user_id = ?", userId).First(&model).Error
+	return &model, err
+}
+
+// HasRequiredRole 用户是否拥有要求双因素认证的已启用角色
+func (dao *GORMUserTwoFactorDAO) HasRequiredRole(ctx context.Context, userId string) (bool, error) {
+	var count int64
+	err := dao.db.WithContext(ctx).Model(&system.Role{}).
+		Joins("JOIN careful_system_users_role ON careful_system_users_role.role_id = careful_system_role.id").
+		Where("careful_system_users_role.user_id = ? AND careful_system_role.status = ? AND careful_system_role.require_two_fa = ?",
+			userId, true, true).
+		Count(&count).Error
+	return count > 0, err
+}
@@ -98,7 +98,7 @@ func (dao *GORMBucketDAO) Update(ctx context.Context, model tools.Bucket) error
 // FindById 根据id获取详情
 func (dao *GORMBucketDAO) FindById(ctx context.Context, id string) (*tools.Bucket, error) {
 	var model tools.Bucket
-	err := dao.db.WithContext(ctx).Where("id = ?", id).First(&model).Error
+	err := dao.db.WithContext(ctx).Scopes(filters.WithDataScope(ctx)).Where("id = ?", id).First(&model).Error
 	return &model, err
 }
 
@@ -136,7 +136,7 @@ func (dao *GORMBucketDAO) buildQuery(ctx context.Context, filter domainTools.Buc
 		Name:   filter.Name,
 		Code:   filter.Code,
 	}
-	return builder.Apply(dao.db.WithContext(ctx).Model(&tools.Bucket{}))
+	return builder.Apply(dao.db.WithContext(ctx).Model(&tools.Bucket{}).Scopes(filters.WithDataScope(ctx)))
 }
 
 // CheckExistByName 检查name是否存在
@@ -100,7 +100,7 @@ func (dao *GORMDictDAO) Update(ctx context.Context, model tools.Dict) error {
 // FindById 根据id获取详情
 func (dao *GORMDictDAO) FindById(ctx context.Context, id string) (*tools.Dict, error) {
 	var model tools.Dict
-	err := dao.db.WithContext(ctx).Where("id = ?", id).First(&model).Error
+	err := dao.db.WithContext(ctx).Scopes(filters.WithDataScope(ctx)).Where("id = ?", id).First(&model).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return &model, ErrDictNotFound
@@ -166,7 +166,7 @@ func (dao *GORMDictDAO) buildQuery(ctx context.Context, filter domainTools.DictF
 		Type:      filter.Type,
 		ValueType: filter.ValueType,
 	}
-	return builder.Apply(dao.db.WithContext(ctx).Model(&tools.Dict{}))
+	return builder.Apply(dao.db.WithContext(ctx).Model(&tools.Dict{}).Scopes(filters.WithDataScope(ctx)))
 }
 
 // CheckExistByCode 检查code是否存在
@@ -96,7 +96,7 @@ func (dao *GORMDictTypeDAO) Update(ctx context.Context, model tools.DictType) er
 // FindById 根据id获取详情
 func (dao *GORMDictTypeDAO) FindById(ctx context.Context, id string) (*tools.DictType, error) {
 	var model tools.DictType
-	err := dao.db.WithContext(ctx).
+	err := dao.db.WithContext(ctx).Scopes(filters.WithDataScope(ctx)).
 		Preload("Dict").
 		Where("id = ?", id).First(&model).Error
 	if err != nil {
@@ -168,5 +168,5 @@ func (dao *GORMDictTypeDAO) buildQuery(ctx context.Context, filter domainTools.D
 		ValueType: filter.ValueType,
 		DictId:    filter.DictId,
 	}
-	return builder.Apply(dao.db.WithContext(ctx).Model(&tools.DictType{}))
+	return builder.Apply(dao.db.WithContext(ctx).Model(&tools.DictType{}).Scopes(filters.WithDataScope(ctx)))
 }
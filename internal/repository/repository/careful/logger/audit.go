@@ -0,0 +1,34 @@
+/**
+ * Description：
+ * FileName：audit.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 17:05:48
+ * Remark：
+ */
+
+package logger
+
+import (
+	"context"
+	domainLogger "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/logger"
+	daoLogger "github.com/carefuly/carefuly-admin-go-gin/internal/repository/dao/careful/logger"
+)
+
+type AuditLogRepository interface {
+	Create(ctx context.Context, domain domainLogger.AuditLog) error
+}
+
+type auditLogRepository struct {
+	dao daoLogger.AuditLogDAO
+}
+
+func NewAuditLogRepository(dao daoLogger.AuditLogDAO) AuditLogRepository {
+	return &auditLogRepository{
+		dao: dao,
+	}
+}
+
+// Create 创建
+func (repo *auditLogRepository) Create(ctx context.Context, domain domainLogger.AuditLog) error {
+	return repo.dao.Insert(ctx, domain.AuditLogger)
+}
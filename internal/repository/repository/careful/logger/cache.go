@@ -0,0 +1,91 @@
+/**
+ * Description：
+ * FileName：cache.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 09:24:36
+ * Remark：
+ */
+
+package logger
+
+import (
+	"context"
+	domainLogger "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/logger"
+	modelLogger "github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/logger"
+	daoLogger "github.com/carefuly/carefuly-admin-go-gin/internal/repository/dao/careful/logger"
+)
+
+type CacheLogRepository interface {
+	GetListPage(ctx context.Context, filter domainLogger.CacheLogFilter) ([]domainLogger.CacheLog, int64, error)
+	GetStatGroups(ctx context.Context, filter domainLogger.CacheLogFilter) ([]domainLogger.CacheStatGroup, error)
+	GetTimeSample(ctx context.Context, filter domainLogger.CacheLogFilter, limit int) ([]domainLogger.CacheLog, error)
+}
+
+type cacheLogRepository struct {
+	dao daoLogger.CacheLogDAO
+}
+
+func NewCacheLogRepository(dao daoLogger.CacheLogDAO) CacheLogRepository {
+	return &cacheLogRepository{
+		dao: dao,
+	}
+}
+
+// GetListPage 分页查询
+func (repo *cacheLogRepository) GetListPage(ctx context.Context, filter domainLogger.CacheLogFilter) ([]domainLogger.CacheLog, int64, error) {
+	list, row, err := repo.dao.FindListPage(ctx, filter)
+	if err != nil {
+		return []domainLogger.CacheLog{}, row, err
+	}
+	if len(list) == 0 {
+		return []domainLogger.CacheLog{}, 0, nil
+	}
+
+	var toDomain []domainLogger.CacheLog
+	for _, v := range list {
+		toDomain = append(toDomain, repo.toDomain(v))
+	}
+	return toDomain, row, nil
+}
+
+// GetStatGroups 分组统计
+func (repo *cacheLogRepository) GetStatGroups(ctx context.Context, filter domainLogger.CacheLogFilter) ([]domainLogger.CacheStatGroup, error) {
+	list, err := repo.dao.FindStatGroups(ctx, filter)
+	if err != nil {
+		return []domainLogger.CacheStatGroup{}, err
+	}
+
+	toDomain := make([]domainLogger.CacheStatGroup, 0, len(list))
+	for _, v := range list {
+		toDomain = append(toDomain, domainLogger.CacheStatGroup{CacheStatGroup: *v})
+	}
+	return toDomain, nil
+}
+
+// GetTimeSample 查询耗时样本
+func (repo *cacheLogRepository) GetTimeSample(ctx context.Context, filter domainLogger.CacheLogFilter, limit int) ([]domainLogger.CacheLog, error) {
+	list, err := repo.dao.FindTimeSample(ctx, filter, limit)
+	if err != nil {
+		return []domainLogger.CacheLog{}, err
+	}
+
+	toDomain := make([]domainLogger.CacheLog, 0, len(list))
+	for _, v := range list {
+		toDomain = append(toDomain, repo.toDomain(v))
+	}
+	return toDomain, nil
+}
+
+// toDomain 转换为领域模型
+func (repo *cacheLogRepository) toDomain(entity *modelLogger.CacheLogger) domainLogger.CacheLog {
+	domain := domainLogger.CacheLog{
+		CacheLogger: *entity,
+	}
+	if entity.CreateTime != nil {
+		domain.CreateTime = entity.CreateTime.Format("2006-01-02 15:04:05")
+	}
+	if entity.UpdateTime != nil {
+		domain.UpdateTime = entity.UpdateTime.Format("2006-01-02 15:04:05")
+	}
+	return domain
+}
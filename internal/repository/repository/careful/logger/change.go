@@ -0,0 +1,148 @@
+/**
+ * Description：
+ * FileName：change.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 16:18:27
+ * Remark：
+ */
+
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	domainLogger "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/logger"
+	modelLogger "github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/logger"
+	cacheLogger "github.com/carefuly/carefuly-admin-go-gin/internal/repository/cache/careful/logger"
+	daoLogger "github.com/carefuly/carefuly-admin-go-gin/internal/repository/dao/careful/logger"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/changelog"
+	"go.uber.org/zap"
+	"strings"
+)
+
+var (
+	ErrChangeLogNotFound = daoLogger.ErrChangeLogNotFound
+)
+
+type ChangeLogRepository interface {
+	GetById(ctx context.Context, id string) (domainLogger.ChangeLog, error)
+	GetLatest(ctx context.Context, table, entityId string) (domainLogger.ChangeLog, error)
+	GetHistory(ctx context.Context, table, entityId string) ([]domainLogger.ChangeLog, error)
+	GetListPage(ctx context.Context, filter domainLogger.ChangeLogFilter) ([]domainLogger.ChangeLog, int64, error)
+	// Restore 恢复数据并删除对应的实体缓存
+	Restore(ctx context.Context, table, entityId string, values map[string]any, version int) error
+}
+
+type changeLogRepository struct {
+	dao   daoLogger.ChangeLogDAO
+	cache cacheLogger.ChangeLogCache
+}
+
+func NewChangeLogRepository(dao daoLogger.ChangeLogDAO, cache cacheLogger.ChangeLogCache) ChangeLogRepository {
+	return &changeLogRepository{
+		dao:   dao,
+		cache: cache,
+	}
+}
+
+// GetById 根据ID获取详情
+func (repo *changeLogRepository) GetById(ctx context.Context, id string) (domainLogger.ChangeLog, error) {
+	model, err := repo.dao.FindById(ctx, id)
+	if err != nil {
+		return domainLogger.ChangeLog{}, err
+	}
+	return repo.toDomain(model), nil
+}
+
+// GetLatest 获取数据最近一次的变更记录
+func (repo *changeLogRepository) GetLatest(ctx context.Context, table, entityId string) (domainLogger.ChangeLog, error) {
+	model, err := repo.dao.FindLatest(ctx, table, entityId)
+	if err != nil {
+		return domainLogger.ChangeLog{}, err
+	}
+	return repo.toDomain(model), nil
+}
+
+// GetHistory 获取数据的全部变更记录
+func (repo *changeLogRepository) GetHistory(ctx context.Context, table, entityId string) ([]domainLogger.ChangeLog, error) {
+	list, err := repo.dao.FindHistory(ctx, table, entityId)
+	if err != nil {
+		return []domainLogger.ChangeLog{}, err
+	}
+	if len(list) == 0 {
+		return []domainLogger.ChangeLog{}, nil
+	}
+
+	var toDomain []domainLogger.ChangeLog
+	for _, v := range list {
+		toDomain = append(toDomain, repo.toDomain(v))
+	}
+	return toDomain, nil
+}
+
+// GetListPage 分页查询
+func (repo *changeLogRepository) GetListPage(ctx context.Context, filter domainLogger.ChangeLogFilter) ([]domainLogger.ChangeLog, int64, error) {
+	list, row, err := repo.dao.FindListPage(ctx, filter)
+	if err != nil {
+		return []domainLogger.ChangeLog{}, row, err
+	}
+	if len(list) == 0 {
+		return []domainLogger.ChangeLog{}, 0, nil
+	}
+
+	var toDomain []domainLogger.ChangeLog
+	for _, v := range list {
+		toDomain = append(toDomain, repo.toDomain(v))
+	}
+	return toDomain, row, nil
+}
+
+// Restore 恢复数据
+func (repo *changeLogRepository) Restore(ctx context.Context, table, entityId string, values map[string]any, version int) error {
+	err := repo.dao.Restore(ctx, table, entityId, values, version)
+	if err != nil {
+		return err
+	}
+
+	// 恢复绕过了实体仓储，需删除实体缓存
+	err = repo.cache.DelEntity(ctx, table, entityId)
+	if err != nil {
+		zap.L().Error("Redis异常", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// toDomain 转换为领域模型
+func (repo *changeLogRepository) toDomain(entity *modelLogger.ChangeLogger) domainLogger.ChangeLog {
+	domain := domainLogger.ChangeLog{
+		ChangeLogger: *entity,
+		Changes:      []changelog.Change{},
+		OldValues:    unmarshalValues(entity.OldValues),
+		NewValues:    unmarshalValues(entity.NewValues),
+	}
+	if entity.Changes != "" {
+		_ = json.Unmarshal([]byte(entity.Changes), &domain.Changes)
+	}
+	if entity.CreateTime != nil {
+		domain.CreateTime = entity.CreateTime.Format("2006-01-02 15:04:05")
+	}
+	if entity.UpdateTime != nil {
+		domain.UpdateTime = entity.UpdateTime.Format("2006-01-02 15:04:05")
+	}
+	return domain
+}
+
+// unmarshalValues 数据快照反序列化，数字保留原始精度
+func unmarshalValues(data string) map[string]any {
+	if data == "" {
+		return nil
+	}
+	decoder := json.NewDecoder(strings.NewReader(data))
+	decoder.UseNumber()
+	var values map[string]any
+	if err := decoder.Decode(&values); err != nil {
+		return nil
+	}
+	return values
+}
@@ -0,0 +1,105 @@
+/**
+ * Description：
+ * FileName：login.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 17:53:20
+ * Remark：
+ */
+
+package logger
+
+import (
+	"context"
+	domainLogger "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/logger"
+	modelLogger "github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/logger"
+	daoLogger "github.com/carefuly/carefuly-admin-go-gin/internal/repository/dao/careful/logger"
+	"time"
+)
+
+type LoginLogRepository interface {
+	Create(ctx context.Context, domain domainLogger.LoginLog) error
+	BatchDelete(ctx context.Context, ids []string) error
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+
+	GetListPage(ctx context.Context, filter domainLogger.LoginLogFilter) ([]domainLogger.LoginLog, int64, error)
+	GetListAll(ctx context.Context, filter domainLogger.LoginLogFilter) ([]domainLogger.LoginLog, error)
+}
+
+type loginLogRepository struct {
+	dao daoLogger.LoginLogDAO
+}
+
+func NewLoginLogRepository(dao daoLogger.LoginLogDAO) LoginLogRepository {
+	return &loginLogRepository{
+		dao: dao,
+	}
+}
+
+// Create 创建
+func (repo *loginLogRepository) Create(ctx context.Context, domain domainLogger.LoginLog) error {
+	return repo.dao.Insert(ctx, domain.LoginLogger)
+}
+
+// BatchDelete 批量删除
+func (repo *loginLogRepository) BatchDelete(ctx context.Context, ids []string) error {
+	return repo.dao.BatchDelete(ctx, ids)
+}
+
+// DeleteBefore 清理指定时间之前的日志
+func (repo *loginLogRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	return repo.dao.DeleteBefore(ctx, before)
+}
+
+// GetListPage 分页查询
+func (repo *loginLogRepository) GetListPage(ctx context.Context, filter domainLogger.LoginLogFilter) ([]domainLogger.LoginLog, int64, error) {
+	list, row, err := repo.dao.FindListPage(ctx, filter)
+	if err != nil {
+		return []domainLogger.LoginLog{}, row, err
+	}
+
+	if len(list) == 0 {
+		return []domainLogger.LoginLog{}, 0, nil
+	}
+
+	var toDomain []domainLogger.LoginLog
+	for _, v := range list {
+		toDomain = append(toDomain, repo.toDomain(v))
+	}
+
+	return toDomain, row, nil
+}
+
+// GetListAll 查询所有列表
+func (repo *loginLogRepository) GetListAll(ctx context.Context, filter domainLogger.LoginLogFilter) ([]domainLogger.LoginLog, error) {
+	list, err := repo.dao.FindListAll(ctx, filter)
+	if err != nil {
+		return []domainLogger.LoginLog{}, err
+	}
+
+	if len(list) == 0 {
+		return []domainLogger.LoginLog{}, nil
+	}
+
+	var toDomain []domainLogger.LoginLog
+	for _, v := range list {
+		toDomain = append(toDomain, repo.toDomain(v))
+	}
+
+	return toDomain, nil
+}
+
+// toDomain 转换为领域模型
+func (repo *loginLogRepository) toDomain(entity *modelLogger.LoginLogger) domainLogger.LoginLog {
+	domain := domainLogger.LoginLog{
+		LoginLogger: *entity,
+	}
+
+	if entity.CreateTime != nil {
+		domain.CreateTime = entity.CreateTime.Format("2006-01-02 15:04:05")
+	}
+	if entity.UpdateTime != nil {
+		domain.UpdateTime = entity.UpdateTime.Format("2006-01-02 15:04:05")
+	}
+
+	return domain
+}
@@ -0,0 +1,113 @@
+/**
+ * Description：
+ * FileName：operate.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 08:29:05
+ * Remark：
+ */
+
+package logger
+
+import (
+	"context"
+	domainLogger "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/logger"
+	modelLogger "github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/logger"
+	daoLogger "github.com/carefuly/carefuly-admin-go-gin/internal/repository/dao/careful/logger"
+	"time"
+)
+
+var (
+	ErrOperateLogNotFound = daoLogger.ErrOperateLogNotFound
+)
+
+type OperateLogRepository interface {
+	BatchDelete(ctx context.Context, ids []string) error
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+	GetById(ctx context.Context, id string) (domainLogger.OperateLog, error)
+	GetListPage(ctx context.Context, filter domainLogger.OperateLogFilter) ([]domainLogger.OperateLog, int64, error)
+	GetListAll(ctx context.Context, filter domainLogger.OperateLogFilter) ([]domainLogger.OperateLog, error)
+}
+
+type operateLogRepository struct {
+	dao daoLogger.OperateLogDAO
+}
+
+func NewOperateLogRepository(dao daoLogger.OperateLogDAO) OperateLogRepository {
+	return &operateLogRepository{
+		dao: dao,
+	}
+}
+
+// BatchDelete 批量删除
+func (repo *operateLogRepository) BatchDelete(ctx context.Context, ids []string) error {
+	return repo.dao.BatchDelete(ctx, ids)
+}
+
+// DeleteBefore 清理指定时间之前的日志
+func (repo *operateLogRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	return repo.dao.DeleteBefore(ctx, before)
+}
+
+// GetById 根据ID获取详情
+func (repo *operateLogRepository) GetById(ctx context.Context, id string) (domainLogger.OperateLog, error) {
+	model, err := repo.dao.FindById(ctx, id)
+	if err != nil {
+		return domainLogger.OperateLog{}, err
+	}
+	return repo.toDomain(model), nil
+}
+
+// GetListPage 分页查询
+func (repo *operateLogRepository) GetListPage(ctx context.Context, filter domainLogger.OperateLogFilter) ([]domainLogger.OperateLog, int64, error) {
+	list, row, err := repo.dao.FindListPage(ctx, filter)
+	if err != nil {
+		return []domainLogger.OperateLog{}, row, err
+	}
+	if len(list) == 0 {
+		return []domainLogger.OperateLog{}, 0, nil
+	}
+
+	var toDomain []domainLogger.OperateLog
+	for _, v := range list {
+		toDomain = append(toDomain, repo.toDomain(v))
+	}
+	return toDomain, row, nil
+}
+
+// GetListAll 查询所有列表
+func (repo *operateLogRepository) GetListAll(ctx context.Context, filter domainLogger.OperateLogFilter) ([]domainLogger.OperateLog, error) {
+	list, err := repo.dao.FindListAll(ctx, filter)
+	if err != nil {
+		return []domainLogger.OperateLog{}, err
+	}
+	if len(list) == 0 {
+		return []domainLogger.OperateLog{}, nil
+	}
+
+	var toDomain []domainLogger.OperateLog
+	for _, v := range list {
+		toDomain = append(toDomain, repo.toDomain(v))
+	}
+	return toDomain, nil
+}
+
+// toDomain 转换为领域模型
+func (repo *operateLogRepository) toDomain(entity *modelLogger.OperateLogger) domainLogger.OperateLog {
+	domain := domainLogger.OperateLog{
+		OperateLogger: *entity,
+	}
+	// 请求参数以 text 存储，读取时驱动返回 []byte，转为字符串避免序列化为 base64
+	switch body := entity.RequestBody.(type) {
+	case []byte:
+		domain.RequestBody = string(body)
+	case nil:
+		domain.RequestBody = ""
+	}
+	if entity.CreateTime != nil {
+		domain.CreateTime = entity.CreateTime.Format("2006-01-02 15:04:05")
+	}
+	if entity.UpdateTime != nil {
+		domain.UpdateTime = entity.UpdateTime.Format("2006-01-02 15:04:05")
+	}
+	return domain
+}
@@ -0,0 +1,141 @@
+/**
+ * Description：
+ * FileName：api_key.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 23:27:48
+ * Remark：
+ */
+
+package system
+
+import (
+	"context"
+	"encoding/json"
+	domainSystem "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/system"
+	modelSystem "github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/system"
+	cacheSystem "github.com/carefuly/carefuly-admin-go-gin/internal/repository/cache/careful/system"
+	daoSystem "github.com/carefuly/carefuly-admin-go-gin/internal/repository/dao/careful/system"
+	"go.uber.org/zap"
+	"time"
+)
+
+var ErrApiKeyNotFound = daoSystem.ErrApiKeyNotFound
+
+type ApiKeyRepository interface {
+	Create(ctx context.Context, domain domainSystem.ApiKey) (domainSystem.ApiKey, error)
+	Revoke(ctx context.Context, id, userId, modifier string) (int64, error)
+	UpdateLastUsed(ctx context.Context, id, ip string, usedTime time.Time) error
+	TryTouch(ctx context.Context, id string, interval time.Duration) (bool, error)
+
+	GetById(ctx context.Context, id string) (domainSystem.ApiKey, error)
+	GetByHash(ctx context.Context, hash string) (domainSystem.ApiKey, error)
+	GetListByUserId(ctx context.Context, userId string) ([]domainSystem.ApiKey, error)
+	CountActiveByUserId(ctx context.Context, userId string) (int64, error)
+}
+
+type apiKeyRepository struct {
+	dao   daoSystem.ApiKeyDAO
+	cache cacheSystem.ApiKeyCache
+}
+
+func NewApiKeyRepository(dao daoSystem.ApiKeyDAO, cache cacheSystem.ApiKeyCache) ApiKeyRepository {
+	return &apiKeyRepository{
+		dao:   dao,
+		cache: cache,
+	}
+}
+
+// Create 创建
+func (repo *apiKeyRepository) Create(ctx context.Context, domain domainSystem.ApiKey) (domainSystem.ApiKey, error) {
+	model := domain.ApiKey
+	scopes, err := json.Marshal(domain.Scopes)
+	if err != nil {
+		return domainSystem.ApiKey{}, err
+	}
+	model.Scopes = string(scopes)
+
+	if err := repo.dao.Insert(ctx, model); err != nil {
+		return domainSystem.ApiKey{}, err
+	}
+	return repo.GetByHash(ctx, model.KeyHash)
+}
+
+// Revoke 吊销
+func (repo *apiKeyRepository) Revoke(ctx context.Context, id, userId, modifier string) (int64, error) {
+	return repo.dao.Revoke(ctx, id, userId, modifier)
+}
+
+// UpdateLastUsed 记录最近使用信息
+func (repo *apiKeyRepository) UpdateLastUsed(ctx context.Context, id, ip string, usedTime time.Time) error {
+	return repo.dao.UpdateLastUsed(ctx, id, ip, usedTime)
+}
+
+// TryTouch 获取记录最近使用信息的机会
+func (repo *apiKeyRepository) TryTouch(ctx context.Context, id string, interval time.Duration) (bool, error) {
+	return repo.cache.TryTouch(ctx, id, interval)
+}
+
+// GetById 根据ID查询
+func (repo *apiKeyRepository) GetById(ctx context.Context, id string) (domainSystem.ApiKey, error) {
+	model, err := repo.dao.FindById(ctx, id)
+	if err != nil {
+		return domainSystem.ApiKey{}, err
+	}
+	return repo.toDomain(model), nil
+}
+
+// GetByHash 根据令牌摘要查询
+func (repo *apiKeyRepository) GetByHash(ctx context.Context, hash string) (domainSystem.ApiKey, error) {
+	model, err := repo.dao.FindByHash(ctx, hash)
+	if err != nil {
+		return domainSystem.ApiKey{}, err
+	}
+	return repo.toDomain(model), nil
+}
+
+// GetListByUserId 查询用户的全部令牌
+func (repo *apiKeyRepository) GetListByUserId(ctx context.Context, userId string) ([]domainSystem.ApiKey, error) {
+	models, err := repo.dao.FindListByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]domainSystem.ApiKey, 0, len(models))
+	for _, model := range models {
+		list = append(list, repo.toDomain(model))
+	}
+	return list, nil
+}
+
+// CountActiveByUserId 统计用户有效令牌数量
+func (repo *apiKeyRepository) CountActiveByUserId(ctx context.Context, userId string) (int64, error) {
+	return repo.dao.CountActiveByUserId(ctx, userId)
+}
+
+func (repo *apiKeyRepository) toDomain(entity *modelSystem.ApiKey) domainSystem.ApiKey {
+	domain := domainSystem.ApiKey{
+		ApiKey: *entity,
+		Scopes: []string{},
+	}
+
+	if entity.Scopes != "" {
+		if err := json.Unmarshal([]byte(entity.Scopes), &domain.Scopes); err != nil {
+			// 授权范围无法解析时视为已吊销，避免权限放大
+			zap.L().Error("令牌授权范围解析失败", zap.String("id", entity.Id), zap.Error(err))
+			domain.Revoked = true
+		}
+	}
+	if entity.ExpireTime != nil {
+		domain.ExpireTime = entity.ExpireTime.Format("2006-01-02 15:04:05")
+		domain.Expired = time.Now().After(*entity.ExpireTime)
+	}
+	if entity.LastUsedTime != nil {
+		domain.LastUsedTime = entity.LastUsedTime.Format("2006-01-02 15:04:05")
+	}
+	if entity.CreateTime != nil {
+		domain.CreateTime = entity.CreateTime.Format("2006-01-02 15:04:05")
+	}
+	if entity.UpdateTime != nil {
+		domain.UpdateTime = entity.UpdateTime.Format("2006-01-02 15:04:05")
+	}
+	return domain
+}
@@ -0,0 +1,69 @@
+/**
+ * Description：
+ * FileName：login_failure.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 16:14:47
+ * Remark：
+ */
+
+package system
+
+import (
+	"context"
+	cacheSystem "github.com/carefuly/carefuly-admin-go-gin/internal/repository/cache/careful/system"
+	"time"
+)
+
+type (
+	LoginFailureRule   = cacheSystem.LoginFailureRule
+	LoginFailureResult = cacheSystem.LoginFailureResult
+)
+
+type LoginFailureRepository interface {
+	GetFailures(ctx context.Context, username string) (int64, error)
+	IncrFailures(ctx context.Context, username, ip string, rule LoginFailureRule) (LoginFailureResult, error)
+	GetLockTTL(ctx context.Context, username, ip string) (time.Duration, time.Duration, error)
+	ResetFailures(ctx context.Context, username string) error
+	Unlock(ctx context.Context, username string) error
+	UnlockIp(ctx context.Context, ip string) error
+}
+
+type loginFailureRepository struct {
+	cache cacheSystem.LoginFailureCache
+}
+
+func NewLoginFailureRepository(cache cacheSystem.LoginFailureCache) LoginFailureRepository {
+	return &loginFailureRepository{
+		cache: cache,
+	}
+}
+
+// GetFailures 获取登录失败次数
+func (repo *loginFailureRepository) GetFailures(ctx context.Context, username string) (int64, error) {
+	return repo.cache.Get(ctx, username)
+}
+
+// IncrFailures 记录一次登录失败
+func (repo *loginFailureRepository) IncrFailures(ctx context.Context, username, ip string, rule LoginFailureRule) (LoginFailureResult, error) {
+	return repo.cache.Incr(ctx, username, ip, rule)
+}
+
+// GetLockTTL 获取用户名及IP的剩余锁定时长
+func (repo *loginFailureRepository) GetLockTTL(ctx context.Context, username, ip string) (time.Duration, time.Duration, error) {
+	return repo.cache.LockTTL(ctx, username, ip)
+}
+
+// ResetFailures 清除登录失败次数
+func (repo *loginFailureRepository) ResetFailures(ctx context.Context, username string) error {
+	return repo.cache.Del(ctx, username)
+}
+
+// Unlock 解锁用户名
+func (repo *loginFailureRepository) Unlock(ctx context.Context, username string) error {
+	return repo.cache.Unlock(ctx, username)
+}
+
+// UnlockIp 解锁IP
+func (repo *loginFailureRepository) UnlockIp(ctx context.Context, ip string) error {
+	return repo.cache.UnlockIp(ctx, ip)
+}
@@ -0,0 +1,36 @@
+/**
+ * Description：
+ * FileName：password_reset_limit.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 18:58:44
+ * Remark：
+ */
+
+package system
+
+import (
+	"context"
+	cacheSystem "github.com/carefuly/carefuly-admin-go-gin/internal/repository/cache/careful/system"
+)
+
+type PasswordResetLimitRule = cacheSystem.PasswordResetLimitRule
+
+type PasswordResetLimitRepository interface {
+	// Acquire 占用一次发送次数，超出限制时返回 false
+	Acquire(ctx context.Context, email, ip string, rule PasswordResetLimitRule) (bool, error)
+}
+
+type passwordResetLimitRepository struct {
+	cache cacheSystem.PasswordResetLimitCache
+}
+
+func NewPasswordResetLimitRepository(cache cacheSystem.PasswordResetLimitCache) PasswordResetLimitRepository {
+	return &passwordResetLimitRepository{
+		cache: cache,
+	}
+}
+
+// Acquire 占用一次发送次数
+func (repo *passwordResetLimitRepository) Acquire(ctx context.Context, email, ip string, rule PasswordResetLimitRule) (bool, error) {
+	return repo.cache.Acquire(ctx, email, ip, rule)
+}
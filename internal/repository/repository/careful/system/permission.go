@@ -0,0 +1,313 @@
+/**
+ * Description：
+ * FileName：permission.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 10:03:44
+ * Remark：
+ */
+
+package system
+
+import (
+	"context"
+	"errors"
+	domainSystem "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/system"
+	modelSystem "github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/system"
+	cacheSystem "github.com/carefuly/carefuly-admin-go-gin/internal/repository/cache/careful/system"
+	daoSystem "github.com/carefuly/carefuly-admin-go-gin/internal/repository/dao/careful/system"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/constants/careful/system/role"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/filters"
+	"go.uber.org/zap"
+	"sort"
+)
+
+type PermissionRepository interface {
+	GetByUserId(ctx context.Context, userId string) (domainSystem.UserPermission, error)
+	GetRoutes(ctx context.Context, userId string) ([]*domainSystem.Route, error)
+	SetRoutes(ctx context.Context, userId string, routes []*domainSystem.Route) error
+	// GetUserIdsByRoleIds 关联指定角色的用户ID，用于角色删除前收集需清理缓存的用户
+	GetUserIdsByRoleIds(ctx context.Context, roleIds ...string) ([]string, error)
+
+	DelByUserIds(ctx context.Context, userIds ...string) error
+	DelByRoleIds(ctx context.Context, roleIds ...string) error
+	DelAll(ctx context.Context) error
+}
+
+type permissionRepository struct {
+	dao   daoSystem.PermissionDAO
+	cache cacheSystem.PermissionCache
+}
+
+func NewPermissionRepository(dao daoSystem.PermissionDAO, cache cacheSystem.PermissionCache) PermissionRepository {
+	return &permissionRepository{
+		dao:   dao,
+		cache: cache,
+	}
+}
+
+// GetByUserId 获取用户权限集合
+func (repo *permissionRepository) GetByUserId(ctx context.Context, userId string) (domainSystem.UserPermission, error) {
+	domain, err := repo.cache.Get(ctx, userId)
+	if err == nil && domain != nil {
+		return *domain, nil // 命中缓存
+	}
+	if err != nil && !errors.Is(err, cacheSystem.ErrPermissionNotExist) {
+		// 缓存查询出错但不是"不存在"错误，记录日志但继续查DB
+		zap.L().Error("缓存获取错误:", zap.Error(err))
+	}
+
+	permission, err := repo.load(ctx, userId)
+	if err != nil {
+		return domainSystem.UserPermission{}, err
+	}
+
+	if err := repo.cache.Set(ctx, permission); err != nil {
+		// 网络崩了，也可能是 redis 崩了
+		zap.L().Error("Redis异常", zap.Error(err))
+	}
+
+	return permission, nil
+}
+
+// GetRoutes 获取缓存的用户动态路由，未缓存时返回 nil
+func (repo *permissionRepository) GetRoutes(ctx context.Context, userId string) ([]*domainSystem.Route, error) {
+	routes, err := repo.cache.GetRoutes(ctx, userId)
+	if err != nil {
+		if errors.Is(err, cacheSystem.ErrPermissionNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return routes, nil
+}
+
+// SetRoutes 缓存用户动态路由
+func (repo *permissionRepository) SetRoutes(ctx context.Context, userId string, routes []*domainSystem.Route) error {
+	return repo.cache.SetRoutes(ctx, userId, routes)
+}
+
+// GetUserIdsByRoleIds 获取关联指定角色的用户ID
+func (repo *permissionRepository) GetUserIdsByRoleIds(ctx context.Context, roleIds ...string) ([]string, error) {
+	return repo.dao.FindUserIdsByRoleIds(ctx, roleIds)
+}
+
+// DelByUserIds 删除指定用户的权限缓存
+func (repo *permissionRepository) DelByUserIds(ctx context.Context, userIds ...string) error {
+	return repo.cache.Del(ctx, userIds...)
+}
+
+// DelByRoleIds 删除关联指定角色的用户权限缓存
+func (repo *permissionRepository) DelByRoleIds(ctx context.Context, roleIds ...string) error {
+	userIds, err := repo.dao.FindUserIdsByRoleIds(ctx, roleIds)
+	if err != nil {
+		return err
+	}
+	return repo.cache.Del(ctx, userIds...)
+}
+
+// DelAll 删除全部用户权限缓存
+func (repo *permissionRepository) DelAll(ctx context.Context) error {
+	return repo.cache.DelAll(ctx)
+}
+
+// load 从数据库加载用户权限集合
+func (repo *permissionRepository) load(ctx context.Context, userId string) (domainSystem.UserPermission, error) {
+	permission := domainSystem.UserPermission{
+		UserId:    userId,
+		RoleIds:   []string{},
+		RoleCodes: []string{},
+		Apis:      []domainSystem.ApiPermission{},
+		MenuIds:   []string{},
+		DataScope: filters.DataScope{UserId: userId, DeptIds: []string{}},
+
+		Columns:       map[string][]string{},
+		HiddenColumns: map[string][]string{},
+	}
+
+	roles, err := repo.dao.FindRolesByUserId(ctx, userId)
+	if err != nil {
+		return permission, err
+	}
+	for _, r := range roles {
+		permission.RoleIds = append(permission.RoleIds, r.Id)
+		permission.RoleCodes = append(permission.RoleCodes, r.Code)
+		if r.Code == role.SuperAdminCode {
+			permission.SuperAdmin = true
+		}
+	}
+
+	if err := repo.loadDataScope(ctx, &permission, roles); err != nil {
+		return permission, err
+	}
+
+	menuIds, err := repo.dao.FindMenuIdsByRoleIds(ctx, permission.RoleIds)
+	if err != nil {
+		return permission, err
+	}
+	permission.MenuIds = append(permission.MenuIds, menuIds...)
+
+	// 超级管理员拥有全部按钮权限
+	var buttons []*modelSystem.MenuButton
+	if permission.SuperAdmin {
+		buttons, err = repo.dao.FindAllMenuButtons(ctx)
+	} else {
+		buttons, err = repo.dao.FindMenuButtonsByRoleIds(ctx, permission.RoleIds)
+	}
+	if err != nil {
+		return permission, err
+	}
+	for _, b := range buttons {
+		permission.Apis = append(permission.Apis, domainSystem.ApiPermission{
+			Code:   b.Code,
+			Api:    b.Api,
+			Method: b.Method,
+			MenuId: b.MenuId,
+		})
+	}
+
+	if err := repo.loadColumns(ctx, &permission); err != nil {
+		return permission, err
+	}
+
+	return permission, nil
+}
+
+// loadDataScope 合并用户全部角色的数据权限范围
+func (repo *permissionRepository) loadDataScope(ctx context.Context, permission *domainSystem.UserPermission, roles []*modelSystem.Role) error {
+	scope := &permission.DataScope
+	if permission.SuperAdmin {
+		scope.All = true
+		return nil
+	}
+
+	var (
+		dept      bool
+		deptBelow bool
+		custom    []string
+	)
+	for _, r := range roles {
+		switch r.DataRange {
+		case role.DataRangeConstOnly:
+			scope.Self = true
+		case role.DataRangeConstDept:
+			dept = true
+		case role.DataRangeConstDeptBelow:
+			deptBelow = true
+		case role.DataRangeConstAll:
+			scope.All = true
+			return nil
+		case role.DataRangeConstCustom:
+			custom = append(custom, r.Id)
+		}
+	}
+	// 未分配角色时仅能访问本人数据
+	if len(roles) == 0 {
+		scope.Self = true
+	}
+
+	deptIds := make(map[string]struct{})
+	if dept || deptBelow {
+		deptId, err := repo.dao.FindDeptIdByUserId(ctx, permission.UserId)
+		if err != nil {
+			return err
+		}
+		if deptId != "" {
+			deptIds[deptId] = struct{}{}
+			if deptBelow {
+				children, err := repo.childDeptIds(ctx, deptId)
+				if err != nil {
+					return err
+				}
+				for _, id := range children {
+					deptIds[id] = struct{}{}
+				}
+			}
+		}
+	}
+	if len(custom) > 0 {
+		ids, err := repo.dao.FindDeptIdsByRoleIds(ctx, custom)
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			deptIds[id] = struct{}{}
+		}
+	}
+
+	for id := range deptIds {
+		scope.DeptIds = append(scope.DeptIds, id)
+	}
+	sort.Strings(scope.DeptIds)
+
+	return nil
+}
+
+// loadColumns 计算用户可查看及无权限查看的列
+// 菜单配置了数据列时，未授权给用户任一角色的列视为隐藏；超级管理员可查看全部列
+func (repo *permissionRepository) loadColumns(ctx context.Context, permission *domainSystem.UserPermission) error {
+	columns, err := repo.dao.FindMenuColumns(ctx)
+	if err != nil {
+		return err
+	}
+	if len(columns) == 0 {
+		return nil
+	}
+
+	if permission.SuperAdmin {
+		for _, c := range columns {
+			permission.Columns[c.MenuId] = append(permission.Columns[c.MenuId], c.Field)
+		}
+		return nil
+	}
+
+	granted, err := repo.dao.FindMenuColumnIdsByRoleIds(ctx, permission.RoleIds)
+	if err != nil {
+		return err
+	}
+	grantedSet := make(map[string]struct{}, len(granted))
+	for _, id := range granted {
+		grantedSet[id] = struct{}{}
+	}
+
+	for _, c := range columns {
+		if _, ok := grantedSet[c.Id]; ok {
+			permission.Columns[c.MenuId] = append(permission.Columns[c.MenuId], c.Field)
+			continue
+		}
+		permission.HiddenColumns[c.MenuId] = append(permission.HiddenColumns[c.MenuId], c.Field)
+	}
+
+	return nil
+}
+
+// childDeptIds 获取部门的全部下级部门ID
+func (repo *permissionRepository) childDeptIds(ctx context.Context, deptId string) ([]string, error) {
+	depts, err := repo.dao.FindDeptTree(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	children := make(map[string][]string)
+	for _, d := range depts {
+		children[d.ParentID] = append(children[d.ParentID], d.Id)
+	}
+
+	var result []string
+	visited := map[string]bool{deptId: true}
+	queue := []string{deptId}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, id := range children[current] {
+			// 防止脏数据形成环
+			if visited[id] {
+				continue
+			}
+			visited[id] = true
+			result = append(result, id)
+			queue = append(queue, id)
+		}
+	}
+
+	return result, nil
+}
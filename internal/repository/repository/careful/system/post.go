@@ -15,6 +15,7 @@ import (
 	modelSystem "github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/system"
 	cacheSystem "github.com/carefuly/carefuly-admin-go-gin/internal/repository/cache/careful/system"
 	daoSystem "github.com/carefuly/carefuly-admin-go-gin/internal/repository/dao/careful/system"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/filters"
 	"github.com/carefuly/carefuly-admin-go-gin/pkg/models"
 	"go.uber.org/zap"
 )
@@ -115,6 +116,10 @@ func (repo *postRepository) Update(ctx context.Context, domain domainSystem.Post
 func (repo *postRepository) GetById(ctx context.Context, id string) (domainSystem.Post, error) {
 	domain, err := repo.cache.Get(ctx, id)
 	if err == nil && domain != nil {
+		// 命中缓存，校验数据权限范围
+		if scope, ok := filters.DataScopeFromContext(ctx); ok && !scope.Allow(domain.Creator, domain.BelongDept) {
+			return domainSystem.Post{}, nil
+		}
 		return *domain, nil // 命中缓存
 	}
 	if err != nil && !errors.Is(err, cacheSystem.ErrPostNotExist) {
@@ -125,8 +130,10 @@ func (repo *postRepository) GetById(ctx context.Context, id string) (domainSyste
 	entity, err := repo.dao.FindById(ctx, id)
 	if err != nil {
 		if errors.Is(err, daoSystem.ErrPostNotFound) {
-			// 数据库不存在，设置防穿透标记
-			_ = repo.cache.SetNotFound(ctx, id)
+			// 数据库不存在，设置防穿透标记(受数据权限限制时仅代表当前用户不可见)
+			if !filters.DataScopeRestricted(ctx) {
+				_ = repo.cache.SetNotFound(ctx, id)
+			}
 			return domainSystem.Post{}, nil
 		}
 		return domainSystem.Post{}, err
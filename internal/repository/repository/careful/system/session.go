@@ -0,0 +1,78 @@
+/**
+ * Description：
+ * FileName：session.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 18:55:20
+ * Remark：
+ */
+
+package system
+
+import (
+	"context"
+	domainSystem "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/system"
+	cacheSystem "github.com/carefuly/carefuly-admin-go-gin/internal/repository/cache/careful/system"
+	"time"
+)
+
+var ErrSessionNotFound = cacheSystem.ErrSessionNotExist
+
+type SessionRepository interface {
+	Create(ctx context.Context, domain domainSystem.Session, expiration time.Duration) error
+	Update(ctx context.Context, domain domainSystem.Session) error
+	Delete(ctx context.Context, userId, jti string) error
+
+	GetByJti(ctx context.Context, jti string) (domainSystem.Session, error)
+	GetTTL(ctx context.Context, jti string) (time.Duration, error)
+	GetJtisByUserId(ctx context.Context, userId string) ([]string, error)
+	GetListAll(ctx context.Context) ([]domainSystem.Session, error)
+}
+
+type sessionRepository struct {
+	cache cacheSystem.SessionCache
+}
+
+func NewSessionRepository(cache cacheSystem.SessionCache) SessionRepository {
+	return &sessionRepository{
+		cache: cache,
+	}
+}
+
+// Create 记录会话
+func (repo *sessionRepository) Create(ctx context.Context, domain domainSystem.Session, expiration time.Duration) error {
+	return repo.cache.Set(ctx, domain, expiration)
+}
+
+// Update 更新会话，保留剩余有效期
+func (repo *sessionRepository) Update(ctx context.Context, domain domainSystem.Session) error {
+	return repo.cache.Update(ctx, domain)
+}
+
+// Delete 删除会话
+func (repo *sessionRepository) Delete(ctx context.Context, userId, jti string) error {
+	return repo.cache.Del(ctx, userId, jti)
+}
+
+// GetByJti 根据jti获取会话
+func (repo *sessionRepository) GetByJti(ctx context.Context, jti string) (domainSystem.Session, error) {
+	session, err := repo.cache.Get(ctx, jti)
+	if err != nil {
+		return domainSystem.Session{}, err
+	}
+	return *session, nil
+}
+
+// GetTTL 获取会话剩余有效期
+func (repo *sessionRepository) GetTTL(ctx context.Context, jti string) (time.Duration, error) {
+	return repo.cache.TTL(ctx, jti)
+}
+
+// GetJtisByUserId 获取用户的全部会话ID
+func (repo *sessionRepository) GetJtisByUserId(ctx context.Context, userId string) ([]string, error) {
+	return repo.cache.GetUserJtis(ctx, userId)
+}
+
+// GetListAll 获取全部在线会话
+func (repo *sessionRepository) GetListAll(ctx context.Context) ([]domainSystem.Session, error) {
+	return repo.cache.GetAll(ctx)
+}
@@ -15,6 +15,7 @@ import (
 	modelSystem "github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/system"
 	cacheSystem "github.com/carefuly/carefuly-admin-go-gin/internal/repository/cache/careful/system"
 	daoSystem "github.com/carefuly/carefuly-admin-go-gin/internal/repository/dao/careful/system"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/filters"
 	"github.com/carefuly/carefuly-admin-go-gin/pkg/models"
 	"go.uber.org/zap"
 )
@@ -29,11 +30,16 @@ var (
 type UserRepository interface {
 	Create(ctx context.Context, domain domainSystem.User) error
 	Delete(ctx context.Context, id string) (int64, error)
+	// BatchDelete 批量删除，返回实际删除的用户ID
+	BatchDelete(ctx context.Context, ids []string) ([]string, error)
 	Update(ctx context.Context, domain domainSystem.User) error
-	UpdatePassword(ctx context.Context, userId string, newPassword, hashedPassword string) error
+	UpdateStatus(ctx context.Context, domain domainSystem.User) error
+	UpdatePassword(ctx context.Context, userId string, hashedPassword string) error
+	SetPassword(ctx context.Context, userId string, hashedPassword string, changeRequired bool) error
 
 	GetById(ctx context.Context, id string) (domainSystem.User, error)
 	GetByUsername(ctx context.Context, username string) (domainSystem.User, error)
+	GetListByEmail(ctx context.Context, email string) ([]domainSystem.User, error)
 	GetListPage(ctx context.Context, filters domainSystem.UserFilter) ([]domainSystem.User, int64, error)
 	GetListAll(ctx context.Context, filters domainSystem.UserFilter) ([]domainSystem.User, error)
 
@@ -76,23 +82,23 @@ func (repo *userRepository) Delete(ctx context.Context, id string) (int64, error
 }
 
 // BatchDelete 批量删除
-func (repo *userRepository) BatchDelete(ctx context.Context, ids []string) error {
-	err := repo.dao.BatchDelete(ctx, ids)
+func (repo *userRepository) BatchDelete(ctx context.Context, ids []string) ([]string, error) {
+	deleted, err := repo.dao.BatchDelete(ctx, ids)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// 删除缓存
-	for _, val := range ids {
+	for _, val := range deleted {
 		err = repo.cache.Del(ctx, val)
 		if err != nil {
 			// 网络崩了，也可能是 redis 崩了
 			zap.L().Error("Redis异常", zap.Error(err))
-			return err
+			return deleted, err
 		}
 	}
 
-	return err
+	return deleted, nil
 }
 
 // Update 更新
@@ -113,15 +119,68 @@ func (repo *userRepository) Update(ctx context.Context, domain domainSystem.User
 	return nil
 }
 
+// UpdateStatus 更新状态
+func (repo *userRepository) UpdateStatus(ctx context.Context, domain domainSystem.User) error {
+	err := repo.dao.UpdateStatus(ctx, repo.toEntity(domain))
+	if err != nil {
+		return err
+	}
+
+	// 删除缓存
+	err = repo.cache.Del(ctx, domain.Id)
+	if err != nil {
+		// 网络崩了，也可能是 redis 崩了
+		zap.L().Error("Redis异常", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
 // UpdatePassword 更新密码
-func (repo *userRepository) UpdatePassword(ctx context.Context, userId string, newPassword, hashedPassword string) error {
-	return repo.dao.UpdatePassword(ctx, userId, newPassword, hashedPassword)
+func (repo *userRepository) UpdatePassword(ctx context.Context, userId string, hashedPassword string) error {
+	err := repo.dao.UpdatePassword(ctx, userId, hashedPassword)
+	if err != nil {
+		return err
+	}
+
+	// 删除缓存
+	err = repo.cache.Del(ctx, userId)
+	if err != nil {
+		// 网络崩了，也可能是 redis 崩了
+		zap.L().Error("Redis异常", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// SetPassword 设置新密码，记录密码修改时间
+func (repo *userRepository) SetPassword(ctx context.Context, userId string, hashedPassword string, changeRequired bool) error {
+	err := repo.dao.SetPassword(ctx, userId, hashedPassword, changeRequired)
+	if err != nil {
+		return err
+	}
+
+	// 删除缓存
+	err = repo.cache.Del(ctx, userId)
+	if err != nil {
+		// 网络崩了，也可能是 redis 崩了
+		zap.L().Error("Redis异常", zap.Error(err))
+		return err
+	}
+
+	return nil
 }
 
 // GetById 根据ID获取
 func (repo *userRepository) GetById(ctx context.Context, id string) (domainSystem.User, error) {
 	main, err := repo.cache.Get(ctx, id)
 	if err == nil && main != nil {
+		// 命中缓存，校验数据权限范围
+		if scope, ok := filters.DataScopeFromContext(ctx); ok && !scope.Allow(main.Creator, main.BelongDept, main.Id) {
+			return domainSystem.User{}, nil
+		}
 		return *main, nil // 命中缓存
 	}
 	if err != nil && !errors.Is(err, cacheSystem.ErrUserNotExist) {
@@ -132,8 +191,10 @@ func (repo *userRepository) GetById(ctx context.Context, id string) (domainSyste
 	entity, err := repo.dao.FindById(ctx, id)
 	if err != nil {
 		if errors.Is(err, daoSystem.ErrUserNotFound) {
-			// 数据库不存在，设置防穿透标记
-			_ = repo.cache.SetNotFound(ctx, id)
+			// 数据库不存在，设置防穿透标记(受数据权限限制时仅代表当前用户不可见)
+			if !filters.DataScopeRestricted(ctx) {
+				_ = repo.cache.SetNotFound(ctx, id)
+			}
 			return domainSystem.User{}, nil
 		}
 		return domainSystem.User{}, err
@@ -157,6 +218,19 @@ func (repo *userRepository) GetByUsername(ctx context.Context, username string)
 	return repo.toDomain(user), nil
 }
 
+// GetListByEmail 根据邮箱获取，包含密码密文
+func (repo *userRepository) GetListByEmail(ctx context.Context, email string) ([]domainSystem.User, error) {
+	users, err := repo.dao.FindListByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]domainSystem.User, 0, len(users))
+	for _, user := range users {
+		list = append(list, repo.toDomain(user))
+	}
+	return list, nil
+}
+
 // GetListPage 分页查询列表
 func (repo *userRepository) GetListPage(ctx context.Context, filters domainSystem.UserFilter) ([]domainSystem.User, int64, error) {
 	list, row, err := repo.dao.FindListPage(ctx, filters)
@@ -212,19 +286,20 @@ func (repo *userRepository) toEntity(domain domainSystem.User) modelSystem.User
 			BelongDept: domain.BelongDept,
 			Remark:     domain.Remark,
 		},
-		Status:      domain.Status,
-		Username:    domain.Username,
-		Password:    domain.Password,
-		PasswordStr: domain.PasswordStr,
-		UserType:    domain.UserType,
-		Name:        domain.Name,
-		Gender:      domain.Gender,
-		Email:       domain.Email,
-		Mobile:      domain.Mobile,
-		Avatar:      domain.Avatar,
-		DeptId:      domain.DeptId,
-		PostIDs:     domain.PostIDs,
-		RoleIDs:     domain.RoleIDs,
+		Status:   domain.Status,
+		Username: domain.Username,
+		Password: domain.Password,
+		UserType: domain.UserType,
+		Name:     domain.Name,
+		Gender:   domain.Gender,
+		Email:    domain.Email,
+		Mobile:   domain.Mobile,
+		Avatar:   domain.Avatar,
+		DeptId:   domain.DeptId,
+		PostIDs:  domain.PostIDs,
+		RoleIDs:  domain.RoleIDs,
+
+		PasswordChangeRequired: domain.PasswordChangeRequired,
 	}
 }
 
@@ -234,6 +309,19 @@ func (repo *userRepository) toDomain(entity *modelSystem.User) domainSystem.User
 		User: *entity,
 	}
 
+	// 关联的岗位及角色ID
+	user.PostIDs = make([]string, 0, len(entity.Post))
+	for _, p := range entity.Post {
+		user.PostIDs = append(user.PostIDs, p.Id)
+	}
+	user.RoleIDs = make([]string, 0, len(entity.Role))
+	for _, r := range entity.Role {
+		user.RoleIDs = append(user.RoleIDs, r.Id)
+	}
+
+	if entity.PasswordUpdateTime != nil {
+		user.PasswordUpdateTime = entity.PasswordUpdateTime.Format("2006-01-02 15:04:05")
+	}
 	if entity.CreateTime != nil {
 		user.CreateTime = entity.CreateTime.Format("2006-01-02 15:04:05")
 	}
@@ -0,0 +1,102 @@
+/**
+ * Description：
+ * FileName：user_identity.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 06:58:05
+ * Remark：
+ */
+
+package system
+
+import (
+	"context"
+	domainSystem "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/system"
+	modelSystem "github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/system"
+	cacheSystem "github.com/carefuly/carefuly-admin-go-gin/internal/repository/cache/careful/system"
+	daoSystem "github.com/carefuly/carefuly-admin-go-gin/internal/repository/dao/careful/system"
+	"time"
+)
+
+var (
+	ErrUserIdentityNotFound = daoSystem.ErrUserIdentityNotFound
+	ErrSSOStateNotFound     = cacheSystem.ErrSSOStateNotExist
+)
+
+type UserIdentityRepository interface {
+	Create(ctx context.Context, domain domainSystem.UserIdentity) error
+	UpdateLastLogin(ctx context.Context, id, email string, loginTime time.Time) error
+
+	GetByProviderSubject(ctx context.Context, provider, subject string) (domainSystem.UserIdentity, error)
+	GetListByUserId(ctx context.Context, userId string) ([]domainSystem.UserIdentity, error)
+
+	SaveState(ctx context.Context, state string, domain domainSystem.SSOState, expiration time.Duration) error
+	TakeState(ctx context.Context, state string) (domainSystem.SSOState, error)
+}
+
+type userIdentityRepository struct {
+	dao   daoSystem.UserIdentityDAO
+	cache cacheSystem.SSOStateCache
+}
+
+func NewUserIdentityRepository(dao daoSystem.UserIdentityDAO, cache cacheSystem.SSOStateCache) UserIdentityRepository {
+	return &userIdentityRepository{
+		dao:   dao,
+		cache: cache,
+	}
+}
+
+// Create 新增
+func (repo *userIdentityRepository) Create(ctx context.Context, domain domainSystem.UserIdentity) error {
+	return repo.dao.Insert(ctx, domain.UserIdentity)
+}
+
+// UpdateLastLogin 记录最近登录
+func (repo *userIdentityRepository) UpdateLastLogin(ctx context.Context, id, email string, loginTime time.Time) error {
+	return repo.dao.UpdateLastLogin(ctx, id, email, loginTime)
+}
+
+// GetByProviderSubject 根据身份提供方及第三方用户标识查询
+func (repo *userIdentityRepository) GetByProviderSubject(ctx context.Context, provider, subject string) (domainSystem.UserIdentity, error) {
+	model, err := repo.dao.FindByProviderSubject(ctx, provider, subject)
+	if err != nil {
+		return domainSystem.UserIdentity{}, err
+	}
+	return repo.toDomain(model), nil
+}
+
+// GetListByUserId 查询用户关联的第三方身份
+func (repo *userIdentityRepository) GetListByUserId(ctx context.Context, userId string) ([]domainSystem.UserIdentity, error) {
+	models, err := repo.dao.FindListByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]domainSystem.UserIdentity, 0, len(models))
+	for _, model := range models {
+		list = append(list, repo.toDomain(model))
+	}
+	return list, nil
+}
+
+// SaveState 保存授权请求
+func (repo *userIdentityRepository) SaveState(ctx context.Context, state string, domain domainSystem.SSOState, expiration time.Duration) error {
+	return repo.cache.Set(ctx, state, domain, expiration)
+}
+
+// TakeState 获取并删除授权请求
+func (repo *userIdentityRepository) TakeState(ctx context.Context, state string) (domainSystem.SSOState, error) {
+	return repo.cache.Take(ctx, state)
+}
+
+func (repo *userIdentityRepository) toDomain(entity *modelSystem.UserIdentity) domainSystem.UserIdentity {
+	domain := domainSystem.UserIdentity{
+		UserIdentity: *entity,
+	}
+
+	if entity.LastLoginTime != nil {
+		domain.LastLoginTime = entity.LastLoginTime.Format("2006-01-02 15:04:05")
+	}
+	if entity.CreateTime != nil {
+		domain.CreateTime = entity.CreateTime.Format("2006-01-02 15:04:05")
+	}
+	return domain
+}
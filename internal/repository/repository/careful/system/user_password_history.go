@@ -0,0 +1,48 @@
+/**
+ * Description：
+ * FileName：user_password_history.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 01:38:54
+ * Remark：
+ */
+
+package system
+
+import (
+	"context"
+	modelSystem "github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/system"
+	daoSystem "github.com/carefuly/carefuly-admin-go-gin/internal/repository/dao/careful/system"
+)
+
+type UserPasswordHistoryRepository interface {
+	// Add 记录历史密码，仅保留最近 keep 条
+	Add(ctx context.Context, userId, hashedPassword string, keep int) error
+	GetRecentPasswords(ctx context.Context, userId string, limit int) ([]string, error)
+}
+
+type userPasswordHistoryRepository struct {
+	dao daoSystem.UserPasswordHistoryDAO
+}
+
+func NewUserPasswordHistoryRepository(dao daoSystem.UserPasswordHistoryDAO) UserPasswordHistoryRepository {
+	return &userPasswordHistoryRepository{
+		dao: dao,
+	}
+}
+
+// Add 记录历史密码
+func (repo *userPasswordHistoryRepository) Add(ctx context.Context, userId, hashedPassword string, keep int) error {
+	err := repo.dao.Insert(ctx, modelSystem.UserPasswordHistory{
+		UserId:   userId,
+		Password: hashedPassword,
+	})
+	if err != nil {
+		return err
+	}
+	return repo.dao.DeleteExceptRecent(ctx, userId, keep)
+}
+
+// GetRecentPasswords 查询最近使用过的密码(密文)
+func (repo *userPasswordHistoryRepository) GetRecentPasswords(ctx context.Context, userId string, limit int) ([]string, error) {
+	return repo.dao.FindRecentPasswords(ctx, userId, limit)
+}
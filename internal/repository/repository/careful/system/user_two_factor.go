@@ -0,0 +1,124 @@
+/**
+ * Description：
+ * FileName：user_two_factor.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 22:16:50
+ * Remark：
+ */
+
+package system
+
+import (
+	"context"
+	domainSystem "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/system"
+	modelSystem "github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/system"
+	cacheSystem "github.com/carefuly/carefuly-admin-go-gin/internal/repository/cache/careful/system"
+	daoSystem "github.com/carefuly/carefuly-admin-go-gin/internal/repository/dao/careful/system"
+	"time"
+)
+
+var (
+	ErrUserTwoFactorNotFound      = daoSystem.ErrUserTwoFactorNotFound
+	ErrTwoFactorChallengeNotFound = cacheSystem.ErrTwoFactorChallengeNotExist
+)
+
+type UserTwoFactorRepository interface {
+	Save(ctx context.Context, domain domainSystem.UserTwoFactor) error
+	Enable(ctx context.Context, userId string, counter int64, recoveryCodes string) (bool, error)
+	UpdateLastCounter(ctx context.Context, userId string, counter int64) (bool, error)
+	UpdateRecoveryCodes(ctx context.Context, userId, oldCodes, newCodes string) (bool, error)
+	DeleteByUserId(ctx context.Context, userId string) error
+
+	GetByUserId(ctx context.Context, userId string) (domainSystem.UserTwoFactor, error)
+	HasRequiredRole(ctx context.Context, userId string) (bool, error)
+
+	CreateChallenge(ctx context.Context, token, userId string, expiration time.Duration) error
+	GetChallenge(ctx context.Context, token string) (string, error)
+	IncrChallengeAttempts(ctx context.Context, token string) (int64, error)
+	DeleteChallenge(ctx context.Context, token string) error
+}
+
+type userTwoFactorRepository struct {
+	dao   daoSystem.UserTwoFactorDAO
+	cache cacheSystem.TwoFactorChallengeCache
+}
+
+func NewUserTwoFactorRepository(dao daoSystem.UserTwoFactorDAO, cache cacheSystem.TwoFactorChallengeCache) UserTwoFactorRepository {
+	return &userTwoFactorRepository{
+		dao:   dao,
+		cache: cache,
+	}
+}
+
+// Save 保存绑定信息
+func (repo *userTwoFactorRepository) Save(ctx context.Context, domain domainSystem.UserTwoFactor) error {
+	return repo.dao.Upsert(ctx, domain.UserTwoFactor)
+}
+
+// Enable 启用
+func (repo *userTwoFactorRepository) Enable(ctx context.Context, userId string, counter int64, recoveryCodes string) (bool, error) {
+	return repo.dao.Enable(ctx, userId, counter, recoveryCodes)
+}
+
+// UpdateLastCounter 记录已使用的时间步
+func (repo *userTwoFactorRepository) UpdateLastCounter(ctx context.Context, userId string, counter int64) (bool, error) {
+	return repo.dao.UpdateLastCounter(ctx, userId, counter)
+}
+
+// UpdateRecoveryCodes 替换恢复码
+func (repo *userTwoFactorRepository) UpdateRecoveryCodes(ctx context.Context, userId, oldCodes, newCodes string) (bool, error) {
+	return repo.dao.UpdateRecoveryCodes(ctx, userId, oldCodes, newCodes)
+}
+
+// DeleteByUserId 删除
+func (repo *userTwoFactorRepository) DeleteByUserId(ctx context.Context, userId string) error {
+	return repo.dao.DeleteByUserId(ctx, userId)
+}
+
+// GetByUserId 根据用户ID查询
+func (repo *userTwoFactorRepository) GetByUserId(ctx context.Context, userId string) (domainSystem.UserTwoFactor, error) {
+	model, err := repo.dao.FindByUserId(ctx, userId)
+	if err != nil {
+		return domainSystem.UserTwoFactor{}, err
+	}
+	return repo.toDomain(model), nil
+}
+
+// HasRequiredRole 用户是否拥有要求双因素认证的角色
+func (repo *userTwoFactorRepository) HasRequiredRole(ctx context.Context, userId string) (bool, error) {
+	return repo.dao.HasRequiredRole(ctx, userId)
+}
+
+// CreateChallenge 创建登录二次验证
+func (repo *userTwoFactorRepository) CreateChallenge(ctx context.Context, token, userId string, expiration time.Duration) error {
+	return repo.cache.Set(ctx, token, userId, expiration)
+}
+
+// GetChallenge 获取登录二次验证对应的用户ID
+func (repo *userTwoFactorRepository) GetChallenge(ctx context.Context, token string) (string, error) {
+	return repo.cache.Get(ctx, token)
+}
+
+// IncrChallengeAttempts 增加登录二次验证尝试次数
+func (repo *userTwoFactorRepository) IncrChallengeAttempts(ctx context.Context, token string) (int64, error) {
+	return repo.cache.IncrAttempts(ctx, token)
+}
+
+// DeleteChallenge 删除登录二次验证
+func (repo *userTwoFactorRepository) DeleteChallenge(ctx context.Context, token string) error {
+	return repo.cache.Del(ctx, token)
+}
+
+func (repo *userTwoFactorRepository) toDomain(entity *modelSystem.UserTwoFactor) domainSystem.UserTwoFactor {
+	domain := domainSystem.UserTwoFactor{
+		UserTwoFactor: *entity,
+	}
+
+	if entity.CreateTime != nil {
+		domain.CreateTime = entity.CreateTime.Format("2006-01-02 15:04:05")
+	}
+	if entity.UpdateTime != nil {
+		domain.UpdateTime = entity.UpdateTime.Format("2006-01-02 15:04:05")
+	}
+	return domain
+}
@@ -11,6 +11,7 @@ package third
 import (
 	"context"
 	"github.com/carefuly/carefuly-admin-go-gin/internal/repository/cache/careful/third"
+	"time"
 )
 
 var (
@@ -23,6 +24,8 @@ var (
 
 type CaptchaRepository interface {
 	Set(ctx context.Context, id, code string, bizType string) error
+	// SetWithExpire 按指定有效期及重新发送间隔保存验证码
+	SetWithExpire(ctx context.Context, id, code string, bizType string, expire, interval time.Duration) error
 	Verify(ctx context.Context, id string, biz string, code string) (bool, error)
 }
 
@@ -40,6 +43,10 @@ func (repo *captchaRepository) Set(ctx context.Context, id, code string, bizType
 	return repo.cache.Set(ctx, id, code, bizType)
 }
 
+func (repo *captchaRepository) SetWithExpire(ctx context.Context, id, code string, bizType string, expire, interval time.Duration) error {
+	return repo.cache.SetWithExpire(ctx, id, code, bizType, expire, interval)
+}
+
 func (repo *captchaRepository) Verify(ctx context.Context, id string, biz string, code string) (bool, error) {
 	return repo.cache.Verify(ctx, id, biz, code)
 }
@@ -16,6 +16,7 @@ import (
 	cacheTools "github.com/carefuly/carefuly-admin-go-gin/internal/repository/cache/careful/tools"
 	cacheDecorator "github.com/carefuly/carefuly-admin-go-gin/internal/repository/cache/decorator/careful/tools"
 	daoTools "github.com/carefuly/carefuly-admin-go-gin/internal/repository/dao/careful/tools"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/filters"
 	"github.com/carefuly/carefuly-admin-go-gin/pkg/models"
 	"go.uber.org/zap"
 )
@@ -106,6 +107,10 @@ func (repo *bucketRepository) Update(ctx context.Context, domain domainTools.Buc
 func (repo *bucketRepository) GetById(ctx context.Context, id string) (domainTools.Bucket, error) {
 	domain, err := repo.cache.Get(ctx, id)
 	if err == nil && domain != nil {
+		// 命中缓存，校验数据权限范围
+		if scope, ok := filters.DataScopeFromContext(ctx); ok && !scope.Allow(domain.Creator, domain.BelongDept) {
+			return domainTools.Bucket{}, nil
+		}
 		return *domain, nil // 命中缓存
 	}
 
@@ -117,8 +122,10 @@ func (repo *bucketRepository) GetById(ctx context.Context, id string) (domainToo
 	entity, err := repo.dao.FindById(ctx, id)
 	if err != nil {
 		if errors.Is(err, daoTools.ErrBucketNotFound) {
-			// 数据库不存在，设置防穿透标记
-			_ = repo.cache.SetNotFound(ctx, id)
+			// 数据库不存在，设置防穿透标记(受数据权限限制时仅代表当前用户不可见)
+			if !filters.DataScopeRestricted(ctx) {
+				_ = repo.cache.SetNotFound(ctx, id)
+			}
 			return domainTools.Bucket{}, daoTools.ErrBucketNotFound
 		}
 		return domainTools.Bucket{}, err
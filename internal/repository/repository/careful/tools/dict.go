@@ -15,6 +15,7 @@ import (
 	modelTools "github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/tools"
 	cacheTools "github.com/carefuly/carefuly-admin-go-gin/internal/repository/cache/careful/tools"
 	daoTools "github.com/carefuly/carefuly-admin-go-gin/internal/repository/dao/careful/tools"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/filters"
 	"github.com/carefuly/carefuly-admin-go-gin/pkg/models"
 	"go.uber.org/zap"
 )
@@ -119,6 +120,10 @@ func (repo *dictRepository) Update(ctx context.Context, domain domainTools.Dict)
 func (repo *dictRepository) GetById(ctx context.Context, id string) (domainTools.Dict, error) {
 	domain, err := repo.cache.Get(ctx, id)
 	if err == nil && domain != nil {
+		// 命中缓存，校验数据权限范围
+		if scope, ok := filters.DataScopeFromContext(ctx); ok && !scope.Allow(domain.Creator, domain.BelongDept) {
+			return domainTools.Dict{}, nil
+		}
 		return *domain, nil // 命中缓存
 	}
 	if err != nil && !errors.Is(err, cacheTools.ErrDictNotExist) {
@@ -129,8 +134,10 @@ func (repo *dictRepository) GetById(ctx context.Context, id string) (domainTools
 	entity, err := repo.dao.FindById(ctx, id)
 	if err != nil {
 		if errors.Is(err, daoTools.ErrDictNotFound) {
-			// 数据库不存在，设置防穿透标记
-			_ = repo.cache.SetNotFound(ctx, id)
+			// 数据库不存在，设置防穿透标记(受数据权限限制时仅代表当前用户不可见)
+			if !filters.DataScopeRestricted(ctx) {
+				_ = repo.cache.SetNotFound(ctx, id)
+			}
 			return domainTools.Dict{}, nil
 		}
 		return domainTools.Dict{}, err
@@ -16,6 +16,7 @@ import (
 	modelTools "github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/tools"
 	cacheTools "github.com/carefuly/carefuly-admin-go-gin/internal/repository/cache/careful/tools"
 	daoTools "github.com/carefuly/carefuly-admin-go-gin/internal/repository/dao/careful/tools"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/filters"
 	"github.com/carefuly/carefuly-admin-go-gin/pkg/models"
 	"go.uber.org/zap"
 )
@@ -125,6 +126,10 @@ func (repo *dictTypeRepository) Update(ctx context.Context, domain domainTools.D
 func (repo *dictTypeRepository) GetById(ctx context.Context, id string) (domainTools.DictType, error) {
 	domain, err := repo.cache.Get(ctx, id)
 	if err == nil && domain != nil {
+		// 命中缓存，校验数据权限范围
+		if scope, ok := filters.DataScopeFromContext(ctx); ok && !scope.Allow(domain.Creator, domain.BelongDept) {
+			return domainTools.DictType{}, nil
+		}
 		return *domain, nil // 命中缓存
 	}
 	if err != nil && !errors.Is(err, cacheTools.ErrDictTypeNotExist) {
@@ -135,8 +140,10 @@ func (repo *dictTypeRepository) GetById(ctx context.Context, id string) (domainT
 	entity, err := repo.dao.FindById(ctx, id)
 	if err != nil {
 		if errors.Is(err, daoTools.ErrDictNotFound) {
-			// 数据库不存在，设置防穿透标记
-			_ = repo.cache.SetNotFound(ctx, id)
+			// 数据库不存在，设置防穿透标记(受数据权限限制时仅代表当前用户不可见)
+			if !filters.DataScopeRestricted(ctx) {
+				_ = repo.cache.SetNotFound(ctx, id)
+			}
 			return domainTools.DictType{}, nil
 		}
 		return domainTools.DictType{}, err
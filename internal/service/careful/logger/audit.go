@@ -0,0 +1,45 @@
+/**
+ * Description：
+ * FileName：audit.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 17:08:20
+ * Remark：
+ */
+
+package logger
+
+import (
+	"context"
+	domainLogger "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/logger"
+	repositoryLogger "github.com/carefuly/carefuly-admin-go-gin/internal/repository/repository/careful/logger"
+	"go.uber.org/zap"
+)
+
+type AuditLogService interface {
+	// Record 记录审计事件，写入失败不影响业务流程
+	Record(ctx context.Context, domain domainLogger.AuditLog)
+}
+
+type auditLogService struct {
+	repo repositoryLogger.AuditLogRepository
+}
+
+func NewAuditLogService(repo repositoryLogger.AuditLogRepository) AuditLogService {
+	return &auditLogService{
+		repo: repo,
+	}
+}
+
+// Record 记录审计事件
+func (svc *auditLogService) Record(ctx context.Context, domain domainLogger.AuditLog) {
+	domain.Creator = domain.OperatorId
+	domain.Modifier = domain.OperatorId
+
+	if err := svc.repo.Create(ctx, domain); err != nil {
+		zap.L().Error("审计日志记录失败",
+			zap.String("event", string(domain.Event)),
+			zap.String("username", domain.Username),
+			zap.Error(err),
+		)
+	}
+}
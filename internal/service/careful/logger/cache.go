@@ -0,0 +1,155 @@
+/**
+ * Description：
+ * FileName：cache.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 09:30:17
+ * Remark：
+ */
+
+package logger
+
+import (
+	"context"
+	domainLogger "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/logger"
+	repositoryLogger "github.com/carefuly/carefuly-admin-go-gin/internal/repository/repository/careful/logger"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/constants/careful/logger/cache"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// cacheStatsSampleLimit 耗时分位数取最近的记录数，避免统计范围较大时加载全部日志
+const cacheStatsSampleLimit = 10000
+
+type CacheLogService interface {
+	GetListPage(ctx context.Context, filter domainLogger.CacheLogFilter) ([]domainLogger.CacheLog, int64, error)
+	// Stats 按缓存key键前缀(去掉最后一段)统计命中率、异常次数及耗时分位数
+	// 次数由数据库分组统计，耗时分位数按最近 cacheStatsSampleLimit 条记录计算
+	Stats(ctx context.Context, filter domainLogger.CacheLogFilter) ([]domainLogger.CacheStats, error)
+}
+
+type cacheLogService struct {
+	repo repositoryLogger.CacheLogRepository
+}
+
+func NewCacheLogService(repo repositoryLogger.CacheLogRepository) CacheLogService {
+	return &cacheLogService{
+		repo: repo,
+	}
+}
+
+// GetListPage 分页查询
+func (svc *cacheLogService) GetListPage(ctx context.Context, filter domainLogger.CacheLogFilter) ([]domainLogger.CacheLog, int64, error) {
+	return svc.repo.GetListPage(ctx, filter)
+}
+
+// Stats 统计
+func (svc *cacheLogService) Stats(ctx context.Context, filter domainLogger.CacheLogFilter) ([]domainLogger.CacheStats, error) {
+	groups, err := svc.repo.GetStatGroups(ctx, filter)
+	if err != nil {
+		return []domainLogger.CacheStats{}, err
+	}
+	samples, err := svc.repo.GetTimeSample(ctx, filter, cacheStatsSampleLimit)
+	if err != nil {
+		return []domainLogger.CacheStats{}, err
+	}
+	return cacheStats(groups, samples), nil
+}
+
+// cacheStats 汇总分组计数并计算耗时分位数，未记录缓存操作的历史数据只计入总数、异常次数及耗时
+func cacheStats(list []domainLogger.CacheStatGroup, samples []domainLogger.CacheLog) []domainLogger.CacheStats {
+	groups := make(map[string]*domainLogger.CacheStats)
+	for _, v := range list {
+		stats, ok := groups[v.Prefix]
+		if !ok {
+			stats = &domainLogger.CacheStats{Prefix: v.Prefix}
+			groups[v.Prefix] = stats
+		}
+
+		stats.Total += v.Total
+		stats.Errors += v.Errors
+		switch v.CacheOperation {
+		case cache.OperationConstGet:
+			stats.Gets += v.Total
+			switch v.CacheResult {
+			case cache.ResultConstHit:
+				stats.Hits += v.Total
+			case cache.ResultConstMiss:
+				stats.Misses += v.Total
+			case cache.ResultConstNotFound:
+				stats.NotFound += v.Total
+			}
+		case cache.OperationConstSet, cache.OperationConstSetNotFound:
+			stats.Sets += v.Total
+		case cache.OperationConstDel:
+			stats.Dels += v.Total
+		}
+	}
+
+	durations := make(map[string][]time.Duration)
+	for _, v := range samples {
+		if d, ok := parseCacheTime(v.CacheTime); ok {
+			prefix := cacheKeyPrefix(v.CacheKey)
+			durations[prefix] = append(durations[prefix], d)
+		}
+	}
+
+	result := make([]domainLogger.CacheStats, 0, len(groups))
+	for prefix, stats := range groups {
+		if stats.Gets > 0 {
+			stats.HitRatio = ratio(stats.Hits, stats.Gets)
+			stats.MissRatio = ratio(stats.Misses, stats.Gets)
+			stats.NotFoundRatio = ratio(stats.NotFound, stats.Gets)
+		}
+		stats.P50 = percentile(durations[prefix], 0.50)
+		stats.P95 = percentile(durations[prefix], 0.95)
+		result = append(result, *stats)
+	}
+
+	// 按操作总数降序
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Total != result[j].Total {
+			return result[i].Total > result[j].Total
+		}
+		return result[i].Prefix < result[j].Prefix
+	})
+	return result
+}
+
+// cacheKeyPrefix 缓存key键前缀，如 careful:tools:bucket:info:1 的前缀为 careful:tools:bucket:info，与分组统计的 SQL 保持一致
+func cacheKeyPrefix(key string) string {
+	if i := strings.LastIndex(key, ":"); i > 0 {
+		return key[:i]
+	}
+	return key
+}
+
+// parseCacheTime 解析耗时，兼容历史数据中以 " | " 开头的格式
+func parseCacheTime(value string) (time.Duration, bool) {
+	value = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(value), "|"))
+	if value == "" {
+		return 0, false
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, false
+	}
+	return d, true
+}
+
+// percentile 最近秩法计算分位数，单位毫秒
+func percentile(durations []time.Duration, p float64) float64 {
+	if len(durations) == 0 {
+		return 0
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	rank := int(math.Ceil(p*float64(len(durations)))) - 1
+	rank = max(0, min(rank, len(durations)-1))
+	return math.Round(float64(durations[rank])/float64(time.Millisecond)*1000) / 1000
+}
+
+// ratio 比例，保留四位小数
+func ratio(n, total int64) float64 {
+	return math.Round(float64(n)/float64(total)*10000) / 10000
+}
@@ -0,0 +1,81 @@
+/**
+ * Description：
+ * FileName：cache_test.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 09:50:33
+ * Remark：
+ */
+
+package logger
+
+import (
+	domainLogger "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/logger"
+	modelLogger "github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/logger"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/constants/careful/logger/cache"
+	"testing"
+)
+
+func cacheGroup(prefix string, operation cache.OperationConst, result cache.ResultConst, total, errors int64) domainLogger.CacheStatGroup {
+	return domainLogger.CacheStatGroup{CacheStatGroup: modelLogger.CacheStatGroup{
+		Prefix:         prefix,
+		CacheOperation: operation,
+		CacheResult:    result,
+		Total:          total,
+		Errors:         errors,
+	}}
+}
+
+func cacheSample(key, cacheTime string) domainLogger.CacheLog {
+	return domainLogger.CacheLog{CacheLogger: modelLogger.CacheLogger{CacheKey: key, CacheTime: cacheTime}}
+}
+
+func TestCacheStats(t *testing.T) {
+	groups := []domainLogger.CacheStatGroup{
+		cacheGroup("careful:tools:bucket:info", cache.OperationConstGet, cache.ResultConstHit, 2, 0),
+		cacheGroup("careful:tools:bucket:info", cache.OperationConstGet, cache.ResultConstMiss, 1, 0),
+		cacheGroup("careful:tools:bucket:info", cache.OperationConstGet, cache.ResultConstNotFound, 1, 0),
+		cacheGroup("careful:tools:bucket:info", cache.OperationConstSet, cache.ResultConstOk, 1, 0),
+		cacheGroup("careful:tools:bucket:info", cache.OperationConstSetNotFound, cache.ResultConstOk, 1, 0),
+		cacheGroup("careful:tools:bucket:info", cache.OperationConstDel, cache.ResultConstError, 1, 1),
+		// 历史数据：未记录操作
+		cacheGroup("careful:tools:bucket:info", "", "", 3, 0),
+		cacheGroup("careful:system:user", cache.OperationConstGet, cache.ResultConstHit, 1, 0),
+	}
+	samples := []domainLogger.CacheLog{
+		cacheSample("careful:tools:bucket:info:1", "1ms"),
+		cacheSample("careful:tools:bucket:info:2", "2ms"),
+		cacheSample("careful:tools:bucket:info:3", "3ms"),
+		cacheSample("careful:tools:bucket:info:4", "4ms"),
+		cacheSample("careful:tools:bucket:info:3", "5ms"),
+		cacheSample("careful:tools:bucket:info:4", "6ms"),
+		cacheSample("careful:tools:bucket:info:1", "7ms"),
+		// 历史数据：耗时以 " | " 开头
+		cacheSample("careful:tools:bucket:info:5", " | 8ms"),
+		cacheSample("careful:tools:bucket:info:5", "9ms"),
+		cacheSample("careful:tools:bucket:info:5", "10ms"),
+		cacheSample("careful:system:user:1", "500µs"),
+	}
+
+	stats := cacheStats(groups, samples)
+	if len(stats) != 2 {
+		t.Fatalf("stats = %+v", stats)
+	}
+
+	bucket := stats[0]
+	if bucket.Prefix != "careful:tools:bucket:info" || bucket.Total != 10 || bucket.Gets != 4 ||
+		bucket.Hits != 2 || bucket.Misses != 1 || bucket.NotFound != 1 ||
+		bucket.Sets != 2 || bucket.Dels != 1 || bucket.Errors != 1 {
+		t.Fatalf("bucket = %+v", bucket)
+	}
+	if bucket.HitRatio != 0.5 || bucket.MissRatio != 0.25 || bucket.NotFoundRatio != 0.25 {
+		t.Fatalf("ratio = %v %v %v", bucket.HitRatio, bucket.MissRatio, bucket.NotFoundRatio)
+	}
+	if bucket.P50 != 5 || bucket.P95 != 10 {
+		t.Fatalf("p50 = %v, p95 = %v", bucket.P50, bucket.P95)
+	}
+
+	user := stats[1]
+	if user.Prefix != "careful:system:user" || user.HitRatio != 1 || user.P50 != 0.5 {
+		t.Fatalf("user = %+v", user)
+	}
+}
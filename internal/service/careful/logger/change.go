@@ -0,0 +1,171 @@
+/**
+ * Description：
+ * FileName：change.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 16:33:40
+ * Remark：
+ */
+
+package logger
+
+import (
+	"context"
+	"errors"
+	domainLogger "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/logger"
+	modelSystem "github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/system"
+	repositoryLogger "github.com/carefuly/carefuly-admin-go-gin/internal/repository/repository/careful/logger"
+	repositorySystem "github.com/carefuly/carefuly-admin-go-gin/internal/repository/repository/careful/system"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/filters"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/changelog"
+	"go.uber.org/zap"
+	"time"
+)
+
+var (
+	ErrChangeLogNotFound       = repositoryLogger.ErrChangeLogNotFound
+	ErrChangeLogRestoreEmpty   = errors.New("变更记录没有可恢复的数据")
+	ErrChangeLogAlreadyCurrent = errors.New("数据已是该版本")
+	ErrChangeLogRestoreDenied  = errors.New("该数据不支持恢复")
+	ErrChangeLogUserDeleted    = errors.New("已删除的用户不支持恢复")
+	ErrChangeLogNoPermission   = errors.New("无权恢复该数据")
+)
+
+// restoreDeniedTables 不支持恢复的表，恢复会重新启用已撤销的密钥、已关闭的两步验证或已解除的第三方账号关联
+var restoreDeniedTables = map[string]bool{
+	new(modelSystem.ApiKey).TableName():        true,
+	new(modelSystem.UserTwoFactor).TableName(): true,
+	new(modelSystem.UserIdentity).TableName():  true,
+}
+
+// restoreUserTable 用户表，主键视为本人数据；用户的角色、岗位关联不记录变更，删除后不支持恢复
+var restoreUserTable = new(modelSystem.User).TableName()
+
+// restoreSkipFields 恢复时不覆盖的字段，由恢复操作重新设置
+var restoreSkipFields = map[string]bool{
+	"id":          true,
+	"version":     true,
+	"modifier":    true,
+	"create_time": true,
+	"update_time": true,
+}
+
+type ChangeLogService interface {
+	GetById(ctx context.Context, id string) (domainLogger.ChangeLog, error)
+	// GetHistory 数据的全部变更记录，最近的在前
+	GetHistory(ctx context.Context, table, entityId string) ([]domainLogger.ChangeLog, error)
+	GetListPage(ctx context.Context, filter domainLogger.ChangeLogFilter) ([]domainLogger.ChangeLog, int64, error)
+	// Restore 将数据恢复为变更记录 id 对应的版本
+	// 新增、修改、恢复记录恢复为变更后的数据，删除记录恢复为删除前的数据(重新创建)
+	// 不记录的字段(如密码)不会被恢复，重新创建时使用数据库默认值
+	// 密钥、两步验证、第三方账号关联及已删除的用户不支持恢复，恢复前后的数据均需在数据权限范围内
+	Restore(ctx context.Context, id, modifier string) error
+}
+
+type changeLogService struct {
+	repo           repositoryLogger.ChangeLogRepository
+	permissionRepo repositorySystem.PermissionRepository
+}
+
+func NewChangeLogService(repo repositoryLogger.ChangeLogRepository, permissionRepo repositorySystem.PermissionRepository) ChangeLogService {
+	return &changeLogService{
+		repo:           repo,
+		permissionRepo: permissionRepo,
+	}
+}
+
+// GetById 获取详情
+func (svc *changeLogService) GetById(ctx context.Context, id string) (domainLogger.ChangeLog, error) {
+	domain, err := svc.repo.GetById(ctx, id)
+	if err != nil {
+		if errors.Is(err, repositoryLogger.ErrChangeLogNotFound) {
+			return domain, ErrChangeLogNotFound
+		}
+		return domain, err
+	}
+	return domain, nil
+}
+
+// GetHistory 获取数据的变更历史
+func (svc *changeLogService) GetHistory(ctx context.Context, table, entityId string) ([]domainLogger.ChangeLog, error) {
+	return svc.repo.GetHistory(ctx, table, entityId)
+}
+
+// GetListPage 分页查询
+func (svc *changeLogService) GetListPage(ctx context.Context, filter domainLogger.ChangeLogFilter) ([]domainLogger.ChangeLog, int64, error) {
+	return svc.repo.GetListPage(ctx, filter)
+}
+
+// Restore 恢复历史版本
+func (svc *changeLogService) Restore(ctx context.Context, id, modifier string) error {
+	target, err := svc.GetById(ctx, id)
+	if err != nil {
+		return err
+	}
+	if restoreDeniedTables[target.EntityTable] {
+		return ErrChangeLogRestoreDenied
+	}
+
+	snapshot := restoreSnapshot(target)
+	if len(snapshot) == 0 {
+		return ErrChangeLogRestoreEmpty
+	}
+
+	latest, err := svc.repo.GetLatest(ctx, target.EntityTable, target.EntityId)
+	if err != nil {
+		return err
+	}
+	if target.EntityTable == restoreUserTable && latest.Action == changelog.ActionDelete {
+		return ErrChangeLogUserDeleted
+	}
+	// 恢复的版本及数据的当前版本均需在数据权限范围内
+	if !allowRestore(ctx, target, snapshot) || !allowRestore(ctx, latest, restoreSnapshot(latest)) {
+		return ErrChangeLogNoPermission
+	}
+	// 最近一次变更即为该版本且数据未被删除
+	if latest.Id == target.Id && target.Action != changelog.ActionDelete {
+		return ErrChangeLogAlreadyCurrent
+	}
+
+	values := make(map[string]any, len(snapshot)+2)
+	for field, value := range snapshot {
+		if restoreSkipFields[field] {
+			continue
+		}
+		values[field] = value
+	}
+	values["modifier"] = modifier
+	values["update_time"] = time.Now()
+
+	if err := svc.repo.Restore(ctx, target.EntityTable, target.EntityId, values, latest.EntityVersion+1); err != nil {
+		return err
+	}
+
+	// 用户、角色、菜单、部门及其关联均会影响权限，恢复后清理全部用户的权限缓存
+	if err := svc.permissionRepo.DelAll(ctx); err != nil {
+		zap.L().Error("清理权限缓存失败", zap.String("table", target.EntityTable), zap.Error(err))
+	}
+	return nil
+}
+
+// restoreSnapshot 变更记录对应版本的数据，删除记录为删除前的数据
+func restoreSnapshot(log domainLogger.ChangeLog) map[string]any {
+	if log.Action == changelog.ActionDelete {
+		return log.OldValues
+	}
+	return log.NewValues
+}
+
+// allowRestore 数据是否在数据权限范围内，用户表主键视为本人数据
+func allowRestore(ctx context.Context, log domainLogger.ChangeLog, values map[string]any) bool {
+	scope, ok := filters.DataScopeFromContext(ctx)
+	if !ok {
+		return true
+	}
+	var owners []string
+	if log.EntityTable == restoreUserTable {
+		owners = append(owners, log.EntityId)
+	}
+	creator, _ := values["creator"].(string)
+	belongDept, _ := values["belong_dept"].(string)
+	return scope.Allow(creator, belongDept, owners...)
+}
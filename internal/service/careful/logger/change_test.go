@@ -0,0 +1,179 @@
+/**
+ * Description：
+ * FileName：change_test.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 17:26:09
+ * Remark：
+ */
+
+package logger
+
+import (
+	"context"
+	"errors"
+	domainLogger "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/logger"
+	repositoryLogger "github.com/carefuly/carefuly-admin-go-gin/internal/repository/repository/careful/logger"
+	repositorySystem "github.com/carefuly/carefuly-admin-go-gin/internal/repository/repository/careful/system"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/filters"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/changelog"
+	"testing"
+)
+
+type stubChangeLogRepository struct {
+	repositoryLogger.ChangeLogRepository
+	logs    map[string]domainLogger.ChangeLog
+	latest  string
+	values  map[string]any
+	version int
+}
+
+func (r *stubChangeLogRepository) GetById(ctx context.Context, id string) (domainLogger.ChangeLog, error) {
+	log, ok := r.logs[id]
+	if !ok {
+		return domainLogger.ChangeLog{}, repositoryLogger.ErrChangeLogNotFound
+	}
+	return log, nil
+}
+
+func (r *stubChangeLogRepository) GetLatest(ctx context.Context, table, entityId string) (domainLogger.ChangeLog, error) {
+	return r.logs[r.latest], nil
+}
+
+func (r *stubChangeLogRepository) Restore(ctx context.Context, table, entityId string, values map[string]any, version int) error {
+	r.values = values
+	r.version = version
+	return nil
+}
+
+// stubPermissionRepository 记录权限缓存清理次数
+type stubPermissionRepository struct {
+	repositorySystem.PermissionRepository
+	cleared int
+}
+
+func (r *stubPermissionRepository) DelAll(ctx context.Context) error {
+	r.cleared++
+	return nil
+}
+
+func newChangeLog(id, action string, version int, oldValues, newValues map[string]any) domainLogger.ChangeLog {
+	log := domainLogger.ChangeLog{OldValues: oldValues, NewValues: newValues}
+	log.Id = id
+	log.Action = action
+	log.EntityTable = "careful_system_post"
+	log.EntityId = "P1"
+	log.EntityVersion = version
+	return log
+}
+
+func TestChangeLogService_Restore(t *testing.T) {
+	v1 := map[string]any{"id": "P1", "name": "v1", "version": 1, "modifier": "U0", "create_time": "t", "update_time": "t"}
+	v2 := map[string]any{"id": "P1", "name": "v2", "version": 2}
+	repo := &stubChangeLogRepository{
+		logs: map[string]domainLogger.ChangeLog{
+			"1": newChangeLog("1", changelog.ActionCreate, 1, nil, v1),
+			"2": newChangeLog("2", changelog.ActionUpdate, 2, v1, v2),
+			"3": newChangeLog("3", changelog.ActionDelete, 2, v2, nil),
+		},
+		latest: "2",
+	}
+	permissionRepo := &stubPermissionRepository{}
+	svc := NewChangeLogService(repo, permissionRepo)
+	ctx := context.Background()
+
+	// 恢复为新增时的版本，审计字段由恢复操作重新设置
+	if err := svc.Restore(ctx, "1", "U1"); err != nil {
+		t.Fatal(err)
+	}
+	if repo.values["name"] != "v1" || repo.values["modifier"] != "U1" || repo.version != 3 {
+		t.Fatalf("values = %v, version = %d", repo.values, repo.version)
+	}
+	for _, field := range []string{"id", "version", "create_time"} {
+		if _, ok := repo.values[field]; ok {
+			t.Errorf("恢复时覆盖了字段 %s", field)
+		}
+	}
+	if permissionRepo.cleared != 1 {
+		t.Fatalf("恢复后应清理权限缓存，实际清理 %d 次", permissionRepo.cleared)
+	}
+
+	// 已是当前版本
+	if err := svc.Restore(ctx, "2", "U1"); !errors.Is(err, ErrChangeLogAlreadyCurrent) {
+		t.Fatalf("err = %v", err)
+	}
+	if permissionRepo.cleared != 1 {
+		t.Fatalf("未恢复时不应清理权限缓存，实际清理 %d 次", permissionRepo.cleared)
+	}
+
+	// 删除记录恢复为删除前的数据
+	repo.latest = "3"
+	if err := svc.Restore(ctx, "3", "U1"); err != nil {
+		t.Fatal(err)
+	}
+	if repo.values["name"] != "v2" || repo.version != 3 {
+		t.Fatalf("values = %v, version = %d", repo.values, repo.version)
+	}
+
+	if err := svc.Restore(ctx, "4", "U1"); !errors.Is(err, ErrChangeLogNotFound) {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestChangeLogService_RestoreDenied(t *testing.T) {
+	inDept := map[string]any{"id": "P1", "name": "v1", "creator": "U0", "belong_dept": "D1"}
+	otherDept := map[string]any{"id": "P1", "name": "v2", "creator": "U0", "belong_dept": "D2"}
+	repo := &stubChangeLogRepository{
+		logs: map[string]domainLogger.ChangeLog{
+			"1": newChangeLog("1", changelog.ActionCreate, 1, nil, inDept),
+			"2": newChangeLog("2", changelog.ActionUpdate, 2, inDept, otherDept),
+			"3": newChangeLog("3", changelog.ActionUpdate, 3, otherDept, inDept),
+		},
+	}
+	svc := NewChangeLogService(repo, &stubPermissionRepository{})
+	ctx := context.WithValue(context.Background(), filters.DataScopeKey, filters.DataScope{UserId: "U1", DeptIds: []string{"D1"}})
+
+	// 恢复的版本不在数据权限范围内
+	repo.latest = "3"
+	if err := svc.Restore(ctx, "2", "U1"); !errors.Is(err, ErrChangeLogNoPermission) {
+		t.Fatalf("err = %v", err)
+	}
+	// 当前版本不在数据权限范围内
+	repo.latest = "2"
+	if err := svc.Restore(ctx, "1", "U1"); !errors.Is(err, ErrChangeLogNoPermission) {
+		t.Fatalf("err = %v", err)
+	}
+	repo.latest = "3"
+	if err := svc.Restore(ctx, "1", "U1"); err != nil {
+		t.Fatal(err)
+	}
+
+	// 用户表主键视为本人数据
+	for _, id := range []string{"1", "2", "3"} {
+		log := repo.logs[id]
+		log.EntityTable = "careful_system_users"
+		log.EntityId = "U1"
+		repo.logs[id] = log
+	}
+	if err := svc.Restore(ctx, "2", "U1"); err != nil {
+		t.Fatal(err)
+	}
+
+	// 已删除的用户
+	deleted := newChangeLog("4", changelog.ActionDelete, 3, inDept, nil)
+	deleted.EntityTable = "careful_system_users"
+	repo.logs["4"] = deleted
+	repo.latest = "4"
+	if err := svc.Restore(ctx, "4", "U1"); !errors.Is(err, ErrChangeLogUserDeleted) {
+		t.Fatalf("err = %v", err)
+	}
+
+	// 密钥、两步验证、第三方账号关联
+	for _, table := range []string{"careful_system_api_key", "careful_system_user_two_factor", "careful_system_user_identity"} {
+		log := newChangeLog("5", changelog.ActionDelete, 2, inDept, nil)
+		log.EntityTable = table
+		repo.logs["5"] = log
+		if err := svc.Restore(context.Background(), "5", "U1"); !errors.Is(err, ErrChangeLogRestoreDenied) {
+			t.Fatalf("%s: err = %v", table, err)
+		}
+	}
+}
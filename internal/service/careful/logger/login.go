@@ -0,0 +1,82 @@
+/**
+ * Description：
+ * FileName：login.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 17:57:02
+ * Remark：
+ */
+
+package logger
+
+import (
+	"context"
+	domainLogger "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/logger"
+	repositoryLogger "github.com/carefuly/carefuly-admin-go-gin/internal/repository/repository/careful/logger"
+	"go.uber.org/zap"
+	"time"
+)
+
+type LoginLogService interface {
+	// Record 异步记录登录日志，不影响登录流程
+	Record(ctx context.Context, domain domainLogger.LoginLog)
+	BatchDelete(ctx context.Context, ids []string) error
+	// Clean 清理 days 天之前的日志，days 为 0 时清理全部
+	Clean(ctx context.Context, days int) (int64, error)
+
+	GetListPage(ctx context.Context, filter domainLogger.LoginLogFilter) ([]domainLogger.LoginLog, int64, error)
+	GetListAll(ctx context.Context, filter domainLogger.LoginLogFilter) ([]domainLogger.LoginLog, error)
+}
+
+type loginLogService struct {
+	repo repositoryLogger.LoginLogRepository
+}
+
+func NewLoginLogService(repo repositoryLogger.LoginLogRepository) LoginLogService {
+	return &loginLogService{
+		repo: repo,
+	}
+}
+
+// Record 异步记录登录日志
+func (svc *loginLogService) Record(ctx context.Context, domain domainLogger.LoginLog) {
+	domain.Creator = domain.UserId
+	domain.Modifier = domain.UserId
+
+	go func() {
+		// 请求上下文(gin.Context)会被复用，使用独立的超时上下文
+		logCtx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		if err := svc.repo.Create(logCtx, domain); err != nil {
+			zap.L().Error("登录日志记录失败",
+				zap.String("username", domain.Username),
+				zap.String("action", string(domain.Action)),
+				zap.Error(err),
+			)
+		}
+	}()
+}
+
+// BatchDelete 批量删除
+func (svc *loginLogService) BatchDelete(ctx context.Context, ids []string) error {
+	return svc.repo.BatchDelete(ctx, ids)
+}
+
+// Clean 清理日志
+func (svc *loginLogService) Clean(ctx context.Context, days int) (int64, error) {
+	before := time.Now()
+	if days > 0 {
+		before = before.AddDate(0, 0, -days)
+	}
+	return svc.repo.DeleteBefore(ctx, before)
+}
+
+// GetListPage 分页查询
+func (svc *loginLogService) GetListPage(ctx context.Context, filter domainLogger.LoginLogFilter) ([]domainLogger.LoginLog, int64, error) {
+	return svc.repo.GetListPage(ctx, filter)
+}
+
+// GetListAll 查询所有列表
+func (svc *loginLogService) GetListAll(ctx context.Context, filter domainLogger.LoginLogFilter) ([]domainLogger.LoginLog, error) {
+	return svc.repo.GetListAll(ctx, filter)
+}
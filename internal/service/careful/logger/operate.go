@@ -0,0 +1,113 @@
+/**
+ * Description：
+ * FileName：operate.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 08:33:48
+ * Remark：
+ */
+
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	domainLogger "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/logger"
+	modelLogger "github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/logger"
+	repositoryLogger "github.com/carefuly/carefuly-admin-go-gin/internal/repository/repository/careful/logger"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/constants/careful/logger/audit"
+	"time"
+)
+
+var (
+	ErrOperateLogNotFound  = repositoryLogger.ErrOperateLogNotFound
+	ErrOperateLogCleanDays = errors.New("保留天数不能小于1")
+)
+
+type OperateLogService interface {
+	BatchDelete(ctx context.Context, ids []string) error
+	// Clean 清理 days 天之前的日志，至少保留 1 天，清理结果记录审计事件
+	Clean(ctx context.Context, days int, operatorId, operator, ip string) (int64, error)
+	// GetById 详情，请求参数及响应信息为 JSON 时格式化输出
+	GetById(ctx context.Context, id string) (domainLogger.OperateLog, error)
+	GetListPage(ctx context.Context, filter domainLogger.OperateLogFilter) ([]domainLogger.OperateLog, int64, error)
+	GetListAll(ctx context.Context, filter domainLogger.OperateLogFilter) ([]domainLogger.OperateLog, error)
+}
+
+type operateLogService struct {
+	repo     repositoryLogger.OperateLogRepository
+	auditSvc AuditLogService
+}
+
+func NewOperateLogService(repo repositoryLogger.OperateLogRepository, auditSvc AuditLogService) OperateLogService {
+	return &operateLogService{
+		repo:     repo,
+		auditSvc: auditSvc,
+	}
+}
+
+// BatchDelete 批量删除
+func (svc *operateLogService) BatchDelete(ctx context.Context, ids []string) error {
+	return svc.repo.BatchDelete(ctx, ids)
+}
+
+// Clean 清理日志
+func (svc *operateLogService) Clean(ctx context.Context, days int, operatorId, operator, ip string) (int64, error) {
+	if days < 1 {
+		return 0, ErrOperateLogCleanDays
+	}
+	rows, err := svc.repo.DeleteBefore(ctx, time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		return 0, err
+	}
+
+	svc.auditSvc.Record(ctx, domainLogger.AuditLog{
+		AuditLogger: modelLogger.AuditLogger{
+			Event:      audit.EventConstOperateLogCleaned,
+			Username:   operator,
+			RequestIp:  ip,
+			OperatorId: operatorId,
+			Operator:   operator,
+			Detail:     fmt.Sprintf("清理 %d 天前的操作日志，共 %d 条", days, rows),
+		},
+	})
+	return rows, nil
+}
+
+// GetById 获取详情
+func (svc *operateLogService) GetById(ctx context.Context, id string) (domainLogger.OperateLog, error) {
+	domain, err := svc.repo.GetById(ctx, id)
+	if err != nil {
+		if errors.Is(err, repositoryLogger.ErrOperateLogNotFound) {
+			return domain, ErrOperateLogNotFound
+		}
+		return domain, err
+	}
+
+	if body, ok := domain.RequestBody.(string); ok {
+		domain.RequestBody = formatBody(body)
+	}
+	domain.RequestResult = formatBody(domain.RequestResult)
+	return domain, nil
+}
+
+// GetListPage 分页查询
+func (svc *operateLogService) GetListPage(ctx context.Context, filter domainLogger.OperateLogFilter) ([]domainLogger.OperateLog, int64, error) {
+	return svc.repo.GetListPage(ctx, filter)
+}
+
+// GetListAll 查询所有列表
+func (svc *operateLogService) GetListAll(ctx context.Context, filter domainLogger.OperateLogFilter) ([]domainLogger.OperateLog, error) {
+	return svc.repo.GetListAll(ctx, filter)
+}
+
+// formatBody 缩进格式化 JSON，非 JSON 内容原样返回
+func formatBody(body string) string {
+	var out bytes.Buffer
+	if err := json.Indent(&out, []byte(body), "", "  "); err != nil {
+		return body
+	}
+	return out.String()
+}
@@ -0,0 +1,93 @@
+/**
+ * Description：
+ * FileName：operate_test.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 08:52:10
+ * Remark：
+ */
+
+package logger
+
+import (
+	"context"
+	"errors"
+	domainLogger "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/logger"
+	modelLogger "github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/logger"
+	repositoryLogger "github.com/carefuly/carefuly-admin-go-gin/internal/repository/repository/careful/logger"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/constants/careful/logger/audit"
+	"testing"
+	"time"
+)
+
+type stubOperateLogRepository struct {
+	repositoryLogger.OperateLogRepository
+	log    domainLogger.OperateLog
+	before time.Time
+}
+
+func (r *stubOperateLogRepository) GetById(ctx context.Context, id string) (domainLogger.OperateLog, error) {
+	if id != r.log.Id {
+		return domainLogger.OperateLog{}, repositoryLogger.ErrOperateLogNotFound
+	}
+	return r.log, nil
+}
+
+func (r *stubOperateLogRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	r.before = before
+	return 1, nil
+}
+
+func TestOperateLogService(t *testing.T) {
+	repo := &stubOperateLogRepository{}
+	repo.log.OperateLogger = modelLogger.OperateLogger{
+		RequestBody:   `{"name":"demo","ids":[1,2]}`,
+		RequestResult: "not json",
+	}
+	repo.log.Id = "1"
+	auditSvc := &recordingAuditLogService{}
+	svc := NewOperateLogService(repo, auditSvc)
+	ctx := context.Background()
+
+	detail, err := svc.GetById(ctx, "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "{\n  \"name\": \"demo\",\n  \"ids\": [\n    1,\n    2\n  ]\n}"
+	if detail.RequestBody != want {
+		t.Fatalf("requestBody = %q", detail.RequestBody)
+	}
+	if detail.RequestResult != "not json" {
+		t.Fatalf("非 JSON 内容应原样返回，实际: %q", detail.RequestResult)
+	}
+
+	if _, err := svc.GetById(ctx, "2"); !errors.Is(err, ErrOperateLogNotFound) {
+		t.Fatalf("err = %v", err)
+	}
+
+	// 至少保留 1 天，不允许清空全部
+	if _, err := svc.Clean(ctx, 0, "1", "admin", "127.0.0.1"); !errors.Is(err, ErrOperateLogCleanDays) {
+		t.Fatalf("保留天数为 0 时应拒绝，实际: %v", err)
+	}
+	if !repo.before.IsZero() || len(auditSvc.logs) != 0 {
+		t.Fatalf("拒绝清理时不应删除日志或记录审计事件")
+	}
+
+	if _, err := svc.Clean(ctx, 7, "1", "admin", "127.0.0.1"); err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Since(repo.before); d < 7*24*time.Hour-time.Minute || d > 7*24*time.Hour+time.Minute {
+		t.Fatalf("清理时间点不正确: %v", repo.before)
+	}
+	if len(auditSvc.logs) != 1 || auditSvc.logs[0].Event != audit.EventConstOperateLogCleaned || auditSvc.logs[0].OperatorId != "1" {
+		t.Fatalf("清理后应记录审计事件，实际: %+v", auditSvc.logs)
+	}
+}
+
+// recordingAuditLogService 记录审计事件
+type recordingAuditLogService struct {
+	logs []domainLogger.AuditLog
+}
+
+func (s *recordingAuditLogService) Record(ctx context.Context, domain domainLogger.AuditLog) {
+	s.logs = append(s.logs, domain)
+}
@@ -0,0 +1,255 @@
+/**
+ * Description：
+ * FileName：api_key.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 23:35:26
+ * Remark：
+ */
+
+package system
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	domainLogger "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/logger"
+	domainSystem "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/system"
+	modelLogger "github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/logger"
+	modelSystem "github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/system"
+	repositorySystem "github.com/carefuly/carefuly-admin-go-gin/internal/repository/repository/careful/system"
+	serviceLogger "github.com/carefuly/carefuly-admin-go-gin/internal/service/careful/logger"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/constants/careful/logger/audit"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/models"
+	"go.uber.org/zap"
+	"strings"
+	"time"
+)
+
+const (
+	ApiKeyScheme        = "ApiKey"    // Authorization 请求头认证方案
+	apiKeyTokenPrefix   = "ck_"       // 令牌明文前缀
+	apiKeyByteSize      = 24          // 令牌随机字节数
+	apiKeyDisplayLength = 11          // 展示的令牌前缀长度
+	apiKeyMaxActive     = 20          // 每个用户有效令牌数量上限
+	apiKeyTouchInterval = time.Minute // 最近使用信息的记录间隔
+)
+
+var (
+	ErrApiKeyNotFound     = repositorySystem.ErrApiKeyNotFound
+	ErrApiKeyInvalid      = errors.New("无效的API Key")
+	ErrApiKeyExpired      = errors.New("API Key已过期")
+	ErrApiKeyRevoked      = errors.New("API Key已吊销")
+	ErrApiKeyUserDisabled = errors.New("API Key所属用户已停用")
+	ErrApiKeyScopeInvalid = errors.New("授权范围超出用户已有权限")
+	ErrApiKeyTooMany      = fmt.Errorf("有效的API Key数量不能超过%d个", apiKeyMaxActive)
+)
+
+type ApiKeyService interface {
+	// Create 创建令牌，明文仅在创建时返回
+	Create(ctx context.Context, user domainSystem.User, name string, scopes []string, expireDays int) (domainSystem.ApiKeyCreated, error)
+	// Revoke 吊销用户本人的令牌
+	Revoke(ctx context.Context, user domainSystem.User, id string) error
+
+	GetListByUserId(ctx context.Context, userId string) ([]domainSystem.ApiKey, error)
+
+	// Authenticate 校验令牌明文，返回令牌及所属用户，并记录最近使用信息
+	Authenticate(ctx context.Context, key, ip string) (domainSystem.ApiKey, domainSystem.User, error)
+}
+
+type apiKeyService struct {
+	repo           repositorySystem.ApiKeyRepository
+	userRepo       repositorySystem.UserRepository
+	permissionRepo repositorySystem.PermissionRepository
+	auditSvc       serviceLogger.AuditLogService
+}
+
+func NewApiKeyService(repo repositorySystem.ApiKeyRepository, userRepo repositorySystem.UserRepository,
+	permissionRepo repositorySystem.PermissionRepository, auditSvc serviceLogger.AuditLogService) ApiKeyService {
+	return &apiKeyService{
+		repo:           repo,
+		userRepo:       userRepo,
+		permissionRepo: permissionRepo,
+		auditSvc:       auditSvc,
+	}
+}
+
+// Create 创建
+func (svc *apiKeyService) Create(ctx context.Context, user domainSystem.User, name string, scopes []string, expireDays int) (domainSystem.ApiKeyCreated, error) {
+	count, err := svc.repo.CountActiveByUserId(ctx, user.Id)
+	if err != nil {
+		return domainSystem.ApiKeyCreated{}, err
+	}
+	if count >= apiKeyMaxActive {
+		return domainSystem.ApiKeyCreated{}, ErrApiKeyTooMany
+	}
+
+	// 授权范围仅能是用户已拥有的按钮权限
+	scopes, err = svc.checkScopes(ctx, user.Id, scopes)
+	if err != nil {
+		return domainSystem.ApiKeyCreated{}, err
+	}
+
+	key, err := newApiKey()
+	if err != nil {
+		return domainSystem.ApiKeyCreated{}, err
+	}
+	expireTime := time.Now().AddDate(0, 0, expireDays)
+
+	created, err := svc.repo.Create(ctx, domainSystem.ApiKey{
+		ApiKey: modelSystem.ApiKey{
+			CoreModels: models.CoreModels{
+				Creator:    user.Id,
+				Modifier:   user.Id,
+				BelongDept: user.DeptId,
+			},
+			UserId:     user.Id,
+			Name:       name,
+			Prefix:     key[:apiKeyDisplayLength],
+			KeyHash:    hashApiKey(key),
+			ExpireTime: &expireTime,
+		},
+		Scopes: scopes,
+	})
+	if err != nil {
+		return domainSystem.ApiKeyCreated{}, err
+	}
+
+	svc.record(ctx, audit.EventConstApiKeyCreated, user, fmt.Sprintf("创建API Key %s(%s)", name, created.Prefix))
+	return domainSystem.ApiKeyCreated{
+		ApiKey: created,
+		Key:    key,
+	}, nil
+}
+
+// Revoke 吊销
+func (svc *apiKeyService) Revoke(ctx context.Context, user domainSystem.User, id string) error {
+	rowsAffected, err := svc.repo.Revoke(ctx, id, user.Id, user.Id)
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrApiKeyNotFound
+	}
+
+	detail := fmt.Sprintf("吊销API Key %s", id)
+	if key, err := svc.repo.GetById(ctx, id); err == nil {
+		detail = fmt.Sprintf("吊销API Key %s(%s)", key.Name, key.Prefix)
+	}
+	svc.record(ctx, audit.EventConstApiKeyRevoked, user, detail)
+	return nil
+}
+
+// GetListByUserId 查询用户的全部令牌
+func (svc *apiKeyService) GetListByUserId(ctx context.Context, userId string) ([]domainSystem.ApiKey, error) {
+	return svc.repo.GetListByUserId(ctx, userId)
+}
+
+// Authenticate 校验令牌
+func (svc *apiKeyService) Authenticate(ctx context.Context, key, ip string) (domainSystem.ApiKey, domainSystem.User, error) {
+	if !strings.HasPrefix(key, apiKeyTokenPrefix) {
+		return domainSystem.ApiKey{}, domainSystem.User{}, ErrApiKeyInvalid
+	}
+
+	apiKey, err := svc.repo.GetByHash(ctx, hashApiKey(key))
+	if err != nil {
+		if errors.Is(err, ErrApiKeyNotFound) {
+			return domainSystem.ApiKey{}, domainSystem.User{}, ErrApiKeyInvalid
+		}
+		return domainSystem.ApiKey{}, domainSystem.User{}, err
+	}
+	if apiKey.Revoked {
+		return domainSystem.ApiKey{}, domainSystem.User{}, ErrApiKeyRevoked
+	}
+	if apiKey.Expired {
+		return domainSystem.ApiKey{}, domainSystem.User{}, ErrApiKeyExpired
+	}
+
+	user, err := svc.userRepo.GetById(ctx, apiKey.UserId)
+	if err != nil {
+		return domainSystem.ApiKey{}, domainSystem.User{}, err
+	}
+	if user.Id == "" {
+		return domainSystem.ApiKey{}, domainSystem.User{}, ErrApiKeyInvalid
+	}
+	if !user.Status {
+		return domainSystem.ApiKey{}, domainSystem.User{}, ErrApiKeyUserDisabled
+	}
+
+	svc.touch(ctx, apiKey.Id, ip)
+	return apiKey, user, nil
+}
+
+// checkScopes 校验授权范围并去重
+func (svc *apiKeyService) checkScopes(ctx context.Context, userId string, scopes []string) ([]string, error) {
+	if len(scopes) == 0 {
+		return []string{}, nil
+	}
+
+	permission, err := svc.permissionRepo.GetByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	granted := make(map[string]bool, len(permission.Apis))
+	for _, api := range permission.Apis {
+		granted[api.Code] = true
+	}
+
+	result := make([]string, 0, len(scopes))
+	seen := make(map[string]bool, len(scopes))
+	for _, code := range scopes {
+		if !granted[code] {
+			return nil, ErrApiKeyScopeInvalid
+		}
+		if !seen[code] {
+			seen[code] = true
+			result = append(result, code)
+		}
+	}
+	return result, nil
+}
+
+// touch 记录最近使用信息，同一令牌在间隔内仅记录一次
+func (svc *apiKeyService) touch(ctx context.Context, id, ip string) {
+	ok, err := svc.repo.TryTouch(ctx, id, apiKeyTouchInterval)
+	if err != nil {
+		zap.L().Error("API Key使用记录限流异常", zap.String("id", id), zap.Error(err))
+		return
+	}
+	if !ok {
+		return
+	}
+	if err := svc.repo.UpdateLastUsed(ctx, id, ip, time.Now()); err != nil {
+		zap.L().Error("API Key使用记录更新失败", zap.String("id", id), zap.Error(err))
+	}
+}
+
+// record 记录审计事件
+func (svc *apiKeyService) record(ctx context.Context, event audit.EventConst, user domainSystem.User, detail string) {
+	svc.auditSvc.Record(ctx, domainLogger.AuditLog{
+		AuditLogger: modelLogger.AuditLogger{
+			Event:      event,
+			Username:   user.Username,
+			OperatorId: user.Id,
+			Operator:   user.Username,
+			Detail:     detail,
+		},
+	})
+}
+
+// newApiKey 生成令牌明文
+func newApiKey() (string, error) {
+	b := make([]byte, apiKeyByteSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return apiKeyTokenPrefix + hex.EncodeToString(b), nil
+}
+
+// hashApiKey 令牌摘要，数据库仅保存摘要
+func hashApiKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,173 @@
+/**
+ * Description：
+ * FileName：api_key_test.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 00:06:42
+ * Remark：
+ */
+
+package system
+
+import (
+	"context"
+	"errors"
+	domainLogger "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/logger"
+	domainSystem "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/system"
+	modelSystem "github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/system"
+	repositorySystem "github.com/carefuly/carefuly-admin-go-gin/internal/repository/repository/careful/system"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/models"
+	"strings"
+	"testing"
+	"time"
+)
+
+// stubApiKeyRepository 以摘要为键保存令牌
+type stubApiKeyRepository struct {
+	repositorySystem.ApiKeyRepository
+	keys    map[string]domainSystem.ApiKey
+	touched int
+}
+
+func (r *stubApiKeyRepository) CountActiveByUserId(ctx context.Context, userId string) (int64, error) {
+	return int64(len(r.keys)), nil
+}
+
+func (r *stubApiKeyRepository) Create(ctx context.Context, domain domainSystem.ApiKey) (domainSystem.ApiKey, error) {
+	domain.Id = domain.KeyHash[:8]
+	r.keys[domain.KeyHash] = domain
+	return domain, nil
+}
+
+func (r *stubApiKeyRepository) GetByHash(ctx context.Context, hash string) (domainSystem.ApiKey, error) {
+	key, ok := r.keys[hash]
+	if !ok {
+		return domainSystem.ApiKey{}, ErrApiKeyNotFound
+	}
+	return key, nil
+}
+
+func (r *stubApiKeyRepository) TryTouch(ctx context.Context, id string, interval time.Duration) (bool, error) {
+	return r.touched == 0, nil
+}
+
+func (r *stubApiKeyRepository) UpdateLastUsed(ctx context.Context, id, ip string, usedTime time.Time) error {
+	r.touched++
+	return nil
+}
+
+// stubPermissionRepository 固定的用户权限
+type stubPermissionRepository struct {
+	repositorySystem.PermissionRepository
+	permission domainSystem.UserPermission
+}
+
+func (r *stubPermissionRepository) GetByUserId(ctx context.Context, userId string) (domainSystem.UserPermission, error) {
+	return r.permission, nil
+}
+
+func (r *stubPermissionRepository) DelByUserIds(ctx context.Context, userIds ...string) error {
+	return nil
+}
+
+type stubAuditLogService struct{}
+
+func (s stubAuditLogService) Record(ctx context.Context, domain domainLogger.AuditLog) {}
+
+func TestApiKeyService_Authenticate(t *testing.T) {
+	user := domainSystem.User{User: modelSystem.User{
+		CoreModels: models.CoreModels{Id: "1"},
+		Status:     true,
+		Username:   "ci",
+	}}
+	repo := &stubApiKeyRepository{keys: map[string]domainSystem.ApiKey{}}
+	userRepo := &stubUserRepository{user: user}
+	permissionRepo := &stubPermissionRepository{permission: domainSystem.UserPermission{
+		Apis: []domainSystem.ApiPermission{{Code: "system:user:list"}, {Code: "system:user:create"}},
+	}}
+	svc := NewApiKeyService(repo, userRepo, permissionRepo, stubAuditLogService{})
+	ctx := context.Background()
+
+	if _, err := svc.Create(ctx, user, "deploy", []string{"system:role:list"}, 30); !errors.Is(err, ErrApiKeyScopeInvalid) {
+		t.Fatalf("授权范围超出用户权限时应拒绝，实际: %v", err)
+	}
+
+	created, err := svc.Create(ctx, user, "deploy", []string{"system:user:list", "system:user:list"}, 30)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(created.Key, created.Prefix) || len(created.Prefix) >= len(created.Key) {
+		t.Fatalf("令牌前缀不正确: %s %s", created.Prefix, created.Key)
+	}
+	if created.KeyHash == "" || strings.Contains(created.KeyHash, created.Key) {
+		t.Fatalf("令牌应仅保存摘要: %s", created.KeyHash)
+	}
+	if len(created.Scopes) != 1 {
+		t.Fatalf("授权范围应去重: %v", created.Scopes)
+	}
+
+	key, owner, err := svc.Authenticate(ctx, created.Key, "10.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if owner.Id != user.Id || key.Id != created.Id {
+		t.Fatalf("认证结果不正确: %+v %+v", key, owner)
+	}
+	if _, _, err := svc.Authenticate(ctx, created.Key, "10.0.0.1"); err != nil {
+		t.Fatal(err)
+	}
+	if repo.touched != 1 {
+		t.Fatalf("间隔内应仅记录一次使用信息，实际: %d", repo.touched)
+	}
+
+	testCases := []struct {
+		name   string
+		key    string
+		modify func(key *domainSystem.ApiKey)
+		status bool
+		want   error
+	}{
+		{name: "格式错误", key: "Bearer", want: ErrApiKeyInvalid},
+		{name: "不存在", key: created.Key + "0", want: ErrApiKeyInvalid},
+		{name: "已吊销", key: created.Key, modify: func(key *domainSystem.ApiKey) { key.Revoked = true }, want: ErrApiKeyRevoked},
+		{name: "已过期", key: created.Key, modify: func(key *domainSystem.ApiKey) { key.Expired = true }, want: ErrApiKeyExpired},
+		{name: "用户已停用", key: created.Key, status: true, want: ErrApiKeyUserDisabled},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			original := repo.keys[created.KeyHash]
+			defer func() {
+				repo.keys[created.KeyHash] = original
+				userRepo.user.Status = true
+			}()
+			if tc.modify != nil {
+				modified := original
+				tc.modify(&modified)
+				repo.keys[created.KeyHash] = modified
+			}
+			if tc.status {
+				userRepo.user.Status = false
+			}
+			if _, _, err := svc.Authenticate(ctx, tc.key, "10.0.0.1"); !errors.Is(err, tc.want) {
+				t.Fatalf("期望 %v，实际 %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestUserPermission_Narrow(t *testing.T) {
+	permission := domainSystem.UserPermission{
+		SuperAdmin: true,
+		Apis:       []domainSystem.ApiPermission{{Code: "a"}, {Code: "b"}, {Code: "c"}},
+	}
+
+	if got := permission.Narrow(nil); !got.SuperAdmin || len(got.Apis) != 3 {
+		t.Fatalf("未指定授权范围时不应收窄: %+v", got)
+	}
+	got := permission.Narrow([]string{"b", "x"})
+	if got.SuperAdmin || len(got.Apis) != 1 || got.Apis[0].Code != "b" {
+		t.Fatalf("授权范围收窄不正确: %+v", got)
+	}
+	if !permission.SuperAdmin || len(permission.Apis) != 3 {
+		t.Fatal("不应修改原权限集合")
+	}
+}
@@ -14,6 +14,7 @@ import (
 	domainSystem "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/system"
 	repositorySystem "github.com/carefuly/carefuly-admin-go-gin/internal/repository/repository/careful/system"
 	"github.com/go-sql-driver/mysql"
+	"go.uber.org/zap"
 )
 
 // DeptTree 部门树形结构
@@ -41,12 +42,14 @@ type DeptService interface {
 }
 
 type deptService struct {
-	repo repositorySystem.DeptRepository
+	repo           repositorySystem.DeptRepository
+	permissionRepo repositorySystem.PermissionRepository
 }
 
-func NewDeptService(repo repositorySystem.DeptRepository) DeptService {
+func NewDeptService(repo repositorySystem.DeptRepository, permissionRepo repositorySystem.PermissionRepository) DeptService {
 	return &deptService{
-		repo: repo,
+		repo:           repo,
+		permissionRepo: permissionRepo,
 	}
 }
 
@@ -69,6 +72,8 @@ func (svc *deptService) Create(ctx context.Context, domain domainSystem.Dept) er
 		return err
 	}
 
+	svc.invalidatePermission(ctx)
+
 	return nil
 }
 
@@ -81,12 +86,17 @@ func (svc *deptService) Delete(ctx context.Context, id string) error {
 	if rowsAffected == 0 {
 		return repositorySystem.ErrDeptNotFound
 	}
+	svc.invalidatePermission(ctx)
 	return err
 }
 
 // BatchDelete 批量删除
 func (svc *deptService) BatchDelete(ctx context.Context, ids []string) error {
-	return svc.repo.BatchDelete(ctx, ids)
+	if err := svc.repo.BatchDelete(ctx, ids); err != nil {
+		return err
+	}
+	svc.invalidatePermission(ctx)
+	return nil
 }
 
 // Update 更新
@@ -112,6 +122,8 @@ func (svc *deptService) Update(ctx context.Context, domain domainSystem.Dept) er
 		}
 	}
 
+	svc.invalidatePermission(ctx)
+
 	return nil
 }
 
@@ -183,3 +195,11 @@ func (svc *deptService) IsDuplicateEntryError(err error) bool {
 	}
 	return false
 }
+
+// invalidatePermission 部门层级变更后清理全部用户权限缓存(数据权限范围依赖部门树)
+func (svc *deptService) invalidatePermission(ctx context.Context) {
+	if err := svc.permissionRepo.DelAll(ctx); err != nil {
+		// 网络崩了，也可能是 redis 崩了
+		zap.L().Error("清理用户权限缓存失败", zap.Error(err))
+	}
+}
@@ -0,0 +1,103 @@
+/**
+ * Description：
+ * FileName：identity_provider.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 07:06:33
+ * Remark：
+ */
+
+package system
+
+import (
+	"context"
+	config "github.com/carefuly/carefuly-admin-go-gin/config/file"
+	domainSystem "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/system"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/oidc"
+	"strings"
+	"time"
+)
+
+// IdentityProvider 外部身份提供方
+// 新增协议时实现该接口并在 NewSSOService 中按配置创建
+type IdentityProvider interface {
+	// AuthURL 跳转至身份提供方的授权地址
+	AuthURL(ctx context.Context, state, nonce, verifier string) (string, error)
+	// Authenticate 使用回调的授权码换取并校验用户身份
+	Authenticate(ctx context.Context, code, verifier, nonce string) (domainSystem.ExternalIdentity, error)
+}
+
+type oidcIdentityProvider struct {
+	name     string
+	provider *oidc.Provider
+
+	usernameClaim string
+	emailClaim    string
+	nameClaim     string
+}
+
+// NewOIDCIdentityProvider OpenID Connect 身份提供方，使用授权码 + PKCE 流程
+func NewOIDCIdentityProvider(cfg config.OIDCProviderConfig) IdentityProvider {
+	p := &oidcIdentityProvider{
+		name: cfg.Name,
+		provider: oidc.NewProvider(oidc.Config{
+			Issuer:       cfg.Issuer,
+			ClientId:     cfg.ClientId,
+			ClientSecret: cfg.ClientSecret,
+			RedirectURL:  cfg.RedirectUrl,
+			Scopes:       cfg.Scopes,
+			Timeout:      time.Duration(cfg.Timeout) * time.Second,
+		}),
+		usernameClaim: cfg.UsernameClaim,
+		emailClaim:    cfg.EmailClaim,
+		nameClaim:     cfg.NameClaim,
+	}
+	if p.usernameClaim == "" {
+		p.usernameClaim = "preferred_username"
+	}
+	if p.emailClaim == "" {
+		p.emailClaim = "email"
+	}
+	if p.nameClaim == "" {
+		p.nameClaim = "name"
+	}
+	return p
+}
+
+// AuthURL 授权地址
+func (p *oidcIdentityProvider) AuthURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	return p.provider.AuthCodeURL(ctx, state, nonce, verifier)
+}
+
+// Authenticate 换取令牌并校验ID令牌
+func (p *oidcIdentityProvider) Authenticate(ctx context.Context, code, verifier, nonce string) (domainSystem.ExternalIdentity, error) {
+	token, err := p.provider.Exchange(ctx, code, verifier)
+	if err != nil {
+		return domainSystem.ExternalIdentity{}, err
+	}
+	claims, err := p.provider.VerifyIDToken(ctx, token.IdToken, nonce)
+	if err != nil {
+		return domainSystem.ExternalIdentity{}, err
+	}
+
+	return domainSystem.ExternalIdentity{
+		Provider:      p.name,
+		Subject:       claims.String("sub"),
+		Username:      strings.TrimSpace(claims.String(p.usernameClaim)),
+		Email:         strings.TrimSpace(claims.String(p.emailClaim)),
+		EmailVerified: emailVerified(claims["email_verified"]),
+		Name:          strings.TrimSpace(claims.String(p.nameClaim)),
+		Claims:        claims,
+	}, nil
+}
+
+// emailVerified 未返回 email_verified 时视为未验证，部分身份提供方以字符串返回
+func emailVerified(value any) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case string:
+		return strings.EqualFold(v, "true")
+	default:
+		return false
+	}
+}
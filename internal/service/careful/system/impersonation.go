@@ -0,0 +1,207 @@
+/**
+ * Description：
+ * FileName：impersonation.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 04:41:27
+ * Remark：
+ */
+
+package system
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	config "github.com/carefuly/carefuly-admin-go-gin/config/file"
+	domainLogger "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/logger"
+	domainSystem "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/system"
+	modelLogger "github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/logger"
+	repositorySystem "github.com/carefuly/carefuly-admin-go-gin/internal/repository/repository/careful/system"
+	serviceLogger "github.com/carefuly/carefuly-admin-go-gin/internal/service/careful/logger"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/constants/careful/logger/audit"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/constants/careful/system/user"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/jwt"
+	"time"
+)
+
+// 模拟登录令牌默认最长有效期
+const impersonateDefaultExpire = 30 * time.Minute
+
+var (
+	ErrImpersonateForbidden = errors.New("无模拟登录权限")
+	ErrImpersonateSelf      = errors.New("不能模拟登录自己")
+	ErrImpersonateDisabled  = errors.New("目标用户已停用")
+	ErrImpersonateAdmin     = errors.New("仅超级管理员可模拟登录超级管理员")
+	ErrImpersonateRole      = errors.New("不能模拟登录拥有自身未拥有角色的用户")
+	ErrNotImpersonating     = errors.New("当前会话不是模拟登录会话")
+)
+
+type ImpersonationService interface {
+	// Start 以目标用户身份签发短期令牌，client 为发起请求的客户端信息(IP、操作系统、浏览器、用户代理)，
+	// minutes 为期望的有效期，不超过配置的最长有效期
+	Start(ctx context.Context, actorId, actorName, userId string, minutes int, client domainSystem.Session) (domainSystem.Impersonation, error)
+	// Stop 结束模拟登录，吊销模拟令牌
+	Stop(ctx context.Context, jti, ip string) error
+}
+
+type impersonationService struct {
+	userRepo       repositorySystem.UserRepository
+	permissionRepo repositorySystem.PermissionRepository
+	sessionRepo    repositorySystem.SessionRepository
+	sessionSvc     SessionService
+	auditSvc       serviceLogger.AuditLogService
+	keys           *jwt.KeySet
+	maxExpire      time.Duration
+}
+
+func NewImpersonationService(userRepo repositorySystem.UserRepository, permissionRepo repositorySystem.PermissionRepository,
+	sessionRepo repositorySystem.SessionRepository, sessionSvc SessionService, auditSvc serviceLogger.AuditLogService,
+	keys *jwt.KeySet, token config.TokenConfig) ImpersonationService {
+	maxExpire := impersonateDefaultExpire
+	if token.ImpersonateExpire > 0 {
+		maxExpire = time.Duration(token.ImpersonateExpire) * time.Minute
+	}
+	return &impersonationService{
+		userRepo:       userRepo,
+		permissionRepo: permissionRepo,
+		sessionRepo:    sessionRepo,
+		sessionSvc:     sessionSvc,
+		auditSvc:       auditSvc,
+		keys:           keys,
+		maxExpire:      maxExpire,
+	}
+}
+
+// Start 开始模拟登录
+func (svc *impersonationService) Start(ctx context.Context, actorId, actorName, userId string, minutes int,
+	client domainSystem.Session) (domainSystem.Impersonation, error) {
+	if actorId == userId {
+		return domainSystem.Impersonation{}, ErrImpersonateSelf
+	}
+
+	actorPermission, err := svc.permissionRepo.GetByUserId(ctx, actorId)
+	if err != nil {
+		return domainSystem.Impersonation{}, err
+	}
+	if !actorPermission.SuperAdmin && !hasPermissionCode(actorPermission, user.PermissionConstImpersonate) {
+		return domainSystem.Impersonation{}, ErrImpersonateForbidden
+	}
+
+	// 按发起人的数据权限范围查询，范围外的用户视为不存在
+	target, err := svc.userRepo.GetById(ctx, userId)
+	if err != nil {
+		return domainSystem.Impersonation{}, err
+	}
+	if target.Id == "" {
+		return domainSystem.Impersonation{}, ErrUserNotFound
+	}
+	if !target.Status {
+		return domainSystem.Impersonation{}, ErrImpersonateDisabled
+	}
+	if !actorPermission.SuperAdmin {
+		targetPermission, err := svc.permissionRepo.GetByUserId(ctx, target.Id)
+		if err != nil {
+			return domainSystem.Impersonation{}, err
+		}
+		if targetPermission.SuperAdmin {
+			return domainSystem.Impersonation{}, ErrImpersonateAdmin
+		}
+		// 目标用户的角色须为发起人角色的子集，防止借助模拟登录提升权限
+		if !containsAll(actorPermission.RoleIds, targetPermission.RoleIds) {
+			return domainSystem.Impersonation{}, ErrImpersonateRole
+		}
+	}
+
+	expire := svc.maxExpire
+	if minutes > 0 {
+		expire = min(time.Duration(minutes)*time.Minute, svc.maxExpire)
+	}
+	token, claims, err := jwt.GenerateImpersonationToken(actorId, actorName, target.Id, target.Username, int(target.UserType),
+		target.DeptId, client.UserAgent, client.RequestIp, svc.keys, expire)
+	if err != nil {
+		return domainSystem.Impersonation{}, err
+	}
+
+	// 直接记录会话，不触发单会话模式下对目标用户已有会话的吊销
+	session := client
+	session.Jti = claims.ID
+	session.UserId = target.Id
+	session.Username = target.Username
+	session.DeptId = target.DeptId
+	session.Family = ""
+	session.LoginTime = claims.IssuedAt.Format("2006-01-02 15:04:05")
+	session.ExpireTime = claims.ExpiresAt.Format("2006-01-02 15:04:05")
+	session.ActorId = actorId
+	session.ActorName = actorName
+	if err := svc.sessionRepo.Create(ctx, session, expire); err != nil {
+		return domainSystem.Impersonation{}, err
+	}
+
+	svc.auditSvc.Record(ctx, domainLogger.AuditLog{
+		AuditLogger: modelLogger.AuditLogger{
+			Event:      audit.EventConstImpersonateStart,
+			Username:   target.Username,
+			RequestIp:  client.RequestIp,
+			OperatorId: actorId,
+			Operator:   actorName,
+			Detail:     fmt.Sprintf("模拟登录用户 %s，会话 %s，有效期至 %s", target.Username, claims.ID, session.ExpireTime),
+		},
+	})
+
+	return domainSystem.Impersonation{
+		Token:      token,
+		Expire:     int(expire.Seconds()),
+		ExpireTime: session.ExpireTime,
+		User:       target,
+	}, nil
+}
+
+// Stop 结束模拟登录
+func (svc *impersonationService) Stop(ctx context.Context, jti, ip string) error {
+	session, err := svc.sessionSvc.GetByJti(ctx, jti)
+	if err != nil {
+		return err
+	}
+	if session.ActorId == "" {
+		return ErrNotImpersonating
+	}
+	if err := svc.sessionSvc.Revoke(ctx, jti); err != nil {
+		return err
+	}
+
+	svc.auditSvc.Record(ctx, domainLogger.AuditLog{
+		AuditLogger: modelLogger.AuditLogger{
+			Event:      audit.EventConstImpersonateStop,
+			Username:   session.Username,
+			RequestIp:  ip,
+			OperatorId: session.ActorId,
+			Operator:   session.ActorName,
+			Detail:     fmt.Sprintf("结束模拟登录用户 %s，会话 %s", session.Username, jti),
+		},
+	})
+	return nil
+}
+
+// hasPermissionCode 是否拥有指定权限值
+func hasPermissionCode(permission domainSystem.UserPermission, code string) bool {
+	for _, api := range permission.Apis {
+		if api.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// containsAll set 是否包含 subset 的全部元素
+func containsAll(set, subset []string) bool {
+	exists := make(map[string]struct{}, len(set))
+	for _, v := range set {
+		exists[v] = struct{}{}
+	}
+	for _, v := range subset {
+		if _, ok := exists[v]; !ok {
+			return false
+		}
+	}
+	return true
+}
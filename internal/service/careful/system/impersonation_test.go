@@ -0,0 +1,137 @@
+/**
+ * Description：
+ * FileName：impersonation_test.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 05:14:08
+ * Remark：
+ */
+
+package system
+
+import (
+	"context"
+	"errors"
+	config "github.com/carefuly/carefuly-admin-go-gin/config/file"
+	domainSystem "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/system"
+	modelSystem "github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/system"
+	repositorySystem "github.com/carefuly/carefuly-admin-go-gin/internal/repository/repository/careful/system"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/constants/careful/system/user"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/models"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/jwt"
+	"testing"
+	"time"
+)
+
+// userPermissionRepository 按用户返回权限
+type userPermissionRepository struct {
+	repositorySystem.PermissionRepository
+	permissions map[string]domainSystem.UserPermission
+}
+
+func (r *userPermissionRepository) GetByUserId(ctx context.Context, userId string) (domainSystem.UserPermission, error) {
+	return r.permissions[userId], nil
+}
+
+// impersonationSessionRepository 记录创建的会话
+type impersonationSessionRepository struct {
+	stubSessionRepository
+	expiration time.Duration
+}
+
+func (r *impersonationSessionRepository) Create(ctx context.Context, domain domainSystem.Session, expiration time.Duration) error {
+	r.sessions[domain.Jti] = domain
+	r.expiration = expiration
+	return nil
+}
+
+// stubSessionService 吊销时删除会话
+type stubSessionService struct {
+	SessionService
+	repo *impersonationSessionRepository
+}
+
+func (s *stubSessionService) GetByJti(ctx context.Context, jti string) (domainSystem.Session, error) {
+	return s.repo.GetByJti(ctx, jti)
+}
+
+func (s *stubSessionService) Revoke(ctx context.Context, jti string) error {
+	delete(s.repo.sessions, jti)
+	return nil
+}
+
+func TestImpersonationService(t *testing.T) {
+	keys := jwt.NewSecretKeySet("secret")
+	target := domainSystem.User{User: modelSystem.User{
+		CoreModels: models.CoreModels{Id: "2"},
+		Status:     true,
+		Username:   "demo",
+		UserType:   user.TypeConstAdminUser,
+	}}
+	permissionRepo := &userPermissionRepository{permissions: map[string]domainSystem.UserPermission{
+		"1": {RoleIds: []string{"support", "staff"}, Apis: []domainSystem.ApiPermission{{Code: user.PermissionConstImpersonate}}},
+		"3": {Apis: []domainSystem.ApiPermission{{Code: "system:user:list"}}},
+	}}
+	sessionRepo := &impersonationSessionRepository{stubSessionRepository: stubSessionRepository{sessions: map[string]domainSystem.Session{}}}
+	auditSvc := &recordingAuditLogService{}
+	svc := NewImpersonationService(&stubUserRepository{user: target}, permissionRepo, sessionRepo,
+		&stubSessionService{repo: sessionRepo}, auditSvc, keys, config.TokenConfig{ImpersonateExpire: 15})
+	ctx := context.Background()
+	client := domainSystem.Session{RequestIp: "10.0.0.1", UserAgent: "Chrome"}
+
+	if _, err := svc.Start(ctx, "3", "guest", "2", 0, client); !errors.Is(err, ErrImpersonateForbidden) {
+		t.Fatalf("无权限时应拒绝，实际: %v", err)
+	}
+	if _, err := svc.Start(ctx, "2", "demo", "2", 0, client); !errors.Is(err, ErrImpersonateSelf) {
+		t.Fatalf("不能模拟自己，实际: %v", err)
+	}
+
+	// 非超级管理员不能模拟超级管理员
+	permissionRepo.permissions["2"] = domainSystem.UserPermission{SuperAdmin: true}
+	if _, err := svc.Start(ctx, "1", "support", "2", 0, client); !errors.Is(err, ErrImpersonateAdmin) {
+		t.Fatalf("模拟超级管理员应被拒绝，实际: %v", err)
+	}
+
+	// 目标用户拥有发起人未拥有的角色时拒绝
+	permissionRepo.permissions["2"] = domainSystem.UserPermission{RoleIds: []string{"staff", "finance"}}
+	if _, err := svc.Start(ctx, "1", "support", "2", 0, client); !errors.Is(err, ErrImpersonateRole) {
+		t.Fatalf("模拟拥有更高角色的用户应被拒绝，实际: %v", err)
+	}
+	permissionRepo.permissions["2"] = domainSystem.UserPermission{RoleIds: []string{"staff"}}
+
+	// 有效期不超过配置的上限
+	result, err := svc.Start(ctx, "1", "support", "2", 60, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Expire != 15*60 || sessionRepo.expiration != 15*time.Minute {
+		t.Fatalf("expire = %d, session expiration = %v", result.Expire, sessionRepo.expiration)
+	}
+
+	claims, err := jwt.ParseToken(result.Token, keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !claims.Impersonating() || claims.ActorId != "1" || claims.SubjectId != "2" || claims.UserId != "2" {
+		t.Fatalf("claims = %+v", claims)
+	}
+	session := sessionRepo.sessions[claims.ID]
+	if session.ActorId != "1" || session.UserId != "2" || session.RequestIp != "10.0.0.1" {
+		t.Fatalf("session = %+v", session)
+	}
+
+	if err := svc.Stop(ctx, claims.ID, "10.0.0.1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := sessionRepo.sessions[claims.ID]; ok {
+		t.Fatalf("结束模拟登录后会话应被吊销")
+	}
+	if len(auditSvc.logs) != 2 || auditSvc.logs[1].OperatorId != "1" {
+		t.Fatalf("audit logs = %+v", auditSvc.logs)
+	}
+
+	// 普通会话不能通过结束模拟登录吊销
+	sessionRepo.sessions["normal"] = domainSystem.Session{Jti: "normal", UserId: "2"}
+	if err := svc.Stop(ctx, "normal", "10.0.0.1"); !errors.Is(err, ErrNotImpersonating) {
+		t.Fatalf("普通会话应被拒绝，实际: %v", err)
+	}
+}
@@ -0,0 +1,210 @@
+/**
+ * Description：
+ * FileName：login_guard.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 16:19:05
+ * Remark：
+ */
+
+package system
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	config "github.com/carefuly/carefuly-admin-go-gin/config/file"
+	domainLogger "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/logger"
+	modelLogger "github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/logger"
+	repositorySystem "github.com/carefuly/carefuly-admin-go-gin/internal/repository/repository/careful/system"
+	serviceLogger "github.com/carefuly/carefuly-admin-go-gin/internal/service/careful/logger"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/constants/careful/logger/audit"
+	"go.uber.org/zap"
+	"time"
+)
+
+var (
+	ErrAccountLocked = errors.New("账号已锁定")
+	ErrIpLocked      = errors.New("IP已锁定")
+)
+
+type LoginGuardService interface {
+	// CheckLocked 检查用户名及IP是否被锁定，返回剩余锁定时长
+	CheckLocked(ctx context.Context, username, ip string) (time.Duration, error)
+	// CaptchaRequired 登录是否需要验证码
+	CaptchaRequired(ctx context.Context, username string) bool
+	// RegisterCaptchaRequired 注册是否需要验证码
+	RegisterCaptchaRequired() bool
+	// RecordFailure 记录登录失败，返回下次登录是否需要验证码
+	RecordFailure(ctx context.Context, username, ip string) bool
+	// RecordSuccess 登录成功后清除失败次数
+	RecordSuccess(ctx context.Context, username string)
+	// Unlock 管理员解锁用户名，ip 不为空时同时解锁IP
+	Unlock(ctx context.Context, username, ip, operatorId, operator string) error
+}
+
+type loginGuardService struct {
+	repo     repositorySystem.LoginFailureRepository
+	auditSvc serviceLogger.AuditLogService
+	captcha  config.CaptchaConfig
+	rule     repositorySystem.LoginFailureRule
+}
+
+func NewLoginGuardService(repo repositorySystem.LoginFailureRepository, auditSvc serviceLogger.AuditLogService,
+	captcha config.CaptchaConfig, lockout config.LockoutConfig) LoginGuardService {
+	rule := repositorySystem.LoginFailureRule{
+		Window:          time.Duration(lockout.Window) * time.Second,
+		UserThreshold:   lockout.Threshold,
+		IpThreshold:     lockout.IpThreshold,
+		LockDuration:    time.Duration(lockout.LockDuration) * time.Second,
+		MaxLockDuration: time.Duration(lockout.MaxLockDuration) * time.Second,
+	}
+	if rule.Window <= 0 {
+		rule.Window = time.Minute * 15
+	}
+	if rule.LockDuration <= 0 {
+		rule.LockDuration = time.Minute
+	}
+	if rule.MaxLockDuration < rule.LockDuration {
+		rule.MaxLockDuration = max(time.Hour, rule.LockDuration)
+	}
+
+	return &loginGuardService{
+		repo:     repo,
+		auditSvc: auditSvc,
+		captcha:  captcha,
+		rule:     rule,
+	}
+}
+
+// CheckLocked 检查用户名及IP是否被锁定
+func (svc *loginGuardService) CheckLocked(ctx context.Context, username, ip string) (time.Duration, error) {
+	if !svc.lockoutEnabled() {
+		return 0, nil
+	}
+
+	userTTL, ipTTL, err := svc.repo.GetLockTTL(ctx, username, ip)
+	if err != nil {
+		// redis 异常时不阻断登录
+		zap.L().Error("获取登录锁定状态异常", zap.Error(err))
+		return 0, nil
+	}
+
+	switch {
+	case userTTL > 0:
+		return userTTL, ErrAccountLocked
+	case ipTTL > 0:
+		return ipTTL, ErrIpLocked
+	default:
+		return 0, nil
+	}
+}
+
+// CaptchaRequired 登录是否需要验证码
+func (svc *loginGuardService) CaptchaRequired(ctx context.Context, username string) bool {
+	if !svc.captcha.Enabled {
+		return false
+	}
+	if svc.captcha.FailureThreshold <= 0 {
+		return true
+	}
+
+	failures, err := svc.repo.GetFailures(ctx, username)
+	if err != nil {
+		// redis 异常时要求验证码
+		zap.L().Error("获取登录失败次数异常", zap.Error(err))
+		return true
+	}
+	return failures >= int64(svc.captcha.FailureThreshold)
+}
+
+// RegisterCaptchaRequired 注册是否需要验证码
+func (svc *loginGuardService) RegisterCaptchaRequired() bool {
+	return svc.captcha.Enabled
+}
+
+// RecordFailure 记录登录失败，返回下次登录是否需要验证码
+func (svc *loginGuardService) RecordFailure(ctx context.Context, username, ip string) bool {
+	if !svc.captcha.Enabled && !svc.lockoutEnabled() {
+		return false
+	}
+
+	result, err := svc.repo.IncrFailures(ctx, username, ip, svc.rule)
+	if err != nil {
+		zap.L().Error("记录登录失败次数异常", zap.Error(err))
+		return svc.captcha.Enabled
+	}
+
+	if result.UserLock > 0 {
+		svc.auditSvc.Record(ctx, domainLogger.AuditLog{
+			AuditLogger: modelLogger.AuditLogger{
+				Event:     audit.EventConstAccountLocked,
+				Username:  username,
+				RequestIp: ip,
+				Detail:    fmt.Sprintf("连续登录失败%d次，锁定%v", result.UserFailures, result.UserLock),
+			},
+		})
+	}
+	if result.IpLock > 0 {
+		svc.auditSvc.Record(ctx, domainLogger.AuditLog{
+			AuditLogger: modelLogger.AuditLogger{
+				Event:     audit.EventConstIpLocked,
+				Username:  username,
+				RequestIp: ip,
+				Detail:    fmt.Sprintf("IP连续登录失败%d次，锁定%v", result.IpFailures, result.IpLock),
+			},
+		})
+	}
+
+	return svc.captcha.Enabled && result.UserFailures >= int64(svc.captcha.FailureThreshold)
+}
+
+// RecordSuccess 登录成功后清除失败次数
+func (svc *loginGuardService) RecordSuccess(ctx context.Context, username string) {
+	if !svc.captcha.Enabled && !svc.lockoutEnabled() {
+		return
+	}
+
+	if err := svc.repo.ResetFailures(ctx, username); err != nil {
+		zap.L().Error("清除登录失败次数异常", zap.Error(err))
+	}
+}
+
+// Unlock 管理员解锁
+func (svc *loginGuardService) Unlock(ctx context.Context, username, ip, operatorId, operator string) error {
+	if err := svc.repo.Unlock(ctx, username); err != nil {
+		return fmt.Errorf("解锁用户失败: %w", err)
+	}
+	svc.auditSvc.Record(ctx, domainLogger.AuditLog{
+		AuditLogger: modelLogger.AuditLogger{
+			Event:      audit.EventConstAccountUnlocked,
+			Username:   username,
+			OperatorId: operatorId,
+			Operator:   operator,
+			Detail:     "管理员解锁账号",
+		},
+	})
+
+	if ip == "" {
+		return nil
+	}
+	if err := svc.repo.UnlockIp(ctx, ip); err != nil {
+		return fmt.Errorf("解锁IP失败: %w", err)
+	}
+	svc.auditSvc.Record(ctx, domainLogger.AuditLog{
+		AuditLogger: modelLogger.AuditLogger{
+			Event:      audit.EventConstIpUnlocked,
+			Username:   username,
+			RequestIp:  ip,
+			OperatorId: operatorId,
+			Operator:   operator,
+			Detail:     "管理员解锁IP",
+		},
+	})
+
+	return nil
+}
+
+// lockoutEnabled 是否启用登录锁定
+func (svc *loginGuardService) lockoutEnabled() bool {
+	return svc.rule.UserThreshold > 0 || svc.rule.IpThreshold > 0
+}
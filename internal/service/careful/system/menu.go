@@ -14,6 +14,7 @@ import (
 	domainSystem "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/system"
 	repositorySystem "github.com/carefuly/carefuly-admin-go-gin/internal/repository/repository/careful/system"
 	"github.com/go-sql-driver/mysql"
+	"go.uber.org/zap"
 )
 
 // MenuTree 菜单树形结构
@@ -42,12 +43,14 @@ type MenuService interface {
 }
 
 type menuService struct {
-	repo repositorySystem.MenuRepository
+	repo           repositorySystem.MenuRepository
+	permissionRepo repositorySystem.PermissionRepository
 }
 
-func NewMenuService(repo repositorySystem.MenuRepository) MenuService {
+func NewMenuService(repo repositorySystem.MenuRepository, permissionRepo repositorySystem.PermissionRepository) MenuService {
 	return &menuService{
-		repo: repo,
+		repo:           repo,
+		permissionRepo: permissionRepo,
 	}
 }
 
@@ -70,6 +73,8 @@ func (svc *menuService) Create(ctx context.Context, domain domainSystem.Menu) er
 		return err
 	}
 
+	svc.invalidatePermission(ctx)
+
 	return nil
 }
 
@@ -82,12 +87,17 @@ func (svc *menuService) Delete(ctx context.Context, id string) error {
 	if rowsAffected == 0 {
 		return repositorySystem.ErrMenuNotFound
 	}
+	svc.invalidatePermission(ctx)
 	return err
 }
 
 // BatchDelete 批量删除
 func (svc *menuService) BatchDelete(ctx context.Context, ids []string) error {
-	return svc.repo.BatchDelete(ctx, ids)
+	if err := svc.repo.BatchDelete(ctx, ids); err != nil {
+		return err
+	}
+	svc.invalidatePermission(ctx)
+	return nil
 }
 
 // Update 更新
@@ -113,6 +123,8 @@ func (svc *menuService) Update(ctx context.Context, domain domainSystem.Menu) er
 		}
 	}
 
+	svc.invalidatePermission(ctx)
+
 	return nil
 }
 
@@ -191,3 +203,11 @@ func (svc *menuService) IsDuplicateEntryError(err error) bool {
 	}
 	return false
 }
+
+// invalidatePermission 菜单变更后清理全部用户权限及动态路由缓存
+func (svc *menuService) invalidatePermission(ctx context.Context) {
+	if err := svc.permissionRepo.DelAll(ctx); err != nil {
+		// 网络崩了，也可能是 redis 崩了
+		zap.L().Error("清理用户权限缓存失败", zap.Error(err))
+	}
+}
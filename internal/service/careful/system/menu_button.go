@@ -14,6 +14,7 @@ import (
 	domainSystem "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/system"
 	repositorySystem "github.com/carefuly/carefuly-admin-go-gin/internal/repository/repository/careful/system"
 	"github.com/carefuly/carefuly-admin-go-gin/pkg/constants/careful/system/menu"
+	"go.uber.org/zap"
 )
 
 type MenuAndButton struct {
@@ -47,14 +48,17 @@ type MenuButtonService interface {
 }
 
 type menuButtonService struct {
-	repo     repositorySystem.MenuButtonRepository
-	menuRepo repositorySystem.MenuRepository
+	repo           repositorySystem.MenuButtonRepository
+	menuRepo       repositorySystem.MenuRepository
+	permissionRepo repositorySystem.PermissionRepository
 }
 
-func NewMenuButtonService(repo repositorySystem.MenuButtonRepository, menuRepo repositorySystem.MenuRepository) MenuButtonService {
+func NewMenuButtonService(repo repositorySystem.MenuButtonRepository, menuRepo repositorySystem.MenuRepository,
+	permissionRepo repositorySystem.PermissionRepository) MenuButtonService {
 	return &menuButtonService{
-		repo:     repo,
-		menuRepo: menuRepo,
+		repo:           repo,
+		menuRepo:       menuRepo,
+		permissionRepo: permissionRepo,
 	}
 }
 
@@ -72,12 +76,17 @@ func (svc *menuButtonService) Delete(ctx context.Context, id string) error {
 	if rowsAffected == 0 {
 		return repositorySystem.ErrMenuButtonNotFound
 	}
+	svc.invalidatePermission(ctx)
 	return err
 }
 
 // BatchDelete 批量删除
 func (svc *menuButtonService) BatchDelete(ctx context.Context, ids []string) error {
-	return svc.repo.BatchDelete(ctx, ids)
+	if err := svc.repo.BatchDelete(ctx, ids); err != nil {
+		return err
+	}
+	svc.invalidatePermission(ctx)
+	return nil
 }
 
 // Update 更新
@@ -89,6 +98,8 @@ func (svc *menuButtonService) Update(ctx context.Context, domain domainSystem.Me
 		}
 		return err
 	}
+	// 接口地址或请求方式可能已变更
+	svc.invalidatePermission(ctx)
 	return err
 }
 
@@ -182,3 +193,11 @@ func (svc *menuButtonService) GetListByMenuIds(ctx context.Context, menuIds []st
 func (svc *menuButtonService) GetListAll(ctx context.Context, filters domainSystem.MenuButtonFilter) ([]domainSystem.MenuButton, error) {
 	return svc.repo.GetListAll(ctx, filters)
 }
+
+// invalidatePermission 按钮变更后清理全部用户权限缓存
+func (svc *menuButtonService) invalidatePermission(ctx context.Context) {
+	if err := svc.permissionRepo.DelAll(ctx); err != nil {
+		// 网络崩了，也可能是 redis 崩了
+		zap.L().Error("清理用户权限缓存失败", zap.Error(err))
+	}
+}
@@ -14,6 +14,7 @@ import (
 	domainSystem "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/system"
 	repositorySystem "github.com/carefuly/carefuly-admin-go-gin/internal/repository/repository/careful/system"
 	"github.com/carefuly/carefuly-admin-go-gin/pkg/constants/careful/system/menu"
+	"go.uber.org/zap"
 )
 
 type MenuAndColumn struct {
@@ -47,20 +48,28 @@ type MenuColumnService interface {
 }
 
 type menuColumnService struct {
-	repo     repositorySystem.MenuColumnRepository
-	menuRepo repositorySystem.MenuRepository
+	repo           repositorySystem.MenuColumnRepository
+	menuRepo       repositorySystem.MenuRepository
+	permissionRepo repositorySystem.PermissionRepository
 }
 
-func NewMenuColumnService(repo repositorySystem.MenuColumnRepository, menuRepo repositorySystem.MenuRepository) MenuColumnService {
+func NewMenuColumnService(repo repositorySystem.MenuColumnRepository, menuRepo repositorySystem.MenuRepository,
+	permissionRepo repositorySystem.PermissionRepository) MenuColumnService {
 	return &menuColumnService{
-		repo:     repo,
-		menuRepo: menuRepo,
+		repo:           repo,
+		menuRepo:       menuRepo,
+		permissionRepo: permissionRepo,
 	}
 }
 
 // Create 创建
 func (svc *menuColumnService) Create(ctx context.Context, domain domainSystem.MenuColumn) error {
-	return svc.repo.Create(ctx, domain)
+	if err := svc.repo.Create(ctx, domain); err != nil {
+		return err
+	}
+	// 新增的列默认对未授权的角色隐藏
+	svc.invalidatePermission(ctx)
+	return nil
 }
 
 // Delete 删除
@@ -72,12 +81,17 @@ func (svc *menuColumnService) Delete(ctx context.Context, id string) error {
 	if rowsAffected == 0 {
 		return repositorySystem.ErrMenuColumnNotFound
 	}
+	svc.invalidatePermission(ctx)
 	return err
 }
 
 // BatchDelete 批量删除
 func (svc *menuColumnService) BatchDelete(ctx context.Context, ids []string) error {
-	return svc.repo.BatchDelete(ctx, ids)
+	if err := svc.repo.BatchDelete(ctx, ids); err != nil {
+		return err
+	}
+	svc.invalidatePermission(ctx)
+	return nil
 }
 
 // Update 更新
@@ -89,6 +103,8 @@ func (svc *menuColumnService) Update(ctx context.Context, domain domainSystem.Me
 		}
 		return err
 	}
+	// 字段名或状态可能已变更
+	svc.invalidatePermission(ctx)
 	return err
 }
 
@@ -182,3 +198,11 @@ func (svc *menuColumnService) GetListByMenuIds(ctx context.Context, menuIds []st
 func (svc *menuColumnService) GetListAll(ctx context.Context, filters domainSystem.MenuColumnFilter) ([]domainSystem.MenuColumn, error) {
 	return svc.repo.GetListAll(ctx, filters)
 }
+
+// invalidatePermission 数据列变更后清理全部用户权限缓存
+func (svc *menuColumnService) invalidatePermission(ctx context.Context) {
+	if err := svc.permissionRepo.DelAll(ctx); err != nil {
+		// 网络崩了，也可能是 redis 崩了
+		zap.L().Error("清理用户权限缓存失败", zap.Error(err))
+	}
+}
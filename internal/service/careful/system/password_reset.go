@@ -0,0 +1,192 @@
+/**
+ * Description：
+ * FileName：password_reset.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 02:40:15
+ * Remark：
+ */
+
+package system
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	domainLogger "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/logger"
+	domainSystem "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/system"
+	modelLogger "github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/logger"
+	repositorySystem "github.com/carefuly/carefuly-admin-go-gin/internal/repository/repository/careful/system"
+	repositoryThird "github.com/carefuly/carefuly-admin-go-gin/internal/repository/repository/careful/third"
+	serviceLogger "github.com/carefuly/carefuly-admin-go-gin/internal/service/careful/logger"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/constants/careful/logger/audit"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/mail"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/third/captcha"
+	"go.uber.org/zap"
+	"math/big"
+	"strings"
+	"time"
+)
+
+const (
+	passwordResetCodeLength   = 6                // 验证码位数
+	passwordResetCodeExpire   = 10 * time.Minute // 验证码有效期
+	passwordResetCodeInterval = time.Minute      // 重新发送间隔
+	passwordResetIpLimit      = 10               // 同一IP每小时最多发送次数
+)
+
+var (
+	ErrMailNotConfigured    = mail.ErrNotConfigured
+	ErrPasswordResetTooMany = repositoryThird.ErrCaptchaSendTooMany
+)
+
+type PasswordResetService interface {
+	// SendCode 向邮箱发送找回密码验证码，邮箱未对应唯一的启用用户时静默忽略，避免暴露账号是否存在
+	// 发送频率在查找用户前按邮箱及IP限制，超出时返回 ErrPasswordResetTooMany
+	SendCode(ctx context.Context, email, username, ip string) error
+	// Reset 校验邮箱验证码后设置新密码，返回被重置的用户
+	Reset(ctx context.Context, email, username, code, newPassword, ip string) (domainSystem.User, error)
+}
+
+type passwordResetService struct {
+	userRepo    repositorySystem.UserRepository
+	userSvc     UserService
+	captchaRepo repositoryThird.CaptchaRepository
+	limitRepo   repositorySystem.PasswordResetLimitRepository
+	sender      mail.Sender
+	auditSvc    serviceLogger.AuditLogService
+}
+
+func NewPasswordResetService(userRepo repositorySystem.UserRepository, userSvc UserService,
+	captchaRepo repositoryThird.CaptchaRepository, limitRepo repositorySystem.PasswordResetLimitRepository,
+	sender mail.Sender, auditSvc serviceLogger.AuditLogService) PasswordResetService {
+	return &passwordResetService{
+		userRepo:    userRepo,
+		userSvc:     userSvc,
+		captchaRepo: captchaRepo,
+		limitRepo:   limitRepo,
+		sender:      sender,
+		auditSvc:    auditSvc,
+	}
+}
+
+// SendCode 发送验证码
+// 频率限制之后的结果(用户不存在、按用户限制、邮件发送失败)均不返回错误，响应与账号是否存在无关
+func (svc *passwordResetService) SendCode(ctx context.Context, email, username, ip string) error {
+	allowed, err := svc.limitRepo.Acquire(ctx, strings.ToLower(strings.TrimSpace(email)), ip, repositorySystem.PasswordResetLimitRule{
+		EmailInterval: passwordResetCodeInterval,
+		IpLimit:       passwordResetIpLimit,
+		IpWindow:      time.Hour,
+	})
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return ErrPasswordResetTooMany
+	}
+
+	user, ok, err := svc.findUser(ctx, email, username)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		zap.L().Info("找回密码邮箱未匹配到用户", zap.String("email", email), zap.String("ip", ip))
+		return nil
+	}
+
+	code, err := newPasswordResetCode()
+	if err != nil {
+		return err
+	}
+	// 验证码按用户保存，同一用户可能通过大小写不同的邮箱重复请求
+	if err := svc.captchaRepo.SetWithExpire(ctx, user.Id, code, captcha.BizPasswordReset,
+		passwordResetCodeExpire, passwordResetCodeInterval); err != nil {
+		zap.L().Warn("找回密码验证码保存失败", zap.String("userId", user.Id), zap.Error(err))
+		return nil
+	}
+
+	err = svc.sender.Send(ctx, mail.Message{
+		To:      []string{user.Email},
+		Subject: "找回密码验证码",
+		Body: fmt.Sprintf("您好，%s：\n\n您正在找回账号 %s 的密码，验证码为 %s，%d 分钟内有效。\n如非本人操作，请忽略本邮件。",
+			user.Name, user.Username, code, int(passwordResetCodeExpire.Minutes())),
+	})
+	if err != nil {
+		zap.L().Error("发送找回密码邮件失败", zap.String("userId", user.Id), zap.Error(err))
+	}
+	return nil
+}
+
+// Reset 重置密码
+func (svc *passwordResetService) Reset(ctx context.Context, email, username, code, newPassword, ip string) (domainSystem.User, error) {
+	// 先校验与用户无关的复杂度要求，避免验证码已使用后才提示密码不合规
+	if err := svc.userSvc.CheckPassword(newPassword, ""); err != nil {
+		return domainSystem.User{}, err
+	}
+
+	user, ok, err := svc.findUser(ctx, email, username)
+	if err != nil {
+		return domainSystem.User{}, err
+	}
+	if !ok {
+		// 与验证码不存在时的响应一致
+		return domainSystem.User{}, repositoryThird.ErrCaptchaNotFound
+	}
+
+	if _, err := svc.captchaRepo.Verify(ctx, user.Id, captcha.BizPasswordReset, code); err != nil {
+		return domainSystem.User{}, err
+	}
+
+	if err := svc.userSvc.RecoverPassword(ctx, user.Id, newPassword); err != nil {
+		return domainSystem.User{}, err
+	}
+
+	svc.auditSvc.Record(ctx, domainLogger.AuditLog{
+		AuditLogger: modelLogger.AuditLogger{
+			Event:      audit.EventConstPasswordRecovered,
+			Username:   user.Username,
+			RequestIp:  ip,
+			OperatorId: user.Id,
+			Operator:   user.Username,
+			Detail:     "通过邮箱验证码找回密码",
+		},
+	})
+	return user, nil
+}
+
+// findUser 查找邮箱对应的启用用户，邮箱被多个用户使用时需指定用户名
+func (svc *passwordResetService) findUser(ctx context.Context, email, username string) (domainSystem.User, bool, error) {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return domainSystem.User{}, false, nil
+	}
+
+	users, err := svc.userRepo.GetListByEmail(ctx, email)
+	if err != nil {
+		return domainSystem.User{}, false, err
+	}
+
+	var matched []domainSystem.User
+	for _, user := range users {
+		if !user.Status || (username != "" && user.Username != username) {
+			continue
+		}
+		matched = append(matched, user)
+	}
+	if len(matched) != 1 {
+		return domainSystem.User{}, false, nil
+	}
+	return matched[0], true, nil
+}
+
+// newPasswordResetCode 生成数字验证码
+func newPasswordResetCode() (string, error) {
+	var b strings.Builder
+	for range passwordResetCodeLength {
+		n, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", fmt.Errorf("生成验证码失败: %w", err)
+		}
+		b.WriteByte(byte('0' + n.Int64()))
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,143 @@
+/**
+ * Description：
+ * FileName：password_reset_test.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 02:52:40
+ * Remark：
+ */
+
+package system
+
+import (
+	"context"
+	"errors"
+	config "github.com/carefuly/carefuly-admin-go-gin/config/file"
+	domainSystem "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/system"
+	modelSystem "github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/system"
+	repositorySystem "github.com/carefuly/carefuly-admin-go-gin/internal/repository/repository/careful/system"
+	repositoryThird "github.com/carefuly/carefuly-admin-go-gin/internal/repository/repository/careful/third"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/models"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/bcrypt"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/mail"
+	"regexp"
+	"testing"
+	"time"
+)
+
+// stubCaptchaRepository 内存验证码存储，验证成功后删除
+type stubCaptchaRepository struct {
+	repositoryThird.CaptchaRepository
+	codes map[string]string
+}
+
+func (r *stubCaptchaRepository) SetWithExpire(ctx context.Context, id, code string, bizType string, expire, interval time.Duration) error {
+	r.codes[id+":"+bizType] = code
+	return nil
+}
+
+func (r *stubCaptchaRepository) Verify(ctx context.Context, id string, biz string, code string) (bool, error) {
+	expected, ok := r.codes[id+":"+biz]
+	if !ok {
+		return false, repositoryThird.ErrCaptchaNotFound
+	}
+	if expected != code {
+		return false, repositoryThird.ErrCaptchaIncorrect
+	}
+	delete(r.codes, id+":"+biz)
+	return true, nil
+}
+
+// stubPasswordResetLimitRepository 同一邮箱只允许发送一次
+type stubPasswordResetLimitRepository struct {
+	sent map[string]bool
+}
+
+func (r *stubPasswordResetLimitRepository) Acquire(ctx context.Context, email, ip string, rule repositorySystem.PasswordResetLimitRule) (bool, error) {
+	if r.sent[email] {
+		return false, nil
+	}
+	r.sent[email] = true
+	return true, nil
+}
+
+// stubMailSender 记录发送的邮件
+type stubMailSender struct {
+	sent []mail.Message
+}
+
+func (s *stubMailSender) Send(ctx context.Context, msg mail.Message) error {
+	s.sent = append(s.sent, msg)
+	return nil
+}
+
+func TestPasswordResetService(t *testing.T) {
+	hashed, err := bcrypt.HashPassword("Old#Pass1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := &stubUserRepository{
+		user: domainSystem.User{User: modelSystem.User{
+			CoreModels: models.CoreModels{Id: "1"},
+			Status:     true,
+			Username:   "admin",
+			Email:      "admin@example.com",
+			Password:   hashed,
+		}},
+	}
+	userSvc := NewUserService(repo, nil, &stubPasswordHistoryRepository{}, config.PasswordPolicyConfig{MinLength: 8, HistoryCount: 2})
+	captchaRepo := &stubCaptchaRepository{codes: map[string]string{}}
+	sender := &stubMailSender{}
+	limitRepo := &stubPasswordResetLimitRepository{sent: map[string]bool{}}
+	svc := NewPasswordResetService(repo, userSvc, captchaRepo, limitRepo, sender, stubAuditLogService{})
+	ctx := context.Background()
+
+	// 未匹配到用户时不发送邮件，也不返回错误
+	if err := svc.SendCode(ctx, "nobody@example.com", "", "127.0.0.1"); err != nil {
+		t.Fatalf("未知邮箱应静默忽略，实际: %v", err)
+	}
+	if len(sender.sent) != 0 {
+		t.Fatalf("未知邮箱不应发送邮件")
+	}
+	if _, err := svc.Reset(ctx, "nobody@example.com", "", "000000", "New#Pass1", "127.0.0.1"); !errors.Is(err, repositoryThird.ErrCaptchaNotFound) {
+		t.Fatalf("未知邮箱应提示验证码不存在，实际: %v", err)
+	}
+
+	if err := svc.SendCode(ctx, "admin@example.com", "", "127.0.0.1"); err != nil {
+		t.Fatal(err)
+	}
+	if len(sender.sent) != 1 || sender.sent[0].To[0] != "admin@example.com" {
+		t.Fatalf("应向用户邮箱发送验证码，实际: %+v", sender.sent)
+	}
+	code := regexp.MustCompile(`\d{6}`).FindString(sender.sent[0].Body)
+
+	// 发送频率在查找用户前限制，账号是否存在的响应一致
+	for _, email := range []string{"nobody@example.com", "Admin@Example.com "} {
+		if err := svc.SendCode(ctx, email, "", "127.0.0.1"); !errors.Is(err, ErrPasswordResetTooMany) {
+			t.Fatalf("%s 重复发送应被限制，实际: %v", email, err)
+		}
+	}
+	if len(sender.sent) != 1 {
+		t.Fatalf("频率限制后不应发送邮件，实际: %d", len(sender.sent))
+	}
+
+	// 密码不符合复杂度时不消耗验证码
+	if _, err := svc.Reset(ctx, "admin@example.com", "", code, "short", "127.0.0.1"); !errors.Is(err, ErrPasswordPolicy) {
+		t.Fatalf("弱密码应被拒绝，实际: %v", err)
+	}
+	if _, err := svc.Reset(ctx, "admin@example.com", "", "abcdef", "New#Pass1", "127.0.0.1"); !errors.Is(err, repositoryThird.ErrCaptchaIncorrect) {
+		t.Fatalf("错误验证码应被拒绝，实际: %v", err)
+	}
+
+	user, err := svc.Reset(ctx, "admin@example.com", "admin", code, "New#Pass1", "127.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Id != "1" || !bcrypt.ComparePasswords(repo.user.Password, "New#Pass1") {
+		t.Fatalf("找回密码后应更新为新密码")
+	}
+
+	// 验证码只能使用一次
+	if _, err := svc.Reset(ctx, "admin@example.com", "", code, "New#Pass2", "127.0.0.1"); !errors.Is(err, repositoryThird.ErrCaptchaNotFound) {
+		t.Fatalf("验证码使用后应失效，实际: %v", err)
+	}
+}
@@ -0,0 +1,158 @@
+/**
+ * Description：
+ * FileName：permission.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 10:15:09
+ * Remark：
+ */
+
+package system
+
+import (
+	"context"
+	domainSystem "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/system"
+	repositorySystem "github.com/carefuly/carefuly-admin-go-gin/internal/repository/repository/careful/system"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/constants/careful/system/menu"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/routematch"
+	"go.uber.org/zap"
+	"strings"
+)
+
+type PermissionService interface {
+	GetUserPermission(ctx context.Context, userId string) (domainSystem.UserPermission, error)
+	GetPermissionInfo(ctx context.Context, userId string) (domainSystem.PermissionInfo, error)
+	GetRoutes(ctx context.Context, userId string) ([]*domainSystem.Route, error)
+	MatchApiPermission(permission domainSystem.UserPermission, method, route string) (domainSystem.ApiPermission, bool)
+}
+
+type permissionService struct {
+	repo    repositorySystem.PermissionRepository
+	menuSvc MenuService
+}
+
+func NewPermissionService(repo repositorySystem.PermissionRepository, menuSvc MenuService) PermissionService {
+	return &permissionService{
+		repo:    repo,
+		menuSvc: menuSvc,
+	}
+}
+
+// GetUserPermission 获取用户权限集合
+func (svc *permissionService) GetUserPermission(ctx context.Context, userId string) (domainSystem.UserPermission, error) {
+	return svc.repo.GetByUserId(ctx, userId)
+}
+
+// GetPermissionInfo 获取当前用户的角色、按钮权限值及可查看的列
+func (svc *permissionService) GetPermissionInfo(ctx context.Context, userId string) (domainSystem.PermissionInfo, error) {
+	permission, err := svc.repo.GetByUserId(ctx, userId)
+	if err != nil {
+		return domainSystem.PermissionInfo{}, err
+	}
+
+	info := domainSystem.PermissionInfo{
+		SuperAdmin: permission.SuperAdmin,
+		Roles:      permission.RoleCodes,
+		Buttons:    []string{},
+		Columns:    permission.Columns,
+	}
+	seen := make(map[string]struct{}, len(permission.Apis))
+	for _, api := range permission.Apis {
+		if api.Code == "" {
+			continue
+		}
+		if _, ok := seen[api.Code]; ok {
+			continue
+		}
+		seen[api.Code] = struct{}{}
+		info.Buttons = append(info.Buttons, api.Code)
+	}
+
+	return info, nil
+}
+
+// GetRoutes 获取当前用户的动态路由
+// 仅保留已授权且已启用的菜单，子菜单已授权时保留其上级目录
+func (svc *permissionService) GetRoutes(ctx context.Context, userId string) ([]*domainSystem.Route, error) {
+	routes, err := svc.repo.GetRoutes(ctx, userId)
+	if err != nil {
+		// 缓存查询出错，记录日志但继续构建
+		zap.L().Error("缓存获取错误:", zap.Error(err))
+	}
+	if routes != nil {
+		return routes, nil // 命中缓存
+	}
+
+	permission, err := svc.repo.GetByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := svc.menuSvc.GetListTree(ctx, domainSystem.MenuFilter{Status: true})
+	if err != nil {
+		return nil, err
+	}
+
+	granted := make(map[string]bool, len(permission.MenuIds))
+	for _, id := range permission.MenuIds {
+		granted[id] = true
+	}
+	routes = svc.buildRoutes(tree, granted, permission.SuperAdmin)
+
+	if err := svc.repo.SetRoutes(ctx, userId, routes); err != nil {
+		// 网络崩了，也可能是 redis 崩了
+		zap.L().Error("Redis异常", zap.Error(err))
+	}
+
+	return routes, nil
+}
+
+// buildRoutes 将菜单树转换为前端路由
+func (svc *permissionService) buildRoutes(tree []*MenuTree, granted map[string]bool, all bool) []*domainSystem.Route {
+	routes := make([]*domainSystem.Route, 0, len(tree))
+	for _, node := range tree {
+		children := svc.buildRoutes(node.Children, granted, all)
+		if !all && !granted[node.Id] && len(children) == 0 {
+			continue
+		}
+		routes = append(routes, &domainSystem.Route{
+			Id:        node.Id,
+			Path:      node.Path,
+			Name:      node.Name,
+			Component: node.Component,
+			Redirect:  node.Redirect,
+			Meta: domainSystem.RouteMeta{
+				Icon:        node.Icon,
+				Title:       node.Title,
+				IsHide:      node.IsHide,
+				IsLink:      node.IsLink,
+				IsKeepAlive: node.IsKeepAlive,
+				IsFull:      node.IsFull,
+				IsAffix:     node.IsAffix,
+			},
+			Children: children,
+		})
+	}
+	return routes
+}
+
+// MatchApiPermission 校验用户是否拥有接口权限，返回匹配到的接口权限
+// route 为 gin 路由模板(ctx.FullPath())
+// 多个接口权限同时匹配时取最精确的一个，其所属菜单决定可查看的列
+func (svc *permissionService) MatchApiPermission(permission domainSystem.UserPermission, method, route string) (domainSystem.ApiPermission, bool) {
+	// 超级管理员跳过校验
+	if permission.SuperAdmin {
+		return domainSystem.ApiPermission{}, true
+	}
+
+	matched, best := domainSystem.ApiPermission{}, -1
+	for _, api := range permission.Apis {
+		if !strings.EqualFold(menu.MethodMapping[api.Method], method) {
+			continue
+		}
+		if score := routematch.Specificity(api.Api, route); score > best {
+			matched, best = api, score
+		}
+	}
+
+	return matched, best >= 0
+}
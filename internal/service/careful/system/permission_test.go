@@ -0,0 +1,50 @@
+/**
+ * Description：
+ * FileName：permission_test.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 18:21:14
+ * Remark：
+ */
+
+package system
+
+import (
+	domainSystem "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/system"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/constants/careful/system/menu"
+	"testing"
+)
+
+func TestPermissionService_MatchApiPermission(t *testing.T) {
+	svc := NewPermissionService(nil, nil)
+
+	// 两个菜单的接口地址后缀相同，通用菜单使用参数段配置
+	apis := []domainSystem.ApiPermission{
+		{Code: "common:export", Api: "/v1/system/:module/export", Method: menu.MethodConstGET, MenuId: "common"},
+		{Code: "user:export", Api: "/v1/system/user/export", Method: menu.MethodConstGET, MenuId: "user"},
+		{Code: "role:export", Api: "/v1/system/role/export", Method: menu.MethodConstGET, MenuId: "role"},
+	}
+
+	testCases := []struct {
+		name   string
+		apis   []domainSystem.ApiPermission
+		method string
+		route  string
+		want   string
+		wantOk bool
+	}{
+		{name: "精确匹配优先", method: "GET", apis: apis, route: "/dev-api/v1/system/user/export", want: "user", wantOk: true},
+		{name: "与配置顺序无关", method: "GET", apis: []domainSystem.ApiPermission{apis[1], apis[0]}, route: "/dev-api/v1/system/user/export", want: "user", wantOk: true},
+		{name: "参数段匹配", method: "GET", apis: apis, route: "/dev-api/v1/system/post/export", want: "common", wantOk: true},
+		{name: "后缀相同的其他模块", method: "GET", apis: apis[1:2], route: "/dev-api/v1/system/role/export", wantOk: false},
+		{name: "请求方式不同", method: "POST", apis: apis, route: "/dev-api/v1/system/user/export", wantOk: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			api, ok := svc.MatchApiPermission(domainSystem.UserPermission{Apis: tc.apis}, tc.method, tc.route)
+			if ok != tc.wantOk || api.MenuId != tc.want {
+				t.Errorf("MatchApiPermission() = %q, %v, want %q, %v", api.MenuId, ok, tc.want, tc.wantOk)
+			}
+		})
+	}
+}
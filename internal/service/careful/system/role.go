@@ -14,6 +14,7 @@ import (
 	domainSystem "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/system"
 	repositorySystem "github.com/carefuly/carefuly-admin-go-gin/internal/repository/repository/careful/system"
 	"github.com/go-sql-driver/mysql"
+	"go.uber.org/zap"
 )
 
 var (
@@ -36,12 +37,14 @@ type RoleService interface {
 }
 
 type roleService struct {
-	repo repositorySystem.RoleRepository
+	repo           repositorySystem.RoleRepository
+	permissionRepo repositorySystem.PermissionRepository
 }
 
-func NewRoleService(repo repositorySystem.RoleRepository) RoleService {
+func NewRoleService(repo repositorySystem.RoleRepository, permissionRepo repositorySystem.PermissionRepository) RoleService {
 	return &roleService{
-		repo: repo,
+		repo:           repo,
+		permissionRepo: permissionRepo,
 	}
 }
 
@@ -69,6 +72,12 @@ func (svc *roleService) Create(ctx context.Context, domain domainSystem.Role) er
 
 // Delete 删除
 func (svc *roleService) Delete(ctx context.Context, id string) error {
+	// 删除后角色关联已不存在，先收集关联的用户
+	userIds, err := svc.permissionRepo.GetUserIdsByRoleIds(ctx, id)
+	if err != nil {
+		return err
+	}
+
 	var mysqlErr *mysql.MySQLError
 	rowsAffected, err := svc.repo.Delete(ctx, id)
 	if err != nil {
@@ -80,12 +89,24 @@ func (svc *roleService) Delete(ctx context.Context, id string) error {
 	if rowsAffected == 0 {
 		return repositorySystem.ErrRoleNotFound
 	}
-	return err
+
+	svc.invalidateUsers(ctx, userIds)
+	return nil
 }
 
 // BatchDelete 批量删除
 func (svc *roleService) BatchDelete(ctx context.Context, ids []string) error {
-	return svc.repo.BatchDelete(ctx, ids)
+	userIds, err := svc.permissionRepo.GetUserIdsByRoleIds(ctx, ids...)
+	if err != nil {
+		return err
+	}
+
+	if err := svc.repo.BatchDelete(ctx, ids); err != nil {
+		return err
+	}
+
+	svc.invalidateUsers(ctx, userIds)
+	return nil
 }
 
 // Update 更新
@@ -111,6 +132,9 @@ func (svc *roleService) Update(ctx context.Context, domain domainSystem.Role) er
 		}
 	}
 
+	// 角色关联的部门/菜单/按钮/列已重写，清理关联用户的权限缓存
+	svc.invalidatePermission(ctx, domain.Id)
+
 	return nil
 }
 
@@ -139,6 +163,24 @@ func (svc *roleService) GetListAll(ctx context.Context, filter domainSystem.Role
 	return svc.repo.GetListAll(ctx, filter)
 }
 
+// invalidatePermission 清理关联指定角色的用户权限缓存
+func (svc *roleService) invalidatePermission(ctx context.Context, ids ...string) {
+	if err := svc.permissionRepo.DelByRoleIds(ctx, ids...); err != nil {
+		// 网络崩了，也可能是 redis 崩了
+		zap.L().Error("清理用户权限缓存失败", zap.Strings("roleIds", ids), zap.Error(err))
+	}
+}
+
+// invalidateUsers 角色删除后清理原关联用户的权限缓存
+func (svc *roleService) invalidateUsers(ctx context.Context, userIds []string) {
+	if len(userIds) == 0 {
+		return
+	}
+	if err := svc.permissionRepo.DelByUserIds(ctx, userIds...); err != nil {
+		zap.L().Error("清理用户权限缓存失败", zap.Strings("userIds", userIds), zap.Error(err))
+	}
+}
+
 // IsDuplicateEntryError 判断是否是唯一冲突错误
 func (svc *roleService) IsDuplicateEntryError(err error) bool {
 	var mysqlErr *mysql.MySQLError
@@ -0,0 +1,203 @@
+/**
+ * Description：
+ * FileName：session.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 19:02:48
+ * Remark：
+ */
+
+package system
+
+import (
+	"context"
+	"errors"
+	config "github.com/carefuly/carefuly-admin-go-gin/config/file"
+	domainSystem "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/system"
+	repositorySystem "github.com/carefuly/carefuly-admin-go-gin/internal/repository/repository/careful/system"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/filters"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/jwt"
+	"sort"
+	"strings"
+	"time"
+)
+
+var ErrSessionNotFound = repositorySystem.ErrSessionNotFound
+
+type SessionService interface {
+	// Create 记录新签发令牌的会话，单会话模式下吊销该用户的其他会话
+	Create(ctx context.Context, domain domainSystem.Session, expiration time.Duration) error
+	// Rotate 刷新令牌轮换时以新会话替换旧会话，旧访问令牌失效，令牌族保留
+	Rotate(ctx context.Context, oldJti string, domain domainSystem.Session, expiration time.Duration) error
+	// Revoke 吊销会话，将 jti 加入黑名单并吊销其刷新令牌族
+	Revoke(ctx context.Context, jti string) error
+	// RevokeFamily 吊销刷新令牌族及该族签发的全部会话
+	RevokeFamily(ctx context.Context, family string) error
+	// RevokeByUserId 吊销用户的全部会话
+	RevokeByUserId(ctx context.Context, userId string) error
+	// IsRevoked 会话是否已吊销
+	IsRevoked(ctx context.Context, jti string) (bool, error)
+
+	GetByJti(ctx context.Context, jti string) (domainSystem.Session, error)
+	// GetListPage 分页查询在线会话，受数据权限限制
+	GetListPage(ctx context.Context, filter domainSystem.SessionFilter) ([]domainSystem.Session, int64, error)
+}
+
+type sessionService struct {
+	repo          repositorySystem.SessionRepository
+	blacklist     *jwt.TokenBlacklist
+	refreshStore  *jwt.RefreshTokenStore
+	singleSession bool
+}
+
+func NewSessionService(repo repositorySystem.SessionRepository, blacklist *jwt.TokenBlacklist,
+	refreshStore *jwt.RefreshTokenStore, token config.TokenConfig) SessionService {
+	return &sessionService{
+		repo:          repo,
+		blacklist:     blacklist,
+		refreshStore:  refreshStore,
+		singleSession: token.SingleSession,
+	}
+}
+
+// Create 记录会话
+func (svc *sessionService) Create(ctx context.Context, domain domainSystem.Session, expiration time.Duration) error {
+	if svc.singleSession {
+		if err := svc.RevokeByUserId(ctx, domain.UserId); err != nil {
+			return err
+		}
+	}
+	return svc.repo.Create(ctx, domain, expiration)
+}
+
+// Rotate 轮换会话
+func (svc *sessionService) Rotate(ctx context.Context, oldJti string, domain domainSystem.Session, expiration time.Duration) error {
+	if _, err := svc.revoke(ctx, oldJti); err != nil && !errors.Is(err, ErrSessionNotFound) {
+		return err
+	}
+	return svc.repo.Create(ctx, domain, expiration)
+}
+
+// Revoke 吊销会话
+func (svc *sessionService) Revoke(ctx context.Context, jti string) error {
+	session, err := svc.revoke(ctx, jti)
+	if err != nil {
+		return err
+	}
+	if session.Family == "" {
+		return nil
+	}
+	return svc.RevokeFamily(ctx, session.Family)
+}
+
+// RevokeFamily 吊销刷新令牌族
+func (svc *sessionService) RevokeFamily(ctx context.Context, family string) error {
+	jtis, err := svc.refreshStore.FamilyJtis(ctx, family)
+	if err != nil {
+		return err
+	}
+
+	// 先吊销令牌族，阻止并发的刷新请求继续轮换
+	if err := svc.refreshStore.RevokeFamily(ctx, family); err != nil {
+		return err
+	}
+	for _, jti := range jtis {
+		if _, err := svc.revoke(ctx, jti); err != nil && !errors.Is(err, ErrSessionNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+// revoke 将会话 jti 加入黑名单并删除会话
+func (svc *sessionService) revoke(ctx context.Context, jti string) (domainSystem.Session, error) {
+	session, err := svc.repo.GetByJti(ctx, jti)
+	if err != nil {
+		return session, err
+	}
+
+	ttl, err := svc.repo.GetTTL(ctx, jti)
+	if err != nil {
+		// 会话恰好过期，令牌已自然失效
+		if errors.Is(err, ErrSessionNotFound) {
+			return session, svc.repo.Delete(ctx, session.UserId, jti)
+		}
+		return session, err
+	}
+
+	if err := svc.blacklist.AddJti(ctx, jti, ttl); err != nil {
+		return session, err
+	}
+	return session, svc.repo.Delete(ctx, session.UserId, jti)
+}
+
+// RevokeByUserId 吊销用户的全部会话
+func (svc *sessionService) RevokeByUserId(ctx context.Context, userId string) error {
+	jtis, err := svc.repo.GetJtisByUserId(ctx, userId)
+	if err != nil {
+		return err
+	}
+
+	for _, jti := range jtis {
+		if err := svc.Revoke(ctx, jti); err != nil {
+			// 集合中残留的已过期会话
+			if errors.Is(err, ErrSessionNotFound) {
+				_ = svc.repo.Delete(ctx, userId, jti)
+				continue
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// IsRevoked 会话是否已吊销
+func (svc *sessionService) IsRevoked(ctx context.Context, jti string) (bool, error) {
+	return svc.blacklist.IsJtiBlacklisted(ctx, jti)
+}
+
+// GetByJti 根据jti获取会话
+func (svc *sessionService) GetByJti(ctx context.Context, jti string) (domainSystem.Session, error) {
+	return svc.repo.GetByJti(ctx, jti)
+}
+
+// GetListPage 分页查询在线会话
+func (svc *sessionService) GetListPage(ctx context.Context, filter domainSystem.SessionFilter) ([]domainSystem.Session, int64, error) {
+	all, err := svc.repo.GetListAll(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// 按会话用户所属部门校验数据权限，本人的会话始终可见
+	scope, restricted := filters.DataScopeFromContext(ctx)
+	list := make([]domainSystem.Session, 0, len(all))
+	for _, session := range all {
+		if restricted && !scope.Allow("", session.DeptId, session.UserId) {
+			continue
+		}
+		if filter.Username != "" && !strings.Contains(session.Username, filter.Username) {
+			continue
+		}
+		if filter.RequestIp != "" && !strings.Contains(session.RequestIp, filter.RequestIp) {
+			continue
+		}
+		if filter.Suspicious && !session.Suspicious {
+			continue
+		}
+		list = append(list, session)
+	}
+
+	// 按登录时间倒序
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].LoginTime > list[j].LoginTime
+	})
+
+	total := int64(len(list))
+	page := max(filter.Page, 1)
+	pageSize := filter.PageSize
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+	start := min((page-1)*pageSize, len(list))
+	end := min(start+pageSize, len(list))
+	return list[start:end], total, nil
+}
@@ -0,0 +1,65 @@
+/**
+ * Description：
+ * FileName：session_test.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 19:40:18
+ * Remark：
+ */
+
+package system
+
+import (
+	"context"
+	config "github.com/carefuly/carefuly-admin-go-gin/config/file"
+	domainSystem "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/system"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/filters"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func (r *stubSessionRepository) GetListAll(ctx context.Context) ([]domainSystem.Session, error) {
+	list := make([]domainSystem.Session, 0, len(r.sessions))
+	for _, session := range r.sessions {
+		list = append(list, session)
+	}
+	return list, nil
+}
+
+func TestSessionService_GetListPage(t *testing.T) {
+	repo := &stubSessionRepository{sessions: map[string]domainSystem.Session{
+		"j1": {Jti: "j1", UserId: "u1", DeptId: "d1"},
+		"j2": {Jti: "j2", UserId: "u2", DeptId: "d1"},
+		"j3": {Jti: "j3", UserId: "u3", DeptId: "d2"},
+		"j4": {Jti: "j4", UserId: "admin"},
+	}}
+	svc := NewSessionService(repo, nil, nil, config.TokenConfig{})
+
+	testCases := []struct {
+		name string
+		ctx  context.Context
+		want []string
+	}{
+		{name: "不受数据权限限制", ctx: context.Background(), want: []string{"j1", "j2", "j3", "j4"}},
+		{name: "全部数据", ctx: context.WithValue(context.Background(), filters.DataScopeKey, filters.DataScope{All: true}), want: []string{"j1", "j2", "j3", "j4"}},
+		{name: "部门内会话", ctx: context.WithValue(context.Background(), filters.DataScopeKey, filters.DataScope{UserId: "u1", DeptIds: []string{"d1"}}), want: []string{"j1", "j2"}},
+		{name: "仅本人会话", ctx: context.WithValue(context.Background(), filters.DataScopeKey, filters.DataScope{UserId: "u3", Self: true}), want: []string{"j3"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			list, total, err := svc.GetListPage(tc.ctx, domainSystem.SessionFilter{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make([]string, 0, len(list))
+			for _, session := range list {
+				got = append(got, session.Jti)
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tc.want) || total != int64(len(tc.want)) {
+				t.Errorf("GetListPage() = %v, %d, want %v", got, total, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,319 @@
+/**
+ * Description：
+ * FileName：sso.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 07:18:46
+ * Remark：
+ */
+
+package system
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	config "github.com/carefuly/carefuly-admin-go-gin/config/file"
+	domainLogger "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/logger"
+	domainSystem "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/system"
+	modelLogger "github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/logger"
+	modelSystem "github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/system"
+	repositorySystem "github.com/carefuly/carefuly-admin-go-gin/internal/repository/repository/careful/system"
+	serviceLogger "github.com/carefuly/carefuly-admin-go-gin/internal/service/careful/logger"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/constants/careful/logger/audit"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/constants/careful/system/user"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/bcrypt"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/oidc"
+	"go.uber.org/zap"
+	"time"
+	"unicode/utf8"
+)
+
+// 授权请求有效期，超时后回调视为无效
+const ssoStateExpire = 10 * time.Minute
+
+// 自动创建用户时用户名、姓名、邮箱的最大长度(与用户表字段一致)
+const ssoFieldMaxLength = 50
+
+var (
+	ErrSSOProviderNotFound = errors.New("身份提供方不存在")
+	ErrSSOStateInvalid     = errors.New("授权请求无效或已过期")
+	ErrSSOAuthenticate     = errors.New("身份提供方认证失败")
+	ErrSSOUserNotLinked    = errors.New("第三方账号未关联系统用户")
+	ErrSSOUsernameConflict = errors.New("用户名已被占用，请联系管理员关联账号")
+)
+
+type SSOService interface {
+	// Providers 已配置的身份提供方
+	Providers() []domainSystem.SSOProvider
+	// Authorize 生成授权地址，state、nonce 及 PKCE verifier 保存至回调时校验
+	Authorize(ctx context.Context, provider string) (domainSystem.SSOAuthorization, error)
+	// Login 使用回调的 state 及授权码登录，返回关联的系统用户
+	Login(ctx context.Context, provider, state, code string) (domainSystem.User, error)
+}
+
+type ssoService struct {
+	userRepo     repositorySystem.UserRepository
+	identityRepo repositorySystem.UserIdentityRepository
+	auditSvc     serviceLogger.AuditLogService
+
+	configs   map[string]config.OIDCProviderConfig
+	providers map[string]IdentityProvider
+	list      []domainSystem.SSOProvider
+}
+
+func NewSSOService(userRepo repositorySystem.UserRepository, identityRepo repositorySystem.UserIdentityRepository,
+	auditSvc serviceLogger.AuditLogService, cfg config.SSOConfig) SSOService {
+	svc := &ssoService{
+		userRepo:     userRepo,
+		identityRepo: identityRepo,
+		auditSvc:     auditSvc,
+		configs:      make(map[string]config.OIDCProviderConfig, len(cfg.Providers)),
+		providers:    make(map[string]IdentityProvider, len(cfg.Providers)),
+		list:         make([]domainSystem.SSOProvider, 0, len(cfg.Providers)),
+	}
+	for _, provider := range cfg.Providers {
+		if provider.Name == "" || provider.Issuer == "" || provider.ClientId == "" {
+			zap.L().Warn("身份提供方配置不完整，已忽略", zap.String("name", provider.Name))
+			continue
+		}
+		if provider.DisplayName == "" {
+			provider.DisplayName = provider.Name
+		}
+		svc.configs[provider.Name] = provider
+		svc.providers[provider.Name] = NewOIDCIdentityProvider(provider)
+		svc.list = append(svc.list, domainSystem.SSOProvider{Name: provider.Name, DisplayName: provider.DisplayName})
+	}
+	return svc
+}
+
+// Providers 身份提供方列表
+func (svc *ssoService) Providers() []domainSystem.SSOProvider {
+	return svc.list
+}
+
+// Authorize 生成授权地址
+func (svc *ssoService) Authorize(ctx context.Context, provider string) (domainSystem.SSOAuthorization, error) {
+	idp, ok := svc.providers[provider]
+	if !ok {
+		return domainSystem.SSOAuthorization{}, ErrSSOProviderNotFound
+	}
+
+	var values [3]string
+	for i := range values {
+		value, err := oidc.RandomString()
+		if err != nil {
+			return domainSystem.SSOAuthorization{}, err
+		}
+		values[i] = value
+	}
+	state, nonce, verifier := values[0], values[1], values[2]
+
+	authURL, err := idp.AuthURL(ctx, state, nonce, verifier)
+	if err != nil {
+		return domainSystem.SSOAuthorization{}, err
+	}
+	err = svc.identityRepo.SaveState(ctx, state, domainSystem.SSOState{
+		Provider: provider,
+		Verifier: verifier,
+		Nonce:    nonce,
+	}, ssoStateExpire)
+	if err != nil {
+		return domainSystem.SSOAuthorization{}, err
+	}
+
+	return domainSystem.SSOAuthorization{AuthUrl: authURL, State: state}, nil
+}
+
+// Login 单点登录
+func (svc *ssoService) Login(ctx context.Context, provider, state, code string) (domainSystem.User, error) {
+	idp, ok := svc.providers[provider]
+	if !ok {
+		return domainSystem.User{}, ErrSSOProviderNotFound
+	}
+
+	// state 只能使用一次，且须由同一身份提供方发起
+	saved, err := svc.identityRepo.TakeState(ctx, state)
+	if err != nil {
+		if errors.Is(err, repositorySystem.ErrSSOStateNotFound) {
+			return domainSystem.User{}, ErrSSOStateInvalid
+		}
+		return domainSystem.User{}, err
+	}
+	if saved.Provider != provider {
+		return domainSystem.User{}, ErrSSOStateInvalid
+	}
+
+	identity, err := idp.Authenticate(ctx, code, saved.Verifier, saved.Nonce)
+	if err != nil {
+		zap.L().Warn("单点登录认证失败", zap.String("provider", provider), zap.Error(err))
+		return domainSystem.User{}, fmt.Errorf("%w: %v", ErrSSOAuthenticate, err)
+	}
+
+	account, err := svc.resolveUser(ctx, svc.configs[provider], identity)
+	if err != nil {
+		return domainSystem.User{}, err
+	}
+	if !account.Status {
+		return domainSystem.User{}, ErrUserDisabled
+	}
+	return account, nil
+}
+
+// resolveUser 查找第三方身份关联的用户，首次登录时按配置关联已有用户或自动创建
+func (svc *ssoService) resolveUser(ctx context.Context, cfg config.OIDCProviderConfig,
+	identity domainSystem.ExternalIdentity) (domainSystem.User, error) {
+	link, err := svc.identityRepo.GetByProviderSubject(ctx, identity.Provider, identity.Subject)
+	if err == nil {
+		account, err := svc.userRepo.GetById(ctx, link.UserId)
+		if err != nil {
+			return domainSystem.User{}, err
+		}
+		if account.Id == "" {
+			return domainSystem.User{}, ErrUserNotFound
+		}
+		if err := svc.identityRepo.UpdateLastLogin(ctx, link.Id, identity.Email, time.Now()); err != nil {
+			zap.L().Error("更新第三方身份登录时间失败", zap.String("id", link.Id), zap.Error(err))
+		}
+		return account, nil
+	}
+	if !errors.Is(err, repositorySystem.ErrUserIdentityNotFound) {
+		return domainSystem.User{}, err
+	}
+
+	account, found, err := svc.matchUser(ctx, cfg, identity)
+	if err != nil {
+		return domainSystem.User{}, err
+	}
+	provisioned := false
+	if !found {
+		if !cfg.AutoCreate {
+			return domainSystem.User{}, ErrSSOUserNotLinked
+		}
+		if account, err = svc.provisionUser(ctx, cfg, identity); err != nil {
+			return domainSystem.User{}, err
+		}
+		provisioned = true
+	}
+
+	now := time.Now()
+	err = svc.identityRepo.Create(ctx, domainSystem.UserIdentity{
+		UserIdentity: modelSystem.UserIdentity{
+			UserId:        account.Id,
+			Provider:      identity.Provider,
+			Subject:       identity.Subject,
+			Email:         identity.Email,
+			LastLoginTime: &now,
+		},
+	})
+	if err != nil {
+		return domainSystem.User{}, err
+	}
+
+	if provisioned {
+		svc.record(ctx, audit.EventConstUserProvisioned, account.Username,
+			fmt.Sprintf("通过身份提供方 %s 自动创建用户，第三方标识 %s", identity.Provider, identity.Subject))
+	}
+	svc.record(ctx, audit.EventConstIdentityLinked, account.Username,
+		fmt.Sprintf("关联身份提供方 %s，第三方标识 %s", identity.Provider, identity.Subject))
+	return account, nil
+}
+
+// matchUser 按配置的方式匹配已有用户
+// 不支持按用户名匹配，第三方账号的用户名可由用户自行修改，按用户名关联可能接管本地账号
+func (svc *ssoService) matchUser(ctx context.Context, cfg config.OIDCProviderConfig,
+	identity domainSystem.ExternalIdentity) (domainSystem.User, bool, error) {
+	switch cfg.MatchBy {
+	case "email":
+		// 未验证的邮箱可能被冒用，不用于关联
+		if identity.Email == "" || !identity.EmailVerified {
+			return domainSystem.User{}, false, nil
+		}
+		users, err := svc.userRepo.GetListByEmail(ctx, identity.Email)
+		if err != nil {
+			return domainSystem.User{}, false, err
+		}
+		// 邮箱被多个用户使用时无法确定关联对象
+		if len(users) != 1 {
+			return domainSystem.User{}, false, nil
+		}
+		return users[0], true, nil
+	default:
+		return domainSystem.User{}, false, nil
+	}
+}
+
+// provisionUser 自动创建用户，密码随机生成，仅能通过单点登录或重置密码后登录
+func (svc *ssoService) provisionUser(ctx context.Context, cfg config.OIDCProviderConfig,
+	identity domainSystem.ExternalIdentity) (domainSystem.User, error) {
+	username := truncate(identity.Username, ssoFieldMaxLength)
+	if username == "" {
+		return domainSystem.User{}, fmt.Errorf("%w: 缺少用户名声明", ErrSSOAuthenticate)
+	}
+
+	// 同名用户已存在时不自动关联，避免第三方账号接管本地账号
+	exists, err := svc.userRepo.CheckExistByUsername(ctx, username, "")
+	if err != nil {
+		return domainSystem.User{}, err
+	}
+	if exists {
+		return domainSystem.User{}, ErrSSOUsernameConflict
+	}
+
+	random, err := oidc.RandomString()
+	if err != nil {
+		return domainSystem.User{}, err
+	}
+	hashedPassword, err := bcrypt.HashPassword(random)
+	if err != nil {
+		return domainSystem.User{}, fmt.Errorf("密码加密失败: %w", err)
+	}
+
+	userType := user.TypeConstAdminUser
+	if cfg.UserType > 0 {
+		userType = user.TypeConst(cfg.UserType)
+	}
+	name := truncate(identity.Name, ssoFieldMaxLength)
+	if name == "" {
+		name = username
+	}
+
+	email := ""
+	if identity.EmailVerified && utf8.RuneCountInString(identity.Email) <= ssoFieldMaxLength {
+		email = identity.Email
+	}
+	err = svc.userRepo.Create(ctx, domainSystem.User{
+		User: modelSystem.User{
+			Status:   true,
+			Username: username,
+			Password: hashedPassword,
+			UserType: userType,
+			Name:     name,
+			Email:    email,
+			DeptId:   cfg.DefaultDeptId,
+			RoleIDs:  cfg.DefaultRoleIds,
+		},
+	})
+	if err != nil {
+		return domainSystem.User{}, fmt.Errorf("创建用户失败: %w", err)
+	}
+	return svc.userRepo.GetByUsername(ctx, username)
+}
+
+// record 记录审计日志
+func (svc *ssoService) record(ctx context.Context, event audit.EventConst, username, detail string) {
+	svc.auditSvc.Record(ctx, domainLogger.AuditLog{
+		AuditLogger: modelLogger.AuditLogger{
+			Event:    event,
+			Username: username,
+			Detail:   detail,
+		},
+	})
+}
+
+// truncate 按字符截断
+func truncate(value string, length int) string {
+	if utf8.RuneCountInString(value) <= length {
+		return value
+	}
+	return string([]rune(value)[:length])
+}
@@ -0,0 +1,237 @@
+/**
+ * Description：
+ * FileName：sso_test.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 07:55:02
+ * Remark：
+ */
+
+package system
+
+import (
+	"context"
+	"errors"
+	config "github.com/carefuly/carefuly-admin-go-gin/config/file"
+	domainSystem "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/system"
+	modelSystem "github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/system"
+	repositorySystem "github.com/carefuly/carefuly-admin-go-gin/internal/repository/repository/careful/system"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/constants/careful/logger/audit"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/models"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/oidc/oidctest"
+	"testing"
+	"time"
+)
+
+// ssoUserRepository 内存用户仓库
+type ssoUserRepository struct {
+	repositorySystem.UserRepository
+	users []domainSystem.User
+}
+
+func (r *ssoUserRepository) Create(ctx context.Context, domain domainSystem.User) error {
+	domain.Id = "new-" + domain.Username
+	r.users = append(r.users, domain)
+	return nil
+}
+
+func (r *ssoUserRepository) GetById(ctx context.Context, id string) (domainSystem.User, error) {
+	for _, u := range r.users {
+		if u.Id == id {
+			return u, nil
+		}
+	}
+	return domainSystem.User{}, nil
+}
+
+func (r *ssoUserRepository) GetByUsername(ctx context.Context, username string) (domainSystem.User, error) {
+	for _, u := range r.users {
+		if u.Username == username {
+			return u, nil
+		}
+	}
+	return domainSystem.User{}, ErrUserNotFound
+}
+
+func (r *ssoUserRepository) GetListByEmail(ctx context.Context, email string) ([]domainSystem.User, error) {
+	var list []domainSystem.User
+	for _, u := range r.users {
+		if u.Email == email {
+			list = append(list, u)
+		}
+	}
+	return list, nil
+}
+
+func (r *ssoUserRepository) CheckExistByUsername(ctx context.Context, username, excludeId string) (bool, error) {
+	_, err := r.GetByUsername(ctx, username)
+	return err == nil, nil
+}
+
+// stubUserIdentityRepository 内存第三方身份仓库
+type stubUserIdentityRepository struct {
+	identities []domainSystem.UserIdentity
+	states     map[string]domainSystem.SSOState
+}
+
+func (r *stubUserIdentityRepository) Create(ctx context.Context, domain domainSystem.UserIdentity) error {
+	r.identities = append(r.identities, domain)
+	return nil
+}
+
+func (r *stubUserIdentityRepository) UpdateLastLogin(ctx context.Context, id, email string, loginTime time.Time) error {
+	return nil
+}
+
+func (r *stubUserIdentityRepository) GetByProviderSubject(ctx context.Context, provider, subject string) (domainSystem.UserIdentity, error) {
+	for _, identity := range r.identities {
+		if identity.Provider == provider && identity.Subject == subject {
+			return identity, nil
+		}
+	}
+	return domainSystem.UserIdentity{}, repositorySystem.ErrUserIdentityNotFound
+}
+
+func (r *stubUserIdentityRepository) GetListByUserId(ctx context.Context, userId string) ([]domainSystem.UserIdentity, error) {
+	return nil, nil
+}
+
+func (r *stubUserIdentityRepository) SaveState(ctx context.Context, state string, domain domainSystem.SSOState, expiration time.Duration) error {
+	r.states[state] = domain
+	return nil
+}
+
+func (r *stubUserIdentityRepository) TakeState(ctx context.Context, state string) (domainSystem.SSOState, error) {
+	domain, ok := r.states[state]
+	if !ok {
+		return domainSystem.SSOState{}, repositorySystem.ErrSSOStateNotFound
+	}
+	delete(r.states, state)
+	return domain, nil
+}
+
+func TestSSOService(t *testing.T) {
+	idp, err := oidctest.NewServer("admin", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idp.Close()
+
+	provider := config.OIDCProviderConfig{
+		Name:           "corp",
+		Issuer:         idp.Issuer(),
+		ClientId:       "admin",
+		ClientSecret:   "secret",
+		RedirectUrl:    "http://localhost/sso/callback",
+		MatchBy:        "email",
+		AutoCreate:     true,
+		DefaultRoleIds: []string{"role-1"},
+	}
+	userRepo := &ssoUserRepository{users: []domainSystem.User{{User: modelSystem.User{
+		CoreModels: models.CoreModels{Id: "1"},
+		Status:     true,
+		Username:   "alice",
+		Email:      "alice@example.com",
+	}}}}
+	identityRepo := &stubUserIdentityRepository{states: map[string]domainSystem.SSOState{}}
+	auditSvc := &recordingAuditLogService{}
+	svc := NewSSOService(userRepo, identityRepo, auditSvc, config.SSOConfig{Providers: []config.OIDCProviderConfig{provider}})
+	ctx := context.Background()
+
+	login := func(claims map[string]any) (domainSystem.User, error) {
+		idp.SetClaims(claims)
+		authorization, err := svc.Authorize(ctx, "corp")
+		if err != nil {
+			t.Fatal(err)
+		}
+		code, state, err := idp.Authorize(authorization.AuthUrl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if state != authorization.State {
+			t.Fatalf("state = %s, want %s", state, authorization.State)
+		}
+		return svc.Login(ctx, "corp", state, code)
+	}
+
+	if providers := svc.Providers(); len(providers) != 1 || providers[0].DisplayName != "corp" {
+		t.Fatalf("providers = %+v", providers)
+	}
+	if _, err := svc.Authorize(ctx, "unknown"); !errors.Is(err, ErrSSOProviderNotFound) {
+		t.Fatalf("未配置的身份提供方应被拒绝，实际: %v", err)
+	}
+
+	// 按已验证邮箱关联已有用户
+	account, err := login(map[string]any{"sub": "u-1", "email": "alice@example.com", "email_verified": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if account.Id != "1" || len(identityRepo.identities) != 1 || identityRepo.identities[0].UserId != "1" {
+		t.Fatalf("account = %+v, identities = %+v", account, identityRepo.identities)
+	}
+
+	// 已关联的身份不再按邮箱匹配
+	account, err = login(map[string]any{"sub": "u-1", "email": "changed@example.com"})
+	if err != nil || account.Id != "1" || len(identityRepo.identities) != 1 {
+		t.Fatalf("account = %+v, err = %v", account, err)
+	}
+
+	// 未验证的邮箱不关联，按用户名自动创建，同名用户已存在时拒绝
+	if _, err := login(map[string]any{"sub": "u-2", "email": "alice@example.com", "email_verified": false,
+		"preferred_username": "alice"}); !errors.Is(err, ErrSSOUsernameConflict) {
+		t.Fatalf("同名用户应拒绝自动创建，实际: %v", err)
+	}
+	if _, err := login(map[string]any{"sub": "u-2", "email": "alice@example.com",
+		"preferred_username": "alice"}); !errors.Is(err, ErrSSOUsernameConflict) {
+		t.Fatalf("未返回 email_verified 时不应关联，实际: %v", err)
+	}
+
+	account, err = login(map[string]any{"sub": "u-3", "preferred_username": "bob", "name": "Bob", "email": "bob@example.com",
+		"email_verified": "true"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if account.Id != "new-bob" || account.Name != "Bob" || account.Email != "bob@example.com" ||
+		len(account.RoleIDs) != 1 || account.Password == "" {
+		t.Fatalf("account = %+v", account)
+	}
+	last := auditSvc.logs[len(auditSvc.logs)-1]
+	if last.Event != audit.EventConstIdentityLinked || auditSvc.logs[len(auditSvc.logs)-2].Event != audit.EventConstUserProvisioned {
+		t.Fatalf("audit logs = %+v", auditSvc.logs)
+	}
+
+	// 停用的用户不能登录
+	userRepo.users[0].Status = false
+	if _, err := login(map[string]any{"sub": "u-1"}); !errors.Is(err, ErrUserDisabled) {
+		t.Fatalf("停用用户应被拒绝，实际: %v", err)
+	}
+
+	// state 只能使用一次
+	idp.SetClaims(map[string]any{"sub": "u-3"})
+	authorization, err := svc.Authorize(ctx, "corp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, state, err := idp.Authorize(authorization.AuthUrl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Login(ctx, "corp", state, code); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Login(ctx, "corp", state, code); !errors.Is(err, ErrSSOStateInvalid) {
+		t.Fatalf("重复使用 state 应被拒绝，实际: %v", err)
+	}
+
+	// 授权码无效时认证失败
+	authorization, _ = svc.Authorize(ctx, "corp")
+	if _, err := svc.Login(ctx, "corp", authorization.State, "invalid"); !errors.Is(err, ErrSSOAuthenticate) {
+		t.Fatalf("无效授权码应认证失败，实际: %v", err)
+	}
+
+	// 未开启自动创建时，未关联的账号不能登录
+	provider.AutoCreate = false
+	svc = NewSSOService(userRepo, identityRepo, auditSvc, config.SSOConfig{Providers: []config.OIDCProviderConfig{provider}})
+	if _, err := login(map[string]any{"sub": "u-4", "preferred_username": "carol"}); !errors.Is(err, ErrSSOUserNotLinked) {
+		t.Fatalf("未关联账号应被拒绝，实际: %v", err)
+	}
+}
@@ -0,0 +1,149 @@
+/**
+ * Description：
+ * FileName：token_binding.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 03:42:10
+ * Remark：
+ */
+
+package system
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	config "github.com/carefuly/carefuly-admin-go-gin/config/file"
+	domainLogger "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/logger"
+	modelLogger "github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/logger"
+	repositorySystem "github.com/carefuly/carefuly-admin-go-gin/internal/repository/repository/careful/system"
+	serviceLogger "github.com/carefuly/carefuly-admin-go-gin/internal/service/careful/logger"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/constants/careful/logger/audit"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/jwt"
+	"go.uber.org/zap"
+	"strings"
+	"time"
+)
+
+// 会话记录的用户代理最大长度
+const sessionUserAgentMaxLength = 255
+
+var ErrTokenBindingMismatch = errors.New("令牌使用环境与签发时不一致")
+
+// TokenBinding 令牌签发时的客户端信息
+type TokenBinding struct {
+	Jti       string // 会话ID
+	UserId    string // 用户ID
+	Username  string // 用户名
+	UserAgent string // 签发时的用户代理
+	Ip        string // 签发时的IP地址
+}
+
+type TokenBindingService interface {
+	// Enabled 是否启用了任一绑定校验
+	Enabled() bool
+	// Check 校验当前请求的客户端与令牌签发时是否一致，不一致时记录审计日志并标记会话，
+	// 按配置需拒绝请求时返回 ErrTokenBindingMismatch
+	Check(ctx context.Context, binding TokenBinding, userAgent, ip string) error
+}
+
+type tokenBindingService struct {
+	sessionRepo   repositorySystem.SessionRepository
+	auditSvc      serviceLogger.AuditLogService
+	userAgentMode jwt.BindingMode
+	ipMode        jwt.BindingMode
+}
+
+func NewTokenBindingService(sessionRepo repositorySystem.SessionRepository, auditSvc serviceLogger.AuditLogService,
+	token config.TokenConfig) TokenBindingService {
+	return &tokenBindingService{
+		sessionRepo:   sessionRepo,
+		auditSvc:      auditSvc,
+		userAgentMode: jwt.ParseBindingMode(token.BindUserAgent),
+		ipMode:        jwt.ParseBindingMode(token.BindIp),
+	}
+}
+
+// Enabled 是否启用
+func (svc *tokenBindingService) Enabled() bool {
+	return svc.userAgentMode.Enabled() || svc.ipMode.Enabled()
+}
+
+// Check 校验
+func (svc *tokenBindingService) Check(ctx context.Context, binding TokenBinding, userAgent, ip string) error {
+	userAgent = truncateUserAgent(userAgent)
+
+	var reasons []string
+	reject := false
+	// 未记录签发信息的旧令牌不做校验
+	if svc.userAgentMode.Enabled() && binding.UserAgent != "" && truncateUserAgent(binding.UserAgent) != userAgent {
+		reasons = append(reasons, "User-Agent")
+		reject = reject || svc.userAgentMode == jwt.BindingReject
+	}
+	if svc.ipMode.Enabled() && binding.Ip != "" && binding.Ip != ip {
+		reasons = append(reasons, "IP")
+		reject = reject || svc.ipMode == jwt.BindingReject
+	}
+	if len(reasons) == 0 {
+		return nil
+	}
+
+	reason := strings.Join(reasons, ",")
+	if svc.markSession(ctx, binding, reason, userAgent, ip) {
+		action := "已放行"
+		if reject {
+			action = "已拒绝"
+		}
+		svc.auditSvc.Record(ctx, domainLogger.AuditLog{
+			AuditLogger: modelLogger.AuditLogger{
+				Event:      audit.EventConstTokenMismatch,
+				Username:   binding.Username,
+				RequestIp:  ip,
+				OperatorId: binding.UserId,
+				Operator:   binding.Username,
+				Detail: fmt.Sprintf("会话 %s 的 %s 与签发时不一致(签发IP %s，User-Agent %s；请求User-Agent %s)，%s",
+					binding.Jti, reason, binding.Ip, binding.UserAgent, userAgent, action),
+			},
+		})
+	}
+
+	if reject {
+		return ErrTokenBindingMismatch
+	}
+	return nil
+}
+
+// markSession 在会话上记录不一致的客户端，同一客户端重复请求时不再重复记录，返回是否为新出现的客户端
+func (svc *tokenBindingService) markSession(ctx context.Context, binding TokenBinding, reason, userAgent, ip string) bool {
+	if binding.Jti == "" {
+		return true
+	}
+
+	session, err := svc.sessionRepo.GetByJti(ctx, binding.Jti)
+	if err != nil {
+		if !errors.Is(err, repositorySystem.ErrSessionNotFound) {
+			zap.L().Error("获取会话异常", zap.String("jti", binding.Jti), zap.Error(err))
+		}
+		return true
+	}
+	if session.Suspicious && session.MismatchIp == ip && session.MismatchUserAgent == userAgent {
+		return false
+	}
+
+	session.Suspicious = true
+	session.MismatchReason = reason
+	session.MismatchIp = ip
+	session.MismatchUserAgent = userAgent
+	session.MismatchTime = time.Now().Format("2006-01-02 15:04:05")
+	if err := svc.sessionRepo.Update(ctx, session); err != nil && !errors.Is(err, repositorySystem.ErrSessionNotFound) {
+		zap.L().Error("标记异常会话失败", zap.String("jti", binding.Jti), zap.Error(err))
+	}
+	return true
+}
+
+// truncateUserAgent 与会话记录的用户代理保持一致的长度
+func truncateUserAgent(userAgent string) string {
+	if len(userAgent) > sessionUserAgentMaxLength {
+		return userAgent[:sessionUserAgentMaxLength]
+	}
+	return userAgent
+}
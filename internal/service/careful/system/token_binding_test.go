@@ -0,0 +1,110 @@
+/**
+ * Description：
+ * FileName：token_binding_test.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 03:58:24
+ * Remark：
+ */
+
+package system
+
+import (
+	"context"
+	"errors"
+	config "github.com/carefuly/carefuly-admin-go-gin/config/file"
+	domainLogger "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/logger"
+	domainSystem "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/system"
+	repositorySystem "github.com/carefuly/carefuly-admin-go-gin/internal/repository/repository/careful/system"
+	"testing"
+)
+
+// stubSessionRepository 内存会话存储
+type stubSessionRepository struct {
+	repositorySystem.SessionRepository
+	sessions map[string]domainSystem.Session
+}
+
+func (r *stubSessionRepository) GetByJti(ctx context.Context, jti string) (domainSystem.Session, error) {
+	session, ok := r.sessions[jti]
+	if !ok {
+		return domainSystem.Session{}, repositorySystem.ErrSessionNotFound
+	}
+	return session, nil
+}
+
+func (r *stubSessionRepository) Update(ctx context.Context, domain domainSystem.Session) error {
+	r.sessions[domain.Jti] = domain
+	return nil
+}
+
+// recordingAuditLogService 记录审计事件
+type recordingAuditLogService struct {
+	logs []domainLogger.AuditLog
+}
+
+func (s *recordingAuditLogService) Record(ctx context.Context, domain domainLogger.AuditLog) {
+	s.logs = append(s.logs, domain)
+}
+
+func TestTokenBindingService_Check(t *testing.T) {
+	binding := TokenBinding{Jti: "jti", UserId: "1", Username: "admin", UserAgent: "Chrome", Ip: "10.0.0.1"}
+	ctx := context.Background()
+
+	testCases := []struct {
+		name       string
+		token      config.TokenConfig
+		userAgent  string
+		ip         string
+		wantReject bool
+		wantAudit  bool
+	}{
+		{name: "未启用", token: config.TokenConfig{}, userAgent: "curl", ip: "10.0.0.2"},
+		{name: "一致", token: config.TokenConfig{BindUserAgent: "reject", BindIp: "reject"}, userAgent: "Chrome", ip: "10.0.0.1"},
+		{name: "IP不一致告警", token: config.TokenConfig{BindIp: "warn"}, userAgent: "curl", ip: "10.0.0.2", wantAudit: true},
+		{name: "UA不一致拒绝", token: config.TokenConfig{BindUserAgent: "reject", BindIp: "warn"}, userAgent: "curl", ip: "10.0.0.1",
+			wantReject: true, wantAudit: true},
+		{name: "未识别的配置视为关闭", token: config.TokenConfig{BindIp: "strict"}, userAgent: "Chrome", ip: "10.0.0.2"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &stubSessionRepository{sessions: map[string]domainSystem.Session{"jti": {Jti: "jti", UserId: "1"}}}
+			auditSvc := &recordingAuditLogService{}
+			svc := NewTokenBindingService(repo, auditSvc, tc.token)
+
+			err := svc.Check(ctx, binding, tc.userAgent, tc.ip)
+			if got := errors.Is(err, ErrTokenBindingMismatch); got != tc.wantReject {
+				t.Fatalf("reject = %v, want %v (err: %v)", got, tc.wantReject, err)
+			}
+			if got := len(auditSvc.logs) > 0; got != tc.wantAudit {
+				t.Fatalf("audit = %v, want %v", got, tc.wantAudit)
+			}
+			if got := repo.sessions["jti"].Suspicious; got != tc.wantAudit {
+				t.Fatalf("suspicious = %v, want %v", got, tc.wantAudit)
+			}
+		})
+	}
+}
+
+// 同一客户端重复请求只记录一次审计日志
+func TestTokenBindingService_AuditOncePerClient(t *testing.T) {
+	repo := &stubSessionRepository{sessions: map[string]domainSystem.Session{"jti": {Jti: "jti", UserId: "1"}}}
+	auditSvc := &recordingAuditLogService{}
+	svc := NewTokenBindingService(repo, auditSvc, config.TokenConfig{BindIp: "warn"})
+	binding := TokenBinding{Jti: "jti", UserId: "1", Username: "admin", Ip: "10.0.0.1"}
+
+	for range 3 {
+		if err := svc.Check(context.Background(), binding, "", "10.0.0.2"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := svc.Check(context.Background(), binding, "", "10.0.0.3"); err != nil {
+		t.Fatal(err)
+	}
+	if len(auditSvc.logs) != 2 {
+		t.Fatalf("audit logs = %d, want 2", len(auditSvc.logs))
+	}
+	if session := repo.sessions["jti"]; session.MismatchIp != "10.0.0.3" || session.MismatchReason != "IP" {
+		t.Fatalf("session = %+v", session)
+	}
+}
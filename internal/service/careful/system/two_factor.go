@@ -0,0 +1,449 @@
+/**
+ * Description：
+ * FileName：two_factor.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 22:24:31
+ * Remark：
+ */
+
+package system
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	config "github.com/carefuly/carefuly-admin-go-gin/config/file"
+	domainLogger "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/logger"
+	domainSystem "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/system"
+	modelLogger "github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/logger"
+	modelSystem "github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/system"
+	repositorySystem "github.com/carefuly/carefuly-admin-go-gin/internal/repository/repository/careful/system"
+	serviceLogger "github.com/carefuly/carefuly-admin-go-gin/internal/service/careful/logger"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/constants/careful/logger/audit"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/constants/careful/system/user"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/models"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/totp"
+	"github.com/skip2/go-qrcode"
+	"strings"
+	"time"
+)
+
+const (
+	RecoveryCodeCount          = 10 // 恢复码数量
+	twoFactorChallengeByteSize = 32 // 登录二次验证令牌字节数
+)
+
+var (
+	ErrTwoFactorNotEnabled        = errors.New("未启用双因素认证")
+	ErrTwoFactorAlreadyEnabled    = errors.New("已启用双因素认证")
+	ErrTwoFactorNotSetup          = errors.New("未绑定认证器")
+	ErrTwoFactorRequired          = errors.New("当前账号要求启用双因素认证")
+	ErrTwoFactorCodeInvalid       = errors.New("验证码错误")
+	ErrTwoFactorChallengeTooMany  = errors.New("验证次数过多")
+	ErrTwoFactorChallengeNotFound = repositorySystem.ErrTwoFactorChallengeNotFound
+	ErrUserTwoFactorNotFound      = repositorySystem.ErrUserTwoFactorNotFound
+)
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+type TwoFactorService interface {
+	// Required 用户是否必须启用双因素认证(角色要求或后台用户)
+	Required(ctx context.Context, user domainSystem.User) (bool, error)
+	// Enabled 用户是否已启用双因素认证
+	Enabled(ctx context.Context, userId string) (bool, error)
+	Status(ctx context.Context, user domainSystem.User) (domainSystem.TwoFactorStatus, error)
+
+	// Setup 生成新的密钥，首次验证通过前不生效
+	Setup(ctx context.Context, user domainSystem.User) (domainSystem.TwoFactorSetup, error)
+	// Enable 校验首个验证码后启用，返回恢复码明文(仅展示一次)
+	Enable(ctx context.Context, user domainSystem.User, code string) ([]string, error)
+	// Disable 校验验证码或恢复码后关闭
+	Disable(ctx context.Context, user domainSystem.User, code string) error
+	// RegenerateRecoveryCodes 校验验证码后重新生成恢复码
+	RegenerateRecoveryCodes(ctx context.Context, userId, code string) ([]string, error)
+	// Verify 校验验证码或恢复码，恢复码使用后作废
+	Verify(ctx context.Context, userId, code string) error
+	// Reset 管理员重置用户的双因素认证(丢失设备)
+	Reset(ctx context.Context, user domainSystem.User, operatorId, operator string) error
+
+	// CreateChallenge 密码校验通过后创建登录二次验证
+	CreateChallenge(ctx context.Context, userId string) (string, time.Duration, error)
+	// VerifyChallenge 完成登录二次验证，返回用户ID
+	VerifyChallenge(ctx context.Context, token, code string) (string, error)
+}
+
+type twoFactorService struct {
+	repo        repositorySystem.UserTwoFactorRepository
+	auditSvc    serviceLogger.AuditLogService
+	issuer      string
+	adminUser   bool
+	expire      time.Duration
+	maxAttempts int64
+}
+
+func NewTwoFactorService(repo repositorySystem.UserTwoFactorRepository, auditSvc serviceLogger.AuditLogService,
+	conf config.TwoFactorConfig) TwoFactorService {
+	svc := &twoFactorService{
+		repo:        repo,
+		auditSvc:    auditSvc,
+		issuer:      conf.Issuer,
+		adminUser:   conf.RequireAdminUser,
+		expire:      time.Duration(conf.ChallengeExpire) * time.Second,
+		maxAttempts: int64(conf.MaxAttempts),
+	}
+	if svc.issuer == "" {
+		svc.issuer = "Carefuly"
+	}
+	if svc.expire <= 0 {
+		svc.expire = 5 * time.Minute
+	}
+	if svc.maxAttempts <= 0 {
+		svc.maxAttempts = 5
+	}
+	return svc
+}
+
+// Required 是否必须启用
+func (svc *twoFactorService) Required(ctx context.Context, u domainSystem.User) (bool, error) {
+	if svc.adminUser && u.UserType == user.TypeConstAdminUser {
+		return true, nil
+	}
+	return svc.repo.HasRequiredRole(ctx, u.Id)
+}
+
+// Enabled 是否已启用
+func (svc *twoFactorService) Enabled(ctx context.Context, userId string) (bool, error) {
+	record, err := svc.repo.GetByUserId(ctx, userId)
+	if err != nil {
+		if errors.Is(err, ErrUserTwoFactorNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return record.Enabled, nil
+}
+
+// Status 状态
+func (svc *twoFactorService) Status(ctx context.Context, u domainSystem.User) (domainSystem.TwoFactorStatus, error) {
+	required, err := svc.Required(ctx, u)
+	if err != nil {
+		return domainSystem.TwoFactorStatus{}, err
+	}
+	status := domainSystem.TwoFactorStatus{Required: required}
+
+	record, err := svc.repo.GetByUserId(ctx, u.Id)
+	if err != nil {
+		if errors.Is(err, ErrUserTwoFactorNotFound) {
+			return status, nil
+		}
+		return status, err
+	}
+	status.Enabled = record.Enabled
+	if record.Enabled {
+		status.RecoveryCodes = len(decodeRecoveryCodes(record.RecoveryCodes))
+	}
+	return status, nil
+}
+
+// Setup 绑定认证器
+func (svc *twoFactorService) Setup(ctx context.Context, u domainSystem.User) (domainSystem.TwoFactorSetup, error) {
+	enabled, err := svc.Enabled(ctx, u.Id)
+	if err != nil {
+		return domainSystem.TwoFactorSetup{}, err
+	}
+	if enabled {
+		return domainSystem.TwoFactorSetup{}, ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return domainSystem.TwoFactorSetup{}, err
+	}
+	err = svc.repo.Save(ctx, domainSystem.UserTwoFactor{
+		UserTwoFactor: modelSystem.UserTwoFactor{
+			CoreModels: models.CoreModels{
+				Creator:    u.Id,
+				Modifier:   u.Id,
+				BelongDept: u.DeptId,
+			},
+			UserId: u.Id,
+			Secret: secret,
+		},
+	})
+	if err != nil {
+		return domainSystem.TwoFactorSetup{}, err
+	}
+
+	uri := totp.URI(svc.issuer, u.Username, secret)
+	png, err := qrcode.Encode(uri, qrcode.Medium, 256)
+	if err != nil {
+		return domainSystem.TwoFactorSetup{}, err
+	}
+
+	return domainSystem.TwoFactorSetup{
+		Secret: secret,
+		Uri:    uri,
+		QrCode: "data:image/png;base64," + base64.StdEncoding.EncodeToString(png),
+	}, nil
+}
+
+// Enable 启用
+func (svc *twoFactorService) Enable(ctx context.Context, u domainSystem.User, code string) ([]string, error) {
+	record, err := svc.repo.GetByUserId(ctx, u.Id)
+	if err != nil {
+		if errors.Is(err, ErrUserTwoFactorNotFound) {
+			return nil, ErrTwoFactorNotSetup
+		}
+		return nil, err
+	}
+	if record.Enabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	counter, ok := totp.Validate(record.Secret, strings.TrimSpace(code), time.Now())
+	if !ok {
+		return nil, ErrTwoFactorCodeInvalid
+	}
+
+	codes, hashed, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	ok, err = svc.repo.Enable(ctx, u.Id, counter, hashed)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	svc.record(ctx, audit.EventConstTwoFactorEnabled, u.Username, u.Id, u.Username, "用户启用双因素认证")
+	return codes, nil
+}
+
+// Disable 关闭
+func (svc *twoFactorService) Disable(ctx context.Context, u domainSystem.User, code string) error {
+	required, err := svc.Required(ctx, u)
+	if err != nil {
+		return err
+	}
+	if required {
+		return ErrTwoFactorRequired
+	}
+
+	if err := svc.Verify(ctx, u.Id, code); err != nil {
+		return err
+	}
+	if err := svc.repo.DeleteByUserId(ctx, u.Id); err != nil {
+		return err
+	}
+
+	svc.record(ctx, audit.EventConstTwoFactorDisabled, u.Username, u.Id, u.Username, "用户关闭双因素认证")
+	return nil
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码
+func (svc *twoFactorService) RegenerateRecoveryCodes(ctx context.Context, userId, code string) ([]string, error) {
+	if err := svc.verifyTotp(ctx, userId, code); err != nil {
+		return nil, err
+	}
+
+	record, err := svc.repo.GetByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	codes, hashed, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	ok, err := svc.repo.UpdateRecoveryCodes(ctx, userId, record.RecoveryCodes, hashed)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrTwoFactorCodeInvalid
+	}
+	return codes, nil
+}
+
+// Verify 校验验证码或恢复码
+func (svc *twoFactorService) Verify(ctx context.Context, userId, code string) error {
+	code = strings.TrimSpace(code)
+	if len(code) == totp.Digits {
+		return svc.verifyTotp(ctx, userId, code)
+	}
+	return svc.useRecoveryCode(ctx, userId, code)
+}
+
+// Reset 管理员重置
+func (svc *twoFactorService) Reset(ctx context.Context, u domainSystem.User, operatorId, operator string) error {
+	if err := svc.repo.DeleteByUserId(ctx, u.Id); err != nil {
+		return err
+	}
+	svc.record(ctx, audit.EventConstTwoFactorReset, u.Username, operatorId, operator, "管理员重置双因素认证")
+	return nil
+}
+
+// CreateChallenge 创建登录二次验证
+func (svc *twoFactorService) CreateChallenge(ctx context.Context, userId string) (string, time.Duration, error) {
+	b := make([]byte, twoFactorChallengeByteSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", 0, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	if err := svc.repo.CreateChallenge(ctx, token, userId, svc.expire); err != nil {
+		return "", 0, err
+	}
+	return token, svc.expire, nil
+}
+
+// VerifyChallenge 完成登录二次验证
+func (svc *twoFactorService) VerifyChallenge(ctx context.Context, token, code string) (string, error) {
+	userId, err := svc.repo.GetChallenge(ctx, token)
+	if err != nil {
+		return "", err
+	}
+
+	attempts, err := svc.repo.IncrChallengeAttempts(ctx, token)
+	if err != nil {
+		return userId, err
+	}
+	if attempts > svc.maxAttempts {
+		_ = svc.repo.DeleteChallenge(ctx, token)
+		return userId, ErrTwoFactorChallengeTooMany
+	}
+
+	if err := svc.Verify(ctx, userId, code); err != nil {
+		if attempts == svc.maxAttempts {
+			_ = svc.repo.DeleteChallenge(ctx, token)
+		}
+		return userId, err
+	}
+
+	// 挑战只能使用一次
+	return userId, svc.repo.DeleteChallenge(ctx, token)
+}
+
+// verifyTotp 校验动态验证码，同一时间步的验证码只能使用一次
+func (svc *twoFactorService) verifyTotp(ctx context.Context, userId, code string) error {
+	record, err := svc.enabledRecord(ctx, userId)
+	if err != nil {
+		return err
+	}
+
+	counter, ok := totp.Validate(record.Secret, strings.TrimSpace(code), time.Now())
+	if !ok {
+		return ErrTwoFactorCodeInvalid
+	}
+	ok, err = svc.repo.UpdateLastCounter(ctx, userId, counter)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrTwoFactorCodeInvalid
+	}
+	return nil
+}
+
+// useRecoveryCode 使用恢复码
+func (svc *twoFactorService) useRecoveryCode(ctx context.Context, userId, code string) error {
+	record, err := svc.enabledRecord(ctx, userId)
+	if err != nil {
+		return err
+	}
+
+	hashed := hashRecoveryCode(code)
+	codes := decodeRecoveryCodes(record.RecoveryCodes)
+	for i, v := range codes {
+		if v != hashed {
+			continue
+		}
+		remaining := append(codes[:i:i], codes[i+1:]...)
+		data, err := json.Marshal(remaining)
+		if err != nil {
+			return err
+		}
+		// 以原值作为条件更新，防止同一恢复码被并发使用
+		ok, err := svc.repo.UpdateRecoveryCodes(ctx, userId, record.RecoveryCodes, string(data))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrTwoFactorCodeInvalid
+		}
+		return nil
+	}
+	return ErrTwoFactorCodeInvalid
+}
+
+// enabledRecord 获取已启用的绑定信息
+func (svc *twoFactorService) enabledRecord(ctx context.Context, userId string) (domainSystem.UserTwoFactor, error) {
+	record, err := svc.repo.GetByUserId(ctx, userId)
+	if err != nil {
+		if errors.Is(err, ErrUserTwoFactorNotFound) {
+			return record, ErrTwoFactorNotEnabled
+		}
+		return record, err
+	}
+	if !record.Enabled {
+		return record, ErrTwoFactorNotEnabled
+	}
+	return record, nil
+}
+
+// record 记录审计事件
+func (svc *twoFactorService) record(ctx context.Context, event audit.EventConst, username, operatorId, operator, detail string) {
+	svc.auditSvc.Record(ctx, domainLogger.AuditLog{
+		AuditLogger: modelLogger.AuditLogger{
+			Event:      event,
+			Username:   username,
+			OperatorId: operatorId,
+			Operator:   operator,
+			Detail:     detail,
+		},
+	})
+}
+
+// newRecoveryCodes 生成恢复码，返回明文及摘要(JSON数组)
+func newRecoveryCodes() ([]string, string, error) {
+	codes := make([]string, 0, RecoveryCodeCount)
+	hashed := make([]string, 0, RecoveryCodeCount)
+	for range RecoveryCodeCount {
+		b := make([]byte, 7)
+		if _, err := rand.Read(b); err != nil {
+			return nil, "", err
+		}
+		raw := strings.ToLower(recoveryCodeEncoding.EncodeToString(b))[:10]
+		code := raw[:5] + "-" + raw[5:]
+		codes = append(codes, code)
+		hashed = append(hashed, hashRecoveryCode(code))
+	}
+
+	data, err := json.Marshal(hashed)
+	if err != nil {
+		return nil, "", err
+	}
+	return codes, string(data), nil
+}
+
+// hashRecoveryCode 恢复码摘要，忽略大小写及分隔符
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+func decodeRecoveryCodes(data string) []string {
+	var codes []string
+	if data == "" {
+		return codes
+	}
+	_ = json.Unmarshal([]byte(data), &codes)
+	return codes
+}
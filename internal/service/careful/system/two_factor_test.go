@@ -0,0 +1,213 @@
+/**
+ * Description：
+ * FileName：two_factor_test.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 21:06:18
+ * Remark：
+ */
+
+package system
+
+import (
+	"context"
+	"errors"
+	config "github.com/carefuly/carefuly-admin-go-gin/config/file"
+	domainSystem "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/system"
+	modelSystem "github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/system"
+	repositorySystem "github.com/carefuly/carefuly-admin-go-gin/internal/repository/repository/careful/system"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/totp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// stubUserTwoFactorRepository 内存双因素认证存储，条件更新语义与数据库一致
+type stubUserTwoFactorRepository struct {
+	repositorySystem.UserTwoFactorRepository
+	record     domainSystem.UserTwoFactor
+	challenges map[string]string
+	attempts   map[string]int64
+}
+
+func newStubUserTwoFactorRepository(t *testing.T, userId string) *stubUserTwoFactorRepository {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &stubUserTwoFactorRepository{
+		record: domainSystem.UserTwoFactor{UserTwoFactor: modelSystem.UserTwoFactor{
+			UserId:  userId,
+			Secret:  secret,
+			Enabled: true,
+		}},
+		challenges: map[string]string{},
+		attempts:   map[string]int64{},
+	}
+}
+
+func (r *stubUserTwoFactorRepository) GetByUserId(ctx context.Context, userId string) (domainSystem.UserTwoFactor, error) {
+	if r.record.UserId != userId {
+		return domainSystem.UserTwoFactor{}, repositorySystem.ErrUserTwoFactorNotFound
+	}
+	return r.record, nil
+}
+
+func (r *stubUserTwoFactorRepository) UpdateLastCounter(ctx context.Context, userId string, counter int64) (bool, error) {
+	if r.record.UserId != userId || r.record.LastCounter >= counter {
+		return false, nil
+	}
+	r.record.LastCounter = counter
+	return true, nil
+}
+
+func (r *stubUserTwoFactorRepository) UpdateRecoveryCodes(ctx context.Context, userId, oldCodes, newCodes string) (bool, error) {
+	if r.record.UserId != userId || r.record.RecoveryCodes != oldCodes {
+		return false, nil
+	}
+	r.record.RecoveryCodes = newCodes
+	return true, nil
+}
+
+func (r *stubUserTwoFactorRepository) CreateChallenge(ctx context.Context, token, userId string, expiration time.Duration) error {
+	r.challenges[token] = userId
+	return nil
+}
+
+func (r *stubUserTwoFactorRepository) GetChallenge(ctx context.Context, token string) (string, error) {
+	userId, ok := r.challenges[token]
+	if !ok {
+		return "", repositorySystem.ErrTwoFactorChallengeNotFound
+	}
+	return userId, nil
+}
+
+func (r *stubUserTwoFactorRepository) IncrChallengeAttempts(ctx context.Context, token string) (int64, error) {
+	r.attempts[token]++
+	return r.attempts[token], nil
+}
+
+func (r *stubUserTwoFactorRepository) DeleteChallenge(ctx context.Context, token string) error {
+	delete(r.challenges, token)
+	delete(r.attempts, token)
+	return nil
+}
+
+func TestTwoFactorService_VerifyChallenge(t *testing.T) {
+	repo := newStubUserTwoFactorRepository(t, "1")
+	svc := NewTwoFactorService(repo, stubAuditLogService{}, config.TwoFactorConfig{MaxAttempts: 3})
+	ctx := context.Background()
+
+	// 达到最大尝试次数后挑战作废
+	token, _, err := svc.CreateChallenge(ctx, "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= 3; i++ {
+		if _, err := svc.VerifyChallenge(ctx, token, "wrong-code"); !errors.Is(err, ErrTwoFactorCodeInvalid) {
+			t.Fatalf("第 %d 次错误验证码应被拒绝，实际: %v", i, err)
+		}
+		if _, ok := repo.challenges[token]; ok != (i < 3) {
+			t.Fatalf("第 %d 次验证失败后挑战是否保留与预期不符", i)
+		}
+	}
+	code, err := totp.Code(repo.record.Secret, totp.Counter(time.Now()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.VerifyChallenge(ctx, token, code); !errors.Is(err, ErrTwoFactorChallengeNotFound) {
+		t.Fatalf("挑战作废后正确验证码也应被拒绝，实际: %v", err)
+	}
+
+	// 并发请求超过最大尝试次数时直接拒绝，不再校验验证码
+	token, _, err = svc.CreateChallenge(ctx, "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo.attempts[token] = 3
+	if _, err := svc.VerifyChallenge(ctx, token, code); !errors.Is(err, ErrTwoFactorChallengeTooMany) {
+		t.Fatalf("超过最大尝试次数应被拒绝，实际: %v", err)
+	}
+	if _, ok := repo.challenges[token]; ok {
+		t.Fatalf("超过最大尝试次数后应删除挑战")
+	}
+	if repo.record.LastCounter != 0 {
+		t.Fatalf("超过最大尝试次数时不应消耗验证码")
+	}
+
+	// 验证通过后挑战只能使用一次
+	token, _, err = svc.CreateChallenge(ctx, "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	userId, err := svc.VerifyChallenge(ctx, token, code)
+	if err != nil || userId != "1" {
+		t.Fatalf("正确验证码应通过，实际: %q %v", userId, err)
+	}
+	if _, err := svc.VerifyChallenge(ctx, token, code); !errors.Is(err, ErrTwoFactorChallengeNotFound) {
+		t.Fatalf("挑战重复使用应被拒绝，实际: %v", err)
+	}
+}
+
+func TestTwoFactorService_VerifyTotpReplay(t *testing.T) {
+	repo := newStubUserTwoFactorRepository(t, "1")
+	svc := NewTwoFactorService(repo, stubAuditLogService{}, config.TwoFactorConfig{})
+	ctx := context.Background()
+
+	counter := totp.Counter(time.Now())
+	code, err := totp.Code(repo.record.Secret, counter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous, err := totp.Code(repo.record.Secret, counter-1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := svc.Verify(ctx, "1", code); err != nil {
+		t.Fatalf("正确验证码应通过，实际: %v", err)
+	}
+	if repo.record.LastCounter != counter {
+		t.Fatalf("应记录已使用的时间步，实际: %d", repo.record.LastCounter)
+	}
+	if err := svc.Verify(ctx, "1", code); !errors.Is(err, ErrTwoFactorCodeInvalid) {
+		t.Fatalf("同一验证码重复使用应被拒绝，实际: %v", err)
+	}
+	if err := svc.Verify(ctx, "1", previous); !errors.Is(err, ErrTwoFactorCodeInvalid) {
+		t.Fatalf("早于已使用时间步的验证码应被拒绝，实际: %v", err)
+	}
+}
+
+func TestTwoFactorService_UseRecoveryCode(t *testing.T) {
+	repo := newStubUserTwoFactorRepository(t, "1")
+	codes, hashed, err := newRecoveryCodes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo.record.RecoveryCodes = hashed
+	svc := NewTwoFactorService(repo, stubAuditLogService{}, config.TwoFactorConfig{})
+	ctx := context.Background()
+
+	if err := svc.Verify(ctx, "1", codes[0]); err != nil {
+		t.Fatalf("恢复码应通过，实际: %v", err)
+	}
+	if err := svc.Verify(ctx, "1", codes[0]); !errors.Is(err, ErrTwoFactorCodeInvalid) {
+		t.Fatalf("恢复码只能使用一次，实际: %v", err)
+	}
+	if n := len(decodeRecoveryCodes(repo.record.RecoveryCodes)); n != RecoveryCodeCount-1 {
+		t.Fatalf("剩余恢复码数量 = %d，期望 %d", n, RecoveryCodeCount-1)
+	}
+
+	// 忽略大小写及分隔符
+	if err := svc.Verify(ctx, "1", strings.ToUpper(strings.ReplaceAll(codes[1], "-", ""))); err != nil {
+		t.Fatalf("恢复码应忽略大小写及分隔符，实际: %v", err)
+	}
+	if err := svc.Verify(ctx, "1", "aaaaa-bbbbb"); !errors.Is(err, ErrTwoFactorCodeInvalid) {
+		t.Fatalf("未知恢复码应被拒绝，实际: %v", err)
+	}
+
+	// 未启用时拒绝
+	repo.record.Enabled = false
+	if err := svc.Verify(ctx, "1", codes[2]); !errors.Is(err, ErrTwoFactorNotEnabled) {
+		t.Fatalf("未启用双因素认证时应拒绝，实际: %v", err)
+	}
+}
@@ -12,28 +12,49 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	config "github.com/carefuly/carefuly-admin-go-gin/config/file"
 	domainSystem "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/system"
 	repositorySystem "github.com/carefuly/carefuly-admin-go-gin/internal/repository/repository/careful/system"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/constants/careful/system/user"
 	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/bcrypt"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/pwdpolicy"
 	"github.com/go-sql-driver/mysql"
+	"go.uber.org/zap"
+	"time"
 )
 
 var (
-	ErrUserNotFound          = repositorySystem.ErrUserNotFound
-	ErrUsernameDuplicate     = repositorySystem.ErrUsernameDuplicate
-	ErrUserDuplicate         = repositorySystem.ErrUserDuplicate
-	ErrUserInvalidCredential = errors.New("用户名或密码错误")
-	ErrUserTypeDoesNotMatch  = errors.New("用户类型不匹配")
+	ErrUserNotFound             = repositorySystem.ErrUserNotFound
+	ErrUsernameDuplicate        = repositorySystem.ErrUsernameDuplicate
+	ErrUserDuplicate            = repositorySystem.ErrUserDuplicate
+	ErrUserVersionInconsistency = repositorySystem.ErrUserVersionInconsistency
+	ErrUserInvalidCredential    = errors.New("用户名或密码错误")
+	ErrUserTypeDoesNotMatch     = errors.New("用户类型不匹配")
+	ErrUserDisabled             = errors.New("用户已停用")
+	ErrUserRoleNotAssignable    = errors.New("无权分配该角色")
+	ErrPasswordPolicy           = pwdpolicy.ErrPolicy
 )
 
 type UserService interface {
 	Register(ctx context.Context, user domainSystem.User) error
 	Login(ctx context.Context, username, password string) (domainSystem.User, error)
+	// TypeLogin 按用户类型登录，用户类型不一致时返回 ErrUserTypeDoesNotMatch
+	TypeLogin(ctx context.Context, username, password string, userType user.TypeConst) (domainSystem.User, error)
 	ChangePassword(ctx context.Context, userId string, oldPassword, newPassword string) error
 
 	Create(ctx context.Context, domain domainSystem.User) error
 	Delete(ctx context.Context, id string) error
+	// BatchDelete 批量删除，仅删除数据权限范围内的用户，返回实际删除的用户ID
+	BatchDelete(ctx context.Context, ids []string) ([]string, error)
 	Update(ctx context.Context, domain domainSystem.User) error
+	UpdateStatus(ctx context.Context, domain domainSystem.User) error
+	ResetPassword(ctx context.Context, userId, newPassword string) error
+	// RecoverPassword 身份已通过邮箱验证码等方式确认后设置新密码，不校验旧密码
+	RecoverPassword(ctx context.Context, userId, newPassword string) error
+	// CheckPassword 校验密码复杂度策略
+	CheckPassword(password, username string) error
+	// PasswordExpired 密码是否已超过最长使用天数
+	PasswordExpired(user domainSystem.User) bool
 
 	GetById(ctx context.Context, id string) (domainSystem.User, error)
 	GetByUsername(ctx context.Context, username string) (domainSystem.User, error)
@@ -42,12 +63,19 @@ type UserService interface {
 }
 
 type userService struct {
-	repo repositorySystem.UserRepository
+	repo           repositorySystem.UserRepository
+	permissionRepo repositorySystem.PermissionRepository
+	historyRepo    repositorySystem.UserPasswordHistoryRepository
+	policy         config.PasswordPolicyConfig
 }
 
-func NewUserService(repo repositorySystem.UserRepository) UserService {
+func NewUserService(repo repositorySystem.UserRepository, permissionRepo repositorySystem.PermissionRepository,
+	historyRepo repositorySystem.UserPasswordHistoryRepository, policy config.PasswordPolicyConfig) UserService {
 	return &userService{
-		repo: repo,
+		repo:           repo,
+		permissionRepo: permissionRepo,
+		historyRepo:    historyRepo,
+		policy:         policy,
 	}
 }
 
@@ -62,14 +90,17 @@ func (svc *userService) Register(ctx context.Context, user domainSystem.User) er
 		return repositorySystem.ErrUsernameDuplicate
 	}
 
+	// 校验密码策略
+	if err := svc.validatePassword(user.Password, user.Username); err != nil {
+		return err
+	}
+
 	// 加密密码
 	hashedPassword, err := bcrypt.HashPassword(user.Password)
 	if err != nil {
 		return fmt.Errorf("密码加密失败: %w", err)
 	}
 
-	// 保存明文密码（注意：实际生产环境不应存储明文密码）
-	user.PasswordStr = user.Password
 	user.Password = hashedPassword
 
 	// 创建用户
@@ -99,9 +130,31 @@ func (svc *userService) Login(ctx context.Context, username, password string) (d
 		return domainSystem.User{}, ErrUserInvalidCredential
 	}
 
+	// 密码正确后再校验状态，避免暴露账号是否存在
+	if !user.Status {
+		return domainSystem.User{}, ErrUserDisabled
+	}
+
+	// 加密强度调整后，使用本次登录的明文密码重新加密
+	if bcrypt.NeedsRehash(user.Password) {
+		svc.rehashPassword(ctx, user.Id, password)
+	}
+
 	return user, nil
 }
 
+// TypeLogin 按用户类型登录
+func (svc *userService) TypeLogin(ctx context.Context, username, password string, userType user.TypeConst) (domainSystem.User, error) {
+	main, err := svc.Login(ctx, username, password)
+	if err != nil {
+		return domainSystem.User{}, err
+	}
+	if main.UserType != userType {
+		return domainSystem.User{}, ErrUserTypeDoesNotMatch
+	}
+	return main, nil
+}
+
 // ChangePassword 修改密码
 func (svc *userService) ChangePassword(ctx context.Context, userId string, oldPassword, newPassword string) error {
 	// 获取用户信息
@@ -112,20 +165,29 @@ func (svc *userService) ChangePassword(ctx context.Context, userId string, oldPa
 		}
 		return err
 	}
+	if main.Id == "" {
+		return repositorySystem.ErrUserNotFound
+	}
+
+	// 缓存中不包含密码，需从数据库读取
+	main, err = svc.repo.GetByUsername(ctx, main.Username)
+	if err != nil {
+		if errors.Is(err, repositorySystem.ErrUserNotFound) {
+			return repositorySystem.ErrUserNotFound
+		}
+		return err
+	}
 
 	// 验证旧密码
 	if !bcrypt.ComparePasswords(main.Password, oldPassword) {
 		return ErrUserInvalidCredential
 	}
 
-	// 加密新密码
-	hashedPassword, err := bcrypt.HashPassword(newPassword)
-	if err != nil {
-		return fmt.Errorf("密码加密失败: %w", err)
-	}
-
 	// 更新密码
-	if err := svc.repo.UpdatePassword(ctx, userId, newPassword, hashedPassword); err != nil {
+	if err := svc.setPassword(ctx, main, newPassword, false); err != nil {
+		if errors.Is(err, ErrPasswordPolicy) {
+			return err
+		}
 		return fmt.Errorf("更新密码失败: %w", err)
 	}
 
@@ -143,15 +205,25 @@ func (svc *userService) Create(ctx context.Context, domain domainSystem.User) er
 		return repositorySystem.ErrUsernameDuplicate
 	}
 
+	// 校验密码策略
+	if err := svc.validatePassword(domain.Password, domain.Username); err != nil {
+		return err
+	}
+
 	// 加密密码
 	hashedPassword, err := bcrypt.HashPassword(domain.Password)
 	if err != nil {
 		return fmt.Errorf("密码加密失败: %w", err)
 	}
 
-	// 保存明文密码（注意：实际生产环境不应存储明文密码）
-	domain.PasswordStr = domain.Password
+	// 校验角色分配
+	if err := svc.checkRoleAssignment(ctx, nil, domain.RoleIDs); err != nil {
+		return err
+	}
+
 	domain.Password = hashedPassword
+	// 管理员设置的密码，按策略要求用户首次登录时修改
+	domain.PasswordChangeRequired = svc.policy.ChangeOnAdminSet
 
 	// 创建用户
 	if err := svc.repo.Create(ctx, domain); err != nil {
@@ -173,9 +245,20 @@ func (svc *userService) Delete(ctx context.Context, id string) error {
 	if rowsAffected == 0 {
 		return repositorySystem.ErrUserNotFound
 	}
+	svc.invalidatePermission(ctx, id)
 	return nil
 }
 
+// BatchDelete 批量删除
+func (svc *userService) BatchDelete(ctx context.Context, ids []string) ([]string, error) {
+	deleted, err := svc.repo.BatchDelete(ctx, ids)
+	if err != nil {
+		return deleted, err
+	}
+	svc.invalidatePermission(ctx, deleted...)
+	return deleted, nil
+}
+
 // Update 更新
 func (svc *userService) Update(ctx context.Context, domain domainSystem.User) error {
 	// 检查用户是否存在
@@ -187,16 +270,181 @@ func (svc *userService) Update(ctx context.Context, domain domainSystem.User) er
 		return repositorySystem.ErrUsernameDuplicate
 	}
 
+	// 校验角色分配(受数据权限限制)
+	current, err := svc.repo.GetById(ctx, domain.Id)
+	if err != nil {
+		return err
+	}
+	if current.Id == "" {
+		return repositorySystem.ErrUserNotFound
+	}
+	if err := svc.checkRoleAssignment(ctx, current.RoleIDs, domain.RoleIDs); err != nil {
+		return err
+	}
+
 	if err := svc.repo.Update(ctx, domain); err != nil {
-		if svc.IsDuplicateEntryError(err) {
+		switch {
+		case svc.IsDuplicateEntryError(err):
 			return repositorySystem.ErrUsernameDuplicate
+		case errors.Is(err, repositorySystem.ErrUserNotFound):
+			return repositorySystem.ErrUserNotFound
+		case errors.Is(err, repositorySystem.ErrUserVersionInconsistency):
+			return repositorySystem.ErrUserVersionInconsistency
+		default:
+			return fmt.Errorf("更新用户失败: %w", err)
+		}
+	}
+
+	// 角色、部门可能已变更
+	svc.invalidatePermission(ctx, domain.Id)
+
+	return nil
+}
+
+// checkRoleAssignment 校验角色分配，防止越权授予角色
+// 超级管理员可分配任意角色，其他用户仅能分配或移除自己持有的角色
+func (svc *userService) checkRoleAssignment(ctx context.Context, current, requested []string) error {
+	before := make(map[string]bool, len(current))
+	for _, id := range current {
+		before[id] = true
+	}
+	after := make(map[string]bool, len(requested))
+	for _, id := range requested {
+		after[id] = true
+	}
+	var changed []string
+	for id := range after {
+		if !before[id] {
+			changed = append(changed, id)
+		}
+	}
+	for id := range before {
+		if !after[id] {
+			changed = append(changed, id)
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+
+	operatorId, _ := ctx.Value("userId").(string)
+	if operatorId == "" {
+		return ErrUserRoleNotAssignable
+	}
+	permission, err := svc.permissionRepo.GetByUserId(ctx, operatorId)
+	if err != nil {
+		return err
+	}
+	if permission.SuperAdmin {
+		return nil
+	}
+	held := make(map[string]bool, len(permission.RoleIds))
+	for _, id := range permission.RoleIds {
+		held[id] = true
+	}
+	for _, id := range changed {
+		if !held[id] {
+			return ErrUserRoleNotAssignable
+		}
+	}
+	return nil
+}
+
+// UpdateStatus 启用/停用
+func (svc *userService) UpdateStatus(ctx context.Context, domain domainSystem.User) error {
+	if err := svc.repo.UpdateStatus(ctx, domain); err != nil {
+		switch {
+		case errors.Is(err, repositorySystem.ErrUserNotFound):
+			return repositorySystem.ErrUserNotFound
+		case errors.Is(err, repositorySystem.ErrUserVersionInconsistency):
+			return repositorySystem.ErrUserVersionInconsistency
+		default:
+			return fmt.Errorf("更新用户状态失败: %w", err)
 		}
-		return fmt.Errorf("更新用户失败: %w", err)
+	}
+	return nil
+}
+
+// ResetPassword 管理员重置密码
+func (svc *userService) ResetPassword(ctx context.Context, userId, newPassword string) error {
+	// 获取用户信息(受数据权限限制)
+	main, err := svc.repo.GetById(ctx, userId)
+	if err != nil {
+		return err
+	}
+	if main.Id == "" {
+		return repositorySystem.ErrUserNotFound
+	}
+
+	// 缓存中不包含密码，需从数据库读取
+	main, err = svc.repo.GetByUsername(ctx, main.Username)
+	if err != nil {
+		if errors.Is(err, repositorySystem.ErrUserNotFound) {
+			return repositorySystem.ErrUserNotFound
+		}
+		return err
+	}
+
+	// 管理员重置的密码，按策略要求用户下次登录时修改
+	if err := svc.setPassword(ctx, main, newPassword, svc.policy.ChangeOnAdminSet); err != nil {
+		if errors.Is(err, repositorySystem.ErrUserNotFound) || errors.Is(err, ErrPasswordPolicy) {
+			return err
+		}
+		return fmt.Errorf("重置密码失败: %w", err)
+	}
+
+	return nil
+}
+
+// RecoverPassword 找回密码
+func (svc *userService) RecoverPassword(ctx context.Context, userId, newPassword string) error {
+	main, err := svc.repo.GetById(ctx, userId)
+	if err != nil {
+		return err
+	}
+	if main.Id == "" {
+		return repositorySystem.ErrUserNotFound
+	}
+
+	// 缓存中不包含密码，需从数据库读取
+	main, err = svc.repo.GetByUsername(ctx, main.Username)
+	if err != nil {
+		return err
+	}
+
+	// 用户本人设置的密码，无需再次修改
+	if err := svc.setPassword(ctx, main, newPassword, false); err != nil {
+		if errors.Is(err, repositorySystem.ErrUserNotFound) || errors.Is(err, ErrPasswordPolicy) {
+			return err
+		}
+		return fmt.Errorf("找回密码失败: %w", err)
 	}
 
 	return nil
 }
 
+// CheckPassword 校验密码复杂度策略
+func (svc *userService) CheckPassword(password, username string) error {
+	return svc.validatePassword(password, username)
+}
+
+// PasswordExpired 密码是否已过期，未记录修改时间的历史用户以创建时间计算
+func (svc *userService) PasswordExpired(user domainSystem.User) bool {
+	if svc.policy.MaxAgeDays <= 0 {
+		return false
+	}
+
+	updateTime := user.PasswordUpdateTime
+	if updateTime == "" {
+		updateTime = user.CreateTime
+	}
+	changed, err := time.ParseInLocation("2006-01-02 15:04:05", updateTime, time.Local)
+	if err != nil {
+		return false
+	}
+	return time.Now().After(changed.AddDate(0, 0, svc.policy.MaxAgeDays))
+}
+
 // GetById 获取详情
 func (svc *userService) GetById(ctx context.Context, id string) (domainSystem.User, error) {
 	main, err := svc.repo.GetById(ctx, id)
@@ -228,6 +476,79 @@ func (svc *userService) GetListAll(ctx context.Context, filter domainSystem.User
 	return svc.repo.GetListAll(ctx, filter)
 }
 
+// validatePassword 校验密码复杂度策略
+func (svc *userService) validatePassword(password, username string) error {
+	return pwdpolicy.Policy{
+		MinLength:        svc.policy.MinLength,
+		MaxLength:        svc.policy.MaxLength,
+		RequireUpper:     svc.policy.RequireUpper,
+		RequireLower:     svc.policy.RequireLower,
+		RequireDigit:     svc.policy.RequireDigit,
+		RequireSymbol:    svc.policy.RequireSymbol,
+		DisallowUsername: svc.policy.DisallowUsername,
+	}.Validate(password, username)
+}
+
+// setPassword 校验策略及历史密码后设置新密码，user 需包含当前密码密文
+func (svc *userService) setPassword(ctx context.Context, user domainSystem.User, password string, changeRequired bool) error {
+	if err := svc.validatePassword(password, user.Username); err != nil {
+		return err
+	}
+
+	// 不能与当前密码及最近使用过的密码相同
+	var history []string
+	if svc.policy.HistoryCount > 1 {
+		recent, err := svc.historyRepo.GetRecentPasswords(ctx, user.Id, svc.policy.HistoryCount-1)
+		if err != nil {
+			return err
+		}
+		history = recent
+	}
+	if svc.policy.HistoryCount > 0 {
+		for _, hashed := range append([]string{user.Password}, history...) {
+			if hashed != "" && bcrypt.ComparePasswords(hashed, password) {
+				return pwdpolicy.Reused(svc.policy.HistoryCount)
+			}
+		}
+	}
+
+	hashedPassword, err := bcrypt.HashPassword(password)
+	if err != nil {
+		return fmt.Errorf("密码加密失败: %w", err)
+	}
+	if err := svc.repo.SetPassword(ctx, user.Id, hashedPassword, changeRequired); err != nil {
+		return err
+	}
+
+	// 记录被替换的密码，供后续修改时比对
+	if svc.policy.HistoryCount > 1 && user.Password != "" {
+		if err := svc.historyRepo.Add(ctx, user.Id, user.Password, svc.policy.HistoryCount-1); err != nil {
+			zap.L().Error("历史密码记录失败", zap.String("userId", user.Id), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// rehashPassword 重新加密密码，失败不影响登录
+func (svc *userService) rehashPassword(ctx context.Context, userId, password string) {
+	hashedPassword, err := bcrypt.HashPassword(password)
+	if err != nil {
+		zap.L().Error("密码重新加密失败", zap.Error(err))
+		return
+	}
+	if err := svc.repo.UpdatePassword(ctx, userId, hashedPassword); err != nil {
+		zap.L().Error("密码重新加密保存失败", zap.String("userId", userId), zap.Error(err))
+	}
+}
+
+// invalidatePermission 用户角色或部门变更后清理其权限缓存
+func (svc *userService) invalidatePermission(ctx context.Context, userIds ...string) {
+	if err := svc.permissionRepo.DelByUserIds(ctx, userIds...); err != nil {
+		// 网络崩了，也可能是 redis 崩了
+		zap.L().Error("清理用户权限缓存失败", zap.Error(err))
+	}
+}
+
 // IsDuplicateEntryError 判断是否是唯一冲突错误
 func (svc *userService) IsDuplicateEntryError(err error) bool {
 	var mysqlErr *mysql.MySQLError
@@ -0,0 +1,349 @@
+/**
+ * Description：
+ * FileName：user_test.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 15:41:18
+ * Remark：
+ */
+
+package system
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	config "github.com/carefuly/carefuly-admin-go-gin/config/file"
+	domainSystem "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/system"
+	modelSystem "github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/system"
+	repositorySystem "github.com/carefuly/carefuly-admin-go-gin/internal/repository/repository/careful/system"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/constants/careful/system/user"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/models"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/bcrypt"
+	xbcrypt "golang.org/x/crypto/bcrypt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// stubUserRepository 记录写入仓储的用户数据
+type stubUserRepository struct {
+	repositorySystem.UserRepository
+	user    domainSystem.User
+	written []string
+}
+
+func (r *stubUserRepository) CheckExistByUsername(ctx context.Context, username, excludeId string) (bool, error) {
+	return false, nil
+}
+
+func (r *stubUserRepository) Create(ctx context.Context, domain domainSystem.User) error {
+	data, err := json.Marshal(domain)
+	if err != nil {
+		return err
+	}
+	r.written = append(r.written, string(data), domain.Password)
+	return nil
+}
+
+func (r *stubUserRepository) Update(ctx context.Context, domain domainSystem.User) error {
+	r.user.RoleIDs = domain.RoleIDs
+	return nil
+}
+
+func (r *stubUserRepository) UpdatePassword(ctx context.Context, userId string, hashedPassword string) error {
+	r.written = append(r.written, hashedPassword)
+	r.user.Password = hashedPassword
+	return nil
+}
+
+func (r *stubUserRepository) SetPassword(ctx context.Context, userId string, hashedPassword string, changeRequired bool) error {
+	r.written = append(r.written, hashedPassword)
+	r.user.Password = hashedPassword
+	r.user.PasswordChangeRequired = changeRequired
+	return nil
+}
+
+func (r *stubUserRepository) GetById(ctx context.Context, id string) (domainSystem.User, error) {
+	// 与缓存一致，不包含密码
+	user := r.user
+	user.Password = ""
+	return user, nil
+}
+
+func (r *stubUserRepository) GetByUsername(ctx context.Context, username string) (domainSystem.User, error) {
+	return r.user, nil
+}
+
+func (r *stubUserRepository) GetListByEmail(ctx context.Context, email string) ([]domainSystem.User, error) {
+	if r.user.Email != email {
+		return nil, nil
+	}
+	return []domainSystem.User{r.user}, nil
+}
+
+// stubPasswordHistoryRepository 记录历史密码
+type stubPasswordHistoryRepository struct {
+	passwords []string
+}
+
+func (r *stubPasswordHistoryRepository) Add(ctx context.Context, userId, hashedPassword string, keep int) error {
+	r.passwords = append([]string{hashedPassword}, r.passwords...)
+	if len(r.passwords) > keep {
+		r.passwords = r.passwords[:keep]
+	}
+	return nil
+}
+
+func (r *stubPasswordHistoryRepository) GetRecentPasswords(ctx context.Context, userId string, limit int) ([]string, error) {
+	return r.passwords[:min(limit, len(r.passwords))], nil
+}
+
+// 确认用户相关接口不会持久化明文密码
+func TestUserService_NeverPersistPlaintextPassword(t *testing.T) {
+	const password = "Secret#Plain1"
+	const newPassword = "Secret#Plain2"
+
+	weak, err := xbcrypt.GenerateFromPassword([]byte(password), xbcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newUser := func() domainSystem.User {
+		return domainSystem.User{User: modelSystem.User{Username: "admin", Password: password}}
+	}
+
+	testCases := []struct {
+		name  string
+		call  func(svc UserService) error
+		plain string
+	}{
+		{name: "注册", call: func(svc UserService) error { return svc.Register(context.Background(), newUser()) }, plain: password},
+		{name: "新增", call: func(svc UserService) error { return svc.Create(context.Background(), newUser()) }, plain: password},
+		{name: "重置密码", call: func(svc UserService) error { return svc.ResetPassword(context.Background(), "1", newPassword) }, plain: newPassword},
+		{name: "修改密码", call: func(svc UserService) error {
+			return svc.ChangePassword(context.Background(), "1", password, newPassword)
+		}, plain: newPassword},
+		{name: "登录重新加密", call: func(svc UserService) error {
+			_, err := svc.Login(context.Background(), "admin", password)
+			return err
+		}, plain: password},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &stubUserRepository{
+				user: domainSystem.User{User: modelSystem.User{
+					CoreModels: models.CoreModels{Id: "1"},
+					Status:     true,
+					Username:   "admin",
+					Password:   string(weak),
+				}},
+			}
+			history := &stubPasswordHistoryRepository{}
+			svc := NewUserService(repo, nil, history, config.PasswordPolicyConfig{HistoryCount: 3})
+			if err := tc.call(svc); err != nil {
+				t.Fatal(err)
+			}
+			if len(repo.written) == 0 {
+				t.Fatal("未写入任何数据")
+			}
+			for _, written := range append(repo.written, history.passwords...) {
+				if strings.Contains(written, tc.plain) {
+					t.Errorf("写入了明文密码: %s", written)
+				}
+			}
+			hashed := repo.written[len(repo.written)-1]
+			if !bcrypt.ComparePasswords(hashed, tc.plain) || bcrypt.NeedsRehash(hashed) {
+				t.Errorf("密码密文不正确: %s", hashed)
+			}
+		})
+	}
+}
+
+// 确认按用户类型登录时，后台用户与前台用户分别认证
+func TestUserService_TypeLogin(t *testing.T) {
+	const password = "Secret#Plain1"
+
+	hashed, err := bcrypt.HashPassword(password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := &stubUserRepository{
+		user: domainSystem.User{User: modelSystem.User{
+			CoreModels: models.CoreModels{Id: "1"},
+			Status:     true,
+			Username:   "admin",
+			Password:   hashed,
+			UserType:   user.TypeConstAdminUser,
+		}},
+	}
+	svc := NewUserService(repo, nil, nil, config.PasswordPolicyConfig{})
+
+	if _, err := svc.TypeLogin(context.Background(), "admin", password, user.TypeConstAdminUser); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.TypeLogin(context.Background(), "admin", password, user.TypeConstFrontUser); !errors.Is(err, ErrUserTypeDoesNotMatch) {
+		t.Fatalf("用户类型不一致时应登录失败，实际: %v", err)
+	}
+	if _, err := svc.TypeLogin(context.Background(), "admin", "wrong", user.TypeConstFrontUser); !errors.Is(err, ErrUserInvalidCredential) {
+		t.Fatalf("密码错误时应先返回凭证错误，实际: %v", err)
+	}
+}
+
+// 确认注册、新增、修改密码及管理员重置均执行密码策略
+func TestUserService_PasswordPolicy(t *testing.T) {
+	const password = "Carefuly#2026"
+
+	policy := config.PasswordPolicyConfig{
+		MinLength:        8,
+		RequireUpper:     true,
+		RequireLower:     true,
+		RequireDigit:     true,
+		DisallowUsername: true,
+		HistoryCount:     3,
+		MaxAgeDays:       90,
+		ChangeOnAdminSet: true,
+	}
+	newService := func() (UserService, *stubUserRepository) {
+		hashed, err := bcrypt.HashPassword(password)
+		if err != nil {
+			t.Fatal(err)
+		}
+		repo := &stubUserRepository{
+			user: domainSystem.User{User: modelSystem.User{
+				CoreModels: models.CoreModels{Id: "1"},
+				Status:     true,
+				Username:   "admin",
+				Password:   hashed,
+			}},
+		}
+		return NewUserService(repo, nil, &stubPasswordHistoryRepository{}, policy), repo
+	}
+	newUser := func(password string) domainSystem.User {
+		return domainSystem.User{User: modelSystem.User{Username: "admin", Password: password}}
+	}
+
+	t.Run("复杂度", func(t *testing.T) {
+		svc, _ := newService()
+		ctx := context.Background()
+		for name, err := range map[string]error{
+			"注册":   svc.Register(ctx, newUser("weakpass")),
+			"新增":   svc.Create(ctx, newUser("Admin#2026")),
+			"修改密码": svc.ChangePassword(ctx, "1", password, "short"),
+			"重置密码": svc.ResetPassword(ctx, "1", "nouppercase1"),
+		} {
+			if !errors.Is(err, ErrPasswordPolicy) {
+				t.Errorf("%s: 期望密码策略错误，实际: %v", name, err)
+			}
+		}
+	})
+
+	t.Run("历史密码", func(t *testing.T) {
+		svc, _ := newService()
+		ctx := context.Background()
+		if err := svc.ChangePassword(ctx, "1", password, password); !errors.Is(err, ErrPasswordPolicy) {
+			t.Fatalf("不应允许沿用当前密码，实际: %v", err)
+		}
+		if err := svc.ChangePassword(ctx, "1", password, "Carefuly#2027"); err != nil {
+			t.Fatal(err)
+		}
+		if err := svc.ChangePassword(ctx, "1", "Carefuly#2027", "Carefuly#2028"); err != nil {
+			t.Fatal(err)
+		}
+		if err := svc.ChangePassword(ctx, "1", "Carefuly#2028", password); !errors.Is(err, ErrPasswordPolicy) {
+			t.Fatalf("不应允许使用最近3次内的密码，实际: %v", err)
+		}
+		if err := svc.ChangePassword(ctx, "1", "Carefuly#2028", "Carefuly#2029"); err != nil {
+			t.Fatal(err)
+		}
+		if err := svc.ChangePassword(ctx, "1", "Carefuly#2029", password); err != nil {
+			t.Fatalf("超出历史记录数量的密码应允许使用，实际: %v", err)
+		}
+	})
+
+	t.Run("首次登录修改", func(t *testing.T) {
+		svc, repo := newService()
+		ctx := context.Background()
+		if err := svc.ResetPassword(ctx, "1", "Carefuly#2027"); err != nil {
+			t.Fatal(err)
+		}
+		if !repo.user.PasswordChangeRequired {
+			t.Fatal("管理员重置密码后应要求修改密码")
+		}
+		if err := svc.ChangePassword(ctx, "1", "Carefuly#2027", "Carefuly#2028"); err != nil {
+			t.Fatal(err)
+		}
+		if repo.user.PasswordChangeRequired {
+			t.Fatal("用户修改密码后应清除修改要求")
+		}
+	})
+
+	t.Run("密码过期", func(t *testing.T) {
+		svc, _ := newService()
+		user := domainSystem.User{CreateTime: time.Now().AddDate(0, 0, -120).Format("2006-01-02 15:04:05")}
+		if !svc.PasswordExpired(user) {
+			t.Fatal("超过最长使用天数应视为过期")
+		}
+		user.PasswordUpdateTime = time.Now().AddDate(0, 0, -30).Format("2006-01-02 15:04:05")
+		if svc.PasswordExpired(user) {
+			t.Fatal("修改时间未超过最长使用天数不应过期")
+		}
+	})
+}
+
+// 确认非超级管理员只能分配或移除自己持有的角色
+func TestUserService_RoleAssignment(t *testing.T) {
+	ctx := context.WithValue(context.Background(), "userId", "operator")
+	newService := func(superAdmin bool, current ...string) UserService {
+		repo := &stubUserRepository{user: domainSystem.User{User: modelSystem.User{
+			CoreModels: models.CoreModels{Id: "1"},
+			Username:   "user",
+			RoleIDs:    current,
+		}}}
+		permissionRepo := &stubPermissionRepository{permission: domainSystem.UserPermission{
+			SuperAdmin: superAdmin,
+			RoleIds:    []string{"editor"},
+		}}
+		return NewUserService(repo, permissionRepo, &stubPasswordHistoryRepository{}, config.PasswordPolicyConfig{})
+	}
+	update := func(roles ...string) domainSystem.User {
+		return domainSystem.User{User: modelSystem.User{CoreModels: models.CoreModels{Id: "1"}, Username: "user", RoleIDs: roles}}
+	}
+
+	testCases := []struct {
+		name    string
+		call    func() error
+		wantErr error
+	}{
+		{name: "新增分配持有的角色", call: func() error {
+			return newService(false).Create(ctx, domainSystem.User{User: modelSystem.User{
+				Username: "user", Password: "Secret#Role1", RoleIDs: []string{"editor"}}})
+		}},
+		{name: "新增分配未持有的角色", call: func() error {
+			return newService(false).Create(ctx, domainSystem.User{User: modelSystem.User{
+				Username: "user", Password: "Secret#Role1", RoleIDs: []string{"admin"}}})
+		}, wantErr: ErrUserRoleNotAssignable},
+		{name: "修改为未持有的角色", call: func() error {
+			return newService(false, "editor").Update(ctx, update("editor", "admin"))
+		}, wantErr: ErrUserRoleNotAssignable},
+		{name: "移除未持有的角色", call: func() error {
+			return newService(false, "admin", "editor").Update(ctx, update("editor"))
+		}, wantErr: ErrUserRoleNotAssignable},
+		{name: "保留未持有的角色", call: func() error {
+			return newService(false, "admin").Update(ctx, update("admin", "editor"))
+		}},
+		{name: "超级管理员", call: func() error {
+			return newService(true).Update(ctx, update("admin"))
+		}},
+		{name: "无操作人", call: func() error {
+			return newService(true).Update(context.Background(), update("admin"))
+		}, wantErr: ErrUserRoleNotAssignable},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.call(); !errors.Is(err, tc.wantErr) {
+				t.Errorf("err = %v, want %v", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+/**
+ * Description：
+ * FileName：api_key.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 23:52:17
+ * Remark：
+ */
+
+package auth
+
+import (
+	"errors"
+	config "github.com/carefuly/carefuly-admin-go-gin/config/file"
+	domainSystem "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/system"
+	serviceSystem "github.com/carefuly/carefuly-admin-go-gin/internal/service/careful/system"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/response"
+	validate "github.com/carefuly/carefuly-admin-go-gin/pkg/validator"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+// CreateApiKeyRequest 创建API Key
+type CreateApiKeyRequest struct {
+	Name       string   `json:"name" binding:"required,max=64" example:"ci-deploy"`       // 名称
+	Scopes     []string `json:"scopes" binding:"omitempty,max=100,dive,required,max=64"`  // 授权范围(按钮权限值)，为空表示继承用户全部权限
+	ExpireDays int      `json:"expireDays" binding:"required,min=1,max=365" example:"90"` // 有效天数
+}
+
+type ApiKeyHandler interface {
+	RegisterRoutes(router *gin.RouterGroup)
+	Create(ctx *gin.Context)
+	Revoke(ctx *gin.Context)
+	GetListAll(ctx *gin.Context)
+}
+
+type apiKeyHandler struct {
+	rely    config.RelyConfig
+	svc     serviceSystem.ApiKeyService
+	userSvc serviceSystem.UserService
+}
+
+func NewApiKeyHandler(rely config.RelyConfig, svc serviceSystem.ApiKeyService, userSvc serviceSystem.UserService) ApiKeyHandler {
+	return &apiKeyHandler{
+		rely:    rely,
+		svc:     svc,
+		userSvc: userSvc,
+	}
+}
+
+// RegisterRoutes 注册路由
+func (h *apiKeyHandler) RegisterRoutes(router *gin.RouterGroup) {
+	base := router.Group("/apiKey")
+	base.POST("/create", denyImpersonation, h.Create)
+	base.DELETE("/revoke/:id", denyImpersonation, h.Revoke)
+	base.GET("/listAll", h.GetListAll)
+}
+
+// Create
+// @Summary 创建API Key
+// @Description 创建个人访问令牌，供脚本及第三方集成以 Authorization: ApiKey <key> 调用接口，令牌明文仅返回一次
+// @Tags 认证管理/API Key
+// @Accept application/json
+// @Produce application/json
+// @Param CreateApiKeyRequest body CreateApiKeyRequest true "请求"
+// @Success 200 {object} domainSystem.ApiKeyCreated
+// @Failure 400 {object} response.Response
+// @Router /v1/auth/apiKey/create [post]
+// @Security LoginToken
+func (h *apiKeyHandler) Create(ctx *gin.Context) {
+	var req CreateApiKeyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validate.NewValidatorError(h.rely.Trans).HandleValidatorError(ctx, err)
+		return
+	}
+	user, ok := h.currentUser(ctx)
+	if !ok {
+		return
+	}
+
+	created, err := h.svc.Create(ctx, user, req.Name, req.Scopes, req.ExpireDays)
+	if err != nil {
+		switch {
+		case errors.Is(err, serviceSystem.ErrApiKeyScopeInvalid):
+			response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "授权范围超出当前用户已有权限", nil)
+		case errors.Is(err, serviceSystem.ErrApiKeyTooMany):
+			response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		default:
+			ctx.Set("internal", err.Error())
+			zap.L().Error("创建API Key异常", zap.String("userId", user.Id), zap.Error(err))
+			response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+		}
+		return
+	}
+
+	response.NewResponse().SuccessResponse(ctx, "创建成功", created)
+}
+
+// Revoke
+// @Summary 吊销API Key
+// @Description 吊销当前用户的个人访问令牌，吊销后立即失效
+// @Tags 认证管理/API Key
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "id"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Router /v1/auth/apiKey/revoke/{id} [delete]
+// @Security LoginToken
+func (h *apiKeyHandler) Revoke(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if id == "" {
+		response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "ID不能为空", nil)
+		return
+	}
+	user, ok := h.currentUser(ctx)
+	if !ok {
+		return
+	}
+
+	if err := h.svc.Revoke(ctx, user, id); err != nil {
+		switch {
+		case errors.Is(err, serviceSystem.ErrApiKeyNotFound):
+			response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "API Key不存在或已吊销", nil)
+		default:
+			ctx.Set("internal", err.Error())
+			zap.L().Error("吊销API Key异常", zap.String("id", id), zap.Error(err))
+			response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+		}
+		return
+	}
+
+	response.NewResponse().SuccessResponse(ctx, "吊销成功", nil)
+}
+
+// GetListAll
+// @Summary 获取API Key列表
+// @Description 获取当前用户的全部个人访问令牌，仅展示令牌前缀
+// @Tags 认证管理/API Key
+// @Accept application/json
+// @Produce application/json
+// @Success 200 {array} domainSystem.ApiKey
+// @Failure 400 {object} response.Response
+// @Router /v1/auth/apiKey/listAll [get]
+// @Security LoginToken
+func (h *apiKeyHandler) GetListAll(ctx *gin.Context) {
+	uid, ok := ctx.MustGet("userId").(string)
+	if !ok {
+		ctx.Set("internal", uid)
+		zap.S().Error("用户ID获取失败", uid)
+		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+		return
+	}
+
+	list, err := h.svc.GetListByUserId(ctx, uid)
+	if err != nil {
+		ctx.Set("internal", err.Error())
+		zap.L().Error("获取API Key列表异常", zap.String("userId", uid), zap.Error(err))
+		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+		return
+	}
+
+	response.NewResponse().SuccessResponse(ctx, "查询成功", list)
+}
+
+// currentUser 获取当前登录用户，失败时直接响应
+func (h *apiKeyHandler) currentUser(ctx *gin.Context) (domainSystem.User, bool) {
+	uid, ok := ctx.MustGet("userId").(string)
+	if !ok {
+		ctx.Set("internal", uid)
+		zap.S().Error("用户ID获取失败", uid)
+		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+		return domainSystem.User{}, false
+	}
+
+	user, err := h.userSvc.GetById(ctx, uid)
+	if err != nil {
+		ctx.Set("internal", err.Error())
+		zap.L().Error("获取用户信息异常", zap.String("userId", uid), zap.Error(err))
+		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+		return domainSystem.User{}, false
+	}
+	if user.Id == "" {
+		response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "用户不存在", nil)
+		return domainSystem.User{}, false
+	}
+	return user, true
+}
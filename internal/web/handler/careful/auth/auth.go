@@ -10,16 +10,26 @@ package auth
 
 import (
 	"errors"
+	"fmt"
 	config "github.com/carefuly/carefuly-admin-go-gin/config/file"
+	domainLogger "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/logger"
 	domainSystem "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/system"
+	modelLogger "github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/logger"
 	modelSystem "github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/system"
+	serviceLogger "github.com/carefuly/carefuly-admin-go-gin/internal/service/careful/logger"
 	"github.com/carefuly/carefuly-admin-go-gin/internal/service/careful/system"
 	serviceSystem "github.com/carefuly/carefuly-admin-go-gin/internal/service/careful/system"
 	"github.com/carefuly/carefuly-admin-go-gin/internal/service/careful/third"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/constants/careful/logger/login"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/constants/careful/system/user"
 	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/response"
 	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/jwt"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/requestUtils"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/third/captcha"
 	validate "github.com/carefuly/carefuly-admin-go-gin/pkg/validator"
 	"github.com/gin-gonic/gin"
+	"github.com/mssola/user_agent"
+	uuid "github.com/satori/go.uuid"
 	"go.uber.org/zap"
 	"net/http"
 	"strings"
@@ -28,62 +38,121 @@ import (
 
 // RegisterRequest 注册请求
 type RegisterRequest struct {
-	Username string `json:"username" binding:"required,min=3,max=50" example:"demo"`   // 用户名
-	Password string `json:"password" binding:"required,min=6,max=20" example:"123456"` // 密码
+	Username    string `json:"username" binding:"required,min=3,max=50" example:"demo"` // 用户名
+	Password    string `json:"password" binding:"required,max=72" example:"123456"`     // 密码，复杂度由密码策略校验
+	CaptchaId   string `json:"captchaId" binding:"omitempty" example:"8sR1nZ..."`       // 验证码ID
+	CaptchaCode string `json:"captchaCode" binding:"omitempty" example:"123456"`        // 验证码
 }
 
 // LoginRequest 登录请求
 type LoginRequest struct {
-	Username string `json:"username" binding:"required" example:"demo"`   // 用户名
-	Password string `json:"password" binding:"required" example:"123456"` // 密码
+	Username    string `json:"username" binding:"required" example:"demo"`        // 用户名
+	Password    string `json:"password" binding:"required" example:"123456"`      // 密码
+	CaptchaId   string `json:"captchaId" binding:"omitempty" example:"8sR1nZ..."` // 验证码ID
+	CaptchaCode string `json:"captchaCode" binding:"omitempty" example:"123456"`  // 验证码
 }
 
 // UserTypeLoginRequest 多用户类型登录请求
 type UserTypeLoginRequest struct {
-	Username string `json:"username" binding:"required" example:"admin"`  // 用户名
-	Password string `json:"password" binding:"required" example:"123456"` // 密码
-	UserType int    `json:"userType" binding:"required" example:"1"`      // 用户类型
+	Username    string `json:"username" binding:"required" example:"admin"`       // 用户名
+	Password    string `json:"password" binding:"required" example:"123456"`      // 密码
+	UserType    int    `json:"userType" binding:"required,oneof=1 2" example:"1"` // 用户类型【1-后台用户 2-前台用户】
+	CaptchaId   string `json:"captchaId" binding:"omitempty" example:"8sR1nZ..."` // 验证码ID
+	CaptchaCode string `json:"captchaCode" binding:"omitempty" example:"123456"`  // 验证码
+}
+
+// CaptchaRequiredResponse 需要验证码响应
+type CaptchaRequiredResponse struct {
+	CaptchaRequired bool `json:"captchaRequired"` // 是否需要验证码
 }
 
 // LoginResponse 登录响应
 type LoginResponse struct {
-	Token  string            `json:"token"`  // JWT令牌
-	User   domainSystem.User `json:"user"`   // 用户信息
-	Expire int               `json:"expire"` // 过期时间(秒)
+	Token         string            `json:"token"`         // JWT访问令牌
+	RefreshToken  string            `json:"refreshToken"`  // 刷新令牌
+	User          domainSystem.User `json:"user"`          // 用户信息
+	Expire        int               `json:"expire"`        // 访问令牌过期时间(秒)
+	RefreshExpire int               `json:"refreshExpire"` // 刷新令牌过期时间(秒)
+	// TwoFactorSetupRequired 当前账号要求双因素认证但尚未绑定
+	TwoFactorSetupRequired bool `json:"twoFactorSetupRequired"`
+	// PasswordChangeRequired 需先修改密码(管理员设置的初始密码或密码已过期)
+	PasswordChangeRequired bool `json:"passwordChangeRequired"`
+	// PasswordExpired 密码已超过最长使用天数
+	PasswordExpired bool `json:"passwordExpired"`
 }
 
 // RefreshTokenRequest 刷新令牌请求
 type RefreshTokenRequest struct {
-	Token string `json:"token" binding:"required" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6Ikp..."` // 旧的JWT令牌
+	RefreshToken string `json:"refreshToken" binding:"required" example:"q3Zx8kV..."` // 刷新令牌
 }
 
 // ChangePasswordRequest 修改密码请求
 type ChangePasswordRequest struct {
-	OldPassword string `json:"oldPassword" binding:"required" example:"123456"`              // 旧密码
-	NewPassword string `json:"newPassword" binding:"required,min=6,max=20" example:"654321"` // 新密码
+	OldPassword string `json:"oldPassword" binding:"required" example:"123456"`        // 旧密码
+	NewPassword string `json:"newPassword" binding:"required,max=72" example:"654321"` // 新密码，复杂度由密码策略校验
 }
 
 type AuthsHandler interface {
 	RegisterRoutes(router *gin.RouterGroup)
 	RegisterHandler(ctx *gin.Context)
 	LoginHandler(ctx *gin.Context)
+	TypeLoginHandler(ctx *gin.Context)
 	RefreshTokenHandler(ctx *gin.Context)
 	LogoutHandler(ctx *gin.Context)
 	GetCurrentUserHandler(ctx *gin.Context)
 	ChangePasswordHandler(ctx *gin.Context)
+	GetPermissionsHandler(ctx *gin.Context)
+	GetRoutesHandler(ctx *gin.Context)
+	TwoFactorLoginHandler(ctx *gin.Context)
+	GetTwoFactorStatusHandler(ctx *gin.Context)
+	SetupTwoFactorHandler(ctx *gin.Context)
+	EnableTwoFactorHandler(ctx *gin.Context)
+	DisableTwoFactorHandler(ctx *gin.Context)
+	RegenerateRecoveryCodesHandler(ctx *gin.Context)
+	ForgotPasswordCodeHandler(ctx *gin.Context)
+	ForgotPasswordResetHandler(ctx *gin.Context)
+	StopImpersonationHandler(ctx *gin.Context)
+	GetSSOProvidersHandler(ctx *gin.Context)
+	SSOAuthorizeHandler(ctx *gin.Context)
+	SSOLoginHandler(ctx *gin.Context)
 }
 
 type authHandler struct {
-	rely       config.RelyConfig
-	userSvc    serviceSystem.UserService
-	captchaSvc third.CaptchaService
+	rely          config.RelyConfig
+	userSvc       serviceSystem.UserService
+	captchaSvc    third.CaptchaService
+	permissionSvc serviceSystem.PermissionService
+	loginGuardSvc serviceSystem.LoginGuardService
+	loginLogSvc   serviceLogger.LoginLogService
+	sessionSvc    serviceSystem.SessionService
+	twoFactorSvc  serviceSystem.TwoFactorService
+
+	passwordResetSvc serviceSystem.PasswordResetService
+	bindingSvc       serviceSystem.TokenBindingService
+	impersonationSvc serviceSystem.ImpersonationService
+	ssoSvc           serviceSystem.SSOService
 }
 
-func NewRegisterHandler(rely config.RelyConfig, svc serviceSystem.UserService, captchaSvc third.CaptchaService) AuthsHandler {
+func NewRegisterHandler(rely config.RelyConfig, svc serviceSystem.UserService, captchaSvc third.CaptchaService,
+	permissionSvc serviceSystem.PermissionService, loginGuardSvc serviceSystem.LoginGuardService,
+	loginLogSvc serviceLogger.LoginLogService, sessionSvc serviceSystem.SessionService,
+	twoFactorSvc serviceSystem.TwoFactorService, passwordResetSvc serviceSystem.PasswordResetService,
+	bindingSvc serviceSystem.TokenBindingService, impersonationSvc serviceSystem.ImpersonationService,
+	ssoSvc serviceSystem.SSOService) AuthsHandler {
 	return &authHandler{
-		rely:       rely,
-		userSvc:    svc,
-		captchaSvc: captchaSvc,
+		rely:          rely,
+		userSvc:       svc,
+		captchaSvc:    captchaSvc,
+		permissionSvc: permissionSvc,
+		loginGuardSvc: loginGuardSvc,
+		loginLogSvc:   loginLogSvc,
+		sessionSvc:    sessionSvc,
+		twoFactorSvc:  twoFactorSvc,
+
+		passwordResetSvc: passwordResetSvc,
+		bindingSvc:       bindingSvc,
+		impersonationSvc: impersonationSvc,
+		ssoSvc:           ssoSvc,
 	}
 }
 
@@ -91,10 +160,25 @@ func NewRegisterHandler(rely config.RelyConfig, svc serviceSystem.UserService, c
 func (h *authHandler) RegisterRoutes(router *gin.RouterGroup) {
 	router.POST("/register", h.RegisterHandler)
 	router.POST("/login", h.LoginHandler)
+	router.POST("/type-login", h.TypeLoginHandler)
 	router.POST("/refresh-token", h.RefreshTokenHandler)
 	router.POST("/logout", h.LogoutHandler)
 	router.GET("/userinfo", h.GetCurrentUserHandler)
-	router.POST("/change-password", h.ChangePasswordHandler)
+	router.POST("/change-password", denyImpersonation, h.ChangePasswordHandler)
+	router.GET("/permissions", h.GetPermissionsHandler)
+	router.GET("/routes", h.GetRoutesHandler)
+	router.POST("/login/2fa", h.TwoFactorLoginHandler)
+	router.GET("/2fa/status", h.GetTwoFactorStatusHandler)
+	router.POST("/2fa/setup", denyImpersonation, h.SetupTwoFactorHandler)
+	router.POST("/2fa/enable", denyImpersonation, h.EnableTwoFactorHandler)
+	router.POST("/2fa/disable", denyImpersonation, h.DisableTwoFactorHandler)
+	router.POST("/2fa/recoveryCodes", denyImpersonation, h.RegenerateRecoveryCodesHandler)
+	router.POST("/forgot-password/code", h.ForgotPasswordCodeHandler)
+	router.POST("/forgot-password/reset", h.ForgotPasswordResetHandler)
+	router.POST("/impersonate/stop", h.StopImpersonationHandler)
+	router.GET("/sso/providers", h.GetSSOProvidersHandler)
+	router.GET("/sso/authorize", h.SSOAuthorizeHandler)
+	router.POST("/sso/login", h.SSOLoginHandler)
 }
 
 // RegisterHandler
@@ -114,6 +198,12 @@ func (h *authHandler) RegisterHandler(ctx *gin.Context) {
 		return
 	}
 
+	// 校验验证码
+	if h.loginGuardSvc.RegisterCaptchaRequired() &&
+		!h.verifyCaptcha(ctx, captcha.BizCaptchaRegister, req.CaptchaId, req.CaptchaCode) {
+		return
+	}
+
 	// 转换为领域模型
 	user := domainSystem.User{
 		User: modelSystem.User{
@@ -129,6 +219,9 @@ func (h *authHandler) RegisterHandler(ctx *gin.Context) {
 		case errors.Is(err, serviceSystem.ErrUsernameDuplicate):
 			response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "用户名已存在，请重新输入", nil)
 			return
+		case errors.Is(err, serviceSystem.ErrPasswordPolicy):
+			response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+			return
 		default:
 			ctx.Set("internal", err.Error())
 			zap.L().Error("用户注册失败", zap.Error(err))
@@ -157,40 +250,172 @@ func (h *authHandler) LoginHandler(ctx *gin.Context) {
 		return
 	}
 
+	h.passwordLogin(ctx, req.Username, req.Password, req.CaptchaId, req.CaptchaCode, 0)
+}
+
+// TypeLoginHandler
+// @Summary 多用户类型登录
+// @Description 按用户类型登录，后台用户与前台用户分别认证，用户类型不一致时登录失败
+// @Tags 认证管理/账号密码登录
+// @Accept application/json
+// @Produce application/json
+// @Param UserTypeLoginRequest body UserTypeLoginRequest true "请求"
+// @Success 200 {object} LoginResponse
+// @Failure 400 {object} response.Response
+// @Router /v1/auth/type-login [post]
+func (h *authHandler) TypeLoginHandler(ctx *gin.Context) {
+	var req UserTypeLoginRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validate.NewValidatorError(h.rely.Trans).HandleValidatorError(ctx, err)
+		return
+	}
+
+	h.passwordLogin(ctx, req.Username, req.Password, req.CaptchaId, req.CaptchaCode, user.TypeConst(req.UserType))
+}
+
+// passwordLogin 账号密码登录，userType 为 0 时不限制用户类型
+func (h *authHandler) passwordLogin(ctx *gin.Context, username, password, captchaId, captchaCode string, userType user.TypeConst) {
+	// 检查锁定状态
+	ip := requestUtils.NormalizeIP(ctx)
+	if remaining, err := h.loginGuardSvc.CheckLocked(ctx, username, ip); err != nil {
+		h.recordLoginLog(ctx, login.ActionConstLogin, "", username, false, err.Error())
+		switch {
+		case errors.Is(err, serviceSystem.ErrAccountLocked):
+			response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest,
+				fmt.Sprintf("登录失败次数过多，账号已锁定，请%s后重试", formatRemaining(remaining)), nil)
+		default:
+			response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest,
+				fmt.Sprintf("登录失败次数过多，请%s后重试", formatRemaining(remaining)), nil)
+		}
+		return
+	}
+
+	// 校验验证码
+	if h.loginGuardSvc.CaptchaRequired(ctx, username) &&
+		!h.verifyCaptcha(ctx, captcha.BizCaptchaLogin, captchaId, captchaCode) {
+		h.recordLoginLog(ctx, login.ActionConstLogin, "", username, false, "验证码校验失败")
+		return
+	}
+
 	// 调用业务逻辑
-	user, err := h.userSvc.Login(ctx, req.Username, req.Password)
+	var (
+		detail domainSystem.User
+		err    error
+	)
+	if userType == 0 {
+		detail, err = h.userSvc.Login(ctx, username, password)
+	} else {
+		detail, err = h.userSvc.TypeLogin(ctx, username, password, userType)
+	}
 	if err != nil {
 		switch {
 		case errors.Is(err, system.ErrUserInvalidCredential):
-			response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "用户名或密码错误", nil)
+			h.recordLoginLog(ctx, login.ActionConstLogin, "", username, false, "用户名或密码错误")
+			required := h.loginGuardSvc.RecordFailure(ctx, username, ip)
+			response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "用户名或密码错误", CaptchaRequiredResponse{
+				CaptchaRequired: required,
+			})
+			return
+		case errors.Is(err, serviceSystem.ErrUserTypeDoesNotMatch):
+			h.recordLoginLog(ctx, login.ActionConstLogin, "", username, false, "用户类型不匹配")
+			response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "用户类型不匹配，请使用对应的登录入口", nil)
+			return
+		case errors.Is(err, serviceSystem.ErrUserDisabled):
+			h.recordLoginLog(ctx, login.ActionConstLogin, "", username, false, "用户已停用")
+			response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "用户已停用，请联系管理员", nil)
 			return
 		default:
 			ctx.Set("internal", err.Error())
+			h.recordLoginLog(ctx, login.ActionConstLogin, "", username, false, "服务器异常")
 			zap.L().Error("登录失败", zap.Error(err))
 			response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
 			return
 		}
 	}
 
-	// 生成JWT令牌
-	token, err := jwt.GenerateToken(ctx, user.Id, user.Username, int(user.UserType), user.DeptId, h.rely.Token.Secret, h.rely.Token.Expire)
+	// 已启用双因素认证时，返回登录二次验证
+	if h.challengeTwoFactor(ctx, detail) {
+		return
+	}
+
+	h.loginGuardSvc.RecordSuccess(ctx, username)
+	h.completeLogin(ctx, detail, true)
+}
+
+// challengeTwoFactor 用户已启用双因素认证时响应登录二次验证，返回是否已响应
+func (h *authHandler) challengeTwoFactor(ctx *gin.Context, user domainSystem.User) bool {
+	enabled, err := h.twoFactorSvc.Enabled(ctx, user.Id)
 	if err != nil {
-		zap.S().Errorf("生成令牌失败: %v", err)
-		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "生成令牌失败: "+err.Error(), nil)
+		ctx.Set("internal", err.Error())
+		h.recordLoginLog(ctx, login.ActionConstLogin, user.Id, user.Username, false, "服务器异常")
+		zap.L().Error("查询双因素认证状态异常", zap.String("userId", user.Id), zap.Error(err))
+		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+		return true
+	}
+	if !enabled {
+		return false
+	}
+
+	challenge, expire, err := h.twoFactorSvc.CreateChallenge(ctx, user.Id)
+	if err != nil {
+		ctx.Set("internal", err.Error())
+		zap.L().Error("创建登录二次验证异常", zap.String("userId", user.Id), zap.Error(err))
+		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+		return true
+	}
+	response.NewResponse().SuccessResponse(ctx, "请输入双因素认证验证码", TwoFactorChallengeResponse{
+		TwoFactorRequired: true,
+		ChallengeToken:    challenge,
+		Expire:            int(expire.Seconds()),
+	})
+	return true
+}
+
+// completeLogin 签发令牌并响应登录成功，localPassword 为 false 时(单点登录)不提示修改本地密码
+func (h *authHandler) completeLogin(ctx *gin.Context, user domainSystem.User, localPassword bool) {
+	// 初始密码或密码过期时签发受限令牌，修改密码前无法访问其他接口
+	passwordExpired := localPassword && h.userSvc.PasswordExpired(user)
+	passwordChange := localPassword && (user.PasswordChangeRequired || passwordExpired)
+
+	// 要求启用双因素认证但尚未绑定时签发受限令牌，绑定前仅可访问双因素认证等接口
+	// 需修改密码时优先修改密码，修改后刷新令牌再要求绑定双因素认证
+	twoFactorSetup := false
+	if !passwordChange {
+		var err error
+		twoFactorSetup, err = h.twoFactorSetupRequired(ctx, user)
+		if err != nil {
+			ctx.Set("internal", err.Error())
+			h.recordLoginLog(ctx, login.ActionConstLogin, user.Id, user.Username, false, "查询双因素认证要求异常")
+			zap.L().Error("查询双因素认证要求异常", zap.String("userId", user.Id), zap.Error(err))
+			response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+			return
+		}
+	}
+
+	// 签发令牌并记录在线会话
+	resp, err := h.issueToken(ctx, user, passwordChange, twoFactorSetup)
+	if err != nil {
+		ctx.Set("internal", err.Error())
+		h.recordLoginLog(ctx, login.ActionConstLogin, user.Id, user.Username, false, "签发令牌失败")
+		zap.L().Error("签发令牌失败", zap.String("userId", user.Id), zap.Error(err))
+		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
 		return
 	}
 
+	// 提示前端强制修改密码或绑定双因素认证，完成后使用刷新令牌换取不受限的令牌
+	resp.PasswordExpired = passwordExpired
+	resp.PasswordChangeRequired = passwordChange
+	resp.TwoFactorSetupRequired = twoFactorSetup
+
+	h.recordLoginLog(ctx, login.ActionConstLogin, user.Id, user.Username, true, "")
+
 	// 返回用户信息和令牌
-	response.NewResponse().SuccessResponse(ctx, "登录成功", LoginResponse{
-		Token:  token,
-		User:   user,
-		Expire: h.rely.Token.Expire * 3600,
-	})
+	response.NewResponse().SuccessResponse(ctx, "登录成功", resp)
 }
 
 // RefreshTokenHandler
 // @Summary 刷新令牌
-// @Description 使用旧的JWT令牌获取新的令牌
+// @Description 使用刷新令牌换取新的访问令牌及刷新令牌，刷新令牌只能使用一次，重复使用将吊销整个令牌族
 // @Tags 认证管理/刷新令牌
 // @Accept application/json
 // @Produce application/json
@@ -206,24 +431,39 @@ func (h *authHandler) RefreshTokenHandler(ctx *gin.Context) {
 		return
 	}
 
-	// 解析旧令牌
-	claims, err := jwt.ParseToken(req.Token, h.rely.Token.Secret)
+	// 使用刷新令牌
+	rt, err := jwt.NewRefreshTokenStore(h.rely.Redis).Consume(ctx, req.RefreshToken)
 	if err != nil {
 		switch {
-		case errors.Is(err, jwt.ErrExpiredToken):
-			response.NewResponse().ErrorResponse(ctx, http.StatusUnauthorized, "令牌已过期，请重新登录", nil)
-		case errors.Is(err, jwt.ErrInvalidToken):
-			response.NewResponse().ErrorResponse(ctx, http.StatusUnauthorized, "无效的令牌", nil)
+		case errors.Is(err, jwt.ErrRefreshTokenReused):
+			// 已轮换的刷新令牌被再次使用，视为令牌泄露，吊销整个令牌族
+			if err := h.sessionSvc.RevokeFamily(ctx, rt.Family); err != nil {
+				zap.L().Error("吊销令牌族失败", zap.String("family", rt.Family), zap.Error(err))
+			}
+			zap.L().Warn("检测到刷新令牌重复使用", zap.String("userId", rt.UserId), zap.String("family", rt.Family))
+			h.recordLoginLog(ctx, login.ActionConstRefresh, rt.UserId, "", false, "刷新令牌重复使用")
+			response.NewResponse().ErrorResponse(ctx, http.StatusUnauthorized, "刷新令牌已失效，请重新登录", nil)
+		case errors.Is(err, jwt.ErrRefreshTokenInvalid):
+			h.recordLoginLog(ctx, login.ActionConstRefresh, rt.UserId, "", false, err.Error())
+			response.NewResponse().ErrorResponse(ctx, http.StatusUnauthorized, "刷新令牌无效或已过期，请重新登录", nil)
 		default:
-			response.NewResponse().ErrorResponse(ctx, http.StatusUnauthorized, "认证失败", nil)
+			ctx.Set("internal", err.Error())
+			zap.L().Error("使用刷新令牌异常", zap.Error(err))
+			response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
 		}
 		return
 	}
 
+	// 刷新令牌须在登录时的客户端上使用
+	if !h.checkRefreshBinding(ctx, rt) {
+		return
+	}
+
 	// 获取用户信息
-	user, err := h.userSvc.GetById(ctx, claims.UserId)
+	user, err := h.userSvc.GetById(ctx, rt.UserId)
 	if err != nil {
 		if errors.Is(err, system.ErrUserNotFound) {
+			h.recordLoginLog(ctx, login.ActionConstRefresh, rt.UserId, "", false, "用户不存在")
 			response.NewResponse().ErrorResponse(ctx, http.StatusUnauthorized, "用户不存在", nil)
 			return
 		}
@@ -231,21 +471,63 @@ func (h *authHandler) RefreshTokenHandler(ctx *gin.Context) {
 		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
 		return
 	}
+	if !user.Status {
+		if err := h.sessionSvc.RevokeFamily(ctx, rt.Family); err != nil {
+			zap.L().Error("吊销令牌族失败", zap.String("family", rt.Family), zap.Error(err))
+		}
+		h.recordLoginLog(ctx, login.ActionConstRefresh, user.Id, user.Username, false, "用户已停用")
+		response.NewResponse().ErrorResponse(ctx, http.StatusUnauthorized, "用户已停用，请联系管理员", nil)
+		return
+	}
 
-	// 生成新的JWT令牌
-	newToken, err := jwt.GenerateToken(ctx, user.Id, user.Username, int(user.UserType), user.DeptId, h.rely.Token.Secret, h.rely.Token.Expire)
+	// 在同一令牌族内签发新令牌，并以新会话替换旧会话
+	resp, err := h.rotateToken(ctx, user, rt)
 	if err != nil {
-		zap.L().Error("生成新令牌失败", zap.Error(err))
+		ctx.Set("internal", err.Error())
+		zap.L().Error("签发令牌失败", zap.String("userId", user.Id), zap.Error(err))
 		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
 		return
 	}
 
+	h.recordLoginLog(ctx, login.ActionConstRefresh, user.Id, user.Username, true, "")
+
 	// 返回新令牌和用户信息
-	response.NewResponse().SuccessResponse(ctx, "刷新令牌成功", LoginResponse{
-		Token:  newToken,
-		User:   user,
-		Expire: h.rely.Token.Expire * 3600,
-	})
+	response.NewResponse().SuccessResponse(ctx, "刷新令牌成功", resp)
+}
+
+// checkRefreshBinding 校验刷新令牌的使用环境，需拒绝时吊销令牌族并直接响应
+func (h *authHandler) checkRefreshBinding(ctx *gin.Context, rt jwt.RefreshToken) bool {
+	if !h.bindingSvc.Enabled() {
+		return true
+	}
+
+	session, err := h.sessionSvc.GetByJti(ctx, rt.Jti)
+	if err != nil {
+		// 会话已过期或被吊销时无法比对
+		if !errors.Is(err, serviceSystem.ErrSessionNotFound) {
+			zap.L().Error("获取会话异常", zap.String("jti", rt.Jti), zap.Error(err))
+		}
+		return true
+	}
+
+	err = h.bindingSvc.Check(ctx, serviceSystem.TokenBinding{
+		Jti:       session.Jti,
+		UserId:    session.UserId,
+		Username:  session.Username,
+		UserAgent: session.UserAgent,
+		Ip:        session.RequestIp,
+	}, ctx.Request.UserAgent(), requestUtils.NormalizeIP(ctx))
+	if !errors.Is(err, serviceSystem.ErrTokenBindingMismatch) {
+		return true
+	}
+
+	// 视为刷新令牌泄露，吊销整个令牌族
+	if err := h.sessionSvc.RevokeFamily(ctx, rt.Family); err != nil {
+		zap.L().Error("吊销令牌族失败", zap.String("family", rt.Family), zap.Error(err))
+	}
+	h.recordLoginLog(ctx, login.ActionConstRefresh, session.UserId, session.Username, false, "登录环境发生变化")
+	response.NewResponse().ErrorResponse(ctx, http.StatusUnauthorized, "登录环境发生变化，请重新登录", nil)
+	return false
 }
 
 // GetCurrentUserHandler
@@ -284,6 +566,64 @@ func (h *authHandler) GetCurrentUserHandler(ctx *gin.Context) {
 	response.NewResponse().SuccessResponse(ctx, "获取成功", user)
 }
 
+// GetPermissionsHandler
+// @Summary 获取当前用户权限信息
+// @Description 合并当前用户全部已启用角色的角色编码、按钮权限值及可查看的列
+// @Tags 认证管理/权限信息
+// @Accept application/json
+// @Produce application/json
+// @Success 200 {object} domainSystem.PermissionInfo
+// @Failure 401 {object} response.Response
+// @Router /v1/auth/permissions [get]
+// @Security LoginToken
+func (h *authHandler) GetPermissionsHandler(ctx *gin.Context) {
+	userId, exists := ctx.MustGet("userId").(string)
+	if !exists {
+		zap.S().Error("未找到用户认证信息", userId)
+		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+		return
+	}
+
+	info, err := h.permissionSvc.GetPermissionInfo(ctx, userId)
+	if err != nil {
+		ctx.Set("internal", err.Error())
+		zap.L().Error("获取用户权限信息异常", zap.String("userId", userId), zap.Error(err))
+		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+		return
+	}
+
+	response.NewResponse().SuccessResponse(ctx, "获取成功", info)
+}
+
+// GetRoutesHandler
+// @Summary 获取当前用户动态路由
+// @Description 获取当前用户已授权且已启用的菜单路由树
+// @Tags 认证管理/权限信息
+// @Accept application/json
+// @Produce application/json
+// @Success 200 {array} []domainSystem.Route
+// @Failure 401 {object} response.Response
+// @Router /v1/auth/routes [get]
+// @Security LoginToken
+func (h *authHandler) GetRoutesHandler(ctx *gin.Context) {
+	userId, exists := ctx.MustGet("userId").(string)
+	if !exists {
+		zap.S().Error("未找到用户认证信息", userId)
+		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+		return
+	}
+
+	routes, err := h.permissionSvc.GetRoutes(ctx, userId)
+	if err != nil {
+		ctx.Set("internal", err.Error())
+		zap.L().Error("获取用户动态路由异常", zap.String("userId", userId), zap.Error(err))
+		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+		return
+	}
+
+	response.NewResponse().SuccessResponse(ctx, "获取成功", routes)
+}
+
 // LogoutHandler
 // @Summary 退出登录
 // @Description 用户退出登录
@@ -320,7 +660,7 @@ func (h *authHandler) LogoutHandler(ctx *gin.Context) {
 	tokenStr := parts[1]
 
 	// 解析token以获取过期时间
-	claims, err := jwt.ParseToken(tokenStr, h.rely.Token.Secret)
+	claims, err := jwt.ParseToken(tokenStr, h.rely.Keys)
 	if err != nil {
 		zap.S().Errorf("解析token失败: %v", err)
 		response.NewResponse().ErrorResponse(ctx, http.StatusUnauthorized, "退出登录失败：无效的令牌", nil)
@@ -344,7 +684,10 @@ func (h *authHandler) LogoutHandler(ctx *gin.Context) {
 		return
 	}
 
+	h.revokeSession(ctx, claims.ID)
+
 	zap.S().Infof("用户登出成功, userId: %s", userId)
+	h.recordLoginLog(ctx, login.ActionConstLogout, userId, claims.Username, true, "")
 
 	// 返回成功信息
 	response.NewResponse().SuccessResponse(ctx, "退出登录成功", nil)
@@ -386,6 +729,8 @@ func (h *authHandler) ChangePasswordHandler(ctx *gin.Context) {
 			response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "旧密码错误", nil)
 		case errors.Is(err, system.ErrUserNotFound):
 			response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "用户不存在", nil)
+		case errors.Is(err, system.ErrPasswordPolicy):
+			response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
 		default:
 			zap.L().Error("修改密码失败", zap.Error(err))
 			response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "修改密码失败", nil)
@@ -395,3 +740,205 @@ func (h *authHandler) ChangePasswordHandler(ctx *gin.Context) {
 
 	response.NewResponse().SuccessResponse(ctx, "密码修改成功", nil)
 }
+
+// verifyCaptcha 校验验证码，失败时直接响应
+func (h *authHandler) verifyCaptcha(ctx *gin.Context, biz, id, code string) bool {
+	if id == "" || code == "" {
+		response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "请输入验证码", CaptchaRequiredResponse{
+			CaptchaRequired: true,
+		})
+		return false
+	}
+
+	ok, err := h.captchaSvc.Verify(ctx, id, biz, code)
+	if err == nil && ok {
+		return true
+	}
+	h.captchaErrorResponse(ctx, err)
+	return false
+}
+
+// captchaErrorResponse 验证码校验失败响应
+func (h *authHandler) captchaErrorResponse(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, third.ErrCaptchaIncorrect):
+		response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "验证码错误", nil)
+	case errors.Is(err, third.ErrCaptchaNotFound):
+		response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "验证码已过期，请重新获取", nil)
+	case errors.Is(err, third.ErrCaptchaVerifyTooMany):
+		response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "验证码错误次数过多，请重新获取", nil)
+	case errors.Is(err, third.ErrUserBlocked):
+		response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "验证过于频繁，请稍后再试", nil)
+	default:
+		if err != nil {
+			ctx.Set("internal", err.Error())
+		}
+		zap.L().Error("验证码校验异常", zap.Error(err))
+		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+	}
+}
+
+// formatRemaining 剩余锁定时长
+func formatRemaining(d time.Duration) string {
+	if d < time.Minute {
+		return fmt.Sprintf("%d秒", int(d.Seconds()))
+	}
+	return fmt.Sprintf("%d分钟", int((d+time.Minute-1)/time.Minute))
+}
+
+// recordLoginLog 记录登录日志
+func (h *authHandler) recordLoginLog(ctx *gin.Context, action login.ActionConst, userId, username string, status bool, message string) {
+	requestOs, requestBrowser, userAgent := parseUserAgent(ctx)
+	h.loginLogSvc.Record(ctx, domainLogger.LoginLog{
+		LoginLogger: modelLogger.LoginLogger{
+			UserId:         userId,
+			Username:       username,
+			Action:         action,
+			Status:         status,
+			Message:        message,
+			RequestIp:      requestUtils.NormalizeIP(ctx),
+			RequestOs:      requestOs,
+			RequestBrowser: requestBrowser,
+			UserAgent:      userAgent,
+		},
+	})
+}
+
+// issueToken 签发访问令牌及刷新令牌并记录在线会话，开启新的令牌族
+// passwordChange 为 true 时签发受限令牌，仅可访问修改密码等接口
+// twoFactorSetup 为 true 时签发受限令牌，仅可访问绑定双因素认证等接口
+func (h *authHandler) issueToken(ctx *gin.Context, user domainSystem.User, passwordChange, twoFactorSetup bool) (LoginResponse, error) {
+	pair, err := jwt.GenerateToken(ctx, user.Id, user.Username, int(user.UserType), user.DeptId, passwordChange,
+		twoFactorSetup, h.rely.Keys, h.accessExpire())
+	if err != nil {
+		return LoginResponse{}, err
+	}
+
+	family := uuid.NewV4().String()
+	if err := h.sessionSvc.Create(ctx, h.newSession(ctx, pair.Claims, family), h.sessionExpire()); err != nil {
+		return LoginResponse{}, err
+	}
+	return h.saveRefreshToken(ctx, user, pair, family)
+}
+
+// rotateToken 在原令牌族内签发新令牌，旧访问令牌随之失效
+// 受限令牌族在密码修改完成后才签发不受限的令牌，要求双因素认证的账号在绑定完成后才签发不受限的令牌
+func (h *authHandler) rotateToken(ctx *gin.Context, user domainSystem.User, rt jwt.RefreshToken) (LoginResponse, error) {
+	passwordChange := rt.PasswordChange && (user.PasswordChangeRequired || h.userSvc.PasswordExpired(user))
+	twoFactorSetup := false
+	if !passwordChange {
+		var err error
+		if twoFactorSetup, err = h.twoFactorSetupRequired(ctx, user); err != nil {
+			return LoginResponse{}, err
+		}
+	}
+	pair, err := jwt.GenerateToken(ctx, user.Id, user.Username, int(user.UserType), user.DeptId, passwordChange,
+		twoFactorSetup, h.rely.Keys, h.accessExpire())
+	if err != nil {
+		return LoginResponse{}, err
+	}
+
+	if err := h.sessionSvc.Rotate(ctx, rt.Jti, h.newSession(ctx, pair.Claims, rt.Family), h.sessionExpire()); err != nil {
+		return LoginResponse{}, err
+	}
+	resp, err := h.saveRefreshToken(ctx, user, pair, rt.Family)
+	resp.PasswordChangeRequired = passwordChange
+	resp.TwoFactorSetupRequired = twoFactorSetup
+	return resp, err
+}
+
+// twoFactorSetupRequired 当前账号要求双因素认证但尚未绑定
+func (h *authHandler) twoFactorSetupRequired(ctx *gin.Context, user domainSystem.User) (bool, error) {
+	required, err := h.twoFactorSvc.Required(ctx, user)
+	if err != nil || !required {
+		return false, err
+	}
+	enabled, err := h.twoFactorSvc.Enabled(ctx, user.Id)
+	if err != nil {
+		return false, err
+	}
+	return !enabled, nil
+}
+
+// saveRefreshToken 保存刷新令牌
+func (h *authHandler) saveRefreshToken(ctx *gin.Context, user domainSystem.User, pair *jwt.TokenPair, family string) (LoginResponse, error) {
+	refreshExpire := h.refreshExpire()
+	err := jwt.NewRefreshTokenStore(h.rely.Redis).Save(ctx, pair.RefreshToken, jwt.RefreshToken{
+		UserId:         user.Id,
+		Family:         family,
+		Jti:            pair.Claims.ID,
+		PasswordChange: pair.Claims.PasswordChange,
+	}, refreshExpire)
+	if err != nil {
+		return LoginResponse{}, err
+	}
+
+	return LoginResponse{
+		Token:         pair.AccessToken,
+		RefreshToken:  pair.RefreshToken,
+		User:          user,
+		Expire:        int(h.accessExpire().Seconds()),
+		RefreshExpire: int(refreshExpire.Seconds()),
+	}, nil
+}
+
+// newSession 构建令牌对应的在线会话
+func (h *authHandler) newSession(ctx *gin.Context, claims *jwt.Claims, family string) domainSystem.Session {
+	requestOs, requestBrowser, userAgent := parseUserAgent(ctx)
+	return domainSystem.Session{
+		Jti:            claims.ID,
+		UserId:         claims.UserId,
+		Username:       claims.Username,
+		DeptId:         claims.DeptId,
+		RequestIp:      requestUtils.NormalizeIP(ctx),
+		RequestOs:      requestOs,
+		RequestBrowser: requestBrowser,
+		UserAgent:      userAgent,
+		Family:         family,
+		LoginTime:      claims.IssuedAt.Format("2006-01-02 15:04:05"),
+		ExpireTime:     claims.ExpiresAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+// accessExpire 访问令牌有效期
+func (h *authHandler) accessExpire() time.Duration {
+	if h.rely.Token.AccessExpire <= 0 {
+		return 15 * time.Minute
+	}
+	return time.Duration(h.rely.Token.AccessExpire) * time.Minute
+}
+
+// refreshExpire 刷新令牌有效期
+func (h *authHandler) refreshExpire() time.Duration {
+	if h.rely.Token.RefreshExpire <= 0 {
+		return 7 * 24 * time.Hour
+	}
+	return time.Duration(h.rely.Token.RefreshExpire) * time.Hour
+}
+
+// sessionExpire 会话有效期，可刷新期间会话保持在线
+func (h *authHandler) sessionExpire() time.Duration {
+	return max(h.refreshExpire(), h.accessExpire())
+}
+
+// revokeSession 吊销会话，失败时仅记录日志
+func (h *authHandler) revokeSession(ctx *gin.Context, jti string) {
+	if jti == "" {
+		return
+	}
+	if err := h.sessionSvc.Revoke(ctx, jti); err != nil && !errors.Is(err, serviceSystem.ErrSessionNotFound) {
+		zap.L().Error("吊销会话失败", zap.String("jti", jti), zap.Error(err))
+	}
+}
+
+// parseUserAgent 解析操作系统、浏览器及截断后的用户代理
+func parseUserAgent(ctx *gin.Context) (string, string, string) {
+	ua := user_agent.New(ctx.Request.UserAgent())
+	browserName, _ := ua.Browser()
+
+	userAgent := ctx.Request.UserAgent()
+	if len(userAgent) > 255 {
+		userAgent = userAgent[:255]
+	}
+	return ua.OS(), browserName, userAgent
+}
@@ -0,0 +1,59 @@
+/**
+ * Description：
+ * FileName：impersonation.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 05:02:36
+ * Remark：
+ */
+
+package auth
+
+import (
+	"errors"
+	serviceSystem "github.com/carefuly/carefuly-admin-go-gin/internal/service/careful/system"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/response"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/requestUtils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+// StopImpersonationHandler
+// @Summary 结束模拟登录
+// @Description 吊销当前使用的模拟登录令牌，仅模拟登录令牌可调用
+// @Tags 认证管理/模拟登录
+// @Accept application/json
+// @Produce application/json
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Router /v1/auth/impersonate/stop [post]
+// @Security LoginToken
+func (h *authHandler) StopImpersonationHandler(ctx *gin.Context) {
+	if !requestUtils.IsImpersonating(ctx) {
+		response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "当前不是模拟登录", nil)
+		return
+	}
+
+	jti := ctx.GetString("jti")
+	if err := h.impersonationSvc.Stop(ctx, jti, requestUtils.NormalizeIP(ctx)); err != nil {
+		switch {
+		case errors.Is(err, serviceSystem.ErrSessionNotFound), errors.Is(err, serviceSystem.ErrNotImpersonating):
+			response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "模拟登录会话不存在或已结束", nil)
+		default:
+			ctx.Set("internal", err.Error())
+			zap.L().Error("结束模拟登录失败", zap.String("jti", jti), zap.Error(err))
+			response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+		}
+		return
+	}
+
+	response.NewResponse().SuccessResponse(ctx, "已结束模拟登录", nil)
+}
+
+// denyImpersonation 模拟登录令牌不能修改被模拟用户的凭据(密码、双因素认证、API Key)
+func denyImpersonation(ctx *gin.Context) {
+	if requestUtils.IsImpersonating(ctx) {
+		response.NewResponse().ErrorResponse(ctx, http.StatusForbidden, "模拟登录期间不能修改该用户的登录凭据", nil)
+		ctx.Abort()
+	}
+}
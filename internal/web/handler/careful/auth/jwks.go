@@ -0,0 +1,47 @@
+/**
+ * Description：
+ * FileName：jwks.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 20:55:09
+ * Remark：
+ */
+
+package auth
+
+import (
+	config "github.com/carefuly/carefuly-admin-go-gin/config/file"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type JWKSHandler interface {
+	RegisterRoutes(router gin.IRoutes)
+	GetJWKS(ctx *gin.Context)
+}
+
+type jwksHandler struct {
+	rely config.RelyConfig
+}
+
+func NewJWKSHandler(rely config.RelyConfig) JWKSHandler {
+	return &jwksHandler{
+		rely: rely,
+	}
+}
+
+// RegisterRoutes 注册路由
+func (h *jwksHandler) RegisterRoutes(router gin.IRoutes) {
+	router.GET("/.well-known/jwks.json", h.GetJWKS)
+}
+
+// GetJWKS
+// @Summary 获取令牌校验公钥
+// @Description 以 JWKS(RFC 7517) 格式发布全部非对称签名公钥，供其他服务按令牌头部 kid 校验令牌
+// @Tags 认证管理/令牌公钥
+// @Produce application/json
+// @Success 200 {object} jwt.JWKSet
+// @Router /.well-known/jwks.json [get]
+func (h *jwksHandler) GetJWKS(ctx *gin.Context) {
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, h.rely.Keys.JWKS())
+}
@@ -0,0 +1,108 @@
+/**
+ * Description：
+ * FileName：password_reset.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 03:02:18
+ * Remark：
+ */
+
+package auth
+
+import (
+	"errors"
+	serviceSystem "github.com/carefuly/carefuly-admin-go-gin/internal/service/careful/system"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/response"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/requestUtils"
+	validate "github.com/carefuly/carefuly-admin-go-gin/pkg/validator"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+// ForgotPasswordCodeRequest 获取找回密码验证码请求
+type ForgotPasswordCodeRequest struct {
+	Email    string `json:"email" binding:"required,email,max=50" example:"admin@example.com"` // 邮箱
+	Username string `json:"username" binding:"omitempty,max=50" example:"admin"`               // 用户名，邮箱被多个账号使用时必填
+}
+
+// ForgotPasswordResetRequest 通过验证码重置密码请求
+type ForgotPasswordResetRequest struct {
+	Email       string `json:"email" binding:"required,email,max=50" example:"admin@example.com"` // 邮箱
+	Username    string `json:"username" binding:"omitempty,max=50" example:"admin"`               // 用户名，邮箱被多个账号使用时必填
+	Code        string `json:"code" binding:"required,len=6" example:"123456"`                    // 邮箱验证码
+	NewPassword string `json:"newPassword" binding:"required,max=72" example:"654321"`            // 新密码，复杂度由密码策略校验
+}
+
+// ForgotPasswordCodeHandler
+// @Summary 获取找回密码验证码
+// @Description 向邮箱发送找回密码验证码，为避免暴露账号是否存在，邮箱未匹配到用户时同样返回成功
+// @Tags 认证管理/找回密码
+// @Accept application/json
+// @Produce application/json
+// @Param ForgotPasswordCodeRequest body ForgotPasswordCodeRequest true "请求"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Router /v1/auth/forgot-password/code [post]
+func (h *authHandler) ForgotPasswordCodeHandler(ctx *gin.Context) {
+	var req ForgotPasswordCodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validate.NewValidatorError(h.rely.Trans).HandleValidatorError(ctx, err)
+		return
+	}
+
+	// 未配置邮件服务时统一拒绝，不因账号是否存在而返回不同结果
+	if h.rely.Mail.Host == "" {
+		response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "未配置邮件服务，请联系管理员重置密码", nil)
+		return
+	}
+
+	err := h.passwordResetSvc.SendCode(ctx, req.Email, req.Username, requestUtils.NormalizeIP(ctx))
+	if err != nil {
+		switch {
+		case errors.Is(err, serviceSystem.ErrPasswordResetTooMany):
+			response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "验证码发送太频繁，请稍后再试", nil)
+		default:
+			ctx.Set("internal", err.Error())
+			zap.L().Error("发送找回密码验证码异常", zap.Error(err))
+			response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "验证码发送失败，请稍后再试", nil)
+		}
+		return
+	}
+
+	response.NewResponse().SuccessResponse(ctx, "如邮箱已绑定账号，验证码将发送至该邮箱", nil)
+}
+
+// ForgotPasswordResetHandler
+// @Summary 通过验证码重置密码
+// @Description 校验邮箱验证码后设置新密码，成功后吊销该用户的全部会话
+// @Tags 认证管理/找回密码
+// @Accept application/json
+// @Produce application/json
+// @Param ForgotPasswordResetRequest body ForgotPasswordResetRequest true "请求"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Router /v1/auth/forgot-password/reset [post]
+func (h *authHandler) ForgotPasswordResetHandler(ctx *gin.Context) {
+	var req ForgotPasswordResetRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validate.NewValidatorError(h.rely.Trans).HandleValidatorError(ctx, err)
+		return
+	}
+
+	user, err := h.passwordResetSvc.Reset(ctx, req.Email, req.Username, req.Code, req.NewPassword, requestUtils.NormalizeIP(ctx))
+	if err != nil {
+		if errors.Is(err, serviceSystem.ErrPasswordPolicy) {
+			response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+			return
+		}
+		h.captchaErrorResponse(ctx, err)
+		return
+	}
+
+	// 旧密码可能已泄露，吊销已登录的会话
+	if err := h.sessionSvc.RevokeByUserId(ctx, user.Id); err != nil {
+		zap.L().Error("找回密码后吊销会话失败", zap.String("userId", user.Id), zap.Error(err))
+	}
+
+	response.NewResponse().SuccessResponse(ctx, "密码重置成功，请使用新密码登录", nil)
+}
@@ -0,0 +1,138 @@
+/**
+ * Description：
+ * FileName：sso.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 07:41:19
+ * Remark：
+ */
+
+package auth
+
+import (
+	"errors"
+	domainSystem "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/system"
+	serviceSystem "github.com/carefuly/carefuly-admin-go-gin/internal/service/careful/system"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/constants/careful/logger/login"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/response"
+	validate "github.com/carefuly/carefuly-admin-go-gin/pkg/validator"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+// SSOAuthorizeRequest 单点登录授权请求
+type SSOAuthorizeRequest struct {
+	Provider string `form:"provider" binding:"required,max=50" example:"corp"` // 身份提供方标识
+}
+
+// SSOLoginRequest 单点登录请求(身份提供方回调后提交)
+type SSOLoginRequest struct {
+	Provider string `json:"provider" binding:"required,max=50" example:"corp"`      // 身份提供方标识
+	State    string `json:"state" binding:"required,max=128" example:"Jq3v0k..."`   // 回调携带的 state
+	Code     string `json:"code" binding:"required,max=2048" example:"SplxlOBe..."` // 回调携带的授权码
+}
+
+// GetSSOProvidersHandler
+// @Summary 单点登录身份提供方
+// @Description 登录页展示的身份提供方列表，未配置时为空
+// @Tags 认证管理/单点登录
+// @Accept application/json
+// @Produce application/json
+// @Success 200 {array} domainSystem.SSOProvider
+// @Router /v1/auth/sso/providers [get]
+func (h *authHandler) GetSSOProvidersHandler(ctx *gin.Context) {
+	response.NewResponse().SuccessResponse(ctx, "查询成功", h.ssoSvc.Providers())
+}
+
+// SSOAuthorizeHandler
+// @Summary 单点登录授权地址
+// @Description 生成跳转至身份提供方的授权地址(授权码 + PKCE)，state 10 分钟内有效
+// @Tags 认证管理/单点登录
+// @Accept application/json
+// @Produce application/json
+// @Param provider query string true "身份提供方标识"
+// @Success 200 {object} domainSystem.SSOAuthorization
+// @Failure 400 {object} response.Response
+// @Router /v1/auth/sso/authorize [get]
+func (h *authHandler) SSOAuthorizeHandler(ctx *gin.Context) {
+	var req SSOAuthorizeRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		validate.NewValidatorError(h.rely.Trans).HandleValidatorError(ctx, err)
+		return
+	}
+
+	var (
+		authorization domainSystem.SSOAuthorization
+		err           error
+	)
+	authorization, err = h.ssoSvc.Authorize(ctx, req.Provider)
+	if err != nil {
+		switch {
+		case errors.Is(err, serviceSystem.ErrSSOProviderNotFound):
+			response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "身份提供方不存在", nil)
+		default:
+			ctx.Set("internal", err.Error())
+			zap.L().Error("生成单点登录授权地址失败", zap.String("provider", req.Provider), zap.Error(err))
+			response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+		}
+		return
+	}
+
+	response.NewResponse().SuccessResponse(ctx, "获取成功", authorization)
+}
+
+// SSOLoginHandler
+// @Summary 单点登录
+// @Description 使用身份提供方回调的 state 及授权码登录，已启用双因素认证时返回登录二次验证
+// @Tags 认证管理/单点登录
+// @Accept application/json
+// @Produce application/json
+// @Param SSOLoginRequest body SSOLoginRequest true "请求"
+// @Success 200 {object} LoginResponse
+// @Failure 400 {object} response.Response
+// @Router /v1/auth/sso/login [post]
+func (h *authHandler) SSOLoginHandler(ctx *gin.Context) {
+	var req SSOLoginRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validate.NewValidatorError(h.rely.Trans).HandleValidatorError(ctx, err)
+		return
+	}
+
+	detail, err := h.ssoSvc.Login(ctx, req.Provider, req.State, req.Code)
+	if err != nil {
+		var message string
+		switch {
+		case errors.Is(err, serviceSystem.ErrSSOProviderNotFound):
+			message = "身份提供方不存在"
+		case errors.Is(err, serviceSystem.ErrSSOStateInvalid):
+			message = "登录请求已失效，请重新登录"
+		case errors.Is(err, serviceSystem.ErrSSOAuthenticate):
+			message = "身份提供方认证失败"
+		case errors.Is(err, serviceSystem.ErrSSOUserNotLinked):
+			message = "该账号未关联系统用户，请联系管理员"
+		case errors.Is(err, serviceSystem.ErrSSOUsernameConflict):
+			message = "用户名已被占用，请联系管理员关联账号"
+		case errors.Is(err, serviceSystem.ErrUserDisabled):
+			message = "用户已停用，请联系管理员"
+		case errors.Is(err, serviceSystem.ErrUserNotFound):
+			message = "关联的用户不存在，请联系管理员"
+		default:
+			ctx.Set("internal", err.Error())
+			h.recordLoginLog(ctx, login.ActionConstLogin, "", "", false, "单点登录失败: 服务器异常")
+			zap.L().Error("单点登录失败", zap.String("provider", req.Provider), zap.Error(err))
+			response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+			return
+		}
+		ctx.Set("internal", err.Error())
+		h.recordLoginLog(ctx, login.ActionConstLogin, "", "", false, "单点登录失败: "+message)
+		response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, message, nil)
+		return
+	}
+
+	// 单点登录同样要求本地启用的双因素认证
+	if h.challengeTwoFactor(ctx, detail) {
+		return
+	}
+
+	h.completeLogin(ctx, detail, false)
+}
@@ -0,0 +1,304 @@
+/**
+ * Description：
+ * FileName：two_factor.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 22:48:06
+ * Remark：
+ */
+
+package auth
+
+import (
+	"errors"
+	domainSystem "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/system"
+	serviceSystem "github.com/carefuly/carefuly-admin-go-gin/internal/service/careful/system"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/constants/careful/logger/login"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/response"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/requestUtils"
+	validate "github.com/carefuly/carefuly-admin-go-gin/pkg/validator"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+// TwoFactorChallengeResponse 登录二次验证响应
+type TwoFactorChallengeResponse struct {
+	TwoFactorRequired bool   `json:"twoFactorRequired"` // 是否需要二次验证
+	ChallengeToken    string `json:"challengeToken"`    // 二次验证令牌
+	Expire            int    `json:"expire"`            // 过期时间(秒)
+}
+
+// TwoFactorLoginRequest 登录二次验证请求
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challengeToken" binding:"required" example:"Vq3n9..."`  // 二次验证令牌
+	Code           string `json:"code" binding:"required,min=6,max=20" example:"123456"` // 动态验证码或恢复码
+}
+
+// TwoFactorCodeRequest 双因素认证验证码请求
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required,min=6,max=20" example:"123456"` // 动态验证码或恢复码
+}
+
+// RecoveryCodesResponse 恢复码响应
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recoveryCodes"` // 恢复码，仅展示一次
+}
+
+// TwoFactorLoginHandler
+// @Summary 登录二次验证
+// @Description 使用登录返回的二次验证令牌及动态验证码(或恢复码)完成登录
+// @Tags 认证管理/双因素认证
+// @Accept application/json
+// @Produce application/json
+// @Param TwoFactorLoginRequest body TwoFactorLoginRequest true "请求"
+// @Success 200 {object} LoginResponse
+// @Failure 400 {object} response.Response
+// @Router /v1/auth/login/2fa [post]
+func (h *authHandler) TwoFactorLoginHandler(ctx *gin.Context) {
+	var req TwoFactorLoginRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validate.NewValidatorError(h.rely.Trans).HandleValidatorError(ctx, err)
+		return
+	}
+
+	userId, err := h.twoFactorSvc.VerifyChallenge(ctx, req.ChallengeToken, req.Code)
+	if err != nil {
+		switch {
+		case errors.Is(err, serviceSystem.ErrTwoFactorChallengeNotFound):
+			response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "验证已过期，请重新登录", nil)
+		case errors.Is(err, serviceSystem.ErrTwoFactorChallengeTooMany):
+			h.recordLoginLog(ctx, login.ActionConstLogin, userId, "", false, "二次验证次数过多")
+			response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "验证次数过多，请重新登录", nil)
+		case errors.Is(err, serviceSystem.ErrTwoFactorCodeInvalid):
+			// 计入登录失败次数，防止暴力尝试
+			username := ""
+			if user, err := h.userSvc.GetById(ctx, userId); err == nil {
+				username = user.Username
+				h.loginGuardSvc.RecordFailure(ctx, username, requestUtils.NormalizeIP(ctx))
+			}
+			h.recordLoginLog(ctx, login.ActionConstLogin, userId, username, false, "二次验证码错误")
+			response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "验证码错误", nil)
+		case errors.Is(err, serviceSystem.ErrTwoFactorNotEnabled):
+			response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "未启用双因素认证，请重新登录", nil)
+		default:
+			ctx.Set("internal", err.Error())
+			zap.L().Error("登录二次验证异常", zap.Error(err))
+			response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+		}
+		return
+	}
+
+	user, err := h.userSvc.GetById(ctx, userId)
+	if err != nil {
+		ctx.Set("internal", err.Error())
+		zap.L().Error("登录二次验证获取用户信息异常", zap.String("userId", userId), zap.Error(err))
+		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+		return
+	}
+	if !user.Status {
+		h.recordLoginLog(ctx, login.ActionConstLogin, user.Id, user.Username, false, "用户已停用")
+		response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "用户已停用，请联系管理员", nil)
+		return
+	}
+
+	h.loginGuardSvc.RecordSuccess(ctx, user.Username)
+	h.completeLogin(ctx, user, true)
+}
+
+// GetTwoFactorStatusHandler
+// @Summary 获取双因素认证状态
+// @Description 获取当前用户双因素认证的启用状态、是否强制要求及剩余恢复码数量
+// @Tags 认证管理/双因素认证
+// @Accept application/json
+// @Produce application/json
+// @Success 200 {object} domainSystem.TwoFactorStatus
+// @Failure 400 {object} response.Response
+// @Router /v1/auth/2fa/status [get]
+// @Security LoginToken
+func (h *authHandler) GetTwoFactorStatusHandler(ctx *gin.Context) {
+	user, ok := h.currentUser(ctx)
+	if !ok {
+		return
+	}
+
+	status, err := h.twoFactorSvc.Status(ctx, user)
+	if err != nil {
+		ctx.Set("internal", err.Error())
+		zap.L().Error("获取双因素认证状态异常", zap.String("userId", user.Id), zap.Error(err))
+		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+		return
+	}
+
+	response.NewResponse().SuccessResponse(ctx, "获取成功", status)
+}
+
+// SetupTwoFactorHandler
+// @Summary 绑定认证器
+// @Description 生成 TOTP 密钥，返回 otpauth 地址及二维码，首次验证通过后生效
+// @Tags 认证管理/双因素认证
+// @Accept application/json
+// @Produce application/json
+// @Success 200 {object} domainSystem.TwoFactorSetup
+// @Failure 400 {object} response.Response
+// @Router /v1/auth/2fa/setup [post]
+// @Security LoginToken
+func (h *authHandler) SetupTwoFactorHandler(ctx *gin.Context) {
+	user, ok := h.currentUser(ctx)
+	if !ok {
+		return
+	}
+
+	setup, err := h.twoFactorSvc.Setup(ctx, user)
+	if err != nil {
+		switch {
+		case errors.Is(err, serviceSystem.ErrTwoFactorAlreadyEnabled):
+			response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "已启用双因素认证，请先关闭后再重新绑定", nil)
+		default:
+			ctx.Set("internal", err.Error())
+			zap.L().Error("绑定认证器异常", zap.String("userId", user.Id), zap.Error(err))
+			response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+		}
+		return
+	}
+
+	response.NewResponse().SuccessResponse(ctx, "获取成功", setup)
+}
+
+// EnableTwoFactorHandler
+// @Summary 启用双因素认证
+// @Description 校验认证器生成的首个验证码后启用，返回的恢复码仅展示一次
+// @Tags 认证管理/双因素认证
+// @Accept application/json
+// @Produce application/json
+// @Param TwoFactorCodeRequest body TwoFactorCodeRequest true "请求"
+// @Success 200 {object} RecoveryCodesResponse
+// @Failure 400 {object} response.Response
+// @Router /v1/auth/2fa/enable [post]
+// @Security LoginToken
+func (h *authHandler) EnableTwoFactorHandler(ctx *gin.Context) {
+	var req TwoFactorCodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validate.NewValidatorError(h.rely.Trans).HandleValidatorError(ctx, err)
+		return
+	}
+	user, ok := h.currentUser(ctx)
+	if !ok {
+		return
+	}
+
+	codes, err := h.twoFactorSvc.Enable(ctx, user, req.Code)
+	if err != nil {
+		h.twoFactorError(ctx, "启用双因素认证异常", err)
+		return
+	}
+
+	response.NewResponse().SuccessResponse(ctx, "启用成功", RecoveryCodesResponse{
+		RecoveryCodes: codes,
+	})
+}
+
+// DisableTwoFactorHandler
+// @Summary 关闭双因素认证
+// @Description 校验动态验证码或恢复码后关闭，角色或用户类型要求启用时不可关闭
+// @Tags 认证管理/双因素认证
+// @Accept application/json
+// @Produce application/json
+// @Param TwoFactorCodeRequest body TwoFactorCodeRequest true "请求"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Router /v1/auth/2fa/disable [post]
+// @Security LoginToken
+func (h *authHandler) DisableTwoFactorHandler(ctx *gin.Context) {
+	var req TwoFactorCodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validate.NewValidatorError(h.rely.Trans).HandleValidatorError(ctx, err)
+		return
+	}
+	user, ok := h.currentUser(ctx)
+	if !ok {
+		return
+	}
+
+	if err := h.twoFactorSvc.Disable(ctx, user, req.Code); err != nil {
+		h.twoFactorError(ctx, "关闭双因素认证异常", err)
+		return
+	}
+
+	response.NewResponse().SuccessResponse(ctx, "关闭成功", nil)
+}
+
+// RegenerateRecoveryCodesHandler
+// @Summary 重新生成恢复码
+// @Description 校验动态验证码后重新生成恢复码，原恢复码全部作废
+// @Tags 认证管理/双因素认证
+// @Accept application/json
+// @Produce application/json
+// @Param TwoFactorCodeRequest body TwoFactorCodeRequest true "请求"
+// @Success 200 {object} RecoveryCodesResponse
+// @Failure 400 {object} response.Response
+// @Router /v1/auth/2fa/recoveryCodes [post]
+// @Security LoginToken
+func (h *authHandler) RegenerateRecoveryCodesHandler(ctx *gin.Context) {
+	var req TwoFactorCodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validate.NewValidatorError(h.rely.Trans).HandleValidatorError(ctx, err)
+		return
+	}
+	user, ok := h.currentUser(ctx)
+	if !ok {
+		return
+	}
+
+	codes, err := h.twoFactorSvc.RegenerateRecoveryCodes(ctx, user.Id, req.Code)
+	if err != nil {
+		h.twoFactorError(ctx, "重新生成恢复码异常", err)
+		return
+	}
+
+	response.NewResponse().SuccessResponse(ctx, "生成成功", RecoveryCodesResponse{
+		RecoveryCodes: codes,
+	})
+}
+
+// currentUser 获取当前登录用户，失败时直接响应
+func (h *authHandler) currentUser(ctx *gin.Context) (domainSystem.User, bool) {
+	userId, exists := ctx.MustGet("userId").(string)
+	if !exists {
+		zap.S().Error("未找到用户认证信息", userId)
+		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+		return domainSystem.User{}, false
+	}
+
+	user, err := h.userSvc.GetById(ctx, userId)
+	if err != nil {
+		if errors.Is(err, serviceSystem.ErrUserNotFound) {
+			response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "用户不存在", nil)
+			return domainSystem.User{}, false
+		}
+		ctx.Set("internal", err.Error())
+		zap.L().Error("获取用户信息异常", zap.String("userId", userId), zap.Error(err))
+		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+		return domainSystem.User{}, false
+	}
+	return user, true
+}
+
+// twoFactorError 双因素认证错误响应
+func (h *authHandler) twoFactorError(ctx *gin.Context, msg string, err error) {
+	switch {
+	case errors.Is(err, serviceSystem.ErrTwoFactorCodeInvalid):
+		response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "验证码错误", nil)
+	case errors.Is(err, serviceSystem.ErrTwoFactorNotSetup):
+		response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "请先绑定认证器", nil)
+	case errors.Is(err, serviceSystem.ErrTwoFactorNotEnabled):
+		response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "未启用双因素认证", nil)
+	case errors.Is(err, serviceSystem.ErrTwoFactorAlreadyEnabled):
+		response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "已启用双因素认证", nil)
+	case errors.Is(err, serviceSystem.ErrTwoFactorRequired):
+		response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "当前账号要求启用双因素认证，不可关闭", nil)
+	default:
+		ctx.Set("internal", err.Error())
+		zap.L().Error(msg, zap.Error(err))
+		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+	}
+}
@@ -0,0 +1,158 @@
+/**
+ * Description：
+ * FileName：cache_log.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 09:41:50
+ * Remark：
+ */
+
+package monitor
+
+import (
+	config "github.com/carefuly/carefuly-admin-go-gin/config/file"
+	domainLogger "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/logger"
+	serviceLogger "github.com/carefuly/carefuly-admin-go-gin/internal/service/careful/logger"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/columns"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/filters"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/response"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// 未指定开始时间时默认统计最近 24 小时
+const cacheStatsDefaultRange = 24 * time.Hour
+
+// CacheLogListPageResponse 列表分页响应
+type CacheLogListPageResponse struct {
+	List     []domainLogger.CacheLog `json:"list"`     // 列表
+	Total    int64                   `json:"total"`    // 总数
+	Page     int                     `json:"page"`     // 页码
+	PageSize int                     `json:"pageSize"` // 每页数量
+}
+
+type CacheLogHandler interface {
+	RegisterRoutes(router *gin.RouterGroup)
+	GetListPage(ctx *gin.Context)
+	Stats(ctx *gin.Context)
+}
+
+type cacheLogHandler struct {
+	rely config.RelyConfig
+	svc  serviceLogger.CacheLogService
+}
+
+func NewCacheLogHandler(rely config.RelyConfig, svc serviceLogger.CacheLogService) CacheLogHandler {
+	return &cacheLogHandler{
+		rely: rely,
+		svc:  svc,
+	}
+}
+
+// RegisterRoutes 注册路由
+func (h *cacheLogHandler) RegisterRoutes(router *gin.RouterGroup) {
+	base := router.Group("/cacheLog")
+	base.GET("/listPage", h.GetListPage)
+	base.GET("/stats", h.Stats)
+}
+
+// GetListPage
+// @Summary 获取缓存日志分页列表
+// @Description 获取缓存日志分页列表
+// @Tags 系统监控/缓存日志
+// @Accept application/json
+// @Produce application/json
+// @Param page query int true "页码" default(1)
+// @Param pageSize query int true "每页数量" default(10)
+// @Param key query string false "缓存key键前缀"
+// @Param operation query string false "缓存操作" Enums(get, set, del, setNotFound)
+// @Param result query string false "缓存结果" Enums(hit, miss, not_found, ok, error)
+// @Param username query string false "缓存用户名"
+// @Param path query string false "请求地址前缀"
+// @Param hasError query bool false "是否异常"
+// @Param startTime query string false "开始时间"
+// @Param endTime query string false "结束时间"
+// @Success 200 {object} CacheLogListPageResponse
+// @Failure 400 {object} response.Response
+// @Router /v1/monitor/cacheLog/listPage [get]
+// @Security LoginToken
+func (h *cacheLogHandler) GetListPage(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("pageSize", "10"))
+
+	filter := h.filter(ctx)
+	filter.Pagination = filters.Pagination{
+		Page:     page,
+		PageSize: pageSize,
+	}
+
+	list, total, err := h.svc.GetListPage(ctx, filter)
+	if err != nil {
+		ctx.Set("internal", err.Error())
+		zap.L().Error("获取分页列表异常", zap.Error(err))
+		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+		return
+	}
+
+	response.NewResponse().SuccessResponse(ctx, "查询成功", columns.Strip(ctx, CacheLogListPageResponse{
+		List:     list,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}))
+}
+
+// Stats
+// @Summary 缓存命中率统计
+// @Description 按缓存key键前缀统计命中、未命中、防穿透标记比例，异常次数及耗时 p50/p95(毫秒，按最近 10000 条记录计算)，未指定开始时间时统计最近 24 小时
+// @Tags 系统监控/缓存日志
+// @Accept application/json
+// @Produce application/json
+// @Param key query string false "缓存key键前缀"
+// @Param path query string false "请求地址前缀"
+// @Param startTime query string false "开始时间"
+// @Param endTime query string false "结束时间"
+// @Success 200 {array} domainLogger.CacheStats
+// @Failure 400 {object} response.Response
+// @Router /v1/monitor/cacheLog/stats [get]
+// @Security LoginToken
+func (h *cacheLogHandler) Stats(ctx *gin.Context) {
+	filter := domainLogger.CacheLogFilter{
+		Key:       ctx.DefaultQuery("key", ""),
+		Path:      ctx.DefaultQuery("path", ""),
+		StartTime: ctx.DefaultQuery("startTime", ""),
+		EndTime:   ctx.DefaultQuery("endTime", ""),
+	}
+	if filter.StartTime == "" {
+		filter.StartTime = time.Now().Add(-cacheStatsDefaultRange).Format("2006-01-02 15:04:05")
+	}
+
+	stats, err := h.svc.Stats(ctx, filter)
+	if err != nil {
+		ctx.Set("internal", err.Error())
+		zap.L().Error("缓存命中率统计异常", zap.Error(err))
+		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+		return
+	}
+
+	response.NewResponse().SuccessResponse(ctx, "查询成功", stats)
+}
+
+// filter 查询条件
+func (h *cacheLogHandler) filter(ctx *gin.Context) domainLogger.CacheLogFilter {
+	filter := domainLogger.CacheLogFilter{
+		Key:       ctx.DefaultQuery("key", ""),
+		Operation: ctx.DefaultQuery("operation", ""),
+		Result:    ctx.DefaultQuery("result", ""),
+		Username:  ctx.DefaultQuery("username", ""),
+		Path:      ctx.DefaultQuery("path", ""),
+		StartTime: ctx.DefaultQuery("startTime", ""),
+		EndTime:   ctx.DefaultQuery("endTime", ""),
+	}
+	if hasError, err := strconv.ParseBool(ctx.Query("hasError")); err == nil {
+		filter.HasError = &hasError
+	}
+	return filter
+}
@@ -0,0 +1,218 @@
+/**
+ * Description：
+ * FileName：change_log.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 16:45:22
+ * Remark：
+ */
+
+package monitor
+
+import (
+	"errors"
+	config "github.com/carefuly/carefuly-admin-go-gin/config/file"
+	domainLogger "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/logger"
+	serviceLogger "github.com/carefuly/carefuly-admin-go-gin/internal/service/careful/logger"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/columns"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/filters"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/response"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/requestUtils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+	"strconv"
+)
+
+// ChangeLogListPageResponse 列表分页响应
+type ChangeLogListPageResponse struct {
+	List     []domainLogger.ChangeLog `json:"list"`     // 列表
+	Total    int64                    `json:"total"`    // 总数
+	Page     int                      `json:"page"`     // 页码
+	PageSize int                      `json:"pageSize"` // 每页数量
+}
+
+type ChangeLogHandler interface {
+	RegisterRoutes(router *gin.RouterGroup)
+	GetById(ctx *gin.Context)
+	GetHistory(ctx *gin.Context)
+	GetListPage(ctx *gin.Context)
+	Restore(ctx *gin.Context)
+}
+
+type changeLogHandler struct {
+	rely config.RelyConfig
+	svc  serviceLogger.ChangeLogService
+}
+
+func NewChangeLogHandler(rely config.RelyConfig, svc serviceLogger.ChangeLogService) ChangeLogHandler {
+	return &changeLogHandler{
+		rely: rely,
+		svc:  svc,
+	}
+}
+
+// RegisterRoutes 注册路由
+func (h *changeLogHandler) RegisterRoutes(router *gin.RouterGroup) {
+	base := router.Group("/changeLog")
+	base.GET("/getById/:id", h.GetById)
+	base.GET("/history", h.GetHistory)
+	base.GET("/listPage", h.GetListPage)
+	base.POST("/restore/:id", h.Restore)
+}
+
+// GetById
+// @Summary 获取数据变更详情
+// @Description 获取数据变更详情，包含字段差异及变更前后的数据
+// @Tags 系统监控/数据变更
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "ID"
+// @Success 200 {object} domainLogger.ChangeLog
+// @Failure 400 {object} response.Response
+// @Router /v1/monitor/changeLog/getById/{id} [get]
+// @Security LoginToken
+func (h *changeLogHandler) GetById(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if id == "" {
+		response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "ID不能为空", nil)
+		return
+	}
+
+	detail, err := h.svc.GetById(ctx, id)
+	if err != nil {
+		if errors.Is(err, serviceLogger.ErrChangeLogNotFound) {
+			response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "变更记录不存在", nil)
+			return
+		}
+		zap.L().Error("获取数据变更详情失败", zap.Error(err))
+		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+		return
+	}
+
+	response.NewResponse().SuccessResponse(ctx, "查询成功", detail)
+}
+
+// GetHistory
+// @Summary 获取数据变更历史
+// @Description 获取任意一条数据的全部变更记录，最近的在前
+// @Tags 系统监控/数据变更
+// @Accept application/json
+// @Produce application/json
+// @Param entityTable query string true "数据表"
+// @Param entityId query string true "数据ID"
+// @Success 200 {array} domainLogger.ChangeLog
+// @Failure 400 {object} response.Response
+// @Router /v1/monitor/changeLog/history [get]
+// @Security LoginToken
+func (h *changeLogHandler) GetHistory(ctx *gin.Context) {
+	table := ctx.Query("entityTable")
+	entityId := ctx.Query("entityId")
+	if table == "" || entityId == "" {
+		response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "数据表及数据ID不能为空", nil)
+		return
+	}
+
+	list, err := h.svc.GetHistory(ctx, table, entityId)
+	if err != nil {
+		zap.L().Error("获取数据变更历史异常", zap.Error(err))
+		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+		return
+	}
+
+	response.NewResponse().SuccessResponse(ctx, "查询成功", list)
+}
+
+// GetListPage
+// @Summary 获取数据变更分页列表
+// @Description 获取数据变更分页列表，不返回变更前后的数据
+// @Tags 系统监控/数据变更
+// @Accept application/json
+// @Produce application/json
+// @Param page query int true "页码" default(1)
+// @Param pageSize query int true "每页数量" default(10)
+// @Param entityTable query string false "数据表"
+// @Param entityId query string false "数据ID"
+// @Param action query string false "变更类型" Enums(create, update, delete, restore)
+// @Param operator query string false "操作人"
+// @Param startTime query string false "开始时间"
+// @Param endTime query string false "结束时间"
+// @Success 200 {object} ChangeLogListPageResponse
+// @Failure 400 {object} response.Response
+// @Router /v1/monitor/changeLog/listPage [get]
+// @Security LoginToken
+func (h *changeLogHandler) GetListPage(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("pageSize", "10"))
+
+	filter := domainLogger.ChangeLogFilter{
+		Pagination: filters.Pagination{
+			Page:     page,
+			PageSize: pageSize,
+		},
+		EntityTable: ctx.DefaultQuery("entityTable", ""),
+		EntityId:    ctx.DefaultQuery("entityId", ""),
+		Action:      ctx.DefaultQuery("action", ""),
+		Operator:    ctx.DefaultQuery("operator", ""),
+		StartTime:   ctx.DefaultQuery("startTime", ""),
+		EndTime:     ctx.DefaultQuery("endTime", ""),
+	}
+
+	list, total, err := h.svc.GetListPage(ctx, filter)
+	if err != nil {
+		zap.L().Error("获取分页列表异常", zap.Error(err))
+		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+		return
+	}
+
+	response.NewResponse().SuccessResponse(ctx, "查询成功", columns.Strip(ctx, ChangeLogListPageResponse{
+		List:     list,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}))
+}
+
+// Restore
+// @Summary 恢复数据历史版本
+// @Description 将数据恢复为该变更记录对应的版本，删除记录恢复为删除前的数据；密码等不记录的字段不会被恢复；密钥、两步验证、第三方账号关联及已删除的用户不支持恢复
+// @Tags 系统监控/数据变更
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "变更记录ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Router /v1/monitor/changeLog/restore/{id} [post]
+// @Security LoginToken
+func (h *changeLogHandler) Restore(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if id == "" {
+		response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "ID不能为空", nil)
+		return
+	}
+
+	err := h.svc.Restore(ctx, id, requestUtils.GetActorId(ctx))
+	if err != nil {
+		switch {
+		case errors.Is(err, serviceLogger.ErrChangeLogNotFound):
+			response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "变更记录不存在", nil)
+		case errors.Is(err, serviceLogger.ErrChangeLogRestoreEmpty):
+			response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "变更记录没有可恢复的数据", nil)
+		case errors.Is(err, serviceLogger.ErrChangeLogAlreadyCurrent):
+			response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "数据已是该版本", nil)
+		case errors.Is(err, serviceLogger.ErrChangeLogRestoreDenied):
+			response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "该数据不支持恢复", nil)
+		case errors.Is(err, serviceLogger.ErrChangeLogUserDeleted):
+			response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "已删除的用户不支持恢复", nil)
+		case errors.Is(err, serviceLogger.ErrChangeLogNoPermission):
+			response.NewResponse().ErrorResponse(ctx, http.StatusForbidden, "无权恢复该数据", nil)
+		default:
+			ctx.Set("internal", err.Error())
+			zap.L().Error("恢复数据历史版本异常", zap.Error(err))
+			response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+		}
+		return
+	}
+
+	response.NewResponse().SuccessResponse(ctx, "恢复成功", nil)
+}
@@ -0,0 +1,250 @@
+/**
+ * Description：
+ * FileName：login_log.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 18:03:44
+ * Remark：
+ */
+
+package monitor
+
+import (
+	"fmt"
+	config "github.com/carefuly/carefuly-admin-go-gin/config/file"
+	domainLogger "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/logger"
+	serviceLogger "github.com/carefuly/carefuly-admin-go-gin/internal/service/careful/logger"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/columns"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/filters"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/response"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/excelutil"
+	validate "github.com/carefuly/carefuly-admin-go-gin/pkg/validator"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// LoginLogListPageResponse 列表分页响应
+type LoginLogListPageResponse struct {
+	List     []domainLogger.LoginLog `json:"list"`     // 列表
+	Total    int64                   `json:"total"`    // 总数
+	Page     int                     `json:"page"`     // 页码
+	PageSize int                     `json:"pageSize"` // 每页数量
+}
+
+// CleanLoginLogResponse 清理响应
+type CleanLoginLogResponse struct {
+	Rows int64 `json:"rows"` // 清理条数
+}
+
+type LoginLogHandler interface {
+	RegisterRoutes(router *gin.RouterGroup)
+	BatchDelete(ctx *gin.Context)
+	Clean(ctx *gin.Context)
+	GetListPage(ctx *gin.Context)
+	Export(ctx *gin.Context)
+}
+
+type loginLogHandler struct {
+	rely config.RelyConfig
+	svc  serviceLogger.LoginLogService
+}
+
+func NewLoginLogHandler(rely config.RelyConfig, svc serviceLogger.LoginLogService) LoginLogHandler {
+	return &loginLogHandler{
+		rely: rely,
+		svc:  svc,
+	}
+}
+
+// RegisterRoutes 注册路由
+func (h *loginLogHandler) RegisterRoutes(router *gin.RouterGroup) {
+	base := router.Group("/loginLog")
+	base.POST("/delete/batchDelete", h.BatchDelete)
+	base.DELETE("/clean", h.Clean)
+	base.GET("/listPage", h.GetListPage)
+	base.GET("/export", h.Export)
+}
+
+// BatchDelete
+// @Summary 批量删除登录日志
+// @Description 批量删除登录日志
+// @Tags 系统监控/登录日志
+// @Accept application/json
+// @Produce application/json
+// @Param ids body []string true "id数组"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Router /v1/monitor/loginLog/delete/batchDelete [post]
+// @Security LoginToken
+func (h *loginLogHandler) BatchDelete(ctx *gin.Context) {
+	var ids []string
+	if err := ctx.ShouldBindJSON(&ids); err != nil {
+		validate.NewValidatorError(h.rely.Trans).HandleValidatorError(ctx, err)
+		return
+	}
+
+	err := h.svc.BatchDelete(ctx, ids)
+	if err != nil {
+		ctx.Set("internal", err.Error())
+		zap.L().Error("批量删除登录日志异常", zap.Error(err))
+		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+		return
+	}
+
+	response.NewResponse().SuccessResponse(ctx, "批量删除成功", nil)
+}
+
+// Clean
+// @Summary 清理登录日志
+// @Description 清理指定天数之前的登录日志，days 为 0 时清空全部
+// @Tags 系统监控/登录日志
+// @Accept application/json
+// @Produce application/json
+// @Param days query int false "保留天数" default(30)
+// @Success 200 {object} CleanLoginLogResponse
+// @Failure 400 {object} response.Response
+// @Router /v1/monitor/loginLog/clean [delete]
+// @Security LoginToken
+func (h *loginLogHandler) Clean(ctx *gin.Context) {
+	days, err := strconv.Atoi(ctx.DefaultQuery("days", "30"))
+	if err != nil || days < 0 {
+		response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "保留天数不正确", nil)
+		return
+	}
+
+	rows, err := h.svc.Clean(ctx, days)
+	if err != nil {
+		ctx.Set("internal", err.Error())
+		zap.L().Error("清理登录日志异常", zap.Error(err))
+		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+		return
+	}
+
+	response.NewResponse().SuccessResponse(ctx, "清理成功", CleanLoginLogResponse{
+		Rows: rows,
+	})
+}
+
+// GetListPage
+// @Summary 获取登录日志分页列表
+// @Description 获取登录日志分页列表
+// @Tags 系统监控/登录日志
+// @Accept application/json
+// @Produce application/json
+// @Param page query int true "页码" default(1)
+// @Param pageSize query int true "每页数量" default(10)
+// @Param username query string false "用户名"
+// @Param requestIp query string false "请求IP地址"
+// @Param action query string false "登录行为" Enums(login, logout, refresh)
+// @Param status query bool false "结果"
+// @Param startTime query string false "开始时间"
+// @Param endTime query string false "结束时间"
+// @Success 200 {object} LoginLogListPageResponse
+// @Failure 400 {object} response.Response
+// @Router /v1/monitor/loginLog/listPage [get]
+// @Security LoginToken
+func (h *loginLogHandler) GetListPage(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("pageSize", "10"))
+
+	filter := h.filter(ctx)
+	filter.Pagination = filters.Pagination{
+		Page:     page,
+		PageSize: pageSize,
+	}
+
+	list, total, err := h.svc.GetListPage(ctx, filter)
+	if err != nil {
+		zap.L().Error("获取分页列表异常", zap.Error(err))
+		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+		return
+	}
+
+	response.NewResponse().SuccessResponse(ctx, "查询成功", columns.Strip(ctx, LoginLogListPageResponse{
+		List:     list,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}))
+}
+
+// Export
+// @Summary 导出登录日志
+// @Description 导出登录日志到Excel文件
+// @Tags 系统监控/登录日志
+// @Accept application/json
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param username query string false "用户名"
+// @Param requestIp query string false "请求IP地址"
+// @Param action query string false "登录行为" Enums(login, logout, refresh)
+// @Param status query bool false "结果"
+// @Param startTime query string false "开始时间"
+// @Param endTime query string false "结束时间"
+// @Success 200 {file} file "Excel文件"
+// @Failure 500 {object} response.Response
+// @Router /v1/monitor/loginLog/export [get]
+// @Security LoginToken
+func (h *loginLogHandler) Export(ctx *gin.Context) {
+	list, err := h.svc.GetListAll(ctx, h.filter(ctx))
+	if err != nil {
+		zap.L().Error("获取列表异常", zap.Error(err))
+		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+		return
+	}
+
+	// 准备导出配置
+	filename := fmt.Sprintf("登录日志导出_%s.xlsx", time.Now().Format("20060102150405"))
+	cfg := excelutil.ExcelExportConfig{
+		SheetName:  "登录日志",
+		FileName:   filename,
+		StreamMode: true,
+		Columns: columns.ExcelColumns(ctx, []excelutil.ExcelColumn{
+			{Title: "用户名", Field: "Username", Width: 20},
+			{Title: "登录行为", Field: "Action", Width: 12},
+			{Title: "结果", Field: "Status", Width: 8},
+			{Title: "失败原因", Field: "Message", Width: 30},
+			{Title: "请求IP地址", Field: "RequestIp", Width: 18},
+			{Title: "操作系统", Field: "RequestOs", Width: 20},
+			{Title: "浏览器", Field: "RequestBrowser", Width: 20},
+			{Title: "时间", Field: "CreateTime", Width: 22},
+		}),
+		Data: list,
+	}
+
+	// 创建并执行导出器
+	exporter := excelutil.NewExcelExporter(&cfg)
+	f, err := exporter.Export()
+	if err != nil {
+		zap.L().Error("导出登录日志失败", zap.Error(err))
+		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+		return
+	}
+
+	// 设置响应头
+	ctx.Header("Content-Type", "application/octet-stream")
+	ctx.Header("Content-Disposition", "attachment; filename=export.xlsx")
+	ctx.Header("Pragma", "no-cache")
+	ctx.Header("Cache-Control", "no-store")
+
+	// 流式写入响应
+	if _, err := f.WriteTo(ctx.Writer); err != nil {
+		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "生成Excel失败", nil)
+	}
+}
+
+// filter 查询条件
+func (h *loginLogHandler) filter(ctx *gin.Context) domainLogger.LoginLogFilter {
+	filter := domainLogger.LoginLogFilter{
+		Username:  ctx.DefaultQuery("username", ""),
+		RequestIp: ctx.DefaultQuery("requestIp", ""),
+		Action:    ctx.DefaultQuery("action", ""),
+		StartTime: ctx.DefaultQuery("startTime", ""),
+		EndTime:   ctx.DefaultQuery("endTime", ""),
+	}
+	if status, err := strconv.ParseBool(ctx.Query("status")); err == nil {
+		filter.Status = &status
+	}
+	return filter
+}
@@ -0,0 +1,199 @@
+/**
+ * Description：
+ * FileName：online.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 19:20:16
+ * Remark：
+ */
+
+package monitor
+
+import (
+	"errors"
+	config "github.com/carefuly/carefuly-admin-go-gin/config/file"
+	domainSystem "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/system"
+	serviceSystem "github.com/carefuly/carefuly-admin-go-gin/internal/service/careful/system"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/columns"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/filters"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/response"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+	"strconv"
+)
+
+// OnlineListPageResponse 列表分页响应
+type OnlineListPageResponse struct {
+	List     []domainSystem.Session `json:"list"`     // 列表
+	Total    int64                  `json:"total"`    // 总数
+	Page     int                    `json:"page"`     // 页码
+	PageSize int                    `json:"pageSize"` // 每页数量
+}
+
+type OnlineHandler interface {
+	RegisterRoutes(router *gin.RouterGroup)
+	Kick(ctx *gin.Context)
+	KickUser(ctx *gin.Context)
+	GetListPage(ctx *gin.Context)
+}
+
+type onlineHandler struct {
+	rely    config.RelyConfig
+	svc     serviceSystem.SessionService
+	userSvc serviceSystem.UserService
+}
+
+func NewOnlineHandler(rely config.RelyConfig, svc serviceSystem.SessionService, userSvc serviceSystem.UserService) OnlineHandler {
+	return &onlineHandler{
+		rely:    rely,
+		svc:     svc,
+		userSvc: userSvc,
+	}
+}
+
+// RegisterRoutes 注册路由
+func (h *onlineHandler) RegisterRoutes(router *gin.RouterGroup) {
+	base := router.Group("/online")
+	base.DELETE("/kick/:jti", h.Kick)
+	base.DELETE("/kickUser/:userId", h.KickUser)
+	base.GET("/listPage", h.GetListPage)
+}
+
+// Kick
+// @Summary 强制下线会话
+// @Description 吊销指定会话，该会话签发的令牌立即失效，仅能强退数据权限范围内的用户
+// @Tags 系统监控/在线用户
+// @Accept application/json
+// @Produce application/json
+// @Param jti path string true "会话ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Router /v1/monitor/online/kick/{jti} [delete]
+// @Security LoginToken
+func (h *onlineHandler) Kick(ctx *gin.Context) {
+	jti := ctx.Param("jti")
+	if jti == ctx.GetString("jti") {
+		response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "不能强退当前会话", nil)
+		return
+	}
+
+	session, err := h.svc.GetByJti(ctx, jti)
+	if err != nil {
+		h.sessionErrorResponse(ctx, jti, err)
+		return
+	}
+	if !h.allowUser(ctx, session.UserId) {
+		return
+	}
+
+	if err := h.svc.Revoke(ctx, jti); err != nil {
+		h.sessionErrorResponse(ctx, jti, err)
+		return
+	}
+
+	response.NewResponse().SuccessResponse(ctx, "强退成功", nil)
+}
+
+// KickUser
+// @Summary 强制下线用户
+// @Description 吊销指定用户的全部会话，仅能强退数据权限范围内的用户
+// @Tags 系统监控/在线用户
+// @Accept application/json
+// @Produce application/json
+// @Param userId path string true "用户ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Router /v1/monitor/online/kickUser/{userId} [delete]
+// @Security LoginToken
+func (h *onlineHandler) KickUser(ctx *gin.Context) {
+	userId := ctx.Param("userId")
+	if userId == ctx.GetString("userId") {
+		response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "不能强退当前用户", nil)
+		return
+	}
+
+	if !h.allowUser(ctx, userId) {
+		return
+	}
+
+	if err := h.svc.RevokeByUserId(ctx, userId); err != nil {
+		ctx.Set("internal", err.Error())
+		zap.L().Error("强制下线用户异常", zap.String("userId", userId), zap.Error(err))
+		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+		return
+	}
+
+	response.NewResponse().SuccessResponse(ctx, "强退成功", nil)
+}
+
+// GetListPage
+// @Summary 获取在线用户分页列表
+// @Description 获取数据权限范围内的在线会话分页列表，检测到令牌在其他客户端使用的会话会标记为可疑，可通过强制下线吊销
+// @Tags 系统监控/在线用户
+// @Accept application/json
+// @Produce application/json
+// @Param page query int true "页码" default(1)
+// @Param pageSize query int true "每页数量" default(10)
+// @Param username query string false "用户名"
+// @Param requestIp query string false "登录IP地址"
+// @Param suspicious query bool false "仅查询客户端与令牌签发时不一致的会话"
+// @Success 200 {object} OnlineListPageResponse
+// @Failure 400 {object} response.Response
+// @Router /v1/monitor/online/listPage [get]
+// @Security LoginToken
+func (h *onlineHandler) GetListPage(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("pageSize", "10"))
+
+	filter := domainSystem.SessionFilter{
+		Pagination: filters.Pagination{
+			Page:     page,
+			PageSize: pageSize,
+		},
+		Username:   ctx.DefaultQuery("username", ""),
+		RequestIp:  ctx.DefaultQuery("requestIp", ""),
+		Suspicious: ctx.DefaultQuery("suspicious", "") == "true",
+	}
+
+	list, total, err := h.svc.GetListPage(ctx, filter)
+	if err != nil {
+		ctx.Set("internal", err.Error())
+		zap.L().Error("获取分页列表异常", zap.Error(err))
+		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+		return
+	}
+
+	response.NewResponse().SuccessResponse(ctx, "查询成功", columns.Strip(ctx, OnlineListPageResponse{
+		List:     list,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}))
+}
+
+// sessionErrorResponse 会话查询或吊销失败响应
+func (h *onlineHandler) sessionErrorResponse(ctx *gin.Context, jti string, err error) {
+	switch {
+	case errors.Is(err, serviceSystem.ErrSessionNotFound):
+		response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "会话不存在或已过期", nil)
+	default:
+		ctx.Set("internal", err.Error())
+		zap.L().Error("强制下线会话异常", zap.String("jti", jti), zap.Error(err))
+		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+	}
+}
+
+// allowUser 校验目标用户是否在数据权限范围内，不在范围内时写入响应
+func (h *onlineHandler) allowUser(ctx *gin.Context, userId string) bool {
+	if _, err := h.userSvc.GetById(ctx, userId); err != nil {
+		if errors.Is(err, serviceSystem.ErrUserNotFound) {
+			response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "用户不存在", nil)
+			return false
+		}
+		ctx.Set("internal", err.Error())
+		zap.L().Error("获取用户异常", zap.String("userId", userId), zap.Error(err))
+		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+		return false
+	}
+	return true
+}
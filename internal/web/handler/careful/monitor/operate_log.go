@@ -0,0 +1,303 @@
+/**
+ * Description：
+ * FileName：operate_log.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 08:40:26
+ * Remark：
+ */
+
+package monitor
+
+import (
+	"errors"
+	"fmt"
+	config "github.com/carefuly/carefuly-admin-go-gin/config/file"
+	domainLogger "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/logger"
+	serviceLogger "github.com/carefuly/carefuly-admin-go-gin/internal/service/careful/logger"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/columns"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/filters"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/response"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/excelutil"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/requestUtils"
+	validate "github.com/carefuly/carefuly-admin-go-gin/pkg/validator"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OperateLogListPageResponse 列表分页响应
+type OperateLogListPageResponse struct {
+	List     []domainLogger.OperateLog `json:"list"`     // 列表
+	Total    int64                     `json:"total"`    // 总数
+	Page     int                       `json:"page"`     // 页码
+	PageSize int                       `json:"pageSize"` // 每页数量
+}
+
+// CleanOperateLogRequest 清理请求
+type CleanOperateLogRequest struct {
+	Days int `form:"days,default=30" binding:"min=1" example:"30"` // 保留天数，至少 1 天
+}
+
+// CleanOperateLogResponse 清理响应
+type CleanOperateLogResponse struct {
+	Rows int64 `json:"rows"` // 清理条数
+}
+
+type OperateLogHandler interface {
+	RegisterRoutes(router *gin.RouterGroup)
+	BatchDelete(ctx *gin.Context)
+	Clean(ctx *gin.Context)
+	GetById(ctx *gin.Context)
+	GetListPage(ctx *gin.Context)
+	Export(ctx *gin.Context)
+}
+
+type operateLogHandler struct {
+	rely config.RelyConfig
+	svc  serviceLogger.OperateLogService
+}
+
+func NewOperateLogHandler(rely config.RelyConfig, svc serviceLogger.OperateLogService) OperateLogHandler {
+	return &operateLogHandler{
+		rely: rely,
+		svc:  svc,
+	}
+}
+
+// RegisterRoutes 注册路由
+func (h *operateLogHandler) RegisterRoutes(router *gin.RouterGroup) {
+	base := router.Group("/operateLog")
+	base.POST("/delete/batchDelete", h.BatchDelete)
+	base.DELETE("/clean", h.Clean)
+	base.GET("/getById/:id", h.GetById)
+	base.GET("/listPage", h.GetListPage)
+	base.GET("/export", h.Export)
+}
+
+// BatchDelete
+// @Summary 批量删除操作日志
+// @Description 批量删除操作日志
+// @Tags 系统监控/操作日志
+// @Accept application/json
+// @Produce application/json
+// @Param ids body []string true "id数组"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Router /v1/monitor/operateLog/delete/batchDelete [post]
+// @Security LoginToken
+func (h *operateLogHandler) BatchDelete(ctx *gin.Context) {
+	var ids []string
+	if err := ctx.ShouldBindJSON(&ids); err != nil {
+		validate.NewValidatorError(h.rely.Trans).HandleValidatorError(ctx, err)
+		return
+	}
+
+	err := h.svc.BatchDelete(ctx, ids)
+	if err != nil {
+		ctx.Set("internal", err.Error())
+		zap.L().Error("批量删除操作日志异常", zap.Error(err))
+		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+		return
+	}
+
+	response.NewResponse().SuccessResponse(ctx, "批量删除成功", nil)
+}
+
+// Clean
+// @Summary 清理操作日志
+// @Description 清理指定天数之前的操作日志，至少保留 1 天，清理操作记录审计日志
+// @Tags 系统监控/操作日志
+// @Accept application/json
+// @Produce application/json
+// @Param days query int false "保留天数" default(30) minimum(1)
+// @Success 200 {object} CleanOperateLogResponse
+// @Failure 400 {object} response.Response
+// @Router /v1/monitor/operateLog/clean [delete]
+// @Security LoginToken
+func (h *operateLogHandler) Clean(ctx *gin.Context) {
+	var req CleanOperateLogRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		validate.NewValidatorError(h.rely.Trans).HandleValidatorError(ctx, err)
+		return
+	}
+
+	rows, err := h.svc.Clean(ctx, req.Days, requestUtils.GetActorId(ctx), requestUtils.GetActorName(ctx), requestUtils.NormalizeIP(ctx))
+	if err != nil {
+		ctx.Set("internal", err.Error())
+		zap.L().Error("清理操作日志异常", zap.Error(err))
+		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+		return
+	}
+
+	response.NewResponse().SuccessResponse(ctx, "清理成功", CleanOperateLogResponse{
+		Rows: rows,
+	})
+}
+
+// GetById
+// @Summary 获取操作日志详情
+// @Description 获取操作日志详情，请求参数及响应信息为 JSON 时格式化返回
+// @Tags 系统监控/操作日志
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "ID"
+// @Success 200 {object} domainLogger.OperateLog
+// @Failure 400 {object} response.Response
+// @Router /v1/monitor/operateLog/getById/{id} [get]
+// @Security LoginToken
+func (h *operateLogHandler) GetById(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if id == "" {
+		response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "ID不能为空", nil)
+		return
+	}
+
+	detail, err := h.svc.GetById(ctx, id)
+	if err != nil {
+		if errors.Is(err, serviceLogger.ErrOperateLogNotFound) {
+			response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "操作日志不存在", nil)
+			return
+		}
+		zap.L().Error("获取操作日志失败", zap.Error(err))
+		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+		return
+	}
+
+	response.NewResponse().SuccessResponse(ctx, "查询成功", detail)
+}
+
+// GetListPage
+// @Summary 获取操作日志分页列表
+// @Description 获取操作日志分页列表，不返回请求参数及响应信息
+// @Tags 系统监控/操作日志
+// @Accept application/json
+// @Produce application/json
+// @Param page query int true "页码" default(1)
+// @Param pageSize query int true "每页数量" default(10)
+// @Param username query string false "请求用户名"
+// @Param method query string false "请求方式" Enums(POST, PUT, PATCH, DELETE)
+// @Param path query string false "请求地址前缀"
+// @Param status query int false "响应状态码"
+// @Param requestCode query int false "自定义状态码"
+// @Param requestIp query string false "请求IP地址"
+// @Param startTime query string false "开始时间"
+// @Param endTime query string false "结束时间"
+// @Success 200 {object} OperateLogListPageResponse
+// @Failure 400 {object} response.Response
+// @Router /v1/monitor/operateLog/listPage [get]
+// @Security LoginToken
+func (h *operateLogHandler) GetListPage(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("pageSize", "10"))
+
+	filter := h.filter(ctx)
+	filter.Pagination = filters.Pagination{
+		Page:     page,
+		PageSize: pageSize,
+	}
+
+	list, total, err := h.svc.GetListPage(ctx, filter)
+	if err != nil {
+		zap.L().Error("获取分页列表异常", zap.Error(err))
+		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+		return
+	}
+
+	response.NewResponse().SuccessResponse(ctx, "查询成功", columns.Strip(ctx, OperateLogListPageResponse{
+		List:     list,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}))
+}
+
+// Export
+// @Summary 导出操作日志
+// @Description 导出操作日志到Excel文件
+// @Tags 系统监控/操作日志
+// @Accept application/json
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param username query string false "请求用户名"
+// @Param method query string false "请求方式" Enums(POST, PUT, PATCH, DELETE)
+// @Param path query string false "请求地址前缀"
+// @Param status query int false "响应状态码"
+// @Param requestCode query int false "自定义状态码"
+// @Param requestIp query string false "请求IP地址"
+// @Param startTime query string false "开始时间"
+// @Param endTime query string false "结束时间"
+// @Success 200 {file} file "Excel文件"
+// @Failure 500 {object} response.Response
+// @Router /v1/monitor/operateLog/export [get]
+// @Security LoginToken
+func (h *operateLogHandler) Export(ctx *gin.Context) {
+	list, err := h.svc.GetListAll(ctx, h.filter(ctx))
+	if err != nil {
+		zap.L().Error("获取列表异常", zap.Error(err))
+		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+		return
+	}
+
+	// 准备导出配置
+	filename := fmt.Sprintf("操作日志导出_%s.xlsx", time.Now().Format("20060102150405"))
+	cfg := excelutil.ExcelExportConfig{
+		SheetName:  "操作日志",
+		FileName:   filename,
+		StreamMode: true,
+		Columns: columns.ExcelColumns(ctx, []excelutil.ExcelColumn{
+			{Title: "请求用户名", Field: "RequestUsername", Width: 20},
+			{Title: "实际操作人", Field: "ActorUsername", Width: 20},
+			{Title: "请求方式", Field: "RequestMethod", Width: 10},
+			{Title: "请求地址", Field: "RequestPath", Width: 40},
+			{Title: "响应状态码", Field: "RequestStatus", Width: 12},
+			{Title: "自定义状态码", Field: "RequestCode", Width: 12},
+			{Title: "请求耗时", Field: "RequestTime", Width: 15},
+			{Title: "请求IP地址", Field: "RequestIp", Width: 18},
+			{Title: "操作系统", Field: "RequestOs", Width: 20},
+			{Title: "浏览器", Field: "RequestBrowser", Width: 20},
+			{Title: "时间", Field: "CreateTime", Width: 22},
+		}),
+		Data: list,
+	}
+
+	// 创建并执行导出器
+	exporter := excelutil.NewExcelExporter(&cfg)
+	f, err := exporter.Export()
+	if err != nil {
+		zap.L().Error("导出操作日志失败", zap.Error(err))
+		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+		return
+	}
+
+	// 设置响应头
+	ctx.Header("Content-Type", "application/octet-stream")
+	ctx.Header("Content-Disposition", "attachment; filename=export.xlsx")
+	ctx.Header("Pragma", "no-cache")
+	ctx.Header("Cache-Control", "no-store")
+
+	// 流式写入响应
+	if _, err := f.WriteTo(ctx.Writer); err != nil {
+		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "生成Excel失败", nil)
+	}
+}
+
+// filter 查询条件
+func (h *operateLogHandler) filter(ctx *gin.Context) domainLogger.OperateLogFilter {
+	filter := domainLogger.OperateLogFilter{
+		Username:  ctx.DefaultQuery("username", ""),
+		Method:    strings.ToUpper(ctx.DefaultQuery("method", "")),
+		Path:      ctx.DefaultQuery("path", ""),
+		RequestIp: ctx.DefaultQuery("requestIp", ""),
+		StartTime: ctx.DefaultQuery("startTime", ""),
+		EndTime:   ctx.DefaultQuery("endTime", ""),
+	}
+	if status, err := strconv.Atoi(ctx.Query("status")); err == nil {
+		filter.Status = &status
+	}
+	if code, err := strconv.Atoi(ctx.Query("requestCode")); err == nil {
+		filter.RequestCode = &code
+	}
+	return filter
+}
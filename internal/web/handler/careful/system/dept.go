@@ -15,10 +15,12 @@ import (
 	domainSystem "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/system"
 	modelSystem "github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/system"
 	serviceSystem "github.com/carefuly/carefuly-admin-go-gin/internal/service/careful/system"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/columns"
 	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/filters"
 	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/response"
 	"github.com/carefuly/carefuly-admin-go-gin/pkg/models"
 	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/excelutil"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/requestUtils"
 	validate "github.com/carefuly/carefuly-admin-go-gin/pkg/validator"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -139,8 +141,8 @@ func (h *deptHandler) Create(ctx *gin.Context) {
 		Dept: modelSystem.Dept{
 			CoreModels: models.CoreModels{
 				Sort:       req.Sort,
-				Creator:    uid,
-				Modifier:   uid,
+				Creator:    requestUtils.GetActorId(ctx),
+				Modifier:   requestUtils.GetActorId(ctx),
 				BelongDept: user.DeptId,
 				Remark:     req.Remark,
 			},
@@ -276,7 +278,7 @@ func (h *deptHandler) Update(ctx *gin.Context) {
 				Id:         req.Id,
 				Sort:       req.Sort,
 				Version:    req.Version,
-				Modifier:   uid,
+				Modifier:   requestUtils.GetActorId(ctx),
 				BelongDept: user.DeptId,
 				Remark:     req.Remark,
 			},
@@ -337,7 +339,7 @@ func (h *deptHandler) GetById(ctx *gin.Context) {
 		return
 	}
 
-	response.NewResponse().SuccessResponse(ctx, "获取成功", detail)
+	response.NewResponse().SuccessResponse(ctx, "获取成功", columns.Strip(ctx, detail))
 }
 
 // GetDeptTree 获取部门树形结构
@@ -357,24 +359,9 @@ func (h *deptHandler) GetById(ctx *gin.Context) {
 // @Router /v1/system/dept/listTree [get]
 // @Security LoginToken
 func (h *deptHandler) GetDeptTree(ctx *gin.Context) {
-	uid, ok := ctx.MustGet("userId").(string)
-	if !ok {
-		ctx.Set("internal", uid)
-		zap.S().Error("用户ID获取失败", uid)
-		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
-		return
-	}
-
-	user, err := h.userSvc.GetById(ctx, uid)
-	if err != nil {
-		ctx.Set("internal", err.Error())
-		zap.S().Error("获取用户失败", err.Error())
-		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
-		return
-	}
-
 	creator := ctx.DefaultQuery("creator", "")
 	modifier := ctx.DefaultQuery("modifier", "")
+	belongDept := ctx.DefaultQuery("belongDept", "")
 	status, _ := strconv.ParseBool(ctx.DefaultQuery("status", "true"))
 
 	name := ctx.DefaultQuery("name", "")
@@ -384,7 +371,7 @@ func (h *deptHandler) GetDeptTree(ctx *gin.Context) {
 		Filters: filters.Filters{
 			Creator:    creator,
 			Modifier:   modifier,
-			BelongDept: user.DeptId,
+			BelongDept: belongDept,
 		},
 		Status: status,
 		Name:   name,
@@ -399,7 +386,7 @@ func (h *deptHandler) GetDeptTree(ctx *gin.Context) {
 		return
 	}
 
-	response.NewResponse().SuccessResponse(ctx, "查询成功", tree)
+	response.NewResponse().SuccessResponse(ctx, "查询成功", columns.Strip(ctx, tree))
 }
 
 // Export
@@ -419,24 +406,9 @@ func (h *deptHandler) GetDeptTree(ctx *gin.Context) {
 // @Router /v1/system/dept/export [get]
 // @Security LoginToken
 func (h *deptHandler) Export(ctx *gin.Context) {
-	uid, ok := ctx.MustGet("userId").(string)
-	if !ok {
-		ctx.Set("internal", uid)
-		zap.S().Error("用户ID获取失败", uid)
-		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
-		return
-	}
-
-	user, err := h.userSvc.GetById(ctx, uid)
-	if err != nil {
-		ctx.Set("internal", err.Error())
-		zap.S().Error("获取用户失败", err.Error())
-		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
-		return
-	}
-
 	creator := ctx.DefaultQuery("creator", "")
 	modifier := ctx.DefaultQuery("modifier", "")
+	belongDept := ctx.DefaultQuery("belongDept", "")
 	status, _ := strconv.ParseBool(ctx.DefaultQuery("status", "true"))
 
 	name := ctx.DefaultQuery("name", "")
@@ -446,7 +418,7 @@ func (h *deptHandler) Export(ctx *gin.Context) {
 		Filters: filters.Filters{
 			Creator:    creator,
 			Modifier:   modifier,
-			BelongDept: user.DeptId,
+			BelongDept: belongDept,
 		},
 		Status: status,
 		Name:   name,
@@ -466,7 +438,7 @@ func (h *deptHandler) Export(ctx *gin.Context) {
 		SheetName:  "部门",
 		FileName:   filename,
 		StreamMode: true,
-		Columns: []excelutil.ExcelColumn{
+		Columns: columns.ExcelColumns(ctx, []excelutil.ExcelColumn{
 			{Title: "部门名称", Field: "Name", Width: 22},
 			{Title: "部门编码", Field: "Code", Width: 18},
 			{Title: "负责人", Field: "Owner", Width: 18},
@@ -477,7 +449,7 @@ func (h *deptHandler) Export(ctx *gin.Context) {
 			{Title: "创建时间", Field: "CreateTime", Width: 22},
 			{Title: "更新时间", Field: "UpdateTime", Width: 22},
 			{Title: "备注", Field: "Remark", Width: 40},
-		},
+		}),
 		Data: list,
 	}
 
@@ -16,11 +16,13 @@ import (
 	modelSystem "github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/system"
 	serviceSystem "github.com/carefuly/carefuly-admin-go-gin/internal/service/careful/system"
 	"github.com/carefuly/carefuly-admin-go-gin/pkg/constants/careful/system/menu"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/columns"
 	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/filters"
 	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/response"
 	"github.com/carefuly/carefuly-admin-go-gin/pkg/models"
 	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/enumconv"
 	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/excelutil"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/requestUtils"
 	validate "github.com/carefuly/carefuly-admin-go-gin/pkg/validator"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -166,8 +168,8 @@ func (h *menuHandler) Create(ctx *gin.Context) {
 		Menu: modelSystem.Menu{
 			CoreModels: models.CoreModels{
 				Sort:       req.Sort,
-				Creator:    uid,
-				Modifier:   uid,
+				Creator:    requestUtils.GetActorId(ctx),
+				Modifier:   requestUtils.GetActorId(ctx),
 				BelongDept: user.DeptId,
 				Remark:     req.Remark,
 			},
@@ -310,7 +312,7 @@ func (h *menuHandler) Update(ctx *gin.Context) {
 				Id:         req.Id,
 				Sort:       req.Sort,
 				Version:    req.Version,
-				Modifier:   uid,
+				Modifier:   requestUtils.GetActorId(ctx),
 				BelongDept: user.DeptId,
 				Remark:     req.Remark,
 			},
@@ -378,7 +380,7 @@ func (h *menuHandler) GetById(ctx *gin.Context) {
 		return
 	}
 
-	response.NewResponse().SuccessResponse(ctx, "获取成功", detail)
+	response.NewResponse().SuccessResponse(ctx, "获取成功", columns.Strip(ctx, detail))
 }
 
 // GetMenuTree 获取菜单树形结构
@@ -419,7 +421,7 @@ func (h *menuHandler) GetMenuTree(ctx *gin.Context) {
 		return
 	}
 
-	response.NewResponse().SuccessResponse(ctx, "查询成功", tree)
+	response.NewResponse().SuccessResponse(ctx, "查询成功", columns.Strip(ctx, tree))
 }
 
 // GetListAll
@@ -430,6 +432,7 @@ func (h *menuHandler) GetMenuTree(ctx *gin.Context) {
 // @Produce application/json
 // @Param creator query string false "创建人"
 // @Param modifier query string false "修改人"
+// @Param belongDept query string false "数据归属部门"
 // @Param status query bool false "状态" default(true)
 // @Param title query string false "菜单标题"
 // @Success 200 {array} []domainSystem.Menu
@@ -437,24 +440,9 @@ func (h *menuHandler) GetMenuTree(ctx *gin.Context) {
 // @Router /v1/system/menu/listAll [get]
 // @Security LoginToken
 func (h *menuHandler) GetListAll(ctx *gin.Context) {
-	uid, ok := ctx.MustGet("userId").(string)
-	if !ok {
-		ctx.Set("internal", uid)
-		zap.S().Error("用户ID获取失败", uid)
-		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
-		return
-	}
-
-	user, err := h.userSvc.GetById(ctx, uid)
-	if err != nil {
-		ctx.Set("internal", err.Error())
-		zap.S().Error("获取用户失败", err.Error())
-		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
-		return
-	}
-
 	creator := ctx.DefaultQuery("creator", "")
 	modifier := ctx.DefaultQuery("modifier", "")
+	belongDept := ctx.DefaultQuery("belongDept", "")
 	status, _ := strconv.ParseBool(ctx.DefaultQuery("status", "true"))
 
 	title := ctx.DefaultQuery("title", "")
@@ -463,7 +451,7 @@ func (h *menuHandler) GetListAll(ctx *gin.Context) {
 		Filters: filters.Filters{
 			Creator:    creator,
 			Modifier:   modifier,
-			BelongDept: user.DeptId,
+			BelongDept: belongDept,
 		},
 		Status: status,
 		Title:  title,
@@ -476,7 +464,7 @@ func (h *menuHandler) GetListAll(ctx *gin.Context) {
 		return
 	}
 
-	response.NewResponse().SuccessResponse(ctx, "查询成功", list)
+	response.NewResponse().SuccessResponse(ctx, "查询成功", columns.Strip(ctx, list))
 }
 
 // Export
@@ -487,6 +475,7 @@ func (h *menuHandler) GetListAll(ctx *gin.Context) {
 // @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
 // @Param creator query string false "创建人"
 // @Param modifier query string false "修改人"
+// @Param belongDept query string false "数据归属部门"
 // @Param status query bool false "状态" default(true)
 // @Param title query string false "菜单标题"
 // @Success 200 {file} file "Excel文件"
@@ -494,24 +483,9 @@ func (h *menuHandler) GetListAll(ctx *gin.Context) {
 // @Router /v1/system/menu/export [get]
 // @Security LoginToken
 func (h *menuHandler) Export(ctx *gin.Context) {
-	uid, ok := ctx.MustGet("userId").(string)
-	if !ok {
-		ctx.Set("internal", uid)
-		zap.S().Error("用户ID获取失败", uid)
-		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
-		return
-	}
-
-	user, err := h.userSvc.GetById(ctx, uid)
-	if err != nil {
-		ctx.Set("internal", err.Error())
-		zap.S().Error("获取用户失败", err.Error())
-		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
-		return
-	}
-
 	creator := ctx.DefaultQuery("creator", "")
 	modifier := ctx.DefaultQuery("modifier", "")
+	belongDept := ctx.DefaultQuery("belongDept", "")
 	status, _ := strconv.ParseBool(ctx.DefaultQuery("status", "true"))
 
 	title := ctx.DefaultQuery("title", "")
@@ -520,7 +494,7 @@ func (h *menuHandler) Export(ctx *gin.Context) {
 		Filters: filters.Filters{
 			Creator:    creator,
 			Modifier:   modifier,
-			BelongDept: user.DeptId,
+			BelongDept: belongDept,
 		},
 		Status: status,
 		Title:  title,
@@ -539,7 +513,7 @@ func (h *menuHandler) Export(ctx *gin.Context) {
 		SheetName:  "菜单",
 		FileName:   filename,
 		StreamMode: true,
-		Columns: []excelutil.ExcelColumn{
+		Columns: columns.ExcelColumns(ctx, []excelutil.ExcelColumn{
 			{Title: "菜单标题", Field: "Title", Width: 18},
 			{
 				Title: "菜单类型",
@@ -560,7 +534,7 @@ func (h *menuHandler) Export(ctx *gin.Context) {
 			{Title: "创建时间", Field: "CreateTime", Width: 22},
 			{Title: "更新时间", Field: "UpdateTime", Width: 22},
 			{Title: "备注", Field: "Remark", Width: 40},
-		},
+		}),
 		Data: list,
 	}
 
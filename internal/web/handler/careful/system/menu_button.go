@@ -15,10 +15,12 @@ import (
 	modelSystem "github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/system"
 	serviceSystem "github.com/carefuly/carefuly-admin-go-gin/internal/service/careful/system"
 	"github.com/carefuly/carefuly-admin-go-gin/pkg/constants/careful/system/menu"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/columns"
 	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/filters"
 	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/response"
 	"github.com/carefuly/carefuly-admin-go-gin/pkg/models"
 	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/enumconv"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/requestUtils"
 	validate "github.com/carefuly/carefuly-admin-go-gin/pkg/validator"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -146,8 +148,8 @@ func (h *menuButtonHandler) Create(ctx *gin.Context) {
 		MenuButton: modelSystem.MenuButton{
 			CoreModels: models.CoreModels{
 				Sort:       req.Sort,
-				Creator:    uid,
-				Modifier:   uid,
+				Creator:    requestUtils.GetActorId(ctx),
+				Modifier:   requestUtils.GetActorId(ctx),
 				BelongDept: user.DeptId,
 				Remark:     req.Remark,
 			},
@@ -271,7 +273,7 @@ func (h *menuButtonHandler) Update(ctx *gin.Context) {
 				Id:         req.Id,
 				Sort:       req.Sort,
 				Version:    req.Version,
-				Modifier:   uid,
+				Modifier:   requestUtils.GetActorId(ctx),
 				BelongDept: user.DeptId,
 				Remark:     req.Remark,
 			},
@@ -327,7 +329,7 @@ func (h *menuButtonHandler) GetById(ctx *gin.Context) {
 		return
 	}
 
-	response.NewResponse().SuccessResponse(ctx, "获取成功", detail)
+	response.NewResponse().SuccessResponse(ctx, "获取成功", columns.Strip(ctx, detail))
 }
 
 // GetListPage
@@ -340,6 +342,7 @@ func (h *menuButtonHandler) GetById(ctx *gin.Context) {
 // @Param pageSize query int true "每页数量" default(10)
 // @Param creator query string false "创建人"
 // @Param modifier query string false "修改人"
+// @Param belongDept query string false "数据归属部门"
 // @Param status query bool false "状态" default(true)
 // @Param name query string false "名称"
 // @Param code query string false "权限值"
@@ -349,26 +352,11 @@ func (h *menuButtonHandler) GetById(ctx *gin.Context) {
 // @Router /v1/system/menuButton/listPage [get]
 // @Security LoginToken
 func (h *menuButtonHandler) GetListPage(ctx *gin.Context) {
-	uid, ok := ctx.MustGet("userId").(string)
-	if !ok {
-		ctx.Set("internal", uid)
-		zap.S().Error("用户ID获取失败", uid)
-		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
-		return
-	}
-
-	user, err := h.userSvc.GetById(ctx, uid)
-	if err != nil {
-		ctx.Set("internal", err.Error())
-		zap.S().Error("获取用户失败", err.Error())
-		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
-		return
-	}
-
 	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("pageSize", "10"))
 	creator := ctx.DefaultQuery("creator", "")
 	modifier := ctx.DefaultQuery("modifier", "")
+	belongDept := ctx.DefaultQuery("belongDept", "")
 	status, _ := strconv.ParseBool(ctx.DefaultQuery("status", "true"))
 
 	name := ctx.DefaultQuery("name", "")
@@ -383,7 +371,7 @@ func (h *menuButtonHandler) GetListPage(ctx *gin.Context) {
 		Filters: filters.Filters{
 			Creator:    creator,
 			Modifier:   modifier,
-			BelongDept: user.DeptId,
+			BelongDept: belongDept,
 		},
 		Status: status,
 		Name:   name,
@@ -398,12 +386,12 @@ func (h *menuButtonHandler) GetListPage(ctx *gin.Context) {
 		return
 	}
 
-	response.NewResponse().SuccessResponse(ctx, "查询成功", MenuButtonListPageResponse{
+	response.NewResponse().SuccessResponse(ctx, "查询成功", columns.Strip(ctx, MenuButtonListPageResponse{
 		List:     list,
 		Total:    total,
 		Page:     page,
 		PageSize: pageSize,
-	})
+	}))
 }
 
 // GetListByMenuIds
@@ -432,7 +420,7 @@ func (h *menuButtonHandler) GetListByMenuIds(ctx *gin.Context) {
 		return
 	}
 
-	response.NewResponse().SuccessResponse(ctx, "查询成功", tree)
+	response.NewResponse().SuccessResponse(ctx, "查询成功", columns.Strip(ctx, tree))
 }
 
 // GetListAll
@@ -443,6 +431,7 @@ func (h *menuButtonHandler) GetListByMenuIds(ctx *gin.Context) {
 // @Produce application/json
 // @Param creator query string false "创建人"
 // @Param modifier query string false "修改人"
+// @Param belongDept query string false "数据归属部门"
 // @Param status query bool false "状态" default(true)
 // @Param name query string false "名称"
 // @Param code query string false "权限值"
@@ -452,24 +441,9 @@ func (h *menuButtonHandler) GetListByMenuIds(ctx *gin.Context) {
 // @Router /v1/system/menuButton/listAll [get]
 // @Security LoginToken
 func (h *menuButtonHandler) GetListAll(ctx *gin.Context) {
-	uid, ok := ctx.MustGet("userId").(string)
-	if !ok {
-		ctx.Set("internal", uid)
-		zap.S().Error("用户ID获取失败", uid)
-		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
-		return
-	}
-
-	user, err := h.userSvc.GetById(ctx, uid)
-	if err != nil {
-		ctx.Set("internal", err.Error())
-		zap.S().Error("获取用户失败", err.Error())
-		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
-		return
-	}
-
 	creator := ctx.DefaultQuery("creator", "")
 	modifier := ctx.DefaultQuery("modifier", "")
+	belongDept := ctx.DefaultQuery("belongDept", "")
 	status, _ := strconv.ParseBool(ctx.DefaultQuery("status", "true"))
 
 	name := ctx.DefaultQuery("name", "")
@@ -480,7 +454,7 @@ func (h *menuButtonHandler) GetListAll(ctx *gin.Context) {
 		Filters: filters.Filters{
 			Creator:    creator,
 			Modifier:   modifier,
-			BelongDept: user.DeptId,
+			BelongDept: belongDept,
 		},
 		Status: status,
 		Name:   name,
@@ -495,5 +469,5 @@ func (h *menuButtonHandler) GetListAll(ctx *gin.Context) {
 		return
 	}
 
-	response.NewResponse().SuccessResponse(ctx, "查询成功", list)
+	response.NewResponse().SuccessResponse(ctx, "查询成功", columns.Strip(ctx, list))
 }
@@ -14,9 +14,11 @@ import (
 	domainSystem "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/system"
 	modelSystem "github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/system"
 	serviceSystem "github.com/carefuly/carefuly-admin-go-gin/internal/service/careful/system"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/columns"
 	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/filters"
 	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/response"
 	"github.com/carefuly/carefuly-admin-go-gin/pkg/models"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/requestUtils"
 	validate "github.com/carefuly/carefuly-admin-go-gin/pkg/validator"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -133,8 +135,8 @@ func (h *menuColumnHandler) Create(ctx *gin.Context) {
 		MenuColumn: modelSystem.MenuColumn{
 			CoreModels: models.CoreModels{
 				Sort:       req.Sort,
-				Creator:    uid,
-				Modifier:   uid,
+				Creator:    requestUtils.GetActorId(ctx),
+				Modifier:   requestUtils.GetActorId(ctx),
 				BelongDept: user.DeptId,
 				Remark:     req.Remark,
 			},
@@ -256,7 +258,7 @@ func (h *menuColumnHandler) Update(ctx *gin.Context) {
 				Id:         req.Id,
 				Sort:       req.Sort,
 				Version:    req.Version,
-				Modifier:   uid,
+				Modifier:   requestUtils.GetActorId(ctx),
 				BelongDept: user.DeptId,
 				Remark:     req.Remark,
 			},
@@ -311,7 +313,7 @@ func (h *menuColumnHandler) GetById(ctx *gin.Context) {
 		return
 	}
 
-	response.NewResponse().SuccessResponse(ctx, "获取成功", detail)
+	response.NewResponse().SuccessResponse(ctx, "获取成功", columns.Strip(ctx, detail))
 }
 
 // GetListPage
@@ -324,6 +326,7 @@ func (h *menuColumnHandler) GetById(ctx *gin.Context) {
 // @Param pageSize query int true "每页数量" default(10)
 // @Param creator query string false "创建人"
 // @Param modifier query string false "修改人"
+// @Param belongDept query string false "数据归属部门"
 // @Param status query bool false "状态" default(true)
 // @Param title query string false "标题"
 // @Param field query string false "字段名"
@@ -333,26 +336,11 @@ func (h *menuColumnHandler) GetById(ctx *gin.Context) {
 // @Router /v1/system/menuColumn/listPage [get]
 // @Security LoginToken
 func (h *menuColumnHandler) GetListPage(ctx *gin.Context) {
-	uid, ok := ctx.MustGet("userId").(string)
-	if !ok {
-		ctx.Set("internal", uid)
-		zap.S().Error("用户ID获取失败", uid)
-		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
-		return
-	}
-
-	user, err := h.userSvc.GetById(ctx, uid)
-	if err != nil {
-		ctx.Set("internal", err.Error())
-		zap.S().Error("获取用户失败", err.Error())
-		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
-		return
-	}
-
 	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("pageSize", "10"))
 	creator := ctx.DefaultQuery("creator", "")
 	modifier := ctx.DefaultQuery("modifier", "")
+	belongDept := ctx.DefaultQuery("belongDept", "")
 	status, _ := strconv.ParseBool(ctx.DefaultQuery("status", "true"))
 
 	title := ctx.DefaultQuery("title", "")
@@ -367,7 +355,7 @@ func (h *menuColumnHandler) GetListPage(ctx *gin.Context) {
 		Filters: filters.Filters{
 			Creator:    creator,
 			Modifier:   modifier,
-			BelongDept: user.DeptId,
+			BelongDept: belongDept,
 		},
 		Status: status,
 		Title:  title,
@@ -382,12 +370,12 @@ func (h *menuColumnHandler) GetListPage(ctx *gin.Context) {
 		return
 	}
 
-	response.NewResponse().SuccessResponse(ctx, "查询成功", MenuColumnListPageResponse{
+	response.NewResponse().SuccessResponse(ctx, "查询成功", columns.Strip(ctx, MenuColumnListPageResponse{
 		List:     list,
 		Total:    total,
 		Page:     page,
 		PageSize: pageSize,
-	})
+	}))
 }
 
 // GetListByMenuIds
@@ -416,7 +404,7 @@ func (h *menuColumnHandler) GetListByMenuIds(ctx *gin.Context) {
 		return
 	}
 
-	response.NewResponse().SuccessResponse(ctx, "查询成功", tree)
+	response.NewResponse().SuccessResponse(ctx, "查询成功", columns.Strip(ctx, tree))
 }
 
 // GetListAll
@@ -427,6 +415,7 @@ func (h *menuColumnHandler) GetListByMenuIds(ctx *gin.Context) {
 // @Produce application/json
 // @Param creator query string false "创建人"
 // @Param modifier query string false "修改人"
+// @Param belongDept query string false "数据归属部门"
 // @Param status query bool false "状态" default(true)
 // @Param title query string false "标题"
 // @Param field query string false "字段名"
@@ -436,24 +425,9 @@ func (h *menuColumnHandler) GetListByMenuIds(ctx *gin.Context) {
 // @Router /v1/system/menuColumn/listAll [get]
 // @Security LoginToken
 func (h *menuColumnHandler) GetListAll(ctx *gin.Context) {
-	uid, ok := ctx.MustGet("userId").(string)
-	if !ok {
-		ctx.Set("internal", uid)
-		zap.S().Error("用户ID获取失败", uid)
-		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
-		return
-	}
-
-	user, err := h.userSvc.GetById(ctx, uid)
-	if err != nil {
-		ctx.Set("internal", err.Error())
-		zap.S().Error("获取用户失败", err.Error())
-		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
-		return
-	}
-
 	creator := ctx.DefaultQuery("creator", "")
 	modifier := ctx.DefaultQuery("modifier", "")
+	belongDept := ctx.DefaultQuery("belongDept", "")
 	status, _ := strconv.ParseBool(ctx.DefaultQuery("status", "true"))
 
 	title := ctx.DefaultQuery("title", "")
@@ -464,7 +438,7 @@ func (h *menuColumnHandler) GetListAll(ctx *gin.Context) {
 		Filters: filters.Filters{
 			Creator:    creator,
 			Modifier:   modifier,
-			BelongDept: user.DeptId,
+			BelongDept: belongDept,
 		},
 		Status: status,
 		Title:  title,
@@ -479,5 +453,5 @@ func (h *menuColumnHandler) GetListAll(ctx *gin.Context) {
 		return
 	}
 
-	response.NewResponse().SuccessResponse(ctx, "查询成功", list)
+	response.NewResponse().SuccessResponse(ctx, "查询成功", columns.Strip(ctx, list))
 }
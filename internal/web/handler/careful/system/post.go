@@ -15,10 +15,12 @@ import (
 	domainSystem "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/system"
 	modelSystem "github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/system"
 	serviceSystem "github.com/carefuly/carefuly-admin-go-gin/internal/service/careful/system"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/columns"
 	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/filters"
 	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/response"
 	"github.com/carefuly/carefuly-admin-go-gin/pkg/models"
 	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/excelutil"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/requestUtils"
 	validate "github.com/carefuly/carefuly-admin-go-gin/pkg/validator"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -133,8 +135,8 @@ func (h *postHandler) Create(ctx *gin.Context) {
 		Post: modelSystem.Post{
 			CoreModels: models.CoreModels{
 				Sort:       req.Sort,
-				Creator:    uid,
-				Modifier:   uid,
+				Creator:    requestUtils.GetActorId(ctx),
+				Modifier:   requestUtils.GetActorId(ctx),
 				BelongDept: user.DeptId,
 				Remark:     req.Remark,
 			},
@@ -263,7 +265,7 @@ func (h *postHandler) Update(ctx *gin.Context) {
 				Id:         req.Id,
 				Sort:       req.Sort,
 				Version:    req.Version,
-				Modifier:   uid,
+				Modifier:   requestUtils.GetActorId(ctx),
 				BelongDept: user.DeptId,
 				Remark:     req.Remark,
 			},
@@ -320,7 +322,7 @@ func (h *postHandler) GetById(ctx *gin.Context) {
 		return
 	}
 
-	response.NewResponse().SuccessResponse(ctx, "获取成功", detail)
+	response.NewResponse().SuccessResponse(ctx, "获取成功", columns.Strip(ctx, detail))
 }
 
 // GetListPage
@@ -333,6 +335,7 @@ func (h *postHandler) GetById(ctx *gin.Context) {
 // @Param pageSize query int true "每页数量" default(10)
 // @Param creator query string false "创建人"
 // @Param modifier query string false "修改人"
+// @Param belongDept query string false "数据归属部门"
 // @Param status query bool false "状态" default(true)
 // @Param name query string false "岗位名称"
 // @Param code query string false "岗位编码"
@@ -341,26 +344,11 @@ func (h *postHandler) GetById(ctx *gin.Context) {
 // @Router /v1/system/post/listPage [get]
 // @Security LoginToken
 func (h *postHandler) GetListPage(ctx *gin.Context) {
-	uid, ok := ctx.MustGet("userId").(string)
-	if !ok {
-		ctx.Set("internal", uid)
-		zap.S().Error("用户ID获取失败", uid)
-		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
-		return
-	}
-
-	user, err := h.userSvc.GetById(ctx, uid)
-	if err != nil {
-		ctx.Set("internal", err.Error())
-		zap.S().Error("获取用户失败", err.Error())
-		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
-		return
-	}
-
 	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("pageSize", "10"))
 	creator := ctx.DefaultQuery("creator", "")
 	modifier := ctx.DefaultQuery("modifier", "")
+	belongDept := ctx.DefaultQuery("belongDept", "")
 	status, _ := strconv.ParseBool(ctx.DefaultQuery("status", "true"))
 
 	name := ctx.DefaultQuery("name", "")
@@ -374,7 +362,7 @@ func (h *postHandler) GetListPage(ctx *gin.Context) {
 		Filters: filters.Filters{
 			Creator:    creator,
 			Modifier:   modifier,
-			BelongDept: user.DeptId,
+			BelongDept: belongDept,
 		},
 		Status: status,
 		Name:   name,
@@ -388,12 +376,12 @@ func (h *postHandler) GetListPage(ctx *gin.Context) {
 		return
 	}
 
-	response.NewResponse().SuccessResponse(ctx, "查询成功", PostListPageResponse{
+	response.NewResponse().SuccessResponse(ctx, "查询成功", columns.Strip(ctx, PostListPageResponse{
 		List:     list,
 		Total:    total,
 		Page:     page,
 		PageSize: pageSize,
-	})
+	}))
 }
 
 // GetListAll
@@ -404,6 +392,7 @@ func (h *postHandler) GetListPage(ctx *gin.Context) {
 // @Produce application/json
 // @Param creator query string false "创建人"
 // @Param modifier query string false "修改人"
+// @Param belongDept query string false "数据归属部门"
 // @Param status query bool false "状态" default(true)
 // @Param name query string false "岗位名称"
 // @Param code query string false "岗位编码"
@@ -412,24 +401,9 @@ func (h *postHandler) GetListPage(ctx *gin.Context) {
 // @Router /v1/system/post/listAll [get]
 // @Security LoginToken
 func (h *postHandler) GetListAll(ctx *gin.Context) {
-	uid, ok := ctx.MustGet("userId").(string)
-	if !ok {
-		ctx.Set("internal", uid)
-		zap.S().Error("用户ID获取失败", uid)
-		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
-		return
-	}
-
-	user, err := h.userSvc.GetById(ctx, uid)
-	if err != nil {
-		ctx.Set("internal", err.Error())
-		zap.S().Error("获取用户失败", err.Error())
-		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
-		return
-	}
-
 	creator := ctx.DefaultQuery("creator", "")
 	modifier := ctx.DefaultQuery("modifier", "")
+	belongDept := ctx.DefaultQuery("belongDept", "")
 	status, _ := strconv.ParseBool(ctx.DefaultQuery("status", "true"))
 
 	name := ctx.DefaultQuery("name", "")
@@ -439,7 +413,7 @@ func (h *postHandler) GetListAll(ctx *gin.Context) {
 		Filters: filters.Filters{
 			Creator:    creator,
 			Modifier:   modifier,
-			BelongDept: user.DeptId,
+			BelongDept: belongDept,
 		},
 		Status: status,
 		Name:   name,
@@ -453,7 +427,7 @@ func (h *postHandler) GetListAll(ctx *gin.Context) {
 		return
 	}
 
-	response.NewResponse().SuccessResponse(ctx, "查询成功", list)
+	response.NewResponse().SuccessResponse(ctx, "查询成功", columns.Strip(ctx, list))
 }
 
 // Export
@@ -464,6 +438,7 @@ func (h *postHandler) GetListAll(ctx *gin.Context) {
 // @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
 // @Param creator query string false "创建人"
 // @Param modifier query string false "修改人"
+// @Param belongDept query string false "数据归属部门"
 // @Param status query bool false "状态" default(true)
 // @Param name query string false "岗位名称"
 // @Param code query string false "岗位编码"
@@ -472,24 +447,9 @@ func (h *postHandler) GetListAll(ctx *gin.Context) {
 // @Router /v1/system/post/export [get]
 // @Security LoginToken
 func (h *postHandler) Export(ctx *gin.Context) {
-	uid, ok := ctx.MustGet("userId").(string)
-	if !ok {
-		ctx.Set("internal", uid)
-		zap.S().Error("用户ID获取失败", uid)
-		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
-		return
-	}
-
-	user, err := h.userSvc.GetById(ctx, uid)
-	if err != nil {
-		ctx.Set("internal", err.Error())
-		zap.S().Error("获取用户失败", err.Error())
-		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
-		return
-	}
-
 	creator := ctx.DefaultQuery("creator", "")
 	modifier := ctx.DefaultQuery("modifier", "")
+	belongDept := ctx.DefaultQuery("belongDept", "")
 	status, _ := strconv.ParseBool(ctx.DefaultQuery("status", "true"))
 
 	name := ctx.DefaultQuery("name", "")
@@ -499,7 +459,7 @@ func (h *postHandler) Export(ctx *gin.Context) {
 		Filters: filters.Filters{
 			Creator:    creator,
 			Modifier:   modifier,
-			BelongDept: user.DeptId,
+			BelongDept: belongDept,
 		},
 		Status: status,
 		Name:   name,
@@ -519,14 +479,14 @@ func (h *postHandler) Export(ctx *gin.Context) {
 		SheetName:  "岗位",
 		FileName:   filename,
 		StreamMode: true,
-		Columns: []excelutil.ExcelColumn{
+		Columns: columns.ExcelColumns(ctx, []excelutil.ExcelColumn{
 			{Title: "岗位名称", Field: "Name", Width: 22},
 			{Title: "岗位编码", Field: "Code", Width: 17},
 			{Title: "排序", Field: "Sort", Width: 8},
 			{Title: "创建时间", Field: "CreateTime", Width: 22},
 			{Title: "更新时间", Field: "UpdateTime", Width: 22},
 			{Title: "备注", Field: "Remark", Width: 40},
-		},
+		}),
 		Data: list,
 	}
 
@@ -16,10 +16,12 @@ import (
 	modelSystem "github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/system"
 	serviceSystem "github.com/carefuly/carefuly-admin-go-gin/internal/service/careful/system"
 	"github.com/carefuly/carefuly-admin-go-gin/pkg/constants/careful/system/role"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/columns"
 	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/filters"
 	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/response"
 	"github.com/carefuly/carefuly-admin-go-gin/pkg/models"
 	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/excelutil"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/requestUtils"
 	validate "github.com/carefuly/carefuly-admin-go-gin/pkg/validator"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -35,6 +37,8 @@ type CreateRoleRequest struct {
 	Sort   int    `json:"sort" binding:"omitempty" default:"1"`      // 排序
 	Status bool   `json:"status" binding:"omitempty" default:"true"` // 状态【true-启用 false-停用】
 	Remark string `json:"remark" binding:"omitempty,max=255"`        // 备注
+	// RequireTwoFA 是否要求该角色的用户启用双因素认证
+	RequireTwoFA bool `json:"require_two_fa" binding:"omitempty"`
 }
 
 // UpdateRoleRequest 更新
@@ -51,6 +55,7 @@ type UpdateRoleRequest struct {
 	Status        bool                `json:"status" binding:"omitempty" default:"true"` // 状态【true-启用 false-停用】
 	Version       int                 `json:"version" binding:"omitempty"`               // 版本
 	Remark        string              `json:"remark" binding:"omitempty,max=255"`        // 备注
+	RequireTwoFA  bool                `json:"require_two_fa" binding:"omitempty"`        // 是否要求双因素认证
 }
 
 // RoleListPageResponse 列表分页响应
@@ -139,14 +144,15 @@ func (h *roleHandler) Create(ctx *gin.Context) {
 		Role: modelSystem.Role{
 			CoreModels: models.CoreModels{
 				Sort:       req.Sort,
-				Creator:    uid,
-				Modifier:   uid,
+				Creator:    requestUtils.GetActorId(ctx),
+				Modifier:   requestUtils.GetActorId(ctx),
 				BelongDept: user.DeptId,
 				Remark:     req.Remark,
 			},
-			Status: req.Status,
-			Name:   req.Name,
-			Code:   req.Code,
+			Status:       req.Status,
+			Name:         req.Name,
+			Code:         req.Code,
+			RequireTwoFA: req.RequireTwoFA,
 		},
 	}
 
@@ -272,7 +278,7 @@ func (h *roleHandler) Update(ctx *gin.Context) {
 				Id:         req.Id,
 				Sort:       req.Sort,
 				Version:    req.Version,
-				Modifier:   uid,
+				Modifier:   requestUtils.GetActorId(ctx),
 				BelongDept: user.DeptId,
 				Remark:     req.Remark,
 			},
@@ -280,6 +286,7 @@ func (h *roleHandler) Update(ctx *gin.Context) {
 			Name:          req.Name,
 			Code:          req.Code,
 			DataRange:     req.DataRange,
+			RequireTwoFA:  req.RequireTwoFA,
 			DeptIDs:       req.DeptIDs,
 			MenuIDs:       req.MenuIDs,
 			MenuButtonIDs: req.MenuButtonIDs,
@@ -334,7 +341,7 @@ func (h *roleHandler) GetById(ctx *gin.Context) {
 		return
 	}
 
-	response.NewResponse().SuccessResponse(ctx, "获取成功", detail)
+	response.NewResponse().SuccessResponse(ctx, "获取成功", columns.Strip(ctx, detail))
 }
 
 // GetListPage
@@ -347,6 +354,7 @@ func (h *roleHandler) GetById(ctx *gin.Context) {
 // @Param pageSize query int true "每页数量" default(10)
 // @Param creator query string false "创建人"
 // @Param modifier query string false "修改人"
+// @Param belongDept query string false "数据归属部门"
 // @Param status query bool false "状态" default(true)
 // @Param name query string false "角色名称"
 // @Param code query string false "角色编码"
@@ -355,26 +363,11 @@ func (h *roleHandler) GetById(ctx *gin.Context) {
 // @Router /v1/system/role/listPage [get]
 // @Security LoginToken
 func (h *roleHandler) GetListPage(ctx *gin.Context) {
-	uid, ok := ctx.MustGet("userId").(string)
-	if !ok {
-		ctx.Set("internal", uid)
-		zap.S().Error("用户ID获取失败", uid)
-		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
-		return
-	}
-
-	user, err := h.userSvc.GetById(ctx, uid)
-	if err != nil {
-		ctx.Set("internal", err.Error())
-		zap.S().Error("获取用户失败", err.Error())
-		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
-		return
-	}
-
 	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("pageSize", "10"))
 	creator := ctx.DefaultQuery("creator", "")
 	modifier := ctx.DefaultQuery("modifier", "")
+	belongDept := ctx.DefaultQuery("belongDept", "")
 	status, _ := strconv.ParseBool(ctx.DefaultQuery("status", "true"))
 
 	name := ctx.DefaultQuery("name", "")
@@ -388,7 +381,7 @@ func (h *roleHandler) GetListPage(ctx *gin.Context) {
 		Filters: filters.Filters{
 			Creator:    creator,
 			Modifier:   modifier,
-			BelongDept: user.DeptId,
+			BelongDept: belongDept,
 		},
 		Status: status,
 		Name:   name,
@@ -402,12 +395,12 @@ func (h *roleHandler) GetListPage(ctx *gin.Context) {
 		return
 	}
 
-	response.NewResponse().SuccessResponse(ctx, "查询成功", RoleListPageResponse{
+	response.NewResponse().SuccessResponse(ctx, "查询成功", columns.Strip(ctx, RoleListPageResponse{
 		List:     list,
 		Total:    total,
 		Page:     page,
 		PageSize: pageSize,
-	})
+	}))
 }
 
 // GetListAll
@@ -418,6 +411,7 @@ func (h *roleHandler) GetListPage(ctx *gin.Context) {
 // @Produce application/json
 // @Param creator query string false "创建人"
 // @Param modifier query string false "修改人"
+// @Param belongDept query string false "数据归属部门"
 // @Param status query bool false "状态" default(true)
 // @Param name query string false "角色名称"
 // @Param code query string false "角色编码"
@@ -426,24 +420,9 @@ func (h *roleHandler) GetListPage(ctx *gin.Context) {
 // @Router /v1/system/role/listAll [get]
 // @Security LoginToken
 func (h *roleHandler) GetListAll(ctx *gin.Context) {
-	uid, ok := ctx.MustGet("userId").(string)
-	if !ok {
-		ctx.Set("internal", uid)
-		zap.S().Error("用户ID获取失败", uid)
-		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
-		return
-	}
-
-	user, err := h.userSvc.GetById(ctx, uid)
-	if err != nil {
-		ctx.Set("internal", err.Error())
-		zap.S().Error("获取用户失败", err.Error())
-		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
-		return
-	}
-
 	creator := ctx.DefaultQuery("creator", "")
 	modifier := ctx.DefaultQuery("modifier", "")
+	belongDept := ctx.DefaultQuery("belongDept", "")
 	status, _ := strconv.ParseBool(ctx.DefaultQuery("status", "true"))
 
 	name := ctx.DefaultQuery("name", "")
@@ -453,7 +432,7 @@ func (h *roleHandler) GetListAll(ctx *gin.Context) {
 		Filters: filters.Filters{
 			Creator:    creator,
 			Modifier:   modifier,
-			BelongDept: user.DeptId,
+			BelongDept: belongDept,
 		},
 		Status: status,
 		Name:   name,
@@ -467,7 +446,7 @@ func (h *roleHandler) GetListAll(ctx *gin.Context) {
 		return
 	}
 
-	response.NewResponse().SuccessResponse(ctx, "查询成功", list)
+	response.NewResponse().SuccessResponse(ctx, "查询成功", columns.Strip(ctx, list))
 }
 
 // Export
@@ -478,6 +457,7 @@ func (h *roleHandler) GetListAll(ctx *gin.Context) {
 // @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
 // @Param creator query string false "创建人"
 // @Param modifier query string false "修改人"
+// @Param belongDept query string false "数据归属部门"
 // @Param status query bool false "状态" default(true)
 // @Param name query string false "角色名称"
 // @Param code query string false "角色编码"
@@ -486,24 +466,9 @@ func (h *roleHandler) GetListAll(ctx *gin.Context) {
 // @Router /v1/system/role/export [get]
 // @Security LoginToken
 func (h *roleHandler) Export(ctx *gin.Context) {
-	uid, ok := ctx.MustGet("userId").(string)
-	if !ok {
-		ctx.Set("internal", uid)
-		zap.S().Error("用户ID获取失败", uid)
-		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
-		return
-	}
-
-	user, err := h.userSvc.GetById(ctx, uid)
-	if err != nil {
-		ctx.Set("internal", err.Error())
-		zap.S().Error("获取用户失败", err.Error())
-		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
-		return
-	}
-
 	creator := ctx.DefaultQuery("creator", "")
 	modifier := ctx.DefaultQuery("modifier", "")
+	belongDept := ctx.DefaultQuery("belongDept", "")
 	status, _ := strconv.ParseBool(ctx.DefaultQuery("status", "true"))
 
 	name := ctx.DefaultQuery("name", "")
@@ -513,7 +478,7 @@ func (h *roleHandler) Export(ctx *gin.Context) {
 		Filters: filters.Filters{
 			Creator:    creator,
 			Modifier:   modifier,
-			BelongDept: user.DeptId,
+			BelongDept: belongDept,
 		},
 		Status: status,
 		Name:   name,
@@ -533,14 +498,14 @@ func (h *roleHandler) Export(ctx *gin.Context) {
 		SheetName:  "角色",
 		FileName:   filename,
 		StreamMode: true,
-		Columns: []excelutil.ExcelColumn{
+		Columns: columns.ExcelColumns(ctx, []excelutil.ExcelColumn{
 			{Title: "角色名称", Field: "Name", Width: 22},
 			{Title: "角色编码", Field: "Code", Width: 17},
 			{Title: "排序", Field: "Sort", Width: 8},
 			{Title: "创建时间", Field: "CreateTime", Width: 22},
 			{Title: "更新时间", Field: "UpdateTime", Width: 22},
 			{Title: "备注", Field: "Remark", Width: 40},
-		},
+		}),
 		Data: list,
 	}
 
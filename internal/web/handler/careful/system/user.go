@@ -9,17 +9,92 @@
 package system
 
 import (
+	"errors"
 	config "github.com/carefuly/carefuly-admin-go-gin/config/file"
 	domainSystem "github.com/carefuly/carefuly-admin-go-gin/internal/domain/careful/system"
+	modelSystem "github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/system"
 	serviceSystem "github.com/carefuly/carefuly-admin-go-gin/internal/service/careful/system"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/constants/careful/system/user"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/columns"
 	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/filters"
 	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/response"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/models"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/requestUtils"
+	validate "github.com/carefuly/carefuly-admin-go-gin/pkg/validator"
 	"github.com/gin-gonic/gin"
+	"github.com/mssola/user_agent"
 	"go.uber.org/zap"
 	"net/http"
 	"strconv"
 )
 
+// CreateUserRequest 创建
+type CreateUserRequest struct {
+	Username string           `json:"username" binding:"required,min=3,max=20"`  // 用户名
+	Password string           `json:"password" binding:"required,max=72"`        // 密码，复杂度由密码策略校验
+	UserType user.TypeConst   `json:"userType" binding:"omitempty,oneof=1 2"`    // 用户类型
+	Name     string           `json:"name" binding:"omitempty,max=50"`           // 姓名
+	Gender   user.GenderConst `json:"gender" binding:"omitempty,oneof=1 2 3"`    // 性别
+	Email    string           `json:"email" binding:"omitempty,email,max=50"`    // 邮箱
+	Mobile   string           `json:"mobile" binding:"omitempty,max=20"`         // 电话
+	Avatar   string           `json:"avatar" binding:"omitempty"`                // 头像
+	DeptId   string           `json:"dept_id" binding:"omitempty,max=100"`       // 部门ID
+	PostIDs  []string         `json:"post_ids" binding:"omitempty"`              // 岗位ID
+	RoleIDs  []string         `json:"role_ids" binding:"omitempty"`              // 角色ID
+	Sort     int              `json:"sort" binding:"omitempty" default:"1"`      // 排序
+	Status   bool             `json:"status" binding:"omitempty" default:"true"` // 状态【true-启用 false-停用】
+	Remark   string           `json:"remark" binding:"omitempty,max=255"`        // 备注
+}
+
+// UpdateUserRequest 更新
+type UpdateUserRequest struct {
+	Id       string           `json:"id" binding:"required"`                     // 主键ID
+	Username string           `json:"username" binding:"required,min=3,max=20"`  // 用户名
+	UserType user.TypeConst   `json:"userType" binding:"omitempty,oneof=1 2"`    // 用户类型
+	Name     string           `json:"name" binding:"omitempty,max=50"`           // 姓名
+	Gender   user.GenderConst `json:"gender" binding:"omitempty,oneof=1 2 3"`    // 性别
+	Email    string           `json:"email" binding:"omitempty,email,max=50"`    // 邮箱
+	Mobile   string           `json:"mobile" binding:"omitempty,max=20"`         // 电话
+	Avatar   string           `json:"avatar" binding:"omitempty"`                // 头像
+	DeptId   string           `json:"dept_id" binding:"omitempty,max=100"`       // 部门ID
+	PostIDs  []string         `json:"post_ids" binding:"omitempty"`              // 岗位ID
+	RoleIDs  []string         `json:"role_ids" binding:"omitempty"`              // 角色ID
+	Sort     int              `json:"sort" binding:"omitempty" default:"1"`      // 排序
+	Status   bool             `json:"status" binding:"omitempty" default:"true"` // 状态【true-启用 false-停用】
+	Version  int              `json:"version" binding:"omitempty"`               // 版本
+	Remark   string           `json:"remark" binding:"omitempty,max=255"`        // 备注
+}
+
+// UpdateUserStatusRequest 启用/停用
+type UpdateUserStatusRequest struct {
+	Id      string `json:"id" binding:"required"`       // 主键ID
+	Status  bool   `json:"status" binding:"omitempty"`  // 状态【true-启用 false-停用】
+	Version int    `json:"version" binding:"omitempty"` // 版本
+}
+
+// UnlockUserRequest 解锁
+type UnlockUserRequest struct {
+	Id string `json:"id" binding:"required"`     // 主键ID
+	Ip string `json:"ip" binding:"omitempty,ip"` // 同时解锁的IP
+}
+
+// ResetTwoFactorRequest 重置双因素认证
+type ResetTwoFactorRequest struct {
+	Id string `json:"id" binding:"required"` // 主键ID
+}
+
+// ImpersonateUserRequest 模拟登录
+type ImpersonateUserRequest struct {
+	Id      string `json:"id" binding:"required"`                      // 被模拟的用户ID
+	Minutes int    `json:"minutes" binding:"omitempty,min=1,max=1440"` // 有效期(分钟)，不超过配置的最长有效期
+}
+
+// ResetPasswordRequest 重置密码
+type ResetPasswordRequest struct {
+	Id       string `json:"id" binding:"required"`              // 主键ID
+	Password string `json:"password" binding:"required,max=72"` // 新密码，复杂度由密码策略校验
+}
+
 // UserListPageResponse 用户列表分页响应
 type UserListPageResponse struct {
 	List     []domainSystem.User `json:"list"`     // 列表
@@ -30,28 +105,616 @@ type UserListPageResponse struct {
 
 type UserHandler interface {
 	RegisterRoutes(router *gin.RouterGroup)
-
+	Create(ctx *gin.Context)
+	Delete(ctx *gin.Context)
+	BatchDelete(ctx *gin.Context)
+	Update(ctx *gin.Context)
+	UpdateStatus(ctx *gin.Context)
+	ResetPassword(ctx *gin.Context)
+	Unlock(ctx *gin.Context)
+	ResetTwoFactor(ctx *gin.Context)
+	Impersonate(ctx *gin.Context)
+	GetById(ctx *gin.Context)
 	GetListPage(ctx *gin.Context)
 }
 
 type userHandler struct {
-	rely config.RelyConfig
-	svc  serviceSystem.UserService
+	rely             config.RelyConfig
+	svc              serviceSystem.UserService
+	loginGuardSvc    serviceSystem.LoginGuardService
+	twoFactorSvc     serviceSystem.TwoFactorService
+	impersonationSvc serviceSystem.ImpersonationService
+	sessionSvc       serviceSystem.SessionService
 }
 
-func NewUserHandler(rely config.RelyConfig, svc serviceSystem.UserService) UserHandler {
+func NewUserHandler(rely config.RelyConfig, svc serviceSystem.UserService, loginGuardSvc serviceSystem.LoginGuardService,
+	twoFactorSvc serviceSystem.TwoFactorService, impersonationSvc serviceSystem.ImpersonationService,
+	sessionSvc serviceSystem.SessionService) UserHandler {
 	return &userHandler{
-		rely: rely,
-		svc:  svc,
+		rely:             rely,
+		svc:              svc,
+		loginGuardSvc:    loginGuardSvc,
+		twoFactorSvc:     twoFactorSvc,
+		impersonationSvc: impersonationSvc,
+		sessionSvc:       sessionSvc,
 	}
 }
 
 // RegisterRoutes 注册路由
 func (h *userHandler) RegisterRoutes(router *gin.RouterGroup) {
 	base := router.Group("/user")
+	base.POST("/create", h.Create)
+	base.DELETE("/delete/:id", h.Delete)
+	base.POST("/delete/batchDelete", h.BatchDelete)
+	base.PUT("/update", h.Update)
+	base.PUT("/updateStatus", h.UpdateStatus)
+	base.PUT("/resetPassword", h.ResetPassword)
+	base.PUT("/unlock", h.Unlock)
+	base.PUT("/resetTwoFactor", h.ResetTwoFactor)
+	base.POST("/impersonate", h.Impersonate)
+	base.GET("/getById/:id", h.GetById)
 	base.GET("/listPage", h.GetListPage)
 }
 
+// Create
+// @Summary 创建用户
+// @Description 创建用户并分配角色、岗位
+// @Tags 系统管理/用户管理
+// @Accept application/json
+// @Produce application/json
+// @Param CreateUserRequest body CreateUserRequest true "请求"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Router /v1/system/user/create [post]
+// @Security LoginToken
+func (h *userHandler) Create(ctx *gin.Context) {
+	uid, ok := ctx.MustGet("userId").(string)
+	if !ok {
+		ctx.Set("internal", uid)
+		zap.S().Error("用户ID获取失败", uid)
+		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+		return
+	}
+
+	current, err := h.svc.GetById(ctx, uid)
+	if err != nil {
+		ctx.Set("internal", err.Error())
+		zap.S().Error("获取用户失败", err.Error())
+		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+		return
+	}
+
+	var req CreateUserRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validate.NewValidatorError(h.rely.Trans).HandleValidatorError(ctx, err)
+		return
+	}
+
+	userType := req.UserType
+	if userType == 0 {
+		userType = user.TypeConstAdminUser
+	}
+	gender := req.Gender
+	if gender == 0 {
+		gender = user.GenderConstSecret
+	}
+
+	// 转换为领域模型
+	domain := domainSystem.User{
+		User: modelSystem.User{
+			CoreModels: models.CoreModels{
+				Sort:       req.Sort,
+				Creator:    requestUtils.GetActorId(ctx),
+				Modifier:   requestUtils.GetActorId(ctx),
+				BelongDept: current.DeptId,
+				Remark:     req.Remark,
+			},
+			Status:   req.Status,
+			Username: req.Username,
+			Password: req.Password,
+			UserType: userType,
+			Name:     req.Name,
+			Gender:   gender,
+			Email:    req.Email,
+			Mobile:   req.Mobile,
+			Avatar:   req.Avatar,
+			DeptId:   req.DeptId,
+			PostIDs:  req.PostIDs,
+			RoleIDs:  req.RoleIDs,
+		},
+	}
+
+	if err := h.svc.Create(ctx, domain); err != nil {
+		switch {
+		case errors.Is(err, serviceSystem.ErrUsernameDuplicate):
+			response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "用户名已存在，请重新输入", nil)
+			return
+		case errors.Is(err, serviceSystem.ErrPasswordPolicy):
+			response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+			return
+		case errors.Is(err, serviceSystem.ErrUserRoleNotAssignable):
+			response.NewResponse().ErrorResponse(ctx, http.StatusForbidden, err.Error(), nil)
+			return
+		default:
+			ctx.Set("internal", err.Error())
+			zap.L().Error("创建用户失败", zap.Error(err))
+			response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+			return
+		}
+	}
+
+	response.NewResponse().SuccessResponse(ctx, "新增成功", nil)
+}
+
+// Delete
+// @Summary 删除用户
+// @Description 删除指定id用户
+// @Tags 系统管理/用户管理
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "id"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Router /v1/system/user/delete/{id} [delete]
+// @Security LoginToken
+func (h *userHandler) Delete(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if id == "" || len(id) == 0 {
+		response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "ID不能为空", nil)
+		return
+	}
+
+	if id == ctx.GetString("userId") {
+		response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "不能删除当前登录用户", nil)
+		return
+	}
+
+	if err := h.svc.Delete(ctx, id); err != nil {
+		switch {
+		case errors.Is(err, serviceSystem.ErrUserNotFound):
+			response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "用户不存在", nil)
+			return
+		default:
+			ctx.Set("internal", err.Error())
+			zap.L().Error("删除用户失败", zap.Error(err))
+			response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+			return
+		}
+	}
+
+	h.revokeSessions(ctx, id)
+	response.NewResponse().SuccessResponse(ctx, "删除成功", nil)
+}
+
+// BatchDelete
+// @Summary 批量删除用户
+// @Description 批量删除用户
+// @Tags 系统管理/用户管理
+// @Accept application/json
+// @Produce application/json
+// @Param ids body []string true "id数组"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Router /v1/system/user/delete/batchDelete [post]
+// @Security LoginToken
+func (h *userHandler) BatchDelete(ctx *gin.Context) {
+	var ids []string
+	if err := ctx.ShouldBindJSON(&ids); err != nil {
+		validate.NewValidatorError(h.rely.Trans).HandleValidatorError(ctx, err)
+		return
+	}
+
+	uid := ctx.GetString("userId")
+	for _, id := range ids {
+		if id == uid {
+			response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "不能删除当前登录用户", nil)
+			return
+		}
+	}
+
+	deleted, err := h.svc.BatchDelete(ctx, ids)
+	if err != nil {
+		ctx.Set("internal", err.Error())
+		zap.L().Error("批量删除用户异常", zap.Error(err))
+		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+		return
+	}
+
+	h.revokeSessions(ctx, deleted...)
+	response.NewResponse().SuccessResponse(ctx, "批量删除成功", nil)
+}
+
+// Update
+// @Summary 更新用户
+// @Description 更新用户信息及角色、岗位
+// @Tags 系统管理/用户管理
+// @Accept application/json
+// @Produce application/json
+// @Param UpdateUserRequest body UpdateUserRequest true "请求"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Router /v1/system/user/update [put]
+// @Security LoginToken
+func (h *userHandler) Update(ctx *gin.Context) {
+	uid, ok := ctx.MustGet("userId").(string)
+	if !ok {
+		ctx.Set("internal", uid)
+		zap.S().Error("用户ID获取失败", uid)
+		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+		return
+	}
+
+	var req UpdateUserRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validate.NewValidatorError(h.rely.Trans).HandleValidatorError(ctx, err)
+		return
+	}
+
+	if req.Id == uid && !req.Status {
+		response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "不能停用当前登录用户", nil)
+		return
+	}
+
+	userType := req.UserType
+	if userType == 0 {
+		userType = user.TypeConstAdminUser
+	}
+	gender := req.Gender
+	if gender == 0 {
+		gender = user.GenderConstSecret
+	}
+
+	// 转换为领域模型
+	domain := domainSystem.User{
+		User: modelSystem.User{
+			CoreModels: models.CoreModels{
+				Id:       req.Id,
+				Sort:     req.Sort,
+				Version:  req.Version,
+				Modifier: requestUtils.GetActorId(ctx),
+				Remark:   req.Remark,
+			},
+			Status:   req.Status,
+			Username: req.Username,
+			UserType: userType,
+			Name:     req.Name,
+			Gender:   gender,
+			Email:    req.Email,
+			Mobile:   req.Mobile,
+			Avatar:   req.Avatar,
+			DeptId:   req.DeptId,
+			PostIDs:  req.PostIDs,
+			RoleIDs:  req.RoleIDs,
+		},
+	}
+
+	if err := h.svc.Update(ctx, domain); err != nil {
+		switch {
+		case errors.Is(err, serviceSystem.ErrUsernameDuplicate):
+			response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "用户名已存在，请重新输入", nil)
+			return
+		case errors.Is(err, serviceSystem.ErrUserNotFound):
+			response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "用户不存在", nil)
+			return
+		case errors.Is(err, serviceSystem.ErrUserVersionInconsistency):
+			response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "数据版本不一致，取消修改，请刷新后重试", nil)
+			return
+		case errors.Is(err, serviceSystem.ErrUserRoleNotAssignable):
+			response.NewResponse().ErrorResponse(ctx, http.StatusForbidden, err.Error(), nil)
+			return
+		default:
+			ctx.Set("internal", err.Error())
+			zap.L().Error("更新用户失败", zap.Error(err))
+			response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+			return
+		}
+	}
+
+	response.NewResponse().SuccessResponse(ctx, "更新成功", nil)
+}
+
+// UpdateStatus
+// @Summary 启用/停用用户
+// @Description 启用或停用指定用户
+// @Tags 系统管理/用户管理
+// @Accept application/json
+// @Produce application/json
+// @Param UpdateUserStatusRequest body UpdateUserStatusRequest true "请求"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Router /v1/system/user/updateStatus [put]
+// @Security LoginToken
+func (h *userHandler) UpdateStatus(ctx *gin.Context) {
+	uid, ok := ctx.MustGet("userId").(string)
+	if !ok {
+		ctx.Set("internal", uid)
+		zap.S().Error("用户ID获取失败", uid)
+		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+		return
+	}
+
+	var req UpdateUserStatusRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validate.NewValidatorError(h.rely.Trans).HandleValidatorError(ctx, err)
+		return
+	}
+
+	if req.Id == uid && !req.Status {
+		response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "不能停用当前登录用户", nil)
+		return
+	}
+
+	domain := domainSystem.User{
+		User: modelSystem.User{
+			CoreModels: models.CoreModels{
+				Id:       req.Id,
+				Version:  req.Version,
+				Modifier: requestUtils.GetActorId(ctx),
+			},
+			Status: req.Status,
+		},
+	}
+
+	if err := h.svc.UpdateStatus(ctx, domain); err != nil {
+		switch {
+		case errors.Is(err, serviceSystem.ErrUserNotFound):
+			response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "用户不存在", nil)
+			return
+		case errors.Is(err, serviceSystem.ErrUserVersionInconsistency):
+			response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "数据版本不一致，取消修改，请刷新后重试", nil)
+			return
+		default:
+			ctx.Set("internal", err.Error())
+			zap.L().Error("更新用户状态失败", zap.Error(err))
+			response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+			return
+		}
+	}
+
+	// 令牌校验不读取用户状态，停用后吊销已登录的会话
+	if !req.Status {
+		h.revokeSessions(ctx, req.Id)
+	}
+	response.NewResponse().SuccessResponse(ctx, "更新成功", nil)
+}
+
+// ResetPassword
+// @Summary 重置用户密码
+// @Description 管理员重置指定用户密码
+// @Tags 系统管理/用户管理
+// @Accept application/json
+// @Produce application/json
+// @Param ResetPasswordRequest body ResetPasswordRequest true "请求"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Router /v1/system/user/resetPassword [put]
+// @Security LoginToken
+func (h *userHandler) ResetPassword(ctx *gin.Context) {
+	var req ResetPasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validate.NewValidatorError(h.rely.Trans).HandleValidatorError(ctx, err)
+		return
+	}
+
+	if err := h.svc.ResetPassword(ctx, req.Id, req.Password); err != nil {
+		switch {
+		case errors.Is(err, serviceSystem.ErrUserNotFound):
+			response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "用户不存在", nil)
+			return
+		case errors.Is(err, serviceSystem.ErrPasswordPolicy):
+			response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+			return
+		default:
+			ctx.Set("internal", err.Error())
+			zap.L().Error("重置密码失败", zap.Error(err))
+			response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+			return
+		}
+	}
+
+	h.revokeSessions(ctx, req.Id)
+	response.NewResponse().SuccessResponse(ctx, "重置成功", nil)
+}
+
+// Unlock
+// @Summary 解锁用户
+// @Description 解除用户因登录失败次数过多造成的锁定
+// @Tags 系统管理/用户管理
+// @Accept application/json
+// @Produce application/json
+// @Param UnlockUserRequest body UnlockUserRequest true "请求"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Router /v1/system/user/unlock [put]
+// @Security LoginToken
+func (h *userHandler) Unlock(ctx *gin.Context) {
+	uid, ok := ctx.MustGet("userId").(string)
+	if !ok {
+		ctx.Set("internal", uid)
+		zap.S().Error("用户ID获取失败", uid)
+		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+		return
+	}
+
+	var req UnlockUserRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validate.NewValidatorError(h.rely.Trans).HandleValidatorError(ctx, err)
+		return
+	}
+
+	detail, err := h.svc.GetById(ctx, req.Id)
+	if err != nil {
+		zap.L().Error("获取用户失败", zap.Error(err))
+		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+		return
+	}
+	if detail.Id == "" {
+		response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "用户不存在", nil)
+		return
+	}
+
+	if err := h.loginGuardSvc.Unlock(ctx, detail.Username, req.Ip, requestUtils.GetActorId(ctx), requestUtils.GetActorName(ctx)); err != nil {
+		ctx.Set("internal", err.Error())
+		zap.L().Error("解锁用户失败", zap.Error(err))
+		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+		return
+	}
+
+	response.NewResponse().SuccessResponse(ctx, "解锁成功", nil)
+}
+
+// ResetTwoFactor
+// @Summary 重置双因素认证
+// @Description 清除用户已绑定的认证器及恢复码，用于用户丢失设备时重新绑定
+// @Tags 系统管理/用户管理
+// @Accept application/json
+// @Produce application/json
+// @Param ResetTwoFactorRequest body ResetTwoFactorRequest true "请求"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Router /v1/system/user/resetTwoFactor [put]
+// @Security LoginToken
+func (h *userHandler) ResetTwoFactor(ctx *gin.Context) {
+	uid, ok := ctx.MustGet("userId").(string)
+	if !ok {
+		ctx.Set("internal", uid)
+		zap.S().Error("用户ID获取失败", uid)
+		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+		return
+	}
+
+	var req ResetTwoFactorRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validate.NewValidatorError(h.rely.Trans).HandleValidatorError(ctx, err)
+		return
+	}
+
+	detail, err := h.svc.GetById(ctx, req.Id)
+	if err != nil {
+		zap.L().Error("获取用户失败", zap.Error(err))
+		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+		return
+	}
+	if detail.Id == "" {
+		response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "用户不存在", nil)
+		return
+	}
+
+	if err := h.twoFactorSvc.Reset(ctx, detail, requestUtils.GetActorId(ctx), requestUtils.GetActorName(ctx)); err != nil {
+		ctx.Set("internal", err.Error())
+		zap.L().Error("重置双因素认证失败", zap.Error(err))
+		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+		return
+	}
+
+	response.NewResponse().SuccessResponse(ctx, "重置成功", nil)
+}
+
+// Impersonate
+// @Description 以目标用户的身份、角色及数据权限签发短期令牌，用于排查用户所见问题，期间的操作同时记录实际操作人；非超级管理员只能模拟角色均为自身所拥有角色的用户
+// @Description 以目标用户的身份、角色及数据权限签发短期令牌，用于排查用户所见问题，期间的操作同时记录实际操作人
+// @Tags 系统管理/用户管理
+// @Accept application/json
+// @Produce application/json
+// @Param ImpersonateUserRequest body ImpersonateUserRequest true "请求"
+// @Success 200 {object} domainSystem.Impersonation
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Router /v1/system/user/impersonate [post]
+// @Security LoginToken
+func (h *userHandler) Impersonate(ctx *gin.Context) {
+	uid, ok := ctx.MustGet("userId").(string)
+	if !ok {
+		ctx.Set("internal", uid)
+		zap.S().Error("用户ID获取失败", uid)
+		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+		return
+	}
+
+	var req ImpersonateUserRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validate.NewValidatorError(h.rely.Trans).HandleValidatorError(ctx, err)
+		return
+	}
+
+	// 模拟登录期间不允许再次发起模拟
+	if requestUtils.IsImpersonating(ctx) {
+		response.NewResponse().ErrorResponse(ctx, http.StatusForbidden, "模拟登录期间不能再次模拟其他用户", nil)
+		return
+	}
+
+	userAgent := ctx.Request.UserAgent()
+	ua := user_agent.New(userAgent)
+	browserName, _ := ua.Browser()
+	if len(userAgent) > 255 {
+		userAgent = userAgent[:255]
+	}
+	client := domainSystem.Session{
+		RequestIp:      requestUtils.NormalizeIP(ctx),
+		RequestOs:      ua.OS(),
+		RequestBrowser: browserName,
+		UserAgent:      userAgent,
+	}
+
+	result, err := h.impersonationSvc.Start(ctx, uid, requestUtils.GetRequestUser(ctx), req.Id, req.Minutes, client)
+	if err != nil {
+		switch {
+		case errors.Is(err, serviceSystem.ErrImpersonateForbidden),
+			errors.Is(err, serviceSystem.ErrImpersonateRole):
+			response.NewResponse().ErrorResponse(ctx, http.StatusForbidden, err.Error(), nil)
+		case errors.Is(err, serviceSystem.ErrUserNotFound):
+			response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "用户不存在", nil)
+		case errors.Is(err, serviceSystem.ErrImpersonateSelf),
+			errors.Is(err, serviceSystem.ErrImpersonateDisabled),
+			errors.Is(err, serviceSystem.ErrImpersonateAdmin):
+			response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		default:
+			ctx.Set("internal", err.Error())
+			zap.L().Error("模拟登录失败", zap.String("userId", req.Id), zap.Error(err))
+			response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+		}
+		return
+	}
+
+	response.NewResponse().SuccessResponse(ctx, "模拟登录成功", result)
+}
+
+// GetById
+// @Summary 获取用户
+// @Description 获取指定id用户信息
+// @Tags 系统管理/用户管理
+// @Accept application/json
+// @Produce application/json
+// @Param id path string true "id"
+// @Success 200 {object} domainSystem.User
+// @Failure 400 {object} response.Response
+// @Router /v1/system/user/getById/{id} [get]
+// @Security LoginToken
+func (h *userHandler) GetById(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if id == "" || len(id) == 0 {
+		response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "ID不能为空", nil)
+		return
+	}
+
+	detail, err := h.svc.GetById(ctx, id)
+	if err != nil {
+		if errors.Is(err, serviceSystem.ErrUserNotFound) {
+			response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "用户不存在", nil)
+			return
+		}
+		zap.L().Error("获取用户失败", zap.Error(err))
+		response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+		return
+	}
+	if detail.Id == "" {
+		response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "用户不存在", nil)
+		return
+	}
+
+	response.NewResponse().SuccessResponse(ctx, "获取成功", columns.Strip(ctx, detail))
+}
+
 // GetListPage
 // @Summary 获取用户分页列表
 // @Description 获取用户分页列表
@@ -98,10 +761,19 @@ func (h *userHandler) GetListPage(ctx *gin.Context) {
 		return
 	}
 
-	response.NewResponse().SuccessResponse(ctx, "查询成功", UserListPageResponse{
+	response.NewResponse().SuccessResponse(ctx, "查询成功", columns.Strip(ctx, UserListPageResponse{
 		List:     list,
 		Total:    total,
 		Page:     page,
 		PageSize: pageSize,
-	})
+	}))
+}
+
+// revokeSessions 吊销用户已登录的会话及刷新令牌族，失败时仅记录日志
+func (h *userHandler) revokeSessions(ctx *gin.Context, userIds ...string) {
+	for _, userId := range userIds {
+		if err := h.sessionSvc.RevokeByUserId(ctx, userId); err != nil {
+			zap.L().Error("吊销用户会话失败", zap.String("userId", userId), zap.Error(err))
+		}
+	}
 }
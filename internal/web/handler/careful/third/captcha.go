@@ -43,9 +43,8 @@ type CaptchaRequest struct {
 }
 
 type CaptchaResponse struct {
-	Id   string `json:"id"`   // 验证码Id
-	Img  string `json:"img"`  // 验证码图片
-	Code string `json:"code"` // 验证码
+	Id  string `json:"id"`  // 验证码Id
+	Img string `json:"img"` // 验证码图片
 }
 
 func (h *captchaHandler) RegisterRoutes(router *gin.RouterGroup) {
@@ -69,16 +68,14 @@ func (h *captchaHandler) GenerateCaptchaHandler(ctx *gin.Context) {
 		return
 	}
 
-	id, b64s, code, err := h.svc.Generate(ctx, req.Type, req.BizType)
-	// 不管成功还是失败, 控制台都要返回验证码
-	zap.L().Info("当前生成的验证码", zap.String("id", id), zap.String("code", code))
+	// 验证码答案仅保存在服务端，不返回也不记录日志
+	id, b64s, _, err := h.svc.Generate(ctx, req.Type, req.BizType)
 
 	switch {
 	case err == nil:
 		response.NewResponse().SuccessResponse(ctx, "验证码生成成功", CaptchaResponse{
-			Id:   id,
-			Img:  b64s,
-			Code: code,
+			Id:  id,
+			Img: b64s,
 		})
 	case errors.Is(err, third.ErrCaptchaSendTooMany):
 		response.NewResponse().ErrorResponse(ctx, http.StatusBadRequest, "验证码发送太频繁，请稍后再试", nil)
@@ -11,6 +11,7 @@ package middleware
 import (
 	"errors"
 	config "github.com/carefuly/carefuly-admin-go-gin/config/file"
+	serviceSystem "github.com/carefuly/carefuly-admin-go-gin/internal/service/careful/system"
 	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/response"
 	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/jwt"
 	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/requestUtils"
@@ -21,14 +22,25 @@ import (
 )
 
 var (
-	UnauthorizedNotFound = "请求未携带token，无权限访问"
-	UnauthorizedInvalid  = "无效的Token"
+	UnauthorizedNotFound    = "请求未携带token，无权限访问"
+	UnauthorizedInvalid     = "无效的Token"
+	ForbiddenPasswordChange = "请先修改密码"
+	ForbiddenTwoFactorSetup = "请先启用双因素认证"
+)
+
+const (
+	ApiKeyIdKey     = "apiKeyId"     // 上下文中 API Key 的ID
+	ApiKeyScopesKey = "apiKeyScopes" // 上下文中 API Key 的授权范围
 )
 
 // LoginJWTMiddlewareBuilder JWT 登录校验
 type LoginJWTMiddlewareBuilder struct {
-	paths []string
-	rely  config.RelyConfig
+	paths          []string
+	passwordPaths  []string
+	twoFactorPaths []string
+	rely           config.RelyConfig
+	apiKeySvc      serviceSystem.ApiKeyService
+	bindingSvc     serviceSystem.TokenBindingService
 }
 
 func NewLoginJWTMiddlewareBuilder(rely config.RelyConfig) *LoginJWTMiddlewareBuilder {
@@ -42,6 +54,30 @@ func (l *LoginJWTMiddlewareBuilder) IgnorePaths(path string) *LoginJWTMiddleware
 	return l
 }
 
+// PasswordChangePaths 需先修改密码的受限令牌可访问的接口
+func (l *LoginJWTMiddlewareBuilder) PasswordChangePaths(path string) *LoginJWTMiddlewareBuilder {
+	l.passwordPaths = append(l.passwordPaths, path)
+	return l
+}
+
+// TwoFactorSetupPaths 需先启用双因素认证的受限令牌可访问的接口
+func (l *LoginJWTMiddlewareBuilder) TwoFactorSetupPaths(path string) *LoginJWTMiddlewareBuilder {
+	l.twoFactorPaths = append(l.twoFactorPaths, path)
+	return l
+}
+
+// ApiKey 允许使用 Authorization: ApiKey <key> 认证
+func (l *LoginJWTMiddlewareBuilder) ApiKey(svc serviceSystem.ApiKeyService) *LoginJWTMiddlewareBuilder {
+	l.apiKeySvc = svc
+	return l
+}
+
+// Binding 校验令牌使用环境与签发时是否一致
+func (l *LoginJWTMiddlewareBuilder) Binding(svc serviceSystem.TokenBindingService) *LoginJWTMiddlewareBuilder {
+	l.bindingSvc = svc
+	return l
+}
+
 // FailedWithStatus 响应失败并设置HTTP状态码
 func (l *LoginJWTMiddlewareBuilder) FailedWithStatus(ctx *gin.Context, httpStatus, code int, msg string) {
 	ctx.JSON(httpStatus, gin.H{
@@ -88,6 +124,12 @@ func (l *LoginJWTMiddlewareBuilder) Build() gin.HandlerFunc {
 			return
 		}
 
+		// 个人访问令牌
+		if seg[0] == serviceSystem.ApiKeyScheme && l.apiKeySvc != nil {
+			l.authenticateApiKey(ctx, seg[1])
+			return
+		}
+
 		tokenStr := seg[1]
 
 		// 检查token是否在黑名单中
@@ -107,7 +149,7 @@ func (l *LoginJWTMiddlewareBuilder) Build() gin.HandlerFunc {
 		}
 
 		// 解析token
-		claims, err := jwt.ParseToken(tokenStr, l.rely.Token.Secret)
+		claims, err := jwt.ParseToken(tokenStr, l.rely.Keys)
 		if err != nil {
 			switch {
 			case errors.Is(err, jwt.ErrExpiredToken):
@@ -121,6 +163,52 @@ func (l *LoginJWTMiddlewareBuilder) Build() gin.HandlerFunc {
 			return
 		}
 
+		// 检查会话是否已被吊销(强制下线、单会话模式)
+		if claims.ID != "" {
+			revoked, err := tokenBlacklist.IsJtiBlacklisted(ctx, claims.ID)
+			if err != nil {
+				zap.L().Error("检查会话黑名单失败", zap.Error(err))
+				response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器内部错误", nil)
+				ctx.Abort()
+				return
+			}
+			if revoked {
+				response.NewResponse().ErrorResponse(ctx, http.StatusUnauthorized, "会话已下线，请重新登录", nil)
+				ctx.Abort()
+				return
+			}
+		}
+
+		// 检查令牌是否在签发时的客户端上使用
+		if l.bindingSvc != nil && l.bindingSvc.Enabled() {
+			err := l.bindingSvc.Check(ctx, serviceSystem.TokenBinding{
+				Jti:       claims.ID,
+				UserId:    claims.UserId,
+				Username:  claims.Username,
+				UserAgent: claims.UserAgent,
+				Ip:        claims.Ip,
+			}, ctx.Request.UserAgent(), requestUtils.NormalizeIP(ctx))
+			if errors.Is(err, serviceSystem.ErrTokenBindingMismatch) {
+				response.NewResponse().ErrorResponse(ctx, http.StatusUnauthorized, "登录环境发生变化，请重新登录", nil)
+				ctx.Abort()
+				return
+			}
+		}
+
+		// 需先修改密码的受限令牌仅可访问修改密码等接口
+		if claims.PasswordChange && !l.passwordChangeAllowed(ctx.Request.URL.Path) {
+			response.NewResponse().ErrorResponse(ctx, http.StatusForbidden, ForbiddenPasswordChange, nil)
+			ctx.Abort()
+			return
+		}
+
+		// 需先启用双因素认证的受限令牌仅可访问绑定双因素认证等接口
+		if claims.TwoFactorSetup && !l.twoFactorSetupAllowed(ctx.Request.URL.Path) {
+			response.NewResponse().ErrorResponse(ctx, http.StatusForbidden, ForbiddenTwoFactorSetup, nil)
+			ctx.Abort()
+			return
+		}
+
 		// gin.Context.Set() 方法将数据存储到上下文，可以在后续的中间件或处理程序中访问。
 		// 通过 gin.Context.Get() 方法获取存储在上下文中的数据。
 		// 通过 gin.Context.Set() 方法存储数据时，需要指定一个键，以便在后续的中间件或处理程序中访问该数据。
@@ -131,15 +219,56 @@ func (l *LoginJWTMiddlewareBuilder) Build() gin.HandlerFunc {
 		// 将用户信息存储到上下文
 
 		ctx.Set("claims", claims)
+		ctx.Set("jti", claims.ID)
 		ctx.Set("userId", claims.UserId)
 		ctx.Set("username", claims.Username)
 		ctx.Set("userType", claims.UserType)
 		ctx.Set("deptId", claims.DeptId)
+
+		// 模拟登录时记录实际操作人
+		if claims.Impersonating() {
+			ctx.Set("actorId", claims.ActorId)
+			ctx.Set("actorName", claims.ActorName)
+			ctx.Set("subjectId", claims.SubjectId)
+		}
+	}
+}
+
+// authenticateApiKey 校验个人访问令牌，并以令牌所属用户的身份写入上下文
+func (l *LoginJWTMiddlewareBuilder) authenticateApiKey(ctx *gin.Context, key string) {
+	ip := requestUtils.NormalizeIP(ctx)
+	apiKey, user, err := l.apiKeySvc.Authenticate(ctx, key, ip)
+	if err != nil {
+		switch {
+		case errors.Is(err, serviceSystem.ErrApiKeyExpired):
+			response.NewResponse().ErrorResponse(ctx, http.StatusUnauthorized, "API Key已过期", nil)
+		case errors.Is(err, serviceSystem.ErrApiKeyRevoked):
+			response.NewResponse().ErrorResponse(ctx, http.StatusUnauthorized, "API Key已吊销", nil)
+		case errors.Is(err, serviceSystem.ErrApiKeyUserDisabled):
+			response.NewResponse().ErrorResponse(ctx, http.StatusUnauthorized, "用户已停用，请联系管理员", nil)
+		case errors.Is(err, serviceSystem.ErrApiKeyInvalid):
+			response.NewResponse().ErrorResponse(ctx, http.StatusUnauthorized, "无效的API Key", nil)
+		default:
+			zap.L().Error("API Key认证异常", zap.Error(err))
+			response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器内部错误", nil)
+		}
+		ctx.Abort()
+		return
 	}
+
+	ctx.Set("requestIp", ip)
+	ctx.Set("request", ctx.Request)
+
+	ctx.Set(ApiKeyIdKey, apiKey.Id)
+	ctx.Set(ApiKeyScopesKey, apiKey.Scopes)
+	ctx.Set("userId", user.Id)
+	ctx.Set("username", user.Username)
+	ctx.Set("userType", int(user.UserType))
+	ctx.Set("deptId", user.DeptId)
 }
 
 // JWTAuthMiddleware JWT认证中间件
-func (l *LoginJWTMiddlewareBuilder) JWTAuthMiddleware(tokenConfig config.TokenConfig) gin.HandlerFunc {
+func (l *LoginJWTMiddlewareBuilder) JWTAuthMiddleware(keys *jwt.KeySet) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		// 获取Authorization头
 		authHeader := ctx.GetHeader("Authorization")
@@ -158,7 +287,7 @@ func (l *LoginJWTMiddlewareBuilder) JWTAuthMiddleware(tokenConfig config.TokenCo
 		}
 
 		// 解析token
-		claims, err := jwt.ParseToken(parts[1], tokenConfig.Secret)
+		claims, err := jwt.ParseToken(parts[1], keys)
 		if err != nil {
 			switch {
 			case errors.Is(err, jwt.ErrExpiredToken):
@@ -180,6 +309,26 @@ func (l *LoginJWTMiddlewareBuilder) JWTAuthMiddleware(tokenConfig config.TokenCo
 	}
 }
 
+// passwordChangeAllowed 检查受限令牌是否可访问该接口
+func (l *LoginJWTMiddlewareBuilder) passwordChangeAllowed(path string) bool {
+	for _, v := range l.passwordPaths {
+		if path == v {
+			return true
+		}
+	}
+	return false
+}
+
+// twoFactorSetupAllowed 检查需先启用双因素认证的受限令牌是否可访问该接口
+func (l *LoginJWTMiddlewareBuilder) twoFactorSetupAllowed(path string) bool {
+	for _, v := range l.twoFactorPaths {
+		if path == v {
+			return true
+		}
+	}
+	return false
+}
+
 // containsAnySubstring 检查字符串是否包含切片中的任意一个子串
 func (l *LoginJWTMiddlewareBuilder) containsAnySubstring(str string, subs []string) bool {
 	for _, sub := range subs {
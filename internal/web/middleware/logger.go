@@ -12,6 +12,7 @@ import (
 	"bytes"
 	"fmt"
 	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/middleware/logger"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/masking"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 	"io/ioutil"
@@ -20,12 +21,17 @@ import (
 )
 
 type Logger struct {
-	zap *zap.Logger
+	zap    *zap.Logger
+	masker *masking.Masker
 }
 
-func NewLogger(zap *zap.Logger) *Logger {
+func NewLogger(zap *zap.Logger, masker *masking.Masker) *Logger {
+	if masker == nil {
+		masker = masking.New(masking.Config{})
+	}
 	return &Logger{
-		zap: zap,
+		zap:    zap,
+		masker: masker,
 	}
 }
 
@@ -37,7 +43,7 @@ func (l *Logger) Logger() gin.HandlerFunc {
 			// 开始时间
 			start := time.Now()
 			path := c.Request.URL.Path
-			query := c.Request.URL.Query()
+			query := l.masker.Values(c.Request.URL.Query())
 
 			// 创建自定义读取器
 			buffer := &bytes.Buffer{}
@@ -53,6 +59,12 @@ func (l *Logger) Logger() gin.HandlerFunc {
 			timeStamp := time.Now()
 			latency := timeStamp.Sub(start)
 
+			// 请求体脱敏后记录
+			body := ""
+			if !l.masker.Policy(c.Request.Method, requestRoute(c)).SkipRequestBody {
+				body = l.masker.Body(buffer.String(), c.GetHeader("Content-Type"))
+			}
+
 			l.zap.Debug(path,
 				zap.String("time", fmt.Sprintf("%v", latency)),
 				zap.Int("status", c.Writer.Status()),
@@ -60,9 +72,18 @@ func (l *Logger) Logger() gin.HandlerFunc {
 				zap.String("ip", c.ClientIP()),
 				zap.String("path", path),
 				zap.Any("query", query),
-				zap.Any("body", loggingReader.Format()),
+				zap.Any("headers", l.masker.Header(c.Request.Header)),
+				zap.String("body", body),
 				zap.String("errors", c.Errors.ByType(gin.ErrorTypePrivate).String()),
 			)
 		}
 	}
 }
+
+// requestRoute 路由模板，未匹配到路由时为实际请求路径
+func requestRoute(c *gin.Context) string {
+	if route := c.FullPath(); route != "" {
+		return route
+	}
+	return c.Request.URL.Path
+}
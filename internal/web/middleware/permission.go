@@ -0,0 +1,120 @@
+/**
+ * Description：
+ * FileName：permission.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 10:26:51
+ * Remark：
+ */
+
+package middleware
+
+import (
+	serviceSystem "github.com/carefuly/carefuly-admin-go-gin/internal/service/careful/system"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/columns"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/filters"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/response"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+var (
+	ForbiddenNoPermission = "无权限访问该接口"
+	ForbiddenApiKey       = "API Key无权访问该接口"
+)
+
+// PermissionMiddlewareBuilder 接口权限校验
+// 依据用户角色关联的菜单按钮(MenuButton.Api + MenuButton.Method)判断是否放行
+// 除显式声明的公开接口外，未登录的请求一律拒绝
+// 使用 API Key 认证时，仅能访问需要权限校验的接口，且受限于令牌的授权范围
+// 校验通过后将用户的数据权限范围及无权限查看的列写入上下文
+type PermissionMiddlewareBuilder struct {
+	public []string
+	paths  []string
+	svc    serviceSystem.PermissionService
+}
+
+func NewPermissionMiddlewareBuilder(svc serviceSystem.PermissionService) *PermissionMiddlewareBuilder {
+	return &PermissionMiddlewareBuilder{
+		svc: svc,
+	}
+}
+
+// PublicPaths 无需登录即可访问的接口，按路由模板(ctx.FullPath())完整匹配
+func (p *PermissionMiddlewareBuilder) PublicPaths(path string) *PermissionMiddlewareBuilder {
+	p.public = append(p.public, path)
+	return p
+}
+
+// IgnorePaths 登录即可访问的接口，按路由模板(ctx.FullPath())完整匹配
+func (p *PermissionMiddlewareBuilder) IgnorePaths(path string) *PermissionMiddlewareBuilder {
+	p.paths = append(p.paths, path)
+	return p
+}
+
+// Build 接口权限中间件
+func (p *PermissionMiddlewareBuilder) Build() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		// 路由不存在交给gin处理
+		route := ctx.FullPath()
+		if route == "" {
+			return
+		}
+		// 公开接口不做权限校验
+		if contains(p.public, route) {
+			return
+		}
+		// 其余接口必须已登录
+		value, exists := ctx.Get("userId")
+		userId, _ := value.(string)
+		if !exists || userId == "" {
+			response.NewResponse().ErrorResponse(ctx, http.StatusForbidden, ForbiddenNoPermission, nil)
+			ctx.Abort()
+			return
+		}
+		_, apiKey := ctx.Get(ApiKeyIdKey)
+		if contains(p.paths, route) {
+			// 登录即可访问的接口(账号、会话、令牌管理等)不允许 API Key 访问
+			if apiKey {
+				response.NewResponse().ErrorResponse(ctx, http.StatusForbidden, ForbiddenApiKey, nil)
+				ctx.Abort()
+			}
+			return
+		}
+
+		permission, err := p.svc.GetUserPermission(ctx, userId)
+		if err != nil {
+			ctx.Set("internal", err.Error())
+			zap.L().Error("接口权限校验异常", zap.String("userId", userId), zap.Error(err))
+			response.NewResponse().ErrorResponse(ctx, http.StatusInternalServerError, "服务器异常", nil)
+			ctx.Abort()
+			return
+		}
+		if apiKey {
+			permission = permission.Narrow(ctx.GetStringSlice(ApiKeyScopesKey))
+		}
+		api, ok := p.svc.MatchApiPermission(permission, ctx.Request.Method, route)
+		if !ok {
+			response.NewResponse().ErrorResponse(ctx, http.StatusForbidden, ForbiddenNoPermission, nil)
+			ctx.Abort()
+			return
+		}
+
+		// 数据权限范围，供 DAO 查询时使用
+		ctx.Set(filters.DataScopeKey, permission.DataScope)
+		// 接口所属菜单中无权限查看的列，供响应与导出时剔除
+		if hidden := permission.HiddenColumns[api.MenuId]; len(hidden) > 0 {
+			ctx.Set(columns.HiddenColumnsKey, hidden)
+		}
+	}
+}
+
+// contains 检查路由是否在列表中
+func contains(paths []string, route string) bool {
+	for _, v := range paths {
+		if route == v {
+			return true
+		}
+	}
+	return false
+}
@@ -14,23 +14,33 @@ import (
 	loggerModel "github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/logger"
 	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/logger"
 	loggerMiddleware "github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/middleware/logger"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/batchwriter"
 	_import "github.com/carefuly/carefuly-admin-go-gin/pkg/utils/import"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/masking"
 	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/requestUtils"
 	"github.com/gin-gonic/gin"
 	"github.com/mssola/user_agent"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
-	"gorm.io/gorm"
 	"io/ioutil"
 	"strings"
 	"time"
 )
 
+// skippedBody 按接口配置不记录请求体、响应体时的占位内容
+const skippedBody = "未记录"
+
 type Storage struct {
+	masker *masking.Masker
 }
 
-func NewStorage() *Storage {
-	return &Storage{}
+func NewStorage(masker *masking.Masker) *Storage {
+	if masker == nil {
+		masker = masking.New(masking.Config{})
+	}
+	return &Storage{
+		masker: masker,
+	}
 }
 
 func (s *Storage) GetResValue(c *gin.Context, key string) string {
@@ -49,7 +59,9 @@ func (s *Storage) Logger(path string) *zap.Logger {
 	return zap.New(core, zap.AddCaller())
 }
 
-func (s *Storage) StorageLogger(db *gorm.DB) gin.HandlerFunc {
+// StorageLogger 记录操作日志，非 GET 请求加入 logs 队列由后台批量写入数据库
+// 请求体、响应体、查询参数及请求头脱敏后记录，响应体超过配置大小时截断
+func (s *Storage) StorageLogger(logs *batchwriter.Writer[loggerModel.OperateLogger]) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if s.containsAnySubstring(c.Request.URL.Path, []string{"swagger", "static", "export"}) {
 			c.Next()
@@ -57,7 +69,7 @@ func (s *Storage) StorageLogger(db *gorm.DB) gin.HandlerFunc {
 			// 开始时间
 			start := time.Now()
 			path := c.Request.URL.Path
-			query := c.Request.URL.RawQuery
+			query := s.masker.Query(c.Request.URL.RawQuery)
 			contentType := c.GetHeader("Content-Type")
 
 			// 创建自定义读取器
@@ -84,10 +96,14 @@ func (s *Storage) StorageLogger(db *gorm.DB) gin.HandlerFunc {
 			// 获取响应数据
 			responseBody := crw.Body.String()
 			responseJson := crw.Format(responseBody)
+			policy := s.masker.Policy(c.Request.Method, requestRoute(c))
 
 			var record loggerModel.OperateLogger
 
 			record.RequestUsername = requestUtils.GetRequestUser(c)
+			// 模拟登录时请求用户为被模拟的用户，实际操作人为发起模拟的管理员
+			record.ActorId = requestUtils.GetActorId(c)
+			record.ActorUsername = requestUtils.GetActorName(c)
 			record.RequestTime = fmt.Sprintf("%v", latency)
 
 			record.RequestStatus = c.Writer.Status()
@@ -100,8 +116,10 @@ func (s *Storage) StorageLogger(db *gorm.DB) gin.HandlerFunc {
 			var body string
 			if strings.Contains(contentType, "multipart/form-data") {
 				body = "上传文件"
+			} else if policy.SkipRequestBody {
+				body = skippedBody
 			} else {
-				body = _import.CleanInput(buffer.String())
+				body = _import.CleanInput(s.masker.Body(buffer.String(), contentType))
 			}
 			record.RequestBody = body
 
@@ -112,30 +130,37 @@ func (s *Storage) StorageLogger(db *gorm.DB) gin.HandlerFunc {
 			record.UserAgent = c.Request.UserAgent()
 
 			record.RequestCode = responseJson.Code
-			record.RequestResult = responseBody
+			if policy.SkipResponseBody {
+				record.RequestResult = skippedBody
+			} else {
+				result := s.masker.Body(responseBody, c.Writer.Header().Get("Content-Type"))
+				record.RequestResult = masking.Truncate(result, policy.MaxResponseSize)
+			}
 
 			record.Errors = c.Errors.ByType(gin.ErrorTypePrivate).String()
 			record.Internal = s.GetResValue(c, "internal")
 
-			if record.RequestMethod != "GET" { // GET不进行持久化记录
-				record.Insert(c, db, record)
+			if record.RequestMethod != "GET" && logs != nil { // GET不进行持久化记录
+				logs.Write(record)
 			}
 
 			l := s.Logger(record.RequestPath)
 			l.Info(path,
 				zap.String("requestUsername", record.RequestUsername),
+				zap.String("actorUsername", record.ActorUsername),
 				zap.String("requestTime", fmt.Sprintf("%v", latency)),
 				zap.Int("requestStatus", c.Writer.Status()),
 				zap.String("requestMethod", c.Request.Method),
 				zap.String("requestIp", requestUtils.NormalizeIP(c)),
 				zap.String("requestPath", path),
-				zap.Any("requestQuery", query),
-				zap.Any("requestBody", loggingReader.Format()),
+				zap.String("requestQuery", query),
+				zap.Any("requestHeaders", s.masker.Header(c.Request.Header)),
+				zap.String("requestBody", body),
 				zap.String("requestOs", record.RequestOs),
 				zap.String("requestBrowser", record.RequestBrowser),
 				zap.String("userAgent", record.UserAgent),
 				zap.Int("requestCode", record.RequestCode),
-				zap.Any("requestResult", responseJson),
+				zap.String("requestResult", record.RequestResult),
 				zap.String("requestErrors", record.Errors),
 				zap.String("requestInternal", record.Internal),
 			)
@@ -0,0 +1,132 @@
+/**
+ * Description：
+ * FileName：storage_test.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 15:26:40
+ * Remark：
+ */
+
+package middleware
+
+import (
+	"context"
+	"fmt"
+	daoLogger "github.com/carefuly/carefuly-admin-go-gin/internal/repository/dao/careful/logger"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/batchwriter"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/masking"
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// 确认操作日志(数据库及日志文件)不会记录任何密码
+func TestStorageLogger_RedactPassword(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// 日志文件写入当前目录下的 tmp，切换到临时目录
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	// DryRun 模式只生成 SQL 不执行，通过回调获取写入的参数
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		DSN:                       "root:root@tcp(127.0.0.1:3306)/careful",
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var persisted []string
+	err = db.Callback().Create().After("gorm:create").Register("test:capture", func(tx *gorm.DB) {
+		for _, v := range tx.Statement.Vars {
+			persisted = append(persisted, fmt.Sprintf("%v", v))
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 操作日志经队列批量写入，断言前调用 Flush
+	logs := batchwriter.New(batchwriter.Config{Name: "operate_log"}, daoLogger.NewGORMOperateLogDAO(db).InsertBatch)
+	defer logs.Close(context.Background())
+
+	server := gin.New()
+	server.Use(NewStorage(masking.New(masking.Config{})).StorageLogger(logs))
+	server.POST("/dev-api/v1/auth/:action", func(ctx *gin.Context) {
+		// 读取请求体触发记录
+		_, _ = io.ReadAll(ctx.Request.Body)
+		ctx.JSON(http.StatusOK, gin.H{"code": 200, "msg": "ok", "data": gin.H{"token": "Secret#Jwt1", "refreshToken": "Secret#Refresh1"}})
+	})
+
+	testCases := []struct {
+		name   string
+		action string
+		body   string
+		secret []string
+	}{
+		{name: "登录", action: "login", body: `{"username":"admin","password":"Secret#Login1"}`, secret: []string{"Secret#Login1"}},
+		{name: "注册", action: "register", body: `{"username":"u1","password":"Secret#Register1","captchaCode":"abcd"}`, secret: []string{"Secret#Register1"}},
+		{name: "修改密码", action: "changePassword", body: `{"oldPassword":"Secret#Old1","newPassword":"Secret#New1","confirmPassword":"Secret#New1"}`, secret: []string{"Secret#Old1", "Secret#New1"}},
+		{name: "嵌套字段", action: "batch", body: `{"list":[{"username":"u2","password":"Secret#Nested1"}]}`, secret: []string{"Secret#Nested1"}},
+		{name: "查询参数", action: "refresh?refreshToken=Secret%23Query1&lang=zh", body: `{}`, secret: []string{"Secret#Query1", "Secret%23Query1"}},
+	}
+	// 响应中的令牌
+	tokens := []string{"Secret#Jwt1", "Secret#Refresh1"}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			persisted = nil
+			req := httptest.NewRequest(http.MethodPost, "/dev-api/v1/auth/"+tc.action, strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			server.ServeHTTP(httptest.NewRecorder(), req)
+			if err := logs.Flush(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(persisted) == 0 {
+				t.Fatal("操作日志未写入")
+			}
+			record := strings.Join(persisted, "\n")
+			for _, secret := range append(tc.secret, tokens...) {
+				if strings.Contains(record, secret) {
+					t.Errorf("操作日志记录了敏感信息: %s", record)
+				}
+			}
+		})
+	}
+
+	// 日志文件
+	files, err := filepath.Glob(filepath.Join(dir, "tmp", "admin", "v1", "*", "auth", "*.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("日志文件未写入")
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, tc := range testCases {
+			for _, secret := range append(tc.secret, tokens...) {
+				if strings.Contains(string(data), secret) {
+					t.Errorf("日志文件 %s 记录了敏感信息", file)
+				}
+			}
+		}
+	}
+}
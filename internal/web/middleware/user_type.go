@@ -0,0 +1,58 @@
+/**
+ * Description：
+ * FileName：user_type.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 00:31:08
+ * Remark：
+ */
+
+package middleware
+
+import (
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/constants/careful/system/user"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/ginx/response"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+var (
+	ForbiddenUserType = "当前用户类型无权访问该接口"
+)
+
+// UserTypeMiddlewareBuilder 用户类型校验
+// 用于路由组，仅允许指定类型的用户访问，用户类型取自登录令牌
+// 路由组内没有公开接口，未登录的请求一律拒绝
+type UserTypeMiddlewareBuilder struct {
+	types []user.TypeConst
+}
+
+func NewUserTypeMiddlewareBuilder() *UserTypeMiddlewareBuilder {
+	return &UserTypeMiddlewareBuilder{}
+}
+
+// Allow 允许访问的用户类型
+func (u *UserTypeMiddlewareBuilder) Allow(types ...user.TypeConst) *UserTypeMiddlewareBuilder {
+	u.types = append(u.types, types...)
+	return u
+}
+
+// Build 用户类型中间件
+func (u *UserTypeMiddlewareBuilder) Build() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if userId := ctx.GetString("userId"); userId == "" {
+			response.NewResponse().ErrorResponse(ctx, http.StatusForbidden, ForbiddenUserType, nil)
+			ctx.Abort()
+			return
+		}
+
+		userType := user.TypeConst(ctx.GetInt("userType"))
+		for _, t := range u.types {
+			if t == userType {
+				return
+			}
+		}
+
+		response.NewResponse().ErrorResponse(ctx, http.StatusForbidden, ForbiddenUserType, nil)
+		ctx.Abort()
+	}
+}
@@ -12,12 +12,17 @@ import (
 	config "github.com/carefuly/carefuly-admin-go-gin/config/file"
 	cacheSystem "github.com/carefuly/carefuly-admin-go-gin/internal/repository/cache/careful/system"
 	"github.com/carefuly/carefuly-admin-go-gin/internal/repository/cache/careful/third"
+	daoLogger "github.com/carefuly/carefuly-admin-go-gin/internal/repository/dao/careful/logger"
 	"github.com/carefuly/carefuly-admin-go-gin/internal/repository/dao/careful/system"
+	repositoryLogger "github.com/carefuly/carefuly-admin-go-gin/internal/repository/repository/careful/logger"
 	repositorySystem "github.com/carefuly/carefuly-admin-go-gin/internal/repository/repository/careful/system"
 	repositoryThird "github.com/carefuly/carefuly-admin-go-gin/internal/repository/repository/careful/third"
+	serviceLogger "github.com/carefuly/carefuly-admin-go-gin/internal/service/careful/logger"
 	serviceSystem "github.com/carefuly/carefuly-admin-go-gin/internal/service/careful/system"
 	serviceThird "github.com/carefuly/carefuly-admin-go-gin/internal/service/careful/third"
 	"github.com/carefuly/carefuly-admin-go-gin/internal/web/handler/careful/auth"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/jwt"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/mail"
 	"github.com/gin-gonic/gin"
 )
 
@@ -38,12 +43,77 @@ func (r *AuthRouter) RegisterRouter(router *gin.RouterGroup) {
 	captchaRepository := repositoryThird.NewCaptchaRepository(captchaCache)
 	captchaService := serviceThird.NewCaptchaService(captchaRepository)
 
+	permissionCache := cacheSystem.NewRedisPermissionCache(r.rely.Redis)
+	permissionDAO := system.NewGORMPermissionDAO(r.rely.Db.Careful)
+	permissionRepository := repositorySystem.NewPermissionRepository(permissionDAO, permissionCache)
+
 	userCache := cacheSystem.NewRedisUserCache(r.rely.Redis)
 
 	userDAO := system.NewGORMUserDAO(r.rely.Db.Careful)
 	userRepository := repositorySystem.NewUserRepository(userDAO, userCache)
-	userService := serviceSystem.NewUserService(userRepository)
+	passwordHistoryDAO := system.NewGORMUserPasswordHistoryDAO(r.rely.Db.Careful)
+	passwordHistoryRepository := repositorySystem.NewUserPasswordHistoryRepository(passwordHistoryDAO)
+	userService := serviceSystem.NewUserService(userRepository, permissionRepository, passwordHistoryRepository, r.rely.Password)
+
+	menuCache := cacheSystem.NewRedisMenuCache(r.rely.Redis)
+	menuDAO := system.NewGORMMenuDAO(r.rely.Db.Careful)
+	menuRepository := repositorySystem.NewMenuRepository(menuDAO, menuCache)
+	menuService := serviceSystem.NewMenuService(menuRepository, permissionRepository)
+	permissionService := serviceSystem.NewPermissionService(permissionRepository, menuService)
+
+	loginFailureCache := cacheSystem.NewRedisLoginFailureCache(r.rely.Redis)
+	loginFailureRepository := repositorySystem.NewLoginFailureRepository(loginFailureCache)
+	auditLogDAO := daoLogger.NewGORMAuditLogDAO(r.rely.Db.Careful)
+	auditLogRepository := repositoryLogger.NewAuditLogRepository(auditLogDAO)
+	auditLogService := serviceLogger.NewAuditLogService(auditLogRepository)
+	loginGuardService := serviceSystem.NewLoginGuardService(loginFailureRepository, auditLogService, r.rely.Captcha, r.rely.Lockout)
+
+	loginLogDAO := daoLogger.NewGORMLoginLogDAO(r.rely.Db.Careful)
+	loginLogRepository := repositoryLogger.NewLoginLogRepository(loginLogDAO)
+	loginLogService := serviceLogger.NewLoginLogService(loginLogRepository)
+
+	sessionCache := cacheSystem.NewRedisSessionCache(r.rely.Redis)
+	sessionRepository := repositorySystem.NewSessionRepository(sessionCache)
+	sessionService := serviceSystem.NewSessionService(sessionRepository, jwt.NewTokenBlacklist(r.rely.Redis),
+		jwt.NewRefreshTokenStore(r.rely.Redis), r.rely.Token)
+
+	twoFactorDAO := system.NewGORMUserTwoFactorDAO(r.rely.Db.Careful)
+	twoFactorChallengeCache := cacheSystem.NewRedisTwoFactorChallengeCache(r.rely.Redis)
+	twoFactorRepository := repositorySystem.NewUserTwoFactorRepository(twoFactorDAO, twoFactorChallengeCache)
+	twoFactorService := serviceSystem.NewTwoFactorService(twoFactorRepository, auditLogService, r.rely.TwoFactor)
 
-	registerHandler := auth.NewRegisterHandler(r.rely, userService, captchaService)
+	mailSender := mail.NewSMTPSender(mail.SMTPConfig{
+		Host:     r.rely.Mail.Host,
+		Port:     r.rely.Mail.Port,
+		Username: r.rely.Mail.Username,
+		Password: r.rely.Mail.Password,
+		From:     r.rely.Mail.From,
+		SSL:      r.rely.Mail.SSL,
+	})
+	passwordResetLimitCache := cacheSystem.NewRedisPasswordResetLimitCache(r.rely.Redis)
+	passwordResetLimitRepository := repositorySystem.NewPasswordResetLimitRepository(passwordResetLimitCache)
+	passwordResetService := serviceSystem.NewPasswordResetService(userRepository, userService, captchaRepository,
+		passwordResetLimitRepository, mailSender, auditLogService)
+
+	tokenBindingService := serviceSystem.NewTokenBindingService(sessionRepository, auditLogService, r.rely.Token)
+
+	impersonationService := serviceSystem.NewImpersonationService(userRepository, permissionRepository, sessionRepository,
+		sessionService, auditLogService, r.rely.Keys, r.rely.Token)
+
+	userIdentityDAO := system.NewGORMUserIdentityDAO(r.rely.Db.Careful)
+	ssoStateCache := cacheSystem.NewRedisSSOStateCache(r.rely.Redis)
+	userIdentityRepository := repositorySystem.NewUserIdentityRepository(userIdentityDAO, ssoStateCache)
+	ssoService := serviceSystem.NewSSOService(userRepository, userIdentityRepository, auditLogService, r.rely.SSO)
+
+	registerHandler := auth.NewRegisterHandler(r.rely, userService, captchaService, permissionService, loginGuardService,
+		loginLogService, sessionService, twoFactorService, passwordResetService, tokenBindingService, impersonationService,
+		ssoService)
 	registerHandler.RegisterRoutes(baseRouter)
+
+	apiKeyCache := cacheSystem.NewRedisApiKeyCache(r.rely.Redis)
+	apiKeyDAO := system.NewGORMApiKeyDAO(r.rely.Db.Careful)
+	apiKeyRepository := repositorySystem.NewApiKeyRepository(apiKeyDAO, apiKeyCache)
+	apiKeyService := serviceSystem.NewApiKeyService(apiKeyRepository, userRepository, permissionRepository, auditLogService)
+	apiKeyHandler := auth.NewApiKeyHandler(r.rely, apiKeyService, userService)
+	apiKeyHandler.RegisterRoutes(baseRouter)
 }
@@ -0,0 +1,94 @@
+/**
+ * Description：
+ * FileName：monitor.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 18:12:30
+ * Remark：
+ */
+
+package careful
+
+import (
+	config "github.com/carefuly/carefuly-admin-go-gin/config/file"
+	cacheLogger "github.com/carefuly/carefuly-admin-go-gin/internal/repository/cache/careful/logger"
+	cacheSystem "github.com/carefuly/carefuly-admin-go-gin/internal/repository/cache/careful/system"
+	daoLogger "github.com/carefuly/carefuly-admin-go-gin/internal/repository/dao/careful/logger"
+	daoSystem "github.com/carefuly/carefuly-admin-go-gin/internal/repository/dao/careful/system"
+	repositoryLogger "github.com/carefuly/carefuly-admin-go-gin/internal/repository/repository/careful/logger"
+	repositorySystem "github.com/carefuly/carefuly-admin-go-gin/internal/repository/repository/careful/system"
+	serviceLogger "github.com/carefuly/carefuly-admin-go-gin/internal/service/careful/logger"
+	serviceSystem "github.com/carefuly/carefuly-admin-go-gin/internal/service/careful/system"
+	handlerMonitor "github.com/carefuly/carefuly-admin-go-gin/internal/web/handler/careful/monitor"
+	"github.com/carefuly/carefuly-admin-go-gin/internal/web/middleware"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/constants/careful/system/user"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/jwt"
+	"github.com/gin-gonic/gin"
+)
+
+type MonitorRouter struct {
+	rely config.RelyConfig
+}
+
+func NewMonitorRouter(rely config.RelyConfig) *MonitorRouter {
+	return &MonitorRouter{
+		rely: rely,
+	}
+}
+
+func (r *MonitorRouter) RegisterRouter(router *gin.RouterGroup) {
+	baseRouter := router.Group("/monitor",
+		middleware.NewUserTypeMiddlewareBuilder().Allow(user.TypeConstAdminUser).Build())
+
+	// 登录日志
+	loginLogDAO := daoLogger.NewGORMLoginLogDAO(r.rely.Db.Careful)
+	loginLogRepository := repositoryLogger.NewLoginLogRepository(loginLogDAO)
+	loginLogService := serviceLogger.NewLoginLogService(loginLogRepository)
+	loginLogHandler := handlerMonitor.NewLoginLogHandler(r.rely, loginLogService)
+	loginLogHandler.RegisterRoutes(baseRouter)
+
+	// 操作日志
+	operateLogDAO := daoLogger.NewGORMOperateLogDAO(r.rely.Db.Careful)
+	operateLogRepository := repositoryLogger.NewOperateLogRepository(operateLogDAO)
+	auditLogDAO := daoLogger.NewGORMAuditLogDAO(r.rely.Db.Careful)
+	auditLogRepository := repositoryLogger.NewAuditLogRepository(auditLogDAO)
+	auditLogService := serviceLogger.NewAuditLogService(auditLogRepository)
+	operateLogService := serviceLogger.NewOperateLogService(operateLogRepository, auditLogService)
+	operateLogHandler := handlerMonitor.NewOperateLogHandler(r.rely, operateLogService)
+	operateLogHandler.RegisterRoutes(baseRouter)
+
+	// 缓存日志
+	cacheLogDAO := daoLogger.NewGORMCacheLogDAO(r.rely.Db.Careful)
+	cacheLogRepository := repositoryLogger.NewCacheLogRepository(cacheLogDAO)
+	cacheLogService := serviceLogger.NewCacheLogService(cacheLogRepository)
+	cacheLogHandler := handlerMonitor.NewCacheLogHandler(r.rely, cacheLogService)
+	cacheLogHandler.RegisterRoutes(baseRouter)
+
+	// 用户权限
+	permissionCache := cacheSystem.NewRedisPermissionCache(r.rely.Redis)
+	permissionDAO := daoSystem.NewGORMPermissionDAO(r.rely.Db.Careful)
+	permissionRepository := repositorySystem.NewPermissionRepository(permissionDAO, permissionCache)
+
+	// 数据变更
+	changeLogDAO := daoLogger.NewGORMChangeLogDAO(r.rely.Db.Careful)
+	changeLogCache := cacheLogger.NewRedisChangeLogCache(r.rely.Redis)
+	changeLogRepository := repositoryLogger.NewChangeLogRepository(changeLogDAO, changeLogCache)
+	changeLogService := serviceLogger.NewChangeLogService(changeLogRepository, permissionRepository)
+	changeLogHandler := handlerMonitor.NewChangeLogHandler(r.rely, changeLogService)
+	changeLogHandler.RegisterRoutes(baseRouter)
+
+	// 用户
+	userCache := cacheSystem.NewRedisUserCache(r.rely.Redis)
+	userDAO := daoSystem.NewGORMUserDAO(r.rely.Db.Careful)
+	userRepository := repositorySystem.NewUserRepository(userDAO, userCache)
+	passwordHistoryDAO := daoSystem.NewGORMUserPasswordHistoryDAO(r.rely.Db.Careful)
+	passwordHistoryRepository := repositorySystem.NewUserPasswordHistoryRepository(passwordHistoryDAO)
+	userService := serviceSystem.NewUserService(userRepository, permissionRepository, passwordHistoryRepository, r.rely.Password)
+
+	// 在线用户
+	sessionCache := cacheSystem.NewRedisSessionCache(r.rely.Redis)
+	sessionRepository := repositorySystem.NewSessionRepository(sessionCache)
+	sessionService := serviceSystem.NewSessionService(sessionRepository, jwt.NewTokenBlacklist(r.rely.Redis),
+		jwt.NewRefreshTokenStore(r.rely.Redis), r.rely.Token)
+	onlineHandler := handlerMonitor.NewOnlineHandler(r.rely, sessionService, userService)
+	onlineHandler.RegisterRoutes(baseRouter)
+}
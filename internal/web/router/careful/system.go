@@ -11,10 +11,16 @@ package careful
 import (
 	config "github.com/carefuly/carefuly-admin-go-gin/config/file"
 	cacheSystem "github.com/carefuly/carefuly-admin-go-gin/internal/repository/cache/careful/system"
+	daoLogger "github.com/carefuly/carefuly-admin-go-gin/internal/repository/dao/careful/logger"
 	daoSystem "github.com/carefuly/carefuly-admin-go-gin/internal/repository/dao/careful/system"
+	repositoryLogger "github.com/carefuly/carefuly-admin-go-gin/internal/repository/repository/careful/logger"
 	repositorySystem "github.com/carefuly/carefuly-admin-go-gin/internal/repository/repository/careful/system"
+	serviceLogger "github.com/carefuly/carefuly-admin-go-gin/internal/service/careful/logger"
 	serviceSystem "github.com/carefuly/carefuly-admin-go-gin/internal/service/careful/system"
 	handlerSystem "github.com/carefuly/carefuly-admin-go-gin/internal/web/handler/careful/system"
+	"github.com/carefuly/carefuly-admin-go-gin/internal/web/middleware"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/constants/careful/system/user"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/jwt"
 	"github.com/gin-gonic/gin"
 )
 
@@ -29,21 +35,46 @@ func NewSystemRouter(rely config.RelyConfig) *SystemRouter {
 }
 
 func (r *SystemRouter) RegisterRouter(router *gin.RouterGroup) {
-	baseRouter := router.Group("/system")
+	baseRouter := router.Group("/system",
+		middleware.NewUserTypeMiddlewareBuilder().Allow(user.TypeConstAdminUser).Build())
+
+	// 权限
+	permissionCache := cacheSystem.NewRedisPermissionCache(r.rely.Redis)
+	permissionDAO := daoSystem.NewGORMPermissionDAO(r.rely.Db.Careful)
+	permissionRepository := repositorySystem.NewPermissionRepository(permissionDAO, permissionCache)
 
 	// 用户
 	userCache := cacheSystem.NewRedisUserCache(r.rely.Redis)
 	userDAO := daoSystem.NewGORMUserDAO(r.rely.Db.Careful)
 	userRepository := repositorySystem.NewUserRepository(userDAO, userCache)
-	userService := serviceSystem.NewUserService(userRepository)
-	userHandler := handlerSystem.NewUserHandler(r.rely, userService)
+	passwordHistoryDAO := daoSystem.NewGORMUserPasswordHistoryDAO(r.rely.Db.Careful)
+	passwordHistoryRepository := repositorySystem.NewUserPasswordHistoryRepository(passwordHistoryDAO)
+	userService := serviceSystem.NewUserService(userRepository, permissionRepository, passwordHistoryRepository, r.rely.Password)
+	loginFailureCache := cacheSystem.NewRedisLoginFailureCache(r.rely.Redis)
+	loginFailureRepository := repositorySystem.NewLoginFailureRepository(loginFailureCache)
+	auditLogDAO := daoLogger.NewGORMAuditLogDAO(r.rely.Db.Careful)
+	auditLogRepository := repositoryLogger.NewAuditLogRepository(auditLogDAO)
+	auditLogService := serviceLogger.NewAuditLogService(auditLogRepository)
+	loginGuardService := serviceSystem.NewLoginGuardService(loginFailureRepository, auditLogService, r.rely.Captcha, r.rely.Lockout)
+	twoFactorDAO := daoSystem.NewGORMUserTwoFactorDAO(r.rely.Db.Careful)
+	twoFactorChallengeCache := cacheSystem.NewRedisTwoFactorChallengeCache(r.rely.Redis)
+	twoFactorRepository := repositorySystem.NewUserTwoFactorRepository(twoFactorDAO, twoFactorChallengeCache)
+	twoFactorService := serviceSystem.NewTwoFactorService(twoFactorRepository, auditLogService, r.rely.TwoFactor)
+	sessionCache := cacheSystem.NewRedisSessionCache(r.rely.Redis)
+	sessionRepository := repositorySystem.NewSessionRepository(sessionCache)
+	sessionService := serviceSystem.NewSessionService(sessionRepository, jwt.NewTokenBlacklist(r.rely.Redis),
+		jwt.NewRefreshTokenStore(r.rely.Redis), r.rely.Token)
+	impersonationService := serviceSystem.NewImpersonationService(userRepository, permissionRepository, sessionRepository,
+		sessionService, auditLogService, r.rely.Keys, r.rely.Token)
+	userHandler := handlerSystem.NewUserHandler(r.rely, userService, loginGuardService, twoFactorService,
+		impersonationService, sessionService)
 	userHandler.RegisterRoutes(baseRouter)
 
 	// 菜单
 	menuCache := cacheSystem.NewRedisMenuCache(r.rely.Redis)
 	menuDAO := daoSystem.NewGORMMenuDAO(r.rely.Db.Careful)
 	menuRepository := repositorySystem.NewMenuRepository(menuDAO, menuCache)
-	menuService := serviceSystem.NewMenuService(menuRepository)
+	menuService := serviceSystem.NewMenuService(menuRepository, permissionRepository)
 	menuHandler := handlerSystem.NewMenuHandler(r.rely, menuService, userService)
 	menuHandler.RegisterRoutes(baseRouter)
 
@@ -51,7 +82,7 @@ func (r *SystemRouter) RegisterRouter(router *gin.RouterGroup) {
 	menuButtonCache := cacheSystem.NewRedisMenuButtonCache(r.rely.Redis)
 	menuButtonDAO := daoSystem.NewGORMMenuButtonDAO(r.rely.Db.Careful)
 	menuButtonRepository := repositorySystem.NewMenuButtonRepository(menuButtonDAO, menuButtonCache)
-	menuButtonService := serviceSystem.NewMenuButtonService(menuButtonRepository, menuRepository)
+	menuButtonService := serviceSystem.NewMenuButtonService(menuButtonRepository, menuRepository, permissionRepository)
 	menuButtonHandler := handlerSystem.NewMenuButtonHandler(r.rely, menuButtonService, userService)
 	menuButtonHandler.RegisterRoutes(baseRouter)
 
@@ -59,7 +90,7 @@ func (r *SystemRouter) RegisterRouter(router *gin.RouterGroup) {
 	menuColumnCache := cacheSystem.NewRedisMenuColumnCache(r.rely.Redis)
 	menuColumnDAO := daoSystem.NewGORMMenuColumnDAO(r.rely.Db.Careful)
 	menuColumnRepository := repositorySystem.NewMenuColumnRepository(menuColumnDAO, menuColumnCache)
-	menuColumnService := serviceSystem.NewMenuColumnService(menuColumnRepository, menuRepository)
+	menuColumnService := serviceSystem.NewMenuColumnService(menuColumnRepository, menuRepository, permissionRepository)
 	menuColumnHandler := handlerSystem.NewMenuColumnHandler(r.rely, menuColumnService, userService)
 	menuColumnHandler.RegisterRoutes(baseRouter)
 
@@ -67,7 +98,7 @@ func (r *SystemRouter) RegisterRouter(router *gin.RouterGroup) {
 	deptCache := cacheSystem.NewRedisDeptCache(r.rely.Redis)
 	deptDAO := daoSystem.NewGORMDeptDAO(r.rely.Db.Careful)
 	deptRepository := repositorySystem.NewDeptRepository(deptDAO, deptCache)
-	deptService := serviceSystem.NewDeptService(deptRepository)
+	deptService := serviceSystem.NewDeptService(deptRepository, permissionRepository)
 	deptHandler := handlerSystem.NewDeptHandler(r.rely, deptService, userService)
 	deptHandler.RegisterRoutes(baseRouter)
 
@@ -75,7 +106,7 @@ func (r *SystemRouter) RegisterRouter(router *gin.RouterGroup) {
 	roleCache := cacheSystem.NewRedisRoleCache(r.rely.Redis)
 	roleDAO := daoSystem.NewGORMRoleDAO(r.rely.Db.Careful, deptDAO, menuDAO, menuButtonDAO, menuColumnDAO)
 	roleRepository := repositorySystem.NewRoleRepository(roleDAO, roleCache)
-	roleService := serviceSystem.NewRoleService(roleRepository)
+	roleService := serviceSystem.NewRoleService(roleRepository, permissionRepository)
 	roleHandler := handlerSystem.NewRoleHandler(r.rely, roleService, userService)
 	roleHandler.RegisterRoutes(baseRouter)
 
@@ -22,6 +22,8 @@ import (
 	serviceThird "github.com/carefuly/carefuly-admin-go-gin/internal/service/careful/third"
 	serviceTools "github.com/carefuly/carefuly-admin-go-gin/internal/service/careful/tools"
 	handlerTools "github.com/carefuly/carefuly-admin-go-gin/internal/web/handler/careful/tools"
+	"github.com/carefuly/carefuly-admin-go-gin/internal/web/middleware"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/constants/careful/system/user"
 	"github.com/gin-gonic/gin"
 )
 
@@ -36,13 +38,21 @@ func NewToolsRouter(rely config.RelyConfig) *ToolsRouter {
 }
 
 func (r *ToolsRouter) RegisterRouter(router *gin.RouterGroup) {
-	baseRouter := router.Group("/tools")
+	baseRouter := router.Group("/tools",
+		middleware.NewUserTypeMiddlewareBuilder().Allow(user.TypeConstAdminUser).Build())
+
+	// 权限
+	permissionCache := cacheSystem.NewRedisPermissionCache(r.rely.Redis)
+	permissionDAO := daoSystem.NewGORMPermissionDAO(r.rely.Db.Careful)
+	permissionRepository := repositorySystem.NewPermissionRepository(permissionDAO, permissionCache)
 
 	// 用户
 	userCache := cacheSystem.NewRedisUserCache(r.rely.Redis)
 	userDAO := daoSystem.NewGORMUserDAO(r.rely.Db.Careful)
 	userRepository := repositorySystem.NewUserRepository(userDAO, userCache)
-	userService := serviceSystem.NewUserService(userRepository)
+	passwordHistoryDAO := daoSystem.NewGORMUserPasswordHistoryDAO(r.rely.Db.Careful)
+	passwordHistoryRepository := repositorySystem.NewUserPasswordHistoryRepository(passwordHistoryDAO)
+	userService := serviceSystem.NewUserService(userRepository, permissionRepository, passwordHistoryRepository, r.rely.Password)
 
 	// 数据字典
 	dictCache := cacheTools.NewRedisDictCache(r.rely.Redis)
@@ -65,7 +75,7 @@ func (r *ToolsRouter) RegisterRouter(router *gin.RouterGroup) {
 
 	// 存储桶
 	bucketCache := cacheTools.NewRedisBucketCache(r.rely.Redis)
-	bucketCacheLogger := cacheRecord.NewCacheLogger(r.rely.Db.Careful)
+	bucketCacheLogger := cacheRecord.NewCacheLogger(r.rely.CacheLogs)
 	bucketCacheLoggingDecorator := cacheDecorator.NewBucketCacheLoggingDecorator(bucketCache, bucketCacheLogger)
 	bucketDAO := daoTools.NewGORMBucketDAO(r.rely.Db.Careful)
 	bucketRepository := repositoryTools.NewBucketRepository(bucketDAO, bucketCacheLoggingDecorator)
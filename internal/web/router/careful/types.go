@@ -30,4 +30,5 @@ func (r *Router) RegisterRoutes() {
 	NewSystemRouter(r.rely).RegisterRouter(r.router)
 	NewToolsRouter(r.rely).RegisterRouter(r.router)
 	NewThirdRouter(r.rely).RegisterRouter(r.router)
+	NewMonitorRouter(r.rely).RegisterRouter(r.router)
 }
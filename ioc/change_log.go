@@ -0,0 +1,29 @@
+/**
+ * Description：
+ * FileName：change_log.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 16:27:15
+ * Remark：
+ */
+
+package ioc
+
+import (
+	config "github.com/carefuly/carefuly-admin-go-gin/config/file"
+	daoLogger "github.com/carefuly/carefuly-admin-go-gin/internal/repository/dao/careful/logger"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/changelog"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// InitChangeLog 注册数据变更审计插件
+func InitChangeLog(db *gorm.DB, cfg config.ChangeLogConfig) {
+	changeLogDAO := daoLogger.NewGORMChangeLogDAO(db)
+	plugin := changelog.New(changelog.Config{
+		ExcludeTables: cfg.ExcludeTables,
+		ExcludeFields: cfg.ExcludeFields,
+	}, changeLogDAO.Write)
+	if err := db.Use(plugin); err != nil {
+		zap.L().Error("数据变更审计插件注册失败", zap.Error(err))
+	}
+}
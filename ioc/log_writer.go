@@ -0,0 +1,63 @@
+/**
+ * Description：
+ * FileName：log_writer.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 10:48:05
+ * Remark：
+ */
+
+package ioc
+
+import (
+	"context"
+	config "github.com/carefuly/carefuly-admin-go-gin/config/file"
+	modelLogger "github.com/carefuly/carefuly-admin-go-gin/internal/model/careful/logger"
+	daoLogger "github.com/carefuly/carefuly-admin-go-gin/internal/repository/dao/careful/logger"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/batchwriter"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"time"
+)
+
+// InitLogWriters 创建操作日志、缓存日志的批量写入队列
+func InitLogWriters(db *gorm.DB, cfg config.LogWriterConfig) (*batchwriter.Writer[modelLogger.OperateLogger], *batchwriter.Writer[modelLogger.CacheLogger]) {
+	writerConfig := func(name string) batchwriter.Config {
+		return batchwriter.Config{
+			Name:          name,
+			QueueSize:     cfg.QueueSize,
+			BatchSize:     cfg.BatchSize,
+			FlushInterval: time.Duration(cfg.FlushInterval) * time.Millisecond,
+			Overflow:      cfg.Overflow,
+			SpillDir:      cfg.SpillDir,
+		}
+	}
+
+	operateLogDAO := daoLogger.NewGORMOperateLogDAO(db)
+	operateLogs := batchwriter.New(writerConfig("operate_log"), operateLogDAO.InsertBatch)
+
+	cacheLogDAO := daoLogger.NewGORMCacheLogDAO(db)
+	cacheLogs := batchwriter.New(writerConfig("cache_log"), cacheLogDAO.InsertBatch)
+
+	return operateLogs, cacheLogs
+}
+
+// CloseLogWriters 停止服务时写完队列中剩余的日志
+func CloseLogWriters(rely config.RelyConfig) {
+	timeout := 10 * time.Second
+	if rely.LogWriter.DrainTimeout > 0 {
+		timeout = time.Duration(rely.LogWriter.DrainTimeout) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if rely.OperateLogs != nil {
+		if err := rely.OperateLogs.Close(ctx); err != nil {
+			zap.L().Error("操作日志队列关闭失败", zap.Error(err))
+		}
+	}
+	if rely.CacheLogs != nil {
+		if err := rely.CacheLogs.Close(ctx); err != nil {
+			zap.L().Error("缓存日志队列关闭失败", zap.Error(err))
+		}
+	}
+}
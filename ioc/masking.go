@@ -0,0 +1,36 @@
+/**
+ * Description：
+ * FileName：masking.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 14:25:41
+ * Remark：
+ */
+
+package ioc
+
+import (
+	config "github.com/carefuly/carefuly-admin-go-gin/config/file"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/masking"
+)
+
+// InitMasker 创建操作日志脱敏器
+func InitMasker(cfg config.LogMaskConfig) *masking.Masker {
+	routes := make([]masking.Route, 0, len(cfg.Routes))
+	for _, route := range cfg.Routes {
+		routes = append(routes, masking.Route{
+			Method:           route.Method,
+			Path:             route.Path,
+			SkipRequestBody:  route.SkipRequestBody,
+			SkipResponseBody: route.SkipResponseBody,
+			MaxResponseSize:  route.MaxResponseSize,
+		})
+	}
+	return masking.New(masking.Config{
+		Keys:            cfg.Keys,
+		Paths:           cfg.Paths,
+		Headers:         cfg.Headers,
+		Mask:            cfg.Mask,
+		MaxResponseSize: cfg.MaxResponseSize,
+		Routes:          routes,
+	})
+}
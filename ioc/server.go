@@ -12,6 +12,14 @@ import (
 	"fmt"
 	config "github.com/carefuly/carefuly-admin-go-gin/config/file"
 	"github.com/carefuly/carefuly-admin-go-gin/docs"
+	cacheSystem "github.com/carefuly/carefuly-admin-go-gin/internal/repository/cache/careful/system"
+	daoLogger "github.com/carefuly/carefuly-admin-go-gin/internal/repository/dao/careful/logger"
+	daoSystem "github.com/carefuly/carefuly-admin-go-gin/internal/repository/dao/careful/system"
+	repositoryLogger "github.com/carefuly/carefuly-admin-go-gin/internal/repository/repository/careful/logger"
+	repositorySystem "github.com/carefuly/carefuly-admin-go-gin/internal/repository/repository/careful/system"
+	serviceLogger "github.com/carefuly/carefuly-admin-go-gin/internal/service/careful/logger"
+	serviceSystem "github.com/carefuly/carefuly-admin-go-gin/internal/service/careful/system"
+	"github.com/carefuly/carefuly-admin-go-gin/internal/web/handler/careful/auth"
 	"github.com/carefuly/carefuly-admin-go-gin/internal/web/middleware"
 	"github.com/carefuly/carefuly-admin-go-gin/internal/web/router/careful"
 	"github.com/gin-gonic/gin"
@@ -31,6 +39,55 @@ import (
 	"strings"
 )
 
+// publicPaths 无需登录即可访问的接口
+var publicPaths = []string{
+	"/dev-api/v1/auth/register",
+	"/dev-api/v1/auth/login",
+	"/dev-api/v1/auth/type-login",
+	"/dev-api/v1/auth/login/2fa",
+	"/dev-api/v1/auth/refresh-token",
+	"/dev-api/v1/auth/forgot-password/code",
+	"/dev-api/v1/auth/forgot-password/reset",
+	"/dev-api/v1/auth/sso/providers",
+	"/dev-api/v1/auth/sso/authorize",
+	"/dev-api/v1/auth/sso/login",
+	"/dev-api/v1/third/generateCaptcha",
+	"/.well-known/jwks.json",
+}
+
+// loginPaths 登录即可访问的接口(账号、会话、令牌管理等)，无需菜单按钮权限
+var loginPaths = []string{
+	"/dev-api/v1/auth/logout",
+	"/dev-api/v1/auth/userinfo",
+	"/dev-api/v1/auth/change-password",
+	"/dev-api/v1/auth/permissions",
+	"/dev-api/v1/auth/routes",
+	"/dev-api/v1/auth/2fa/status",
+	"/dev-api/v1/auth/2fa/setup",
+	"/dev-api/v1/auth/2fa/enable",
+	"/dev-api/v1/auth/2fa/disable",
+	"/dev-api/v1/auth/2fa/recoveryCodes",
+	"/dev-api/v1/auth/impersonate/stop",
+	"/dev-api/v1/auth/apiKey/create",
+	"/dev-api/v1/auth/apiKey/revoke/:id",
+	"/dev-api/v1/auth/apiKey/listAll",
+}
+
+// passwordChangePaths 需先修改密码时仍可访问的接口
+var passwordChangePaths = []string{
+	"/dev-api/v1/auth/change-password",
+	"/dev-api/v1/auth/userinfo",
+	"/dev-api/v1/auth/logout",
+}
+
+// twoFactorSetupPaths 需先启用双因素认证时仍可访问的接口，刷新令牌接口无需登录
+var twoFactorSetupPaths = []string{
+	"/dev-api/v1/auth/2fa/status",
+	"/dev-api/v1/auth/2fa/setup",
+	"/dev-api/v1/auth/2fa/enable",
+	"/dev-api/v1/auth/logout",
+}
+
 type Server struct {
 	rely   config.RelyConfig
 	locale string
@@ -44,16 +101,60 @@ func NewServer(rely config.RelyConfig, locale string) *Server {
 }
 
 func (s *Server) InitGinMiddlewares(rely config.RelyConfig) []gin.HandlerFunc {
+	// 接口权限
+	permissionCache := cacheSystem.NewRedisPermissionCache(rely.Redis)
+	permissionDAO := daoSystem.NewGORMPermissionDAO(rely.Db.Careful)
+	permissionRepository := repositorySystem.NewPermissionRepository(permissionDAO, permissionCache)
+	menuCache := cacheSystem.NewRedisMenuCache(rely.Redis)
+	menuDAO := daoSystem.NewGORMMenuDAO(rely.Db.Careful)
+	menuRepository := repositorySystem.NewMenuRepository(menuDAO, menuCache)
+	menuService := serviceSystem.NewMenuService(menuRepository, permissionRepository)
+	permissionService := serviceSystem.NewPermissionService(permissionRepository, menuService)
+
+	// 个人访问令牌
+	userCache := cacheSystem.NewRedisUserCache(rely.Redis)
+	userDAO := daoSystem.NewGORMUserDAO(rely.Db.Careful)
+	userRepository := repositorySystem.NewUserRepository(userDAO, userCache)
+	auditLogDAO := daoLogger.NewGORMAuditLogDAO(rely.Db.Careful)
+	auditLogRepository := repositoryLogger.NewAuditLogRepository(auditLogDAO)
+	auditLogService := serviceLogger.NewAuditLogService(auditLogRepository)
+	apiKeyCache := cacheSystem.NewRedisApiKeyCache(rely.Redis)
+	apiKeyDAO := daoSystem.NewGORMApiKeyDAO(rely.Db.Careful)
+	apiKeyRepository := repositorySystem.NewApiKeyRepository(apiKeyDAO, apiKeyCache)
+	apiKeyService := serviceSystem.NewApiKeyService(apiKeyRepository, userRepository, permissionRepository, auditLogService)
+
+	// 令牌绑定
+	sessionCache := cacheSystem.NewRedisSessionCache(rely.Redis)
+	sessionRepository := repositorySystem.NewSessionRepository(sessionCache)
+	tokenBindingService := serviceSystem.NewTokenBindingService(sessionRepository, auditLogService, rely.Token)
+
+	jwtMiddleware := middleware.NewLoginJWTMiddlewareBuilder(rely)
+	permissionMiddleware := middleware.NewPermissionMiddlewareBuilder(permissionService).
+		PublicPaths("/swagger/*any").
+		PublicPaths("/static/*filepath")
+	for _, path := range publicPaths {
+		jwtMiddleware.IgnorePaths(path)
+		permissionMiddleware.PublicPaths(path)
+	}
+	for _, path := range loginPaths {
+		permissionMiddleware.IgnorePaths(path)
+	}
+	for _, path := range passwordChangePaths {
+		jwtMiddleware.PasswordChangePaths(path)
+	}
+	for _, path := range twoFactorSetupPaths {
+		jwtMiddleware.TwoFactorSetupPaths(path)
+	}
+
 	return []gin.HandlerFunc{
 		middleware.CORSMiddleware(),
-		middleware.NewLoginJWTMiddlewareBuilder(rely).
-			IgnorePaths("/dev-api/v1/auth/register").
-			IgnorePaths("/dev-api/v1/auth/login").
-			IgnorePaths("/dev-api/v1/auth/refresh-token").
-			IgnorePaths("/dev-api/v1/third/generateCaptcha").
+		jwtMiddleware.
+			ApiKey(apiKeyService).
+			Binding(tokenBindingService).
 			Build(),
-		middleware.NewLogger(rely.Logger).Logger(),
-		middleware.NewStorage().StorageLogger(rely.Db.Careful),
+		middleware.NewLogger(rely.Logger, rely.Masker).Logger(),
+		middleware.NewStorage(rely.Masker).StorageLogger(rely.OperateLogs),
+		permissionMiddleware.Build(),
 	}
 }
 
@@ -126,6 +227,8 @@ func (s *Server) InitWebServer(middle []gin.HandlerFunc, rely config.RelyConfig)
 	staticDir := s.StaticPath()
 	// 设置静态路由
 	server.Static("/static", staticDir)
+	// 令牌校验公钥
+	auth.NewJWKSHandler(rely).RegisterRoutes(server)
 
 	ApiGroup := server.Group("/dev-api")
 	v1 := ApiGroup.Group("/v1")
@@ -0,0 +1,33 @@
+/**
+ * Description：
+ * FileName：token.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 20:48:13
+ * Remark：
+ */
+
+package ioc
+
+import (
+	config "github.com/carefuly/carefuly-admin-go-gin/config/file"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/jwt"
+	"go.uber.org/zap"
+)
+
+func InitTokenKeys(token config.TokenConfig) *jwt.KeySet {
+	files := make([]jwt.KeyFile, 0, len(token.Keys))
+	for _, key := range token.Keys {
+		files = append(files, jwt.KeyFile{
+			Kid:            key.Kid,
+			Algorithm:      key.Algorithm,
+			PrivateKeyFile: key.PrivateKey,
+			PublicKeyFile:  key.PublicKey,
+		})
+	}
+
+	keys, err := jwt.NewKeySet(token.Secret, token.SigningKid, files)
+	if err != nil {
+		zap.L().Fatal("令牌签名密钥加载失败", zap.Error(err))
+	}
+	return keys
+}
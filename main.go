@@ -9,13 +9,17 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	config "github.com/carefuly/carefuly-admin-go-gin/config/file"
 	"github.com/carefuly/carefuly-admin-go-gin/ioc"
 	"go.uber.org/zap"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 )
 
 // @title CarefulAdmin
@@ -39,25 +43,49 @@ func main() {
 
 	dbPool := ioc.NewDbPool()
 	dbPool.InitDatabases(initConfig.DatabaseConfig)
+	ioc.InitChangeLog(dbPool.CarefulDB, initConfig.ChangeLogConfig)
 	relyConfig.Db = config.DatabasesPool{
 		Careful: dbPool.CarefulDB,
 	}
 
 	relyConfig.Redis = ioc.InitCache(initConfig.CacheConfig)
 	relyConfig.Token = initConfig.TokenConfig
+	relyConfig.Keys = ioc.InitTokenKeys(initConfig.TokenConfig)
+	relyConfig.Captcha = initConfig.CaptchaConfig
+	relyConfig.Lockout = initConfig.LockoutConfig
+	relyConfig.TwoFactor = initConfig.TwoFactorConfig
+	relyConfig.Password = initConfig.PasswordPolicyConfig
+	relyConfig.Mail = initConfig.MailConfig
+	relyConfig.SSO = initConfig.SSOConfig
+	relyConfig.LogWriter = initConfig.LogWriterConfig
+	relyConfig.OperateLogs, relyConfig.CacheLogs = ioc.InitLogWriters(dbPool.CarefulDB, initConfig.LogWriterConfig)
+	relyConfig.Masker = ioc.InitMasker(initConfig.LogMaskConfig)
 
 	server := ioc.NewServer(relyConfig, "zh")
 	middlewares := server.InitGinMiddlewares(relyConfig)
 	relyConfig.Trans, _ = server.InitGinTrans()
 	engine := server.InitWebServer(middlewares, relyConfig)
 
-	err := engine.Run(fmt.Sprintf("%s:%d", initConfig.ServerConfig.Host, initConfig.ServerConfig.Port))
-	if err != nil {
-		zap.L().Error("启动失败", zap.Error(err))
+	srv := &http.Server{
+		Addr:    fmt.Sprintf("%s:%d", initConfig.ServerConfig.Host, initConfig.ServerConfig.Port),
+		Handler: engine,
 	}
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			zap.L().Error("启动失败", zap.Error(err))
+		}
+	}()
 
 	// 接收终止信号
-	quit := make(chan os.Signal)
+	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
+
+	// 先停止接收请求，再写完队列中的日志
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		zap.L().Error("服务关闭异常", zap.Error(err))
+	}
+	ioc.CloseLogWriters(relyConfig)
 }
@@ -0,0 +1,30 @@
+/**
+ * Description：
+ * FileName：const.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 16:52:40
+ * Remark：
+ */
+
+package audit
+
+type EventConst string
+
+const (
+	EventConstAccountLocked     EventConst = "account_locked"      // 账号锁定
+	EventConstIpLocked          EventConst = "ip_locked"           // IP锁定
+	EventConstAccountUnlocked   EventConst = "account_unlocked"    // 账号解锁
+	EventConstIpUnlocked        EventConst = "ip_unlocked"         // IP解锁
+	EventConstTwoFactorEnabled  EventConst = "two_factor_enabled"  // 启用双因素认证
+	EventConstTwoFactorDisabled EventConst = "two_factor_disabled" // 关闭双因素认证
+	EventConstTwoFactorReset    EventConst = "two_factor_reset"    // 管理员重置双因素认证
+	EventConstApiKeyCreated     EventConst = "api_key_created"     // 创建API Key
+	EventConstApiKeyRevoked     EventConst = "api_key_revoked"     // 吊销API Key
+	EventConstPasswordRecovered EventConst = "password_recovered"  // 找回密码
+	EventConstTokenMismatch     EventConst = "token_mismatch"      // 令牌使用环境与签发时不一致
+	EventConstImpersonateStart  EventConst = "impersonate_started" // 开始模拟登录
+	EventConstImpersonateStop   EventConst = "impersonate_stopped" // 结束模拟登录
+	EventConstIdentityLinked    EventConst = "identity_linked"     // 关联第三方身份
+	EventConstUserProvisioned   EventConst = "user_provisioned"    // 单点登录自动创建用户
+	EventConstOperateLogCleaned EventConst = "operate_log_cleaned" // 清理操作日志
+)
@@ -0,0 +1,28 @@
+/**
+ * Description：
+ * FileName：const.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 09:05:41
+ * Remark：
+ */
+
+package cache
+
+type OperationConst string
+
+const (
+	OperationConstGet         OperationConst = "get"         // 读取
+	OperationConstSet         OperationConst = "set"         // 写入
+	OperationConstDel         OperationConst = "del"         // 删除
+	OperationConstSetNotFound OperationConst = "setNotFound" // 写入防穿透标记
+)
+
+type ResultConst string
+
+const (
+	ResultConstHit      ResultConst = "hit"       // 命中
+	ResultConstMiss     ResultConst = "miss"      // 未命中(键不存在)
+	ResultConstNotFound ResultConst = "not_found" // 命中防穿透标记
+	ResultConstOk       ResultConst = "ok"        // 写入或删除成功
+	ResultConstError    ResultConst = "error"     // 异常
+)
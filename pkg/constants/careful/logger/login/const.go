@@ -0,0 +1,17 @@
+/**
+ * Description：
+ * FileName：const.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 17:40:16
+ * Remark：
+ */
+
+package login
+
+type ActionConst string
+
+const (
+	ActionConstLogin   ActionConst = "login"   // 登录
+	ActionConstLogout  ActionConst = "logout"  // 退出登录
+	ActionConstRefresh ActionConst = "refresh" // 刷新令牌
+)
@@ -35,3 +35,6 @@ var DataRangeImportMapping = map[string]DataRangeConst{
 	"全部数据权限":     DataRangeConstAll,
 	"自定数据权限":     DataRangeConstCustom,
 }
+
+// SuperAdminCode 超级管理员角色编码【拥有该角色的用户跳过接口权限校验】
+const SuperAdminCode = "admin"
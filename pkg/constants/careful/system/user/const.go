@@ -22,3 +22,6 @@ const (
 	GenderConstFemale                        // 女
 	GenderConstSecret                        // 保密
 )
+
+// PermissionConstImpersonate 模拟登录权限值，需在菜单按钮中配置后授予角色
+const PermissionConstImpersonate = "system:user:impersonate"
@@ -0,0 +1,122 @@
+/**
+ * Description：
+ * FileName：columns.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 13:05:37
+ * Remark：
+ */
+
+package columns
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/excelutil"
+	"strings"
+)
+
+// HiddenColumnsKey 请求上下文中无权限查看的列的键
+const HiddenColumnsKey = "hiddenColumns"
+
+// childrenKey 树形结构的子节点字段
+const childrenKey = "children"
+
+// listKey 分页结构的列表字段
+const listKey = "list"
+
+// HiddenFromContext 获取请求上下文中无权限查看的列
+func HiddenFromContext(ctx context.Context) []string {
+	if ctx == nil {
+		return nil
+	}
+	hidden, _ := ctx.Value(HiddenColumnsKey).([]string)
+	return hidden
+}
+
+// Strip 剔除响应数据中无权限查看的字段
+// 支持单个对象、列表、树形结构(children)以及分页结构(list)
+func Strip(ctx context.Context, data any) any {
+	hidden := HiddenFromContext(ctx)
+	if len(hidden) == 0 || data == nil {
+		return data
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return data
+	}
+	var value any
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return data
+	}
+
+	set := normalizeSet(hidden)
+	// 分页结构只处理列表数据
+	if page, ok := value.(map[string]any); ok {
+		if list, ok := page[listKey].([]any); ok {
+			page[listKey] = stripValue(list, set)
+			return page
+		}
+	}
+	return stripValue(value, set)
+}
+
+// ExcelColumns 剔除导出配置中无权限查看的列
+func ExcelColumns(ctx context.Context, cols []excelutil.ExcelColumn) []excelutil.ExcelColumn {
+	hidden := HiddenFromContext(ctx)
+	if len(hidden) == 0 {
+		return cols
+	}
+
+	set := normalizeSet(hidden)
+	result := make([]excelutil.ExcelColumn, 0, len(cols))
+	for _, col := range cols {
+		// 嵌套字段(Dept.Name)按顶层字段判断
+		field, _, _ := strings.Cut(col.Field, ".")
+		if _, ok := set[normalize(field)]; ok {
+			continue
+		}
+		result = append(result, col)
+	}
+	return result
+}
+
+// stripValue 递归处理列表及子节点
+func stripValue(value any, set map[string]struct{}) any {
+	switch v := value.(type) {
+	case []any:
+		for i := range v {
+			v[i] = stripValue(v[i], set)
+		}
+		return v
+	case map[string]any:
+		for key := range v {
+			if key == childrenKey {
+				continue
+			}
+			if _, ok := set[normalize(key)]; ok {
+				delete(v, key)
+			}
+		}
+		if children, ok := v[childrenKey]; ok {
+			v[childrenKey] = stripValue(children, set)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// normalizeSet 构建列名集合
+func normalizeSet(fields []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(fields))
+	for _, field := range fields {
+		set[normalize(field)] = struct{}{}
+	}
+	return set
+}
+
+// normalize 统一列名格式，兼容 json 字段名(parent_id/belongDept)与结构体字段名(ParentID/BelongDept)
+func normalize(field string) string {
+	return strings.ToLower(strings.ReplaceAll(field, "_", ""))
+}
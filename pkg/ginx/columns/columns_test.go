@@ -0,0 +1,103 @@
+/**
+ * Description：
+ * FileName：columns_test.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 19:12:40
+ * Remark：
+ */
+
+package columns
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/excelutil"
+	"reflect"
+	"testing"
+)
+
+type testDept struct {
+	Id         string     `json:"id"`
+	Name       string     `json:"name"`
+	ParentId   string     `json:"parent_id"`
+	BelongDept string     `json:"belongDept"`
+	Children   []testDept `json:"children"`
+}
+
+type testPage struct {
+	List  []testDept `json:"list"`
+	Total int64      `json:"total"`
+}
+
+func TestStrip(t *testing.T) {
+	hidden := context.WithValue(context.Background(), HiddenColumnsKey, []string{"ParentID", "belong_dept", "children", "total"})
+	dept := testDept{Id: "1", Name: "总部", ParentId: "0", BelongDept: "d1"}
+	tree := testDept{Id: "1", Name: "总部", ParentId: "0", Children: []testDept{{Id: "2", Name: "研发部", ParentId: "1"}}}
+
+	testCases := []struct {
+		name string
+		ctx  context.Context
+		data any
+		want string
+	}{
+		{name: "无隐藏列", ctx: context.Background(), data: dept, want: `{"belongDept":"d1","children":null,"id":"1","name":"总部","parent_id":"0"}`},
+		{name: "单个对象", ctx: hidden, data: dept, want: `{"children":null,"id":"1","name":"总部"}`},
+		{name: "列表", ctx: hidden, data: []testDept{dept}, want: `[{"children":null,"id":"1","name":"总部"}]`},
+		{name: "树形结构保留子节点", ctx: hidden, data: tree, want: `{"children":[{"children":null,"id":"2","name":"研发部"}],"id":"1","name":"总部"}`},
+		{name: "分页结构只处理列表", ctx: hidden, data: testPage{List: []testDept{dept}, Total: 1}, want: `{"list":[{"children":null,"id":"1","name":"总部"}],"total":1}`},
+		{name: "空数据", ctx: hidden, data: nil, want: `null`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			raw, err := json.Marshal(Strip(tc.ctx, tc.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			// 统一按字段名排序后比较
+			var value any
+			if err := json.Unmarshal(raw, &value); err != nil {
+				t.Fatal(err)
+			}
+			got, _ := json.Marshal(value)
+			if string(got) != tc.want {
+				t.Errorf("Strip() = %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestExcelColumns(t *testing.T) {
+	cols := []excelutil.ExcelColumn{
+		{Title: "用户名", Field: "Username"},
+		{Title: "手机号", Field: "Mobile"},
+		{Title: "部门", Field: "Dept.Name"},
+		{Title: "归属部门", Field: "BelongDept"},
+	}
+
+	testCases := []struct {
+		name   string
+		hidden []string
+		want   []string
+	}{
+		{name: "无隐藏列", want: []string{"Username", "Mobile", "Dept.Name", "BelongDept"}},
+		{name: "json字段名", hidden: []string{"mobile", "belong_dept"}, want: []string{"Username", "Dept.Name"}},
+		{name: "嵌套字段按顶层字段判断", hidden: []string{"dept"}, want: []string{"Username", "Mobile", "BelongDept"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tc.hidden != nil {
+				ctx = context.WithValue(ctx, HiddenColumnsKey, tc.hidden)
+			}
+			var got []string
+			for _, col := range ExcelColumns(ctx, cols) {
+				got = append(got, col.Field)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("ExcelColumns() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+/**
+ * Description：
+ * FileName：datascope.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 11:02:15
+ * Remark：
+ */
+
+package filters
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"strings"
+)
+
+// DataScopeKey 请求上下文中数据权限范围的键
+const DataScopeKey = "dataScope"
+
+// DataScope 数据权限范围
+// 多个角色的数据权限取并集，All 为 true 时不做任何限制
+type DataScope struct {
+	All     bool     `json:"all"`     // 全部数据权限
+	UserId  string   `json:"userId"`  // 当前用户ID
+	Self    bool     `json:"self"`    // 本人创建的数据
+	DeptIds []string `json:"deptIds"` // 可访问的数据归属部门
+}
+
+// DataScopeFromContext 获取请求上下文中的数据权限范围
+func DataScopeFromContext(ctx context.Context) (DataScope, bool) {
+	if ctx == nil {
+		return DataScope{}, false
+	}
+	scope, ok := ctx.Value(DataScopeKey).(DataScope)
+	return scope, ok
+}
+
+// DataScopeRestricted 请求上下文是否受数据权限限制
+func DataScopeRestricted(ctx context.Context) bool {
+	scope, ok := DataScopeFromContext(ctx)
+	return ok && !scope.All
+}
+
+// Allow 校验单条数据是否在数据权限范围内
+// owners 为额外视为"本人数据"的字段值(如用户表主键)
+func (s DataScope) Allow(creator, belongDept string, owners ...string) bool {
+	if s.All {
+		return true
+	}
+	if s.Self && creator == s.UserId {
+		return true
+	}
+	for _, owner := range owners {
+		if owner != "" && owner == s.UserId {
+			return true
+		}
+	}
+	for _, id := range s.DeptIds {
+		if id == belongDept {
+			return true
+		}
+	}
+	return false
+}
+
+// WithDataScope 数据权限查询条件
+// 上下文中没有数据权限范围(如系统内部调用、白名单接口)时不做限制
+// ownerColumns 为额外视为"本人数据"的列(如用户表主键 id)
+func WithDataScope(ctx context.Context, ownerColumns ...string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		scope, ok := DataScopeFromContext(ctx)
+		if !ok || scope.All {
+			return db
+		}
+
+		var conditions []string
+		var args []interface{}
+		if scope.Self {
+			conditions = append(conditions, "creator = ?")
+			args = append(args, scope.UserId)
+		}
+		for _, column := range ownerColumns {
+			conditions = append(conditions, column+" = ?")
+			args = append(args, scope.UserId)
+		}
+		if len(scope.DeptIds) > 0 {
+			conditions = append(conditions, "belong_dept IN ?")
+			args = append(args, scope.DeptIds)
+		}
+
+		// 没有任何数据权限
+		if len(conditions) == 0 {
+			return db.Where("1 = 0")
+		}
+
+		return db.Where("("+strings.Join(conditions, " OR ")+")", args...)
+	}
+}
@@ -0,0 +1,38 @@
+/**
+ * Description：
+ * FileName：datascope_test.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 11:48:20
+ * Remark：
+ */
+
+package filters
+
+import "testing"
+
+func TestDataScope_Allow(t *testing.T) {
+	testCases := []struct {
+		name       string
+		scope      DataScope
+		creator    string
+		belongDept string
+		owners     []string
+		want       bool
+	}{
+		{name: "全部数据", scope: DataScope{All: true, UserId: "u1"}, creator: "u2", belongDept: "d2", want: true},
+		{name: "本人创建", scope: DataScope{UserId: "u1", Self: true}, creator: "u1", belongDept: "d2", want: true},
+		{name: "非本人创建", scope: DataScope{UserId: "u1", Self: true}, creator: "u2", belongDept: "d2", want: false},
+		{name: "部门内数据", scope: DataScope{UserId: "u1", DeptIds: []string{"d1", "d2"}}, creator: "u2", belongDept: "d2", want: true},
+		{name: "部门外数据", scope: DataScope{UserId: "u1", DeptIds: []string{"d1"}}, creator: "u2", belongDept: "d2", want: false},
+		{name: "本人用户记录", scope: DataScope{UserId: "u1"}, creator: "u2", belongDept: "d2", owners: []string{"u1"}, want: true},
+		{name: "无任何权限", scope: DataScope{UserId: "u1"}, creator: "u1", belongDept: "d1", want: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.scope.Allow(tc.creator, tc.belongDept, tc.owners...); got != tc.want {
+				t.Errorf("Allow(%q, %q, %v) = %v, want %v", tc.creator, tc.belongDept, tc.owners, got, tc.want)
+			}
+		})
+	}
+}
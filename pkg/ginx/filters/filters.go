@@ -35,5 +35,8 @@ func (f *Filters) Apply(query *gorm.DB) *gorm.DB {
 	if f.Modifier != "" {
 		query = query.Where("modifier LIKE ?", "%"+f.Modifier+"%")
 	}
+	if f.BelongDept != "" {
+		query = query.Where("belong_dept = ?", f.BelongDept)
+	}
 	return query
 }
@@ -30,15 +30,6 @@ func (lr *LoggingReader) Read(p []byte) (n int, err error) {
 	return
 }
 
-func (lr *LoggingReader) Format() any {
-	var result map[string]any
-	err := json.Unmarshal([]byte(lr.Buffer.String()), &result)
-	if err != nil {
-		return nil
-	}
-	return result
-}
-
 // CustomGinResponseWriter 自定义 Gin 响应写入器
 type CustomGinResponseWriter struct {
 	gin.ResponseWriter
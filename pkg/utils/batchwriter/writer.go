@@ -0,0 +1,266 @@
+/**
+ * Description：
+ * FileName：writer.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 10:05:12
+ * Remark：
+ */
+
+// Package batchwriter 有界队列 + 后台批量写入，用于操作日志、缓存日志等不影响主流程的写入
+package batchwriter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 队列已满时的处理方式
+const (
+	OverflowDrop  = "drop"  // 丢弃
+	OverflowSpill = "spill" // 追加写入本地文件
+)
+
+// 丢弃告警最短间隔，避免队列持续满载时刷屏
+const dropWarnInterval = 10 * time.Second
+
+var ErrClosed = errors.New("写入器已关闭")
+
+// Config 写入器配置
+type Config struct {
+	Name          string        // 名称，用于日志及溢出文件名
+	QueueSize     int           // 队列容量，默认 10000
+	BatchSize     int           // 每批写入条数，默认 200
+	FlushInterval time.Duration // 刷新间隔，默认 1 秒
+	Overflow      string        // 队列已满时的处理方式，默认丢弃
+	SpillDir      string        // 溢出文件目录，默认 tmp/spill
+}
+
+// FlushFunc 批量写入，返回错误时该批数据按溢出方式处理
+type FlushFunc[T any] func(ctx context.Context, items []T) error
+
+// Writer 批量写入器
+// Write 不阻塞，由后台协程按数量或时间间隔批量写入，Close 时写完队列中剩余的数据
+type Writer[T any] struct {
+	cfg   Config
+	flush FlushFunc[T]
+
+	mu      sync.RWMutex // 保护 closed，防止关闭后继续向队列发送
+	closed  bool
+	queue   chan T
+	flushCh chan chan struct{}
+	done    chan struct{}
+
+	spillMu  sync.Mutex
+	dropped  atomic.Uint64
+	spilled  atomic.Uint64
+	lastWarn atomic.Int64
+}
+
+// New 创建写入器并启动后台协程
+func New[T any](cfg Config, flush FlushFunc[T]) *Writer[T] {
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = 10000
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 200
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = time.Second
+	}
+	if cfg.Overflow != OverflowSpill {
+		cfg.Overflow = OverflowDrop
+	}
+	if cfg.SpillDir == "" {
+		cfg.SpillDir = filepath.Join("tmp", "spill")
+	}
+
+	w := &Writer[T]{
+		cfg:     cfg,
+		flush:   flush,
+		queue:   make(chan T, cfg.QueueSize),
+		flushCh: make(chan chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// Write 加入队列，队列已满或已关闭时按溢出方式处理，返回是否进入队列
+func (w *Writer[T]) Write(item T) bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if !w.closed {
+		select {
+		case w.queue <- item:
+			return true
+		default:
+		}
+	}
+	w.overflow([]T{item})
+	return false
+}
+
+// Flush 立即写入队列中已有的数据并等待完成
+func (w *Writer[T]) Flush(ctx context.Context) error {
+	w.mu.RLock()
+	closed := w.closed
+	w.mu.RUnlock()
+	if closed {
+		return ErrClosed
+	}
+
+	ack := make(chan struct{})
+	select {
+	case w.flushCh <- ack:
+	case <-w.done:
+		return ErrClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case <-ack:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close 停止接收并写完队列中剩余的数据，ctx 超时后返回，剩余数据由后台协程继续写入
+func (w *Writer[T]) Close(ctx context.Context) error {
+	w.mu.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.queue)
+	}
+	w.mu.Unlock()
+
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("%s 队列未写完: %w", w.cfg.Name, ctx.Err())
+	}
+}
+
+// Dropped 已丢弃条数
+func (w *Writer[T]) Dropped() uint64 {
+	return w.dropped.Load()
+}
+
+// Spilled 已写入溢出文件条数
+func (w *Writer[T]) Spilled() uint64 {
+	return w.spilled.Load()
+}
+
+// run 后台协程，达到批量大小或刷新间隔时写入
+func (w *Writer[T]) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]T, 0, w.cfg.BatchSize)
+	write := func() {
+		if len(batch) == 0 {
+			return
+		}
+		w.write(batch)
+		batch = make([]T, 0, w.cfg.BatchSize)
+	}
+
+	for {
+		select {
+		case item, ok := <-w.queue:
+			if !ok {
+				write()
+				return
+			}
+			batch = append(batch, item)
+			if len(batch) >= w.cfg.BatchSize {
+				write()
+			}
+		case <-ticker.C:
+			write()
+		case ack := <-w.flushCh:
+			// 取出已入队的数据一并写入
+			for drained := false; !drained; {
+				select {
+				case item, ok := <-w.queue:
+					if !ok {
+						drained = true
+						break
+					}
+					batch = append(batch, item)
+					if len(batch) >= w.cfg.BatchSize {
+						write()
+					}
+				default:
+					drained = true
+				}
+			}
+			write()
+			close(ack)
+		}
+	}
+}
+
+// write 写入一批数据，失败时按溢出方式处理
+func (w *Writer[T]) write(batch []T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := w.flush(ctx, batch); err != nil {
+		zap.L().Error("批量写入失败", zap.String("name", w.cfg.Name), zap.Int("count", len(batch)), zap.Error(err))
+		w.overflow(batch)
+	}
+}
+
+// overflow 丢弃或写入溢出文件
+func (w *Writer[T]) overflow(items []T) {
+	if w.cfg.Overflow == OverflowSpill {
+		err := w.spill(items)
+		if err == nil {
+			w.spilled.Add(uint64(len(items)))
+			return
+		}
+		zap.L().Error("写入溢出文件失败", zap.String("name", w.cfg.Name), zap.Error(err))
+	}
+
+	total := w.dropped.Add(uint64(len(items)))
+	now := time.Now().UnixNano()
+	last := w.lastWarn.Load()
+	if now-last >= int64(dropWarnInterval) && w.lastWarn.CompareAndSwap(last, now) {
+		zap.L().Warn("队列已满，数据已丢弃", zap.String("name", w.cfg.Name), zap.Uint64("dropped", total))
+	}
+}
+
+// spill 以 JSON Lines 格式按天追加写入溢出文件
+func (w *Writer[T]) spill(items []T) error {
+	w.spillMu.Lock()
+	defer w.spillMu.Unlock()
+
+	if err := os.MkdirAll(w.cfg.SpillDir, 0o755); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s-%s.jsonl", w.cfg.Name, time.Now().Format("20060102"))
+	f, err := os.OpenFile(filepath.Join(w.cfg.SpillDir, name), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	encoder := json.NewEncoder(f)
+	for _, item := range items {
+		if err := encoder.Encode(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+/**
+ * Description：
+ * FileName：writer_test.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 10:24:47
+ * Remark：
+ */
+
+package batchwriter
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder 记录每批写入的数据
+type recorder struct {
+	mu      sync.Mutex
+	batches [][]int
+	block   chan struct{}
+	err     error
+}
+
+func (r *recorder) flush(ctx context.Context, items []int) error {
+	if r.block != nil {
+		<-r.block
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.batches = append(r.batches, append([]int(nil), items...))
+	return r.err
+}
+
+func (r *recorder) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for _, b := range r.batches {
+		n += len(b)
+	}
+	return n
+}
+
+func TestWriter_BatchSizeAndClose(t *testing.T) {
+	r := &recorder{}
+	w := New(Config{Name: "test", BatchSize: 3, FlushInterval: time.Hour}, r.flush)
+	for i := 0; i < 7; i++ {
+		if !w.Write(i) {
+			t.Fatalf("写入 %d 失败", i)
+		}
+	}
+	if err := w.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// 达到批量大小时写入，关闭时写完剩余数据
+	if len(r.batches) != 3 || len(r.batches[0]) != 3 || len(r.batches[2]) != 1 || r.count() != 7 {
+		t.Fatalf("batches = %v", r.batches)
+	}
+	// 关闭后不再接收
+	if w.Write(8) || w.Dropped() != 1 {
+		t.Fatalf("关闭后写入应被丢弃, dropped = %d", w.Dropped())
+	}
+	if err := w.Flush(context.Background()); !errors.Is(err, ErrClosed) {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestWriter_FlushInterval(t *testing.T) {
+	r := &recorder{}
+	w := New(Config{Name: "test", BatchSize: 100, FlushInterval: 20 * time.Millisecond}, r.flush)
+	defer w.Close(context.Background())
+
+	w.Write(1)
+	deadline := time.Now().Add(time.Second)
+	for r.count() == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if r.count() != 1 {
+		t.Fatal("未按刷新间隔写入")
+	}
+
+	w.Write(2)
+	if err := w.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if r.count() != 2 {
+		t.Fatalf("Flush 后应写入全部数据, count = %d", r.count())
+	}
+}
+
+func TestWriter_OverflowDrop(t *testing.T) {
+	r := &recorder{block: make(chan struct{})}
+	w := New(Config{Name: "test", QueueSize: 2, BatchSize: 1, FlushInterval: time.Hour}, r.flush)
+
+	// 第一条被后台协程取出并阻塞在写入中，随后队列容量为 2
+	w.Write(0)
+	time.Sleep(20 * time.Millisecond)
+	accepted := 0
+	for i := 1; i <= 5; i++ {
+		if w.Write(i) {
+			accepted++
+		}
+	}
+	if accepted != 2 || w.Dropped() != 3 {
+		t.Fatalf("accepted = %d, dropped = %d", accepted, w.Dropped())
+	}
+
+	close(r.block)
+	if err := w.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if r.count() != 3 {
+		t.Fatalf("count = %d", r.count())
+	}
+}
+
+func TestWriter_OverflowSpill(t *testing.T) {
+	dir := t.TempDir()
+	r := &recorder{err: errors.New("db down")}
+	w := New(Config{Name: "operate", BatchSize: 2, FlushInterval: time.Hour, Overflow: OverflowSpill, SpillDir: dir}, r.flush)
+	for i := 0; i < 3; i++ {
+		w.Write(i)
+	}
+	if err := w.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// 写入失败的批次写入溢出文件
+	if w.Spilled() != 3 || w.Dropped() != 0 {
+		t.Fatalf("spilled = %d, dropped = %d", w.Spilled(), w.Dropped())
+	}
+	f, err := os.Open(filepath.Join(dir, "operate-"+time.Now().Format("20060102")+".jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	lines := 0
+	for scanner := bufio.NewScanner(f); scanner.Scan(); {
+		lines++
+	}
+	if lines != 3 {
+		t.Fatalf("lines = %d", lines)
+	}
+}
+
+func TestWriter_CloseTimeout(t *testing.T) {
+	r := &recorder{block: make(chan struct{})}
+	w := New(Config{Name: "test", BatchSize: 1, FlushInterval: time.Hour}, r.flush)
+	w.Write(1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := w.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v", err)
+	}
+	close(r.block)
+	if err := w.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
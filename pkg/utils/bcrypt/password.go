@@ -12,9 +12,12 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+// Cost 密码加密强度，调高后旧密码会在用户下次登录时重新加密
+const Cost = bcrypt.DefaultCost
+
 // HashPassword 对密码进行加密
 func HashPassword(password string) (string, error) {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	bytes, err := bcrypt.GenerateFromPassword([]byte(password), Cost)
 	if err != nil {
 		return "", err
 	}
@@ -26,3 +29,12 @@ func ComparePasswords(hashedPassword, password string) bool {
 	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
 	return err == nil
 }
+
+// NeedsRehash 密码加密强度低于当前配置时需要重新加密
+func NeedsRehash(hashedPassword string) bool {
+	cost, err := bcrypt.Cost([]byte(hashedPassword))
+	if err != nil {
+		return false
+	}
+	return cost < Cost
+}
@@ -0,0 +1,47 @@
+/**
+ * Description：
+ * FileName：password_test.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 15:12:06
+ * Remark：
+ */
+
+package bcrypt
+
+import (
+	"golang.org/x/crypto/bcrypt"
+	"testing"
+)
+
+func TestNeedsRehash(t *testing.T) {
+	weak, err := bcrypt.GenerateFromPassword([]byte("123456"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	current, err := HashPassword("123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name   string
+		hashed string
+		want   bool
+	}{
+		{name: "低于当前强度", hashed: string(weak), want: true},
+		{name: "当前强度", hashed: current, want: false},
+		{name: "非法密文", hashed: "123456", want: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := NeedsRehash(tc.hashed); got != tc.want {
+				t.Errorf("NeedsRehash() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+
+	if !ComparePasswords(current, "123456") {
+		t.Error("ComparePasswords() = false, want true")
+	}
+}
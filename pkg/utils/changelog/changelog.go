@@ -0,0 +1,521 @@
+/**
+ * Description：
+ * FileName：changelog.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 15:06:48
+ * Remark：
+ */
+
+// Package changelog 数据变更审计，通过 GORM 回调记录继承 models.CoreModels 的模型每次新增、修改、删除的字段差异
+package changelog
+
+import (
+	"context"
+	"fmt"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/models"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/requestUtils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 变更类型
+const (
+	ActionCreate  = "create"  // 新增
+	ActionUpdate  = "update"  // 修改
+	ActionDelete  = "delete"  // 删除
+	ActionRestore = "restore" // 恢复历史版本
+)
+
+const (
+	actionKey   = "changelog:action"   // 指定变更类型，未指定时按回调类型记录
+	snapshotKey = "changelog:snapshot" // 修改、删除前的数据快照
+)
+
+// primaryKey CoreModels 的主键列
+const primaryKey = "id"
+
+// DefaultExcludeTables 默认不记录的表，支持 * 通配
+var DefaultExcludeTables = []string{
+	"careful_logger_*",
+	"careful_system_user_password_history",
+}
+
+// DefaultExcludeFields 默认不记录的字段(列名)
+var DefaultExcludeFields = []string{
+	"password",
+	"secret",
+	"key_hash",
+	"recovery_codes",
+	"last_counter",
+	"last_used_time",
+	"last_used_ip",
+	"last_login_time",
+}
+
+// systemFields 每次修改都会变化的审计字段，保留在快照中但不计入差异
+var systemFields = map[string]bool{
+	"version":     true,
+	"modifier":    true,
+	"update_time": true,
+}
+
+// Config 变更审计配置，在默认规则基础上追加
+type Config struct {
+	ExcludeTables []string // 不记录的表
+	ExcludeFields []string // 不记录的字段(列名)
+}
+
+// Change 字段差异
+type Change struct {
+	Field string `json:"field"` // 字段(列名)
+	Label string `json:"label"` // 字段说明
+	Old   any    `json:"old"`   // 修改前的值
+	New   any    `json:"new"`   // 修改后的值
+}
+
+// Entry 变更记录
+type Entry struct {
+	Action     string         // 变更类型
+	Table      string         // 表名
+	RecordId   string         // 记录ID
+	Version    int            // 变更后的版本号，删除时为删除前的版本号
+	OperatorId string         // 操作人ID
+	Operator   string         // 操作人
+	Changes    []Change       // 字段差异
+	OldValues  map[string]any // 变更前的数据，新增时为空
+	NewValues  map[string]any // 变更后的数据，删除时为空
+}
+
+// WriteFunc 写入变更记录，tx 与数据变更处于同一事务
+type WriteFunc func(tx *gorm.DB, entries []Entry) error
+
+// Plugin GORM 插件
+type Plugin struct {
+	excludeTables []string
+	excludeFields map[string]bool
+	write         WriteFunc
+}
+
+// New 创建插件
+func New(cfg Config, write WriteFunc) *Plugin {
+	p := &Plugin{
+		excludeFields: make(map[string]bool),
+		write:         write,
+	}
+	for _, table := range append(append([]string{}, DefaultExcludeTables...), cfg.ExcludeTables...) {
+		if table = strings.TrimSpace(table); table != "" {
+			p.excludeTables = append(p.excludeTables, table)
+		}
+	}
+	for _, field := range append(append([]string{}, DefaultExcludeFields...), cfg.ExcludeFields...) {
+		if field = strings.TrimSpace(field); field != "" {
+			p.excludeFields[field] = true
+		}
+	}
+	return p
+}
+
+// WithAction 指定变更类型，如恢复历史版本时记录为 restore
+// 通过 Table 操作(无模型)的语句指定后同样会记录
+func WithAction(db *gorm.DB, action string) *gorm.DB {
+	return db.Set(actionKey, action)
+}
+
+func (p *Plugin) Name() string {
+	return "changelog"
+}
+
+// Initialize 注册回调
+// 变更前的快照在执行 SQL 前读取，变更记录在提交事务前写入，数据变更回滚时变更记录一并回滚
+func (p *Plugin) Initialize(db *gorm.DB) error {
+	if err := db.Callback().Create().Before("gorm:commit_or_rollback_transaction").
+		Register("changelog:after_create", p.afterCreate); err != nil {
+		return err
+	}
+	if err := db.Callback().Update().Before("gorm:update").
+		Register("changelog:before_update", p.beforeChange); err != nil {
+		return err
+	}
+	if err := db.Callback().Update().Before("gorm:commit_or_rollback_transaction").
+		Register("changelog:after_update", p.afterUpdate); err != nil {
+		return err
+	}
+	if err := db.Callback().Delete().Before("gorm:delete").
+		Register("changelog:before_delete", p.beforeChange); err != nil {
+		return err
+	}
+	return db.Callback().Delete().Before("gorm:commit_or_rollback_transaction").
+		Register("changelog:after_delete", p.afterDelete)
+}
+
+// afterCreate 新增后读取新增的数据
+func (p *Plugin) afterCreate(tx *gorm.DB) {
+	if !p.enabled(tx) {
+		return
+	}
+	ids := p.primaryValues(tx)
+	if len(ids) == 0 {
+		return
+	}
+	rows, err := p.snapshot(tx, p.newSession(tx).Where(primaryKey+" IN ?", ids))
+	if err != nil {
+		_ = tx.AddError(err)
+		return
+	}
+
+	entries := make([]Entry, 0, len(rows))
+	for _, id := range ids {
+		row, ok := rows[id]
+		if !ok {
+			continue
+		}
+		entries = append(entries, p.entry(tx, p.action(tx, ActionCreate), id, nil, row))
+	}
+	p.flush(tx, entries)
+}
+
+// beforeChange 修改、删除前按相同条件读取将受影响的数据
+func (p *Plugin) beforeChange(tx *gorm.DB) {
+	if !p.enabled(tx) {
+		return
+	}
+
+	query := p.newSession(tx)
+	conditions := false
+	if c, ok := tx.Statement.Clauses["WHERE"]; ok {
+		if where, ok := c.Expression.(clause.Where); ok && len(where.Exprs) > 0 {
+			query = query.Clauses(where)
+			conditions = true
+		}
+	}
+	// 通过模型指定主键时，主键条件在执行 SQL 时才会加入
+	if ids := p.primaryValues(tx); len(ids) > 0 {
+		query = query.Where(primaryKey+" IN ?", ids)
+		conditions = true
+	}
+	// 无条件的全表操作不记录
+	if !conditions {
+		return
+	}
+
+	rows, err := p.snapshot(tx, query)
+	if err != nil {
+		_ = tx.AddError(err)
+		return
+	}
+	tx.InstanceSet(snapshotKey, rows)
+}
+
+// afterUpdate 修改后按修改前的主键读取数据并对比差异
+func (p *Plugin) afterUpdate(tx *gorm.DB) {
+	before, ok := p.before(tx)
+	if !ok {
+		return
+	}
+
+	ids := make([]string, 0, len(before))
+	for id := range before {
+		ids = append(ids, id)
+	}
+	after, err := p.snapshot(tx, p.newSession(tx).Where(primaryKey+" IN ?", ids))
+	if err != nil {
+		_ = tx.AddError(err)
+		return
+	}
+
+	action := p.action(tx, ActionUpdate)
+	entries := make([]Entry, 0, len(after))
+	for id, row := range after {
+		entry := p.entry(tx, action, id, before[id], row)
+		// 仅审计字段或不记录的字段变化时不记录
+		if len(entry.Changes) == 0 {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	p.flush(tx, entries)
+}
+
+// afterDelete 删除后记录删除前的数据
+func (p *Plugin) afterDelete(tx *gorm.DB) {
+	before, ok := p.before(tx)
+	if !ok {
+		return
+	}
+
+	entries := make([]Entry, 0, len(before))
+	for id, row := range before {
+		entries = append(entries, p.entry(tx, ActionDelete, id, row, nil))
+	}
+	p.flush(tx, entries)
+}
+
+// enabled 语句是否需要记录
+func (p *Plugin) enabled(tx *gorm.DB) bool {
+	stmt := tx.Statement
+	if tx.Error != nil || stmt.DryRun || stmt.Table == "" || p.excludedTable(stmt.Table) {
+		return false
+	}
+	if _, ok := tx.Get(actionKey); ok {
+		return true
+	}
+	return stmt.Schema != nil && EmbedsCoreModels(stmt.Schema.ModelType)
+}
+
+// before 修改、删除执行成功时返回修改前的快照
+func (p *Plugin) before(tx *gorm.DB) (map[string]map[string]any, bool) {
+	if tx.Error != nil || tx.Statement.RowsAffected == 0 {
+		return nil, false
+	}
+	value, ok := tx.InstanceGet(snapshotKey)
+	if !ok {
+		return nil, false
+	}
+	rows, ok := value.(map[string]map[string]any)
+	return rows, ok && len(rows) > 0
+}
+
+// action 变更类型，WithAction 指定时优先
+func (p *Plugin) action(tx *gorm.DB, fallback string) string {
+	if value, ok := tx.Get(actionKey); ok {
+		if action, ok := value.(string); ok && action != "" {
+			return action
+		}
+	}
+	return fallback
+}
+
+// newSession 与当前语句使用同一连接(事务)的新查询
+func (p *Plugin) newSession(tx *gorm.DB) *gorm.DB {
+	return tx.Session(&gorm.Session{NewDB: true}).Table(tx.Statement.Table)
+}
+
+// snapshot 读取数据快照，按主键分组，去除不记录的字段
+func (p *Plugin) snapshot(tx *gorm.DB, query *gorm.DB) (map[string]map[string]any, error) {
+	var rows []map[string]any
+	if err := query.Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("读取变更快照失败: %w", err)
+	}
+
+	result := make(map[string]map[string]any, len(rows))
+	for _, row := range rows {
+		id := fmt.Sprint(normalize(row[primaryKey]))
+		snapshot := make(map[string]any, len(row))
+		for field, value := range row {
+			if p.excludeFields[field] {
+				continue
+			}
+			snapshot[field] = normalize(value)
+		}
+		result[id] = snapshot
+	}
+	return result, nil
+}
+
+// primaryValues 模型中已设置的主键
+func (p *Plugin) primaryValues(tx *gorm.DB) []string {
+	stmt := tx.Statement
+	value := stmt.ReflectValue
+	if stmt.Schema == nil {
+		// 通过 Table + map 新增时从 map 中获取
+		if row, ok := stmt.Dest.(map[string]any); ok {
+			if id, ok := row[primaryKey]; ok && id != nil && id != "" {
+				return []string{fmt.Sprint(id)}
+			}
+		}
+		return nil
+	}
+
+	field := stmt.Schema.LookUpField(primaryKey)
+	if field == nil || !value.IsValid() {
+		return nil
+	}
+	var ids []string
+	collect := func(v reflect.Value) {
+		if id, isZero := field.ValueOf(stmt.Context, v); !isZero {
+			ids = append(ids, fmt.Sprint(id))
+		}
+	}
+	switch value.Kind() {
+	case reflect.Struct:
+		collect(value)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			if item := reflect.Indirect(value.Index(i)); item.Kind() == reflect.Struct {
+				collect(item)
+			}
+		}
+	}
+	return ids
+}
+
+// entry 构建变更记录
+func (p *Plugin) entry(tx *gorm.DB, action, id string, oldValues, newValues map[string]any) Entry {
+	entry := Entry{
+		Action:    action,
+		Table:     tx.Statement.Table,
+		RecordId:  id,
+		Changes:   Diff(oldValues, newValues, p.label(tx)),
+		OldValues: oldValues,
+		NewValues: newValues,
+	}
+	if newValues != nil {
+		entry.Version = toInt(newValues["version"])
+	} else {
+		entry.Version = toInt(oldValues["version"])
+	}
+	entry.OperatorId, entry.Operator = operator(tx.Statement.Context)
+	return entry
+}
+
+// label 字段说明(模型 comment 标签)
+func (p *Plugin) label(tx *gorm.DB) func(field string) string {
+	return func(field string) string {
+		if tx.Statement.Schema == nil {
+			return ""
+		}
+		if f := tx.Statement.Schema.LookUpField(field); f != nil {
+			return f.Comment
+		}
+		return ""
+	}
+}
+
+// flush 写入变更记录，失败时回滚数据变更
+func (p *Plugin) flush(tx *gorm.DB, entries []Entry) {
+	if len(entries) == 0 || p.write == nil {
+		return
+	}
+	if err := p.write(tx.Session(&gorm.Session{NewDB: true}), entries); err != nil {
+		_ = tx.AddError(fmt.Errorf("写入变更记录失败: %w", err))
+	}
+}
+
+// excludedTable 是否为不记录的表
+func (p *Plugin) excludedTable(table string) bool {
+	for _, pattern := range p.excludeTables {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(table, prefix) {
+				return true
+			}
+			continue
+		}
+		if pattern == table {
+			return true
+		}
+	}
+	return false
+}
+
+// Diff 对比字段差异，审计字段不计入差异，结果按字段名排序
+// oldValues 为空时为新增，newValues 为空时为删除
+func Diff(oldValues, newValues map[string]any, label func(field string) string) []Change {
+	fields := make(map[string]bool, len(oldValues)+len(newValues))
+	for field := range oldValues {
+		fields[field] = true
+	}
+	for field := range newValues {
+		fields[field] = true
+	}
+
+	changes := make([]Change, 0)
+	for _, field := range sortedKeys(fields) {
+		if systemFields[field] {
+			continue
+		}
+		oldValue, newValue := oldValues[field], newValues[field]
+		if oldValues != nil && newValues != nil && reflect.DeepEqual(oldValue, newValue) {
+			continue
+		}
+		change := Change{Field: field, Old: oldValue, New: newValue}
+		if label != nil {
+			change.Label = label(field)
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// EmbedsCoreModels 模型是否继承 models.CoreModels
+func EmbedsCoreModels(modelType reflect.Type) bool {
+	if modelType == nil {
+		return false
+	}
+	for modelType.Kind() == reflect.Ptr {
+		modelType = modelType.Elem()
+	}
+	if modelType.Kind() != reflect.Struct {
+		return false
+	}
+	coreType := reflect.TypeOf(models.CoreModels{})
+	for i := 0; i < modelType.NumField(); i++ {
+		if field := modelType.Field(i); field.Anonymous && field.Type == coreType {
+			return true
+		}
+	}
+	return false
+}
+
+// operator 实际操作人，模拟登录时为发起模拟的管理员
+func operator(ctx context.Context) (string, string) {
+	if ctx == nil {
+		return "", ""
+	}
+	name, _ := ctx.Value("actorName").(string)
+	if name == "" {
+		name, _ = ctx.Value("username").(string)
+	}
+	return requestUtils.GetActorId(ctx), name
+}
+
+// normalize 统一数据库读取的值类型，便于对比及序列化
+func normalize(value any) any {
+	switch v := value.(type) {
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.Format("2006-01-02 15:04:05")
+	case *time.Time:
+		if v == nil {
+			return nil
+		}
+		return v.Format("2006-01-02 15:04:05")
+	default:
+		return v
+	}
+}
+
+// toInt 版本号转换
+func toInt(value any) int {
+	switch v := value.(type) {
+	case int:
+		return v
+	case int32:
+		return int(v)
+	case int64:
+		return int(v)
+	case uint:
+		return int(v)
+	case uint32:
+		return int(v)
+	case uint64:
+		return int(v)
+	case float64:
+		return int(v)
+	}
+	return 0
+}
+
+// sortedKeys 排序后的键
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,315 @@
+/**
+ * Description：
+ * FileName：changelog_test.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 17:02:36
+ * Remark：
+ */
+
+package changelog
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/models"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type testPost struct {
+	models.CoreModels
+	Name     string `gorm:"type:varchar(50);column:name;comment:岗位名称"`
+	Password string `gorm:"type:varchar(255);column:password;comment:密码"`
+}
+
+func (testPost) TableName() string {
+	return "careful_test_post"
+}
+
+// fakeDriver 记录执行的语句，SELECT 依次返回 results 中的数据
+type fakeDriver struct {
+	mu      sync.Mutex
+	log     []string
+	columns []string
+	results [][][]driver.Value
+}
+
+func (d *fakeDriver) record(s string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.log = append(d.log, s)
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) { return &fakeConn{d: d}, nil }
+
+type fakeConn struct{ d *fakeDriver }
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{d: c.d, query: query}, nil
+}
+func (c *fakeConn) Close() error { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	c.d.record("BEGIN")
+	return c, nil
+}
+func (c *fakeConn) Commit() error {
+	c.d.record("COMMIT")
+	return nil
+}
+func (c *fakeConn) Rollback() error {
+	c.d.record("ROLLBACK")
+	return nil
+}
+
+type fakeStmt struct {
+	d     *fakeDriver
+	query string
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+func (s *fakeStmt) Exec([]driver.Value) (driver.Result, error) {
+	s.d.record(strings.Fields(s.query)[0])
+	return fakeResult{}, nil
+}
+func (s *fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	s.d.record("SELECT")
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	rows := &fakeRows{columns: s.d.columns}
+	if len(s.d.results) > 0 {
+		rows.values, s.d.results = s.d.results[0], s.d.results[1:]
+	}
+	return rows, nil
+}
+
+type fakeResult struct{}
+
+func (fakeResult) LastInsertId() (int64, error) { return 0, nil }
+func (fakeResult) RowsAffected() (int64, error) { return 1, nil }
+
+type fakeRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+var registerOnce sync.Once
+var currentDriver = &proxyDriver{}
+
+// proxyDriver database/sql 驱动只能注册一次，每个用例替换实际驱动
+type proxyDriver struct{ d *fakeDriver }
+
+func (p *proxyDriver) Open(name string) (driver.Conn, error) { return p.d.Open(name) }
+
+func openTestDB(t *testing.T, d *fakeDriver, entries *[]Entry) *gorm.DB {
+	registerOnce.Do(func() { sql.Register("changelog_fake", currentDriver) })
+	currentDriver.d = d
+
+	conn, err := sql.Open("changelog_fake", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.SetMaxOpenConns(1)
+	db, err := gorm.Open(mysql.New(mysql.Config{Conn: conn, SkipInitializeWithVersion: true}),
+		&gorm.Config{DisableAutomaticPing: true, Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Use(New(Config{}, func(tx *gorm.DB, list []Entry) error {
+		d.record("WRITE")
+		*entries = append(*entries, list...)
+		return nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestPlugin_Update(t *testing.T) {
+	d := &fakeDriver{
+		columns: []string{"id", "name", "version", "modifier", "password"},
+		results: [][][]driver.Value{
+			{{"P1", "旧名称", int64(1), "U0", "hash1"}},
+			{{"P1", "新名称", int64(2), "U1", "hash2"}},
+		},
+	}
+	var entries []Entry
+	db := openTestDB(t, d, &entries)
+
+	ctx := context.WithValue(context.Background(), "userId", "U1")
+	err := db.WithContext(ctx).Model(&testPost{CoreModels: models.CoreModels{Id: "P1"}}).
+		Where("version = ?", 1).
+		Updates(map[string]any{"name": "新名称", "version": gorm.Expr("version + 1"), "modifier": "U1"}).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 快照在更新前读取，变更记录在提交前写入
+	if got, want := strings.Join(d.log, ","), "BEGIN,SELECT,UPDATE,SELECT,WRITE,COMMIT"; got != want {
+		t.Fatalf("执行顺序 = %s, want %s", got, want)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("变更记录 = %d, want 1", len(entries))
+	}
+	entry := entries[0]
+	if entry.Action != ActionUpdate || entry.Table != "careful_test_post" || entry.RecordId != "P1" ||
+		entry.Version != 2 || entry.OperatorId != "U1" {
+		t.Errorf("变更记录 = %+v", entry)
+	}
+	want := []Change{{Field: "name", Label: "岗位名称", Old: "旧名称", New: "新名称"}}
+	if !reflect.DeepEqual(entry.Changes, want) {
+		t.Errorf("字段差异 = %+v, want %+v", entry.Changes, want)
+	}
+	if _, ok := entry.OldValues["password"]; ok {
+		t.Error("快照记录了不记录的字段")
+	}
+}
+
+func TestPlugin_DeleteAndRestore(t *testing.T) {
+	d := &fakeDriver{columns: []string{"id", "name", "version"}}
+	var entries []Entry
+	db := openTestDB(t, d, &entries)
+
+	// 删除
+	d.results = [][][]driver.Value{{{"P1", "岗位", int64(3)}}}
+	if err := db.Where("id = ?", "P1").Delete(&testPost{}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if got, want := strings.Join(d.log, ","), "BEGIN,SELECT,DELETE,WRITE,COMMIT"; got != want {
+		t.Fatalf("执行顺序 = %s, want %s", got, want)
+	}
+
+	// 通过 Table 重新创建已删除的数据
+	d.results = [][][]driver.Value{{{"P1", "岗位", int64(4)}}}
+	err := WithAction(db, ActionRestore).Table("careful_test_post").
+		Create(map[string]any{"id": "P1", "name": "岗位", "version": 4}).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("变更记录 = %+v, want 2", entries)
+	}
+	if e := entries[0]; e.Action != ActionDelete || e.Version != 3 || e.NewValues != nil || e.OldValues["name"] != "岗位" {
+		t.Errorf("删除记录 = %+v", e)
+	}
+	if e := entries[1]; e.Action != ActionRestore || e.Version != 4 || e.OldValues != nil || e.NewValues["name"] != "岗位" {
+		t.Errorf("恢复记录 = %+v", e)
+	}
+}
+
+func TestPlugin_SkipExcluded(t *testing.T) {
+	d := &fakeDriver{}
+	var entries []Entry
+	db := openTestDB(t, d, &entries)
+
+	// 仅不记录的字段变化
+	d.columns = []string{"id", "version", "password"}
+	d.results = [][][]driver.Value{
+		{{"P1", int64(1), "hash1"}},
+		{{"P1", int64(2), "hash2"}},
+	}
+	err := db.Model(&testPost{}).Where("id = ?", "P1").
+		Updates(map[string]any{"password": "hash2", "version": gorm.Expr("version + 1")}).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("变更记录 = %+v, want 0", entries)
+	}
+
+	// 未继承 CoreModels 的模型
+	d.log = nil
+	type plain struct {
+		Id   string
+		Name string
+	}
+	if err := db.Table("careful_test_plain").Where("id = ?", "P1").Updates(&plain{Name: "a"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(strings.Join(d.log, ","), "SELECT") {
+		t.Errorf("执行顺序 = %v, 不应读取快照", d.log)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	label := func(field string) string { return "L:" + field }
+
+	testCases := []struct {
+		name      string
+		oldValues map[string]any
+		newValues map[string]any
+		want      []Change
+	}{
+		{
+			name:      "新增",
+			newValues: map[string]any{"id": "1", "name": "a", "version": int64(1)},
+			want: []Change{
+				{Field: "id", Label: "L:id", New: "1"},
+				{Field: "name", Label: "L:name", New: "a"},
+			},
+		},
+		{
+			name:      "修改忽略审计字段",
+			oldValues: map[string]any{"name": "a", "sort": int64(1), "version": int64(1), "update_time": "t1"},
+			newValues: map[string]any{"name": "a", "sort": int64(2), "version": int64(2), "update_time": "t2"},
+			want:      []Change{{Field: "sort", Label: "L:sort", Old: int64(1), New: int64(2)}},
+		},
+		{
+			name:      "删除",
+			oldValues: map[string]any{"name": "a"},
+			want:      []Change{{Field: "name", Label: "L:name", Old: "a"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Diff(tc.oldValues, tc.newValues, label); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Diff() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPlugin_ExcludedTable(t *testing.T) {
+	p := New(Config{ExcludeTables: []string{"careful_tools_bucket"}}, nil)
+	testCases := map[string]bool{
+		"careful_logger_change_log":            true,
+		"careful_system_user_password_history": true,
+		"careful_tools_bucket":                 true,
+		"careful_system_user":                  false,
+	}
+	for table, want := range testCases {
+		if got := p.excludedTable(table); got != want {
+			t.Errorf("excludedTable(%s) = %v, want %v", table, got, want)
+		}
+	}
+}
+
+func TestEmbedsCoreModels(t *testing.T) {
+	if !EmbedsCoreModels(reflect.TypeOf(&testPost{})) {
+		t.Error("testPost 继承了 CoreModels")
+	}
+	if EmbedsCoreModels(reflect.TypeOf(struct{ Id string }{})) {
+		t.Error("匿名结构体未继承 CoreModels")
+	}
+}
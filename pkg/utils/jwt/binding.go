@@ -0,0 +1,37 @@
+/**
+ * Description：
+ * FileName：binding.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 03:30:42
+ * Remark：
+ */
+
+package jwt
+
+import "strings"
+
+// BindingMode 令牌与客户端绑定的校验方式
+type BindingMode string
+
+const (
+	BindingOff    BindingMode = "off"    // 不校验
+	BindingWarn   BindingMode = "warn"   // 不一致时记录审计日志并放行
+	BindingReject BindingMode = "reject" // 不一致时记录审计日志并拒绝请求
+)
+
+// ParseBindingMode 解析配置，未配置或无法识别时不校验
+func ParseBindingMode(mode string) BindingMode {
+	switch BindingMode(strings.ToLower(strings.TrimSpace(mode))) {
+	case BindingWarn:
+		return BindingWarn
+	case BindingReject:
+		return BindingReject
+	default:
+		return BindingOff
+	}
+}
+
+// Enabled 是否需要校验
+func (m BindingMode) Enabled() bool {
+	return m == BindingWarn || m == BindingReject
+}
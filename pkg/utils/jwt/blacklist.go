@@ -19,6 +19,8 @@ import (
 const (
 	// TokenBlacklistPrefix Redis中存储已登出token的前缀
 	TokenBlacklistPrefix = "token:blacklist:"
+	// TokenJtiBlacklistPrefix Redis中存储已吊销会话(jti)的前缀
+	TokenJtiBlacklistPrefix = "token:blacklist:jti:"
 )
 
 // TokenBlacklist JWT Token黑名单实现
@@ -51,3 +53,19 @@ func (b *TokenBlacklist) IsBlacklisted(ctx context.Context, tokenStr string) (bo
 	}
 	return result > 0, nil
 }
+
+// AddJti 将会话(jti)加入黑名单，该会话签发的令牌全部失效
+func (b *TokenBlacklist) AddJti(ctx context.Context, jti string, expiresIn time.Duration) error {
+	key := fmt.Sprintf("%s%s", TokenJtiBlacklistPrefix, jti)
+	return b.rdb.Set(ctx, key, "1", expiresIn).Err()
+}
+
+// IsJtiBlacklisted 检查会话(jti)是否在黑名单中
+func (b *TokenBlacklist) IsJtiBlacklisted(ctx context.Context, jti string) (bool, error) {
+	key := fmt.Sprintf("%s%s", TokenJtiBlacklistPrefix, jti)
+	result, err := b.rdb.Exists(ctx, key).Result()
+	if err != nil {
+		return false, err
+	}
+	return result > 0, nil
+}
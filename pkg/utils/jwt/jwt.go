@@ -9,12 +9,16 @@
 package jwt
 
 import (
+	"crypto/rand"
+	"encoding/base64"
 	"errors"
 	"time"
 
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/requestUtils"
 	"github.com/gin-gonic/gin"
 
 	"github.com/golang-jwt/jwt/v5"
+	uuid "github.com/satori/go.uuid"
 )
 
 var (
@@ -29,42 +33,110 @@ type Claims struct {
 	Username  string `json:"username"`
 	UserType  int    `json:"userType"`
 	UserAgent string `json:"userAgent"`
+	Ip        string `json:"ip"`
 	DeptId    string `json:"deptId"`
+	// 模拟登录时 UserId 与 SubjectId 为被模拟的用户，ActorId 为发起模拟的管理员
+	ActorId   string `json:"actorId,omitempty"`
+	ActorName string `json:"actorName,omitempty"`
+	SubjectId string `json:"subjectId,omitempty"`
+	// PasswordChange 需先修改密码(初始密码或密码已过期)，令牌仅可访问修改密码等接口
+	PasswordChange bool `json:"passwordChange,omitempty"`
+	// TwoFactorSetup 要求启用双因素认证但尚未绑定，令牌仅可访问绑定双因素认证等接口
+	TwoFactorSetup bool `json:"twoFactorSetup,omitempty"`
 }
 
-// GenerateToken generates a new JWT token
-func GenerateToken(ctx *gin.Context, userId, username string, userType int, deptId, secret string, expireHours int) (string, error) {
+// Impersonating 是否为模拟登录令牌
+func (c *Claims) Impersonating() bool {
+	return c.ActorId != ""
+}
+
+// TokenPair 访问令牌及刷新令牌
+type TokenPair struct {
+	AccessToken  string  // 短期访问令牌(JWT)
+	RefreshToken string  // 长期刷新令牌(不透明字符串，存储于Redis)
+	Claims       *Claims // 访问令牌声明
+}
+
+// GenerateToken generates a new JWT access token together with an opaque refresh token,
+// the jti claim identifies the session, passwordChange issues a restricted token that may only change the password,
+// twoFactorSetup issues a restricted token that may only enable two-factor authentication
+func GenerateToken(ctx *gin.Context, userId, username string, userType int, deptId string, passwordChange, twoFactorSetup bool,
+	keys *KeySet, expire time.Duration) (*TokenPair, error) {
 	// Set claims
-	claims := Claims{
+	claims := &Claims{
+		UserId:         userId,
+		Username:       username,
+		UserType:       userType,
+		UserAgent:      ctx.GetHeader("User-Agent"),
+		Ip:             requestUtils.NormalizeIP(ctx),
+		DeptId:         deptId,
+		PasswordChange: passwordChange,
+		TwoFactorSetup: twoFactorSetup,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewV4().String(),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expire)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			NotBefore: jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	// Sign token with the current signing key
+	tokenStr, err := keys.sign(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		AccessToken:  tokenStr,
+		RefreshToken: refreshToken,
+		Claims:       claims,
+	}, nil
+}
+
+// GenerateImpersonationToken generates a short-lived access token that acts as the subject
+// on behalf of the actor, no refresh token is issued
+func GenerateImpersonationToken(actorId, actorName, userId, username string, userType int, deptId, userAgent, ip string,
+	keys *KeySet, expire time.Duration) (string, *Claims, error) {
+	now := time.Now()
+	claims := &Claims{
 		UserId:    userId,
 		Username:  username,
 		UserType:  userType,
-		UserAgent: ctx.GetHeader("User-Agent"),
+		UserAgent: userAgent,
+		Ip:        ip,
 		DeptId:    deptId,
+		ActorId:   actorId,
+		ActorName: actorName,
+		SubjectId: userId,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour * time.Duration(expireHours))),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
+			ID:        uuid.NewV4().String(),
+			ExpiresAt: jwt.NewNumericDate(now.Add(expire)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
 		},
 	}
 
-	// Create token
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-
-	// Sign token with secret
-	return token.SignedString([]byte(secret))
+	tokenStr, err := keys.sign(claims)
+	if err != nil {
+		return "", nil, err
+	}
+	return tokenStr, claims, nil
 }
 
 // ParseToken parses a JWT token and returns the claims
-func ParseToken(tokenString, secret string) (*Claims, error) {
+func ParseToken(tokenString string, keys *KeySet) (*Claims, error) {
 	if tokenString == "" {
 		return nil, ErrTokenNotFound
 	}
 
 	// Parse token
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(secret), nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, keys.keyFunc,
+		jwt.WithValidMethods([]string{"HS256", "RS256", "ES256"}))
 
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenExpired) {
@@ -86,3 +158,12 @@ func ParseToken(tokenString, secret string) (*Claims, error) {
 
 	return claims, nil
 }
+
+// newRefreshToken generates a random opaque refresh token
+func newRefreshToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
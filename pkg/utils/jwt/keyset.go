@@ -0,0 +1,281 @@
+/**
+ * Description：
+ * FileName：keyset.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 20:25:40
+ * Remark：
+ */
+
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var ErrKeyNotFound = errors.New("未找到签名密钥")
+
+// KeyFile 签名密钥配置
+type KeyFile struct {
+	Kid            string // 密钥ID，写入令牌头部 kid
+	Algorithm      string // 签名算法 RS256/ES256
+	PrivateKeyFile string // 私钥PEM文件，为空时该密钥仅用于校验
+	PublicKeyFile  string // 公钥PEM文件，为空时由私钥推导
+}
+
+// Key 签名密钥
+type Key struct {
+	Kid        string
+	Method     jwt.SigningMethod
+	PrivateKey crypto.PrivateKey
+	PublicKey  crypto.PublicKey
+}
+
+// KeySet 令牌签名密钥集合
+// 配置了非对称密钥时使用 signingKid 对应的密钥签名，其余密钥保留用于校验轮换前签发且未过期的令牌；
+// 未配置时退回 HS256 共享密钥
+type KeySet struct {
+	secret  []byte
+	signing *Key
+	keys    map[string]*Key
+	order   []string
+}
+
+// NewKeySet 创建令牌签名密钥集合
+func NewKeySet(secret, signingKid string, files []KeyFile) (*KeySet, error) {
+	ks := &KeySet{
+		secret: []byte(secret),
+		keys:   make(map[string]*Key, len(files)),
+	}
+
+	for _, file := range files {
+		key, err := loadKey(file)
+		if err != nil {
+			return nil, fmt.Errorf("加载签名密钥 %s 失败: %w", file.Kid, err)
+		}
+		if _, ok := ks.keys[key.Kid]; ok {
+			return nil, fmt.Errorf("签名密钥 %s 重复", key.Kid)
+		}
+		ks.keys[key.Kid] = key
+		ks.order = append(ks.order, key.Kid)
+	}
+
+	if len(ks.keys) > 0 {
+		if signingKid == "" {
+			signingKid = ks.order[0]
+		}
+		signing, ok := ks.keys[signingKid]
+		if !ok {
+			return nil, fmt.Errorf("签名密钥 %s: %w", signingKid, ErrKeyNotFound)
+		}
+		if signing.PrivateKey == nil {
+			return nil, fmt.Errorf("签名密钥 %s 未配置私钥", signingKid)
+		}
+		ks.signing = signing
+	} else if len(ks.secret) == 0 {
+		return nil, errors.New("未配置令牌密钥")
+	}
+
+	return ks, nil
+}
+
+// NewSecretKeySet 创建仅使用 HS256 共享密钥的集合
+func NewSecretKeySet(secret string) *KeySet {
+	return &KeySet{
+		secret: []byte(secret),
+		keys:   map[string]*Key{},
+	}
+}
+
+// sign 使用当前签名密钥签名
+func (ks *KeySet) sign(claims jwt.Claims) (string, error) {
+	if ks.signing == nil {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(ks.secret)
+	}
+
+	token := jwt.NewWithClaims(ks.signing.Method, claims)
+	token.Header["kid"] = ks.signing.Kid
+	return token.SignedString(ks.signing.PrivateKey)
+}
+
+// keyFunc 根据令牌头部的 kid 及算法选择校验密钥，算法必须与密钥一致
+func (ks *KeySet) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok && len(ks.secret) > 0 {
+			return ks.secret, nil
+		}
+		return nil, ErrKeyNotFound
+	}
+
+	key, ok := ks.keys[kid]
+	if !ok || token.Method.Alg() != key.Method.Alg() {
+		return nil, ErrKeyNotFound
+	}
+	return key.PublicKey, nil
+}
+
+// JWK 公钥(RFC 7517)
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// PublicKey 解析 JWK 中的 RSA 或 EC 公钥
+func (k JWK) PublicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("解析 JWK n 失败: %w", err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("解析 JWK e 失败: %w", err)
+		}
+		exponent := new(big.Int).SetBytes(e)
+		if len(n) == 0 || !exponent.IsInt64() || exponent.Int64() < 3 || exponent.Int64() > 1<<31-1 {
+			return nil, errors.New("无效的 RSA 公钥")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("不支持的曲线 %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("解析 JWK x 失败: %w", err)
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("解析 JWK y 失败: %w", err)
+		}
+		pub := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !curve.IsOnCurve(pub.X, pub.Y) {
+			return nil, errors.New("无效的 EC 公钥")
+		}
+		return pub, nil
+	default:
+		return nil, fmt.Errorf("不支持的密钥类型 %q", k.Kty)
+	}
+}
+
+// JWKSet 公钥集合
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS 导出全部非对称公钥
+func (ks *KeySet) JWKS() JWKSet {
+	set := JWKSet{Keys: make([]JWK, 0, len(ks.order))}
+	for _, kid := range ks.order {
+		key := ks.keys[kid]
+		jwk := JWK{
+			Kid: key.Kid,
+			Use: "sig",
+			Alg: key.Method.Alg(),
+		}
+		switch pub := key.PublicKey.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case *ecdsa.PublicKey:
+			size := (pub.Curve.Params().BitSize + 7) / 8
+			jwk.Kty = "EC"
+			jwk.Crv = pub.Curve.Params().Name
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub.X.FillBytes(make([]byte, size)))
+			jwk.Y = base64.RawURLEncoding.EncodeToString(pub.Y.FillBytes(make([]byte, size)))
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
+
+// loadKey 从PEM文件加载密钥
+func loadKey(file KeyFile) (*Key, error) {
+	if file.Kid == "" {
+		return nil, errors.New("kid 不能为空")
+	}
+	if file.PrivateKeyFile == "" && file.PublicKeyFile == "" {
+		return nil, errors.New("未配置密钥文件")
+	}
+
+	key := &Key{Kid: file.Kid}
+	switch file.Algorithm {
+	case "RS256":
+		key.Method = jwt.SigningMethodRS256
+	case "ES256":
+		key.Method = jwt.SigningMethodES256
+	default:
+		return nil, fmt.Errorf("不支持的签名算法 %q", file.Algorithm)
+	}
+
+	if file.PrivateKeyFile != "" {
+		data, err := os.ReadFile(file.PrivateKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		switch key.Method {
+		case jwt.SigningMethodRS256:
+			private, err := jwt.ParseRSAPrivateKeyFromPEM(data)
+			if err != nil {
+				return nil, err
+			}
+			key.PrivateKey, key.PublicKey = private, &private.PublicKey
+		default:
+			private, err := jwt.ParseECPrivateKeyFromPEM(data)
+			if err != nil {
+				return nil, err
+			}
+			key.PrivateKey, key.PublicKey = private, &private.PublicKey
+		}
+	}
+
+	if file.PublicKeyFile != "" {
+		data, err := os.ReadFile(file.PublicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		switch key.Method {
+		case jwt.SigningMethodRS256:
+			if key.PublicKey, err = jwt.ParseRSAPublicKeyFromPEM(data); err != nil {
+				return nil, err
+			}
+		default:
+			if key.PublicKey, err = jwt.ParseECPublicKeyFromPEM(data); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// ES256 只能使用 P-256 曲线
+	if pub, ok := key.PublicKey.(*ecdsa.PublicKey); ok && pub.Curve != elliptic.P256() {
+		return nil, fmt.Errorf("ES256 需要 P-256 曲线，实际为 %s", pub.Curve.Params().Name)
+	}
+	return key, nil
+}
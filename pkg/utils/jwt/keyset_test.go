@@ -0,0 +1,231 @@
+/**
+ * Description：
+ * FileName：keyset_test.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 21:02:37
+ * Remark：
+ */
+
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// writeKeys 生成 RSA 及 EC 私钥PEM文件
+func writeKeys(t *testing.T) (string, string, string) {
+	dir := t.TempDir()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaFile := filepath.Join(dir, "rsa.pem")
+	writePEM(t, rsaFile, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey))
+
+	rsaPubDer, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaPubFile := filepath.Join(dir, "rsa.pub.pem")
+	writePEM(t, rsaPubFile, "PUBLIC KEY", rsaPubDer)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecDer, err := x509.MarshalECPrivateKey(ecKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecFile := filepath.Join(dir, "ec.pem")
+	writePEM(t, ecFile, "EC PRIVATE KEY", ecDer)
+
+	return rsaFile, rsaPubFile, ecFile
+}
+
+func writePEM(t *testing.T, name, typ string, der []byte) {
+	if err := os.WriteFile(name, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newTestContext() *gin.Context {
+	ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ctx.Request = httptest.NewRequest("POST", "/dev-api/v1/auth/login", nil)
+	return ctx
+}
+
+func TestKeySetRotation(t *testing.T) {
+	rsaFile, rsaPubFile, ecFile := writeKeys(t)
+
+	// 轮换前：共享密钥 + RS256
+	before, err := NewKeySet("secret", "rsa-1", []KeyFile{
+		{Kid: "rsa-1", Algorithm: "RS256", PrivateKeyFile: rsaFile},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	legacy, err := GenerateToken(newTestContext(), "1", "careful", 1, "", false, false, NewSecretKeySet("secret"), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	old, err := GenerateToken(newTestContext(), "1", "careful", 1, "", false, false, before, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 轮换后：ES256 签名，RS256 仅保留公钥用于校验
+	after, err := NewKeySet("secret", "ec-2", []KeyFile{
+		{Kid: "rsa-1", Algorithm: "RS256", PublicKeyFile: rsaPubFile},
+		{Kid: "ec-2", Algorithm: "ES256", PrivateKeyFile: ecFile},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	current, err := GenerateToken(newTestContext(), "1", "careful", 1, "", false, false, after, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name  string
+		token string
+		kid   string
+	}{
+		{name: "HS256旧令牌", token: legacy.AccessToken, kid: ""},
+		{name: "轮换前RS256令牌", token: old.AccessToken, kid: "rsa-1"},
+		{name: "当前ES256令牌", token: current.AccessToken, kid: "ec-2"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			claims, err := ParseToken(tc.token, after)
+			if err != nil {
+				t.Fatalf("ParseToken() error = %v", err)
+			}
+			if claims.UserId != "1" {
+				t.Errorf("UserId = %q, want %q", claims.UserId, "1")
+			}
+
+			token, _, err := jwt.NewParser().ParseUnverified(tc.token, &Claims{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if kid, _ := token.Header["kid"].(string); kid != tc.kid {
+				t.Errorf("kid = %q, want %q", kid, tc.kid)
+			}
+		})
+	}
+
+	// 移除共享密钥后，旧的 HS256 令牌不再有效
+	withoutSecret, err := NewKeySet("", "ec-2", []KeyFile{
+		{Kid: "ec-2", Algorithm: "ES256", PrivateKeyFile: ecFile},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseToken(legacy.AccessToken, withoutSecret); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("ParseToken() error = %v, want %v", err, ErrInvalidToken)
+	}
+}
+
+func TestKeySetRejectsAlgorithmMismatch(t *testing.T) {
+	rsaFile, _, _ := writeKeys(t)
+	keys, err := NewKeySet("", "rsa-1", []KeyFile{
+		{Kid: "rsa-1", Algorithm: "RS256", PrivateKeyFile: rsaFile},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 以公钥作为 HMAC 密钥伪造令牌
+	pub := keys.keys["rsa-1"].PublicKey.(*rsa.PublicKey)
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, &Claims{UserId: "1"})
+	forged.Header["kid"] = "rsa-1"
+	forgedStr, err := forged.SignedString(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ParseToken(forgedStr, keys); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("ParseToken() error = %v, want %v", err, ErrInvalidToken)
+	}
+}
+
+func TestKeySetJWKS(t *testing.T) {
+	rsaFile, _, ecFile := writeKeys(t)
+	keys, err := NewKeySet("secret", "ec-2", []KeyFile{
+		{Kid: "rsa-1", Algorithm: "RS256", PrivateKeyFile: rsaFile},
+		{Kid: "ec-2", Algorithm: "ES256", PrivateKeyFile: ecFile},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	set := keys.JWKS()
+	if len(set.Keys) != 2 {
+		t.Fatalf("len(Keys) = %d, want 2", len(set.Keys))
+	}
+	if k := set.Keys[0]; k.Kid != "rsa-1" || k.Kty != "RSA" || k.Alg != "RS256" || k.N == "" || k.E != "AQAB" {
+		t.Errorf("RSA JWK = %+v", k)
+	}
+	if k := set.Keys[1]; k.Kid != "ec-2" || k.Kty != "EC" || k.Crv != "P-256" || len(k.X) != 43 || len(k.Y) != 43 {
+		t.Errorf("EC JWK = %+v", k)
+	}
+
+	// 导出的公钥可还原
+	for _, k := range set.Keys {
+		pub, err := k.PublicKey()
+		if err != nil {
+			t.Fatalf("%s: %v", k.Kid, err)
+		}
+		if !pub.(interface{ Equal(crypto.PublicKey) bool }).Equal(keys.keys[k.Kid].PublicKey) {
+			t.Errorf("%s: 还原的公钥不一致", k.Kid)
+		}
+	}
+
+	if len(NewSecretKeySet("secret").JWKS().Keys) != 0 {
+		t.Error("共享密钥不应发布到 JWKS")
+	}
+}
+
+func TestNewKeySetInvalid(t *testing.T) {
+	_, rsaPubFile, _ := writeKeys(t)
+
+	testCases := []struct {
+		name       string
+		signingKid string
+		files      []KeyFile
+	}{
+		{name: "未配置密钥", files: nil},
+		{name: "签名密钥不存在", signingKid: "missing", files: []KeyFile{{Kid: "rsa-1", Algorithm: "RS256", PublicKeyFile: rsaPubFile}}},
+		{name: "签名密钥缺少私钥", signingKid: "rsa-1", files: []KeyFile{{Kid: "rsa-1", Algorithm: "RS256", PublicKeyFile: rsaPubFile}}},
+		{name: "不支持的算法", files: []KeyFile{{Kid: "rsa-1", Algorithm: "HS512", PublicKeyFile: rsaPubFile}}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := NewKeySet("", tc.signingKid, tc.files); err == nil {
+				t.Error("NewKeySet() error = nil, want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+/**
+ * Description：
+ * FileName：refresh.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 19:48:31
+ * Remark：
+ */
+
+package jwt
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// RefreshTokenPrefix Redis中存储刷新令牌的前缀(键为令牌的SHA-256摘要)
+	RefreshTokenPrefix = "token:refresh:"
+	// RefreshFamilyPrefix Redis中存储令牌族的前缀，值为该族签发过的访问令牌jti集合
+	RefreshFamilyPrefix = "token:refresh:family:"
+)
+
+var (
+	ErrRefreshTokenInvalid = errors.New("刷新令牌无效或已过期")
+	ErrRefreshTokenReused  = errors.New("刷新令牌已被使用")
+)
+
+// consumeScript 原子地标记刷新令牌为已使用
+// 返回值：0-不存在 1-成功 2-重复使用 3-令牌族已吊销
+var consumeScript = redis.NewScript(`
+local v = redis.call('HMGET', KEYS[1], 'userId', 'family', 'jti', 'used', 'passwordChange')
+if not v[1] then
+    return {0}
+end
+if redis.call('EXISTS', ARGV[1] .. v[2]) == 0 then
+    return {3, v[1], v[2], v[3], v[5]}
+end
+if v[4] == '1' then
+    return {2, v[1], v[2], v[3], v[5]}
+end
+redis.call('HSET', KEYS[1], 'used', '1')
+return {1, v[1], v[2], v[3], v[5]}
+`)
+
+// RefreshToken 刷新令牌信息
+type RefreshToken struct {
+	UserId string // 用户ID
+	Family string // 令牌族，同一次登录轮换出的刷新令牌属于同一族
+	Jti    string // 同时签发的访问令牌jti
+	// PasswordChange 令牌族签发的为受限令牌，需先修改密码
+	PasswordChange bool
+}
+
+// RefreshTokenStore 刷新令牌存储
+type RefreshTokenStore struct {
+	rdb redis.Cmdable
+}
+
+// NewRefreshTokenStore 创建一个刷新令牌存储实例
+func NewRefreshTokenStore(rdb redis.Cmdable) *RefreshTokenStore {
+	return &RefreshTokenStore{
+		rdb: rdb,
+	}
+}
+
+// Save 保存刷新令牌并登记到令牌族
+func (s *RefreshTokenStore) Save(ctx context.Context, token string, rt RefreshToken, expiresIn time.Duration) error {
+	key := s.key(token)
+	familyKey := fmt.Sprintf("%s%s", RefreshFamilyPrefix, rt.Family)
+
+	pipe := s.rdb.TxPipeline()
+	passwordChange := "0"
+	if rt.PasswordChange {
+		passwordChange = "1"
+	}
+	pipe.HSet(ctx, key, "userId", rt.UserId, "family", rt.Family, "jti", rt.Jti, "used", "0", "passwordChange", passwordChange)
+	pipe.Expire(ctx, key, expiresIn)
+	pipe.SAdd(ctx, familyKey, rt.Jti)
+	pipe.Expire(ctx, familyKey, expiresIn)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// Consume 使用刷新令牌，每个刷新令牌只能使用一次
+// 重复使用时返回 ErrRefreshTokenReused 及令牌信息，调用方应吊销整个令牌族
+func (s *RefreshTokenStore) Consume(ctx context.Context, token string) (RefreshToken, error) {
+	result, err := consumeScript.Run(ctx, s.rdb, []string{s.key(token)}, RefreshFamilyPrefix).Slice()
+	if err != nil {
+		return RefreshToken{}, err
+	}
+
+	code, _ := result[0].(int64)
+	if code == 0 {
+		return RefreshToken{}, ErrRefreshTokenInvalid
+	}
+
+	rt := RefreshToken{}
+	rt.UserId, _ = result[1].(string)
+	rt.Family, _ = result[2].(string)
+	rt.Jti, _ = result[3].(string)
+	if len(result) > 4 {
+		passwordChange, _ := result[4].(string)
+		rt.PasswordChange = passwordChange == "1"
+	}
+
+	switch code {
+	case 1:
+		return rt, nil
+	case 2:
+		return rt, ErrRefreshTokenReused
+	default:
+		return rt, ErrRefreshTokenInvalid
+	}
+}
+
+// FamilyJtis 获取令牌族签发过的访问令牌jti
+func (s *RefreshTokenStore) FamilyJtis(ctx context.Context, family string) ([]string, error) {
+	return s.rdb.SMembers(ctx, fmt.Sprintf("%s%s", RefreshFamilyPrefix, family)).Result()
+}
+
+// RevokeFamily 吊销令牌族，该族的刷新令牌全部失效
+func (s *RefreshTokenStore) RevokeFamily(ctx context.Context, family string) error {
+	return s.rdb.Del(ctx, fmt.Sprintf("%s%s", RefreshFamilyPrefix, family)).Err()
+}
+
+func (s *RefreshTokenStore) key(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return fmt.Sprintf("%s%s", RefreshTokenPrefix, hex.EncodeToString(sum[:]))
+}
@@ -0,0 +1,175 @@
+/**
+ * Description：
+ * FileName：mail.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 02:10:36
+ * Remark：
+ */
+
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrNotConfigured = errors.New("未配置邮件服务")
+
+// Message 邮件内容
+type Message struct {
+	To      []string // 收件人
+	Subject string   // 主题
+	Body    string   // 正文
+	HTML    bool     // 正文是否为 HTML
+}
+
+// Sender 邮件发送器
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// SMTPConfig SMTP 服务配置
+type SMTPConfig struct {
+	Host     string        // 服务器地址
+	Port     int           // 端口
+	Username string        // 认证用户名，为空时不认证
+	Password string        // 认证密码
+	From     string        // 发件人，如 "Carefuly <noreply@example.com>"
+	SSL      bool          // 是否使用隐式 TLS(如 465 端口)，否则在服务器支持时使用 STARTTLS
+	Timeout  time.Duration // 连接超时，默认 10 秒
+}
+
+type smtpSender struct {
+	cfg SMTPConfig
+}
+
+// NewSMTPSender SMTP 邮件发送器，未配置服务器地址时发送返回 ErrNotConfigured
+func NewSMTPSender(cfg SMTPConfig) Sender {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	return &smtpSender{
+		cfg: cfg,
+	}
+}
+
+func (s *smtpSender) Send(ctx context.Context, msg Message) error {
+	if s.cfg.Host == "" {
+		return ErrNotConfigured
+	}
+	if len(msg.To) == 0 {
+		return errors.New("收件人不能为空")
+	}
+
+	from, err := mail.ParseAddress(s.cfg.From)
+	if err != nil {
+		return fmt.Errorf("发件人格式错误: %w", err)
+	}
+	data, err := Build(from, msg)
+	if err != nil {
+		return err
+	}
+
+	addr := net.JoinHostPort(s.cfg.Host, strconv.Itoa(s.cfg.Port))
+	dialer := &net.Dialer{Timeout: s.cfg.Timeout}
+	var conn net.Conn
+	if s.cfg.SSL {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: s.cfg.Host}}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("连接邮件服务器失败: %w", err)
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(s.cfg.Timeout * 3)
+	}
+	_ = conn.SetDeadline(deadline)
+
+	client, err := smtp.NewClient(conn, s.cfg.Host)
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if !s.cfg.SSL {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(&tls.Config{ServerName: s.cfg.Host}); err != nil {
+				return err
+			}
+		}
+	}
+	if s.cfg.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)); err != nil {
+			return fmt.Errorf("邮件服务器认证失败: %w", err)
+		}
+	}
+
+	if err := client.Mail(from.Address); err != nil {
+		return err
+	}
+	for _, to := range msg.To {
+		if err := client.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// Build 生成邮件原文，主题按 RFC 2047 编码，正文使用 Base64 编码
+func Build(from *mail.Address, msg Message) ([]byte, error) {
+	to := make([]string, 0, len(msg.To))
+	for _, addr := range msg.To {
+		parsed, err := mail.ParseAddress(addr)
+		if err != nil {
+			return nil, fmt.Errorf("收件人格式错误: %w", err)
+		}
+		to = append(to, parsed.String())
+	}
+
+	contentType := "text/plain"
+	if msg.HTML {
+		contentType = "text/html"
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("From: " + from.String() + "\r\n")
+	buf.WriteString("To: " + strings.Join(to, ", ") + "\r\n")
+	buf.WriteString("Subject: " + mime.BEncoding.Encode("UTF-8", msg.Subject) + "\r\n")
+	buf.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: " + contentType + "; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: base64\r\n\r\n")
+
+	// 每行不超过 76 个字符
+	encoded := base64.StdEncoding.EncodeToString([]byte(msg.Body))
+	for len(encoded) > 76 {
+		buf.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	buf.WriteString(encoded + "\r\n")
+	return buf.Bytes(), nil
+}
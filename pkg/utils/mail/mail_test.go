@@ -0,0 +1,148 @@
+/**
+ * Description：
+ * FileName：mail_test.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 02:18:04
+ * Remark：
+ */
+
+package mail
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"errors"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"testing"
+)
+
+// smtpStub 本地 SMTP 替身，记录收到的信封及邮件原文
+type smtpStub struct {
+	listener net.Listener
+	from     string
+	rcpt     []string
+	data     string
+	done     chan struct{}
+}
+
+func newSMTPStub(t *testing.T) *smtpStub {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	stub := &smtpStub{listener: listener, done: make(chan struct{})}
+	t.Cleanup(func() { _ = listener.Close() })
+	go stub.serve()
+	return stub
+}
+
+func (s *smtpStub) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *smtpStub) serve() {
+	defer close(s.done)
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	tp := textproto.NewConn(conn)
+	_ = tp.PrintfLine("220 localhost ESMTP stub")
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(line)
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			_ = tp.PrintfLine("250 localhost")
+		case strings.HasPrefix(cmd, "MAIL FROM:"):
+			s.from = strings.Trim(line[len("MAIL FROM:"):], "<> ")
+			_ = tp.PrintfLine("250 OK")
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			s.rcpt = append(s.rcpt, strings.Trim(line[len("RCPT TO:"):], "<> "))
+			_ = tp.PrintfLine("250 OK")
+		case cmd == "DATA":
+			_ = tp.PrintfLine("354 End data with <CR><LF>.<CR><LF>")
+			lines, err := tp.ReadDotLines()
+			if err != nil {
+				return
+			}
+			s.data = strings.Join(lines, "\n")
+			_ = tp.PrintfLine("250 OK")
+		case cmd == "QUIT":
+			_ = tp.PrintfLine("221 Bye")
+			return
+		default:
+			_ = tp.PrintfLine("502 Command not implemented")
+		}
+	}
+}
+
+func TestSMTPSender_Send(t *testing.T) {
+	stub := newSMTPStub(t)
+	sender := NewSMTPSender(SMTPConfig{
+		Host: "127.0.0.1",
+		Port: stub.port(),
+		From: "Carefuly <noreply@example.com>",
+	})
+
+	err := sender.Send(context.Background(), Message{
+		To:      []string{"user@example.com"},
+		Subject: "重置密码验证码",
+		Body:    "您的验证码为 123456",
+	})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	<-stub.done
+
+	if stub.from != "noreply@example.com" {
+		t.Fatalf("from = %q", stub.from)
+	}
+	if len(stub.rcpt) != 1 || stub.rcpt[0] != "user@example.com" {
+		t.Fatalf("rcpt = %v", stub.rcpt)
+	}
+
+	header, body, ok := strings.Cut(stub.data, "\n\n")
+	if !ok {
+		t.Fatalf("data without body: %q", stub.data)
+	}
+	msg, err := textproto.NewReader(bufio.NewReader(strings.NewReader(header + "\n\n"))).ReadMIMEHeader()
+	if err != nil {
+		t.Fatalf("parse header: %v", err)
+	}
+	if got := msg.Get("Subject"); got == "重置密码验证码" || !strings.HasPrefix(got, "=?UTF-8?b?") {
+		t.Fatalf("subject should be encoded, got %q", got)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(body, "\n", ""))
+	if err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if string(decoded) != "您的验证码为 123456" {
+		t.Fatalf("body = %q", decoded)
+	}
+}
+
+func TestSMTPSender_NotConfigured(t *testing.T) {
+	err := NewSMTPSender(SMTPConfig{}).Send(context.Background(), Message{To: []string{"user@example.com"}})
+	if !errors.Is(err, ErrNotConfigured) {
+		t.Fatalf("err = %v, want ErrNotConfigured", err)
+	}
+}
+
+func TestBuild_InvalidRecipient(t *testing.T) {
+	from := &mail.Address{Address: "noreply@example.com"}
+	_, err := Build(from, Message{To: []string{"not an address"}})
+	if err == nil || !strings.Contains(err.Error(), "收件人格式错误") {
+		t.Fatalf("err = %v", err)
+	}
+}
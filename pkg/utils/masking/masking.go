@@ -0,0 +1,340 @@
+/**
+ * Description：
+ * FileName：masking.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 14:02:37
+ * Remark：
+ */
+
+// Package masking 日志脱敏，请求体、响应体、查询参数及请求头在记录前按字段名、JSON 路径脱敏
+package masking
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/routematch"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultMask 脱敏后的值
+const DefaultMask = "******"
+
+// DefaultMaxResponseSize 响应体默认最大记录字节数
+const DefaultMaxResponseSize = 4096
+
+// DefaultKeys 默认敏感字段名，支持 * 通配，不区分大小写
+var DefaultKeys = []string{
+	"*password*",
+	"*token",
+	"*secret*",
+	"*apikey*",
+	"*api-key*",
+	"*api_key*",
+	"authorization",
+	"cookie",
+	"set-cookie",
+	"recoveryCodes",
+}
+
+// DefaultPaths 默认敏感 JSON 路径
+var DefaultPaths = []string{
+	"data.key",    // 个人访问令牌明文
+	"data.uri",    // 双因素认证 otpauth 地址(含密钥)
+	"data.qrCode", // 双因素认证二维码(含密钥)
+}
+
+// DefaultRoutes 默认不记录请求体的接口，请求体含一次性验证码、恢复码或授权码
+var DefaultRoutes = []Route{
+	{Method: http.MethodPost, Path: "/auth/login/2fa", SkipRequestBody: true},
+	{Method: http.MethodPost, Path: "/auth/forgot-password/code", SkipRequestBody: true},
+	{Method: http.MethodPost, Path: "/auth/forgot-password/reset", SkipRequestBody: true},
+	{Method: http.MethodPost, Path: "/auth/2fa/enable", SkipRequestBody: true},
+	{Method: http.MethodPost, Path: "/auth/2fa/disable", SkipRequestBody: true},
+	{Method: http.MethodPost, Path: "/auth/2fa/recoveryCodes", SkipRequestBody: true},
+	{Method: http.MethodPost, Path: "/auth/sso/login", SkipRequestBody: true},
+}
+
+// Config 脱敏配置，Keys、Paths、Headers、Routes 在默认规则基础上追加
+type Config struct {
+	Keys            []string // 敏感字段名(JSON、查询参数、请求头)
+	Paths           []string // JSON 路径，以 . 分隔，* 匹配任意字段或数组元素，如 data.list.*.mobile
+	Headers         []string // 敏感请求头
+	Mask            string   // 脱敏后的值，默认 ******
+	MaxResponseSize int      // 响应体最大记录字节数，默认 4096
+	Routes          []Route  // 按接口覆盖记录策略
+}
+
+// Route 按接口覆盖记录策略
+type Route struct {
+	Method           string // 请求方式，为空匹配全部
+	Path             string // 接口地址，匹配规则同 routematch.Match
+	SkipRequestBody  bool   // 不记录请求体
+	SkipResponseBody bool   // 不记录响应体
+	MaxResponseSize  int    // 响应体最大记录字节数，大于 0 时覆盖全局配置
+}
+
+// Policy 单个请求的记录策略
+type Policy struct {
+	SkipRequestBody  bool
+	SkipResponseBody bool
+	MaxResponseSize  int
+}
+
+// Masker 脱敏器，创建后只读，可并发使用
+type Masker struct {
+	keys            []string
+	paths           [][]string
+	headers         []string
+	mask            string
+	maxResponseSize int
+	routes          []Route
+}
+
+// New 创建脱敏器
+func New(cfg Config) *Masker {
+	m := &Masker{
+		mask:            cfg.Mask,
+		maxResponseSize: cfg.MaxResponseSize,
+		routes:          append(append([]Route{}, DefaultRoutes...), cfg.Routes...),
+	}
+	if m.mask == "" {
+		m.mask = DefaultMask
+	}
+	if m.maxResponseSize <= 0 {
+		m.maxResponseSize = DefaultMaxResponseSize
+	}
+	for _, key := range append(append([]string{}, DefaultKeys...), cfg.Keys...) {
+		if key = strings.ToLower(strings.TrimSpace(key)); key != "" {
+			m.keys = append(m.keys, key)
+		}
+	}
+	for _, path := range append(append([]string{}, DefaultPaths...), cfg.Paths...) {
+		if path = strings.ToLower(strings.TrimSpace(path)); path != "" {
+			m.paths = append(m.paths, strings.Split(path, "."))
+		}
+	}
+	for _, header := range cfg.Headers {
+		if header = strings.ToLower(strings.TrimSpace(header)); header != "" {
+			m.headers = append(m.headers, header)
+		}
+	}
+	return m
+}
+
+// Mask 脱敏后的值
+func (m *Masker) Mask() string {
+	return m.mask
+}
+
+// Policy 获取接口的记录策略，多条规则匹配时依次叠加
+// route 为路由模板(ctx.FullPath())或实际请求路径
+func (m *Masker) Policy(method, route string) Policy {
+	policy := Policy{MaxResponseSize: m.maxResponseSize}
+	for _, r := range m.routes {
+		if r.Method != "" && !strings.EqualFold(r.Method, method) {
+			continue
+		}
+		if !routematch.Match(r.Path, route) {
+			continue
+		}
+		policy.SkipRequestBody = policy.SkipRequestBody || r.SkipRequestBody
+		policy.SkipResponseBody = policy.SkipResponseBody || r.SkipResponseBody
+		if r.MaxResponseSize > 0 {
+			policy.MaxResponseSize = r.MaxResponseSize
+		}
+	}
+	return policy
+}
+
+// IsSensitiveKey 字段名是否敏感
+func (m *Masker) IsSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, pattern := range m.keys {
+		if matchWildcard(pattern, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSensitiveHeader 请求头是否敏感
+func (m *Masker) IsSensitiveHeader(name string) bool {
+	lower := strings.ToLower(name)
+	for _, pattern := range m.headers {
+		if matchWildcard(pattern, lower) {
+			return true
+		}
+	}
+	return m.IsSensitiveKey(name)
+}
+
+// Body 脱敏请求体或响应体
+// JSON 按字段名、路径脱敏，表单按查询参数脱敏，其余内容原样返回；未命中敏感字段时返回原文
+func (m *Masker) Body(raw, contentType string) string {
+	if strings.TrimSpace(raw) == "" {
+		return raw
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(raw))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err == nil && !decoder.More() {
+		masked, changed := m.Value(value)
+		if !changed {
+			return raw
+		}
+		var buf bytes.Buffer
+		encoder := json.NewEncoder(&buf)
+		encoder.SetEscapeHTML(false)
+		if err := encoder.Encode(masked); err != nil {
+			return m.mask
+		}
+		return strings.TrimRight(buf.String(), "\n")
+	}
+
+	if strings.Contains(contentType, "application/x-www-form-urlencoded") {
+		return m.Query(raw)
+	}
+	return raw
+}
+
+// Value 脱敏已解析的 JSON 数据(原地修改)，返回是否命中敏感字段
+func (m *Masker) Value(value any) (any, bool) {
+	return m.maskValue(value, nil)
+}
+
+func (m *Masker) maskValue(value any, path []string) (any, bool) {
+	changed := false
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			itemPath := append(path[:len(path):len(path)], strings.ToLower(key))
+			if m.IsSensitiveKey(key) || m.matchPath(itemPath) {
+				v[key] = m.mask
+				changed = true
+				continue
+			}
+			masked, c := m.maskValue(item, itemPath)
+			v[key] = masked
+			changed = changed || c
+		}
+	case []any:
+		for i, item := range v {
+			itemPath := append(path[:len(path):len(path)], strconv.Itoa(i))
+			if m.matchPath(itemPath) {
+				v[i] = m.mask
+				changed = true
+				continue
+			}
+			masked, c := m.maskValue(item, itemPath)
+			v[i] = masked
+			changed = changed || c
+		}
+	}
+	return value, changed
+}
+
+// matchPath 路径是否命中敏感 JSON 路径
+func (m *Masker) matchPath(path []string) bool {
+	for _, pattern := range m.paths {
+		if len(pattern) != len(path) {
+			continue
+		}
+		matched := true
+		for i, seg := range pattern {
+			if seg != "*" && seg != path[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// Query 脱敏查询字符串，未命中敏感参数时返回原文
+func (m *Masker) Query(raw string) string {
+	if raw == "" {
+		return raw
+	}
+	// 解析失败时仍使用已解析的部分，避免原文泄露
+	values, err := url.ParseQuery(raw)
+	masked, changed := m.maskValues(values)
+	if !changed && err == nil {
+		return raw
+	}
+	return masked.Encode()
+}
+
+// Values 脱敏查询参数，返回副本
+func (m *Masker) Values(values url.Values) url.Values {
+	masked, _ := m.maskValues(values)
+	return masked
+}
+
+func (m *Masker) maskValues(values url.Values) (url.Values, bool) {
+	changed := false
+	masked := make(url.Values, len(values))
+	for key, items := range values {
+		if m.IsSensitiveKey(key) {
+			masked[key] = []string{m.mask}
+			changed = true
+			continue
+		}
+		masked[key] = append([]string{}, items...)
+	}
+	return masked, changed
+}
+
+// Header 脱敏请求头，返回副本
+func (m *Masker) Header(header http.Header) map[string]string {
+	masked := make(map[string]string, len(header))
+	for name, items := range header {
+		if m.IsSensitiveHeader(name) {
+			masked[name] = m.mask
+			continue
+		}
+		masked[name] = strings.Join(items, ", ")
+	}
+	return masked
+}
+
+// Truncate 截断超过 max 字节的内容，不截断多字节字符
+func Truncate(s string, max int) string {
+	if max <= 0 || len(s) <= max {
+		return s
+	}
+	cut := max
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return fmt.Sprintf("%s...(已截断，共 %d 字节)", s[:cut], len(s))
+}
+
+// matchWildcard 通配符匹配，* 匹配任意字符
+func matchWildcard(pattern, s string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == s
+	}
+	if !strings.HasPrefix(s, parts[0]) {
+		return false
+	}
+	s = s[len(parts[0]):]
+	last := parts[len(parts)-1]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(s, part)
+		if i < 0 {
+			return false
+		}
+		s = s[i+len(part):]
+	}
+	return strings.HasSuffix(s, last)
+}
@@ -0,0 +1,124 @@
+/**
+ * Description：
+ * FileName：masking_test.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 14:41:26
+ * Remark：
+ */
+
+package masking
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestMasker_Body(t *testing.T) {
+	m := New(Config{Keys: []string{"mobile"}, Paths: []string{"data.list.*.email"}})
+
+	testCases := []struct {
+		name        string
+		raw         string
+		contentType string
+		want        string
+	}{
+		{name: "密码", raw: `{"username":"admin","password":"p1"}`, want: `{"password":"******","username":"admin"}`},
+		{name: "修改密码", raw: `{"oldPassword":"p1","newPassword":"p2"}`, want: `{"newPassword":"******","oldPassword":"******"}`},
+		{name: "令牌", raw: `{"code":200,"data":{"token":"t1","refreshToken":"t2","expire":7200}}`, want: `{"code":200,"data":{"expire":7200,"refreshToken":"******","token":"******"}}`},
+		{name: "追加字段名", raw: `{"Mobile":"13800000000"}`, want: `{"Mobile":"******"}`},
+		{name: "路径", raw: `{"data":{"list":[{"email":"a@b.c","name":"a"}],"email":"x"}}`, want: `{"data":{"email":"x","list":[{"email":"******","name":"a"}]}}`},
+		{name: "默认路径", raw: `{"data":{"key":"ck_1","name":"n"}}`, want: `{"data":{"key":"******","name":"n"}}`},
+		{name: "无敏感字段返回原文", raw: `{"b":1, "a":2}`, want: `{"b":1, "a":2}`},
+		{name: "大整数", raw: `{"id":12345678901234567890,"password":"p"}`, want: `{"id":12345678901234567890,"password":"******"}`},
+		{name: "表单", raw: "username=admin&password=p1", contentType: "application/x-www-form-urlencoded", want: "password=%2A%2A%2A%2A%2A%2A&username=admin"},
+		{name: "非JSON", raw: "password=p1", want: "password=p1"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := m.Body(tc.raw, tc.contentType); got != tc.want {
+				t.Errorf("Body() = %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMasker_QueryAndHeader(t *testing.T) {
+	m := New(Config{Headers: []string{"X-Trace-*"}})
+
+	if got := m.Query("refreshToken=abc&lang=zh"); got != "lang=zh&refreshToken=%2A%2A%2A%2A%2A%2A" {
+		t.Errorf("Query() = %s", got)
+	}
+	if got := m.Query("lang=zh&page=1"); got != "lang=zh&page=1" {
+		t.Errorf("Query() = %s", got)
+	}
+
+	header := http.Header{}
+	header.Set("Authorization", "Bearer abc")
+	header.Set("X-Api-Key", "ck_1")
+	header.Set("X-Trace-Id", "1")
+	header.Set("Content-Type", "application/json")
+	got := m.Header(header)
+	for _, name := range []string{"Authorization", "X-Api-Key", "X-Trace-Id"} {
+		if got[name] != DefaultMask {
+			t.Errorf("Header()[%s] = %s", name, got[name])
+		}
+	}
+	if got["Content-Type"] != "application/json" {
+		t.Errorf("Header()[Content-Type] = %s", got["Content-Type"])
+	}
+	if header.Get("Authorization") != "Bearer abc" {
+		t.Error("Header() 修改了原请求头")
+	}
+}
+
+func TestMasker_Policy(t *testing.T) {
+	m := New(Config{
+		MaxResponseSize: 100,
+		Routes: []Route{
+			{Path: "/system/dept/import", SkipRequestBody: true},
+			{Method: "GET", Path: "/system/user/:id", SkipResponseBody: true, MaxResponseSize: 10},
+			{Method: "POST", Path: "/system/user/create", SkipResponseBody: true},
+			// 仅配置末尾路径段时不匹配任何接口
+			{Path: "create", SkipRequestBody: true},
+		},
+	})
+
+	testCases := []struct {
+		name   string
+		method string
+		route  string
+		want   Policy
+	}{
+		{name: "默认", method: "POST", route: "/dev-api/v1/system/dept/create", want: Policy{MaxResponseSize: 100}},
+		{name: "跳过请求体", method: "POST", route: "/dev-api/v1/system/dept/import", want: Policy{SkipRequestBody: true, MaxResponseSize: 100}},
+		{name: "请求方式匹配", method: "GET", route: "/dev-api/v1/system/user/:id", want: Policy{SkipResponseBody: true, MaxResponseSize: 10}},
+		{name: "请求方式不匹配", method: "PUT", route: "/dev-api/v1/system/user/:id", want: Policy{MaxResponseSize: 100}},
+		{name: "完整路径匹配", method: "POST", route: "/dev-api/v1/system/user/create", want: Policy{SkipResponseBody: true, MaxResponseSize: 100}},
+		{name: "不影响其他模块同名接口", method: "POST", route: "/dev-api/v1/system/role/create", want: Policy{MaxResponseSize: 100}},
+		{name: "实际请求路径", method: "GET", route: "/dev-api/v1/system/user/1", want: Policy{SkipResponseBody: true, MaxResponseSize: 10}},
+		{name: "默认跳过找回密码验证码", method: "POST", route: "/dev-api/v1/auth/forgot-password/reset", want: Policy{SkipRequestBody: true, MaxResponseSize: 100}},
+		{name: "默认跳过登录二次验证", method: "POST", route: "/dev-api/v1/auth/login/2fa", want: Policy{SkipRequestBody: true, MaxResponseSize: 100}},
+		{name: "默认跳过双因素认证验证码", method: "POST", route: "/dev-api/v1/auth/2fa/disable", want: Policy{SkipRequestBody: true, MaxResponseSize: 100}},
+		{name: "默认规则不影响其他认证接口", method: "POST", route: "/dev-api/v1/auth/login", want: Policy{MaxResponseSize: 100}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := m.Policy(tc.method, tc.route); got != tc.want {
+				t.Errorf("Policy() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if got := Truncate("abc", 10); got != "abc" {
+		t.Errorf("Truncate() = %s", got)
+	}
+	got := Truncate("中文内容", 4)
+	if !strings.HasPrefix(got, "中...") || !strings.Contains(got, "12 字节") {
+		t.Errorf("Truncate() = %s", got)
+	}
+}
@@ -0,0 +1,345 @@
+/**
+ * Description：
+ * FileName：oidc.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 05:48:20
+ * Remark：
+ */
+
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/jwt"
+	gojwt "github.com/golang-jwt/jwt/v5"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 公钥集合缓存时间，令牌使用未知 kid 时提前刷新
+const (
+	keysCacheTTL         = time.Hour
+	keysRefreshInterval  = 10 * time.Second
+	maxResponseBodyBytes = 1 << 20
+)
+
+var (
+	ErrDiscovery = errors.New("获取身份提供方配置失败")
+	ErrExchange  = errors.New("授权码换取令牌失败")
+	ErrIDToken   = errors.New("ID令牌校验失败")
+)
+
+// 身份提供方可能使用的签名算法，不接受 none 及共享密钥算法
+var validMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+// Config 身份提供方配置
+type Config struct {
+	Issuer       string        // 颁发者地址，用于发现配置并校验令牌 iss
+	ClientId     string        // 客户端ID
+	ClientSecret string        // 客户端密钥，为空时视为公共客户端
+	RedirectURL  string        // 回调地址
+	Scopes       []string      // 授权范围，默认 openid profile email
+	Timeout      time.Duration // 请求超时，默认 10 秒
+}
+
+// Discovery 身份提供方元数据(OpenID Connect Discovery)
+type Discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JwksURI               string `json:"jwks_uri"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+}
+
+// Token 令牌端点响应
+type Token struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	IdToken     string `json:"id_token"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+// Claims ID令牌声明
+type Claims map[string]any
+
+// String 读取字符串声明，不存在或类型不符时返回空串
+func (c Claims) String(name string) string {
+	value, _ := c[name].(string)
+	return value
+}
+
+// Provider OpenID Connect 身份提供方客户端
+type Provider struct {
+	cfg    Config
+	client *http.Client
+
+	mu        sync.Mutex
+	discovery *Discovery
+	keys      map[string]crypto.PublicKey
+	keysTime  time.Time
+}
+
+// NewProvider 创建身份提供方客户端，元数据及公钥在首次使用时获取
+func NewProvider(cfg Config) *Provider {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{"openid", "profile", "email"}
+	}
+	cfg.Issuer = strings.TrimSuffix(cfg.Issuer, "/")
+	return &Provider{
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.Timeout},
+	}
+}
+
+// Discover 获取身份提供方元数据，成功后缓存
+func (p *Provider) Discover(ctx context.Context) (*Discovery, error) {
+	p.mu.Lock()
+	cached := p.discovery
+	p.mu.Unlock()
+	if cached != nil {
+		return cached, nil
+	}
+
+	var discovery Discovery
+	if err := p.getJSON(ctx, p.cfg.Issuer+"/.well-known/openid-configuration", &discovery); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDiscovery, err)
+	}
+	// 防止被篡改的元数据将令牌校验指向其他颁发者
+	if strings.TrimSuffix(discovery.Issuer, "/") != p.cfg.Issuer {
+		return nil, fmt.Errorf("%w: issuer 不一致(%s)", ErrDiscovery, discovery.Issuer)
+	}
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JwksURI == "" {
+		return nil, fmt.Errorf("%w: 缺少必要的端点", ErrDiscovery)
+	}
+
+	p.mu.Lock()
+	p.discovery = &discovery
+	p.mu.Unlock()
+	return &discovery, nil
+}
+
+// AuthCodeURL 授权地址，使用 PKCE(S256)，verifier 需保存至回调时换取令牌
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	discovery, err := p.Discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", p.cfg.ClientId)
+	query.Set("redirect_uri", p.cfg.RedirectURL)
+	query.Set("scope", strings.Join(p.cfg.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", CodeChallenge(verifier))
+	query.Set("code_challenge_method", "S256")
+
+	sep := "?"
+	if strings.Contains(discovery.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return discovery.AuthorizationEndpoint + sep + query.Encode(), nil
+}
+
+// Exchange 使用授权码及 PKCE verifier 换取令牌
+func (p *Provider) Exchange(ctx context.Context, code, verifier string) (Token, error) {
+	discovery, err := p.Discover(ctx)
+	if err != nil {
+		return Token{}, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.cfg.RedirectURL)
+	form.Set("client_id", p.cfg.ClientId)
+	form.Set("code_verifier", verifier)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Token{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.cfg.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.cfg.ClientId), url.QueryEscape(p.cfg.ClientSecret))
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return Token{}, fmt.Errorf("%w: %v", ErrExchange, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBodyBytes))
+	if err != nil {
+		return Token{}, fmt.Errorf("%w: %v", ErrExchange, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var failure struct {
+			Error       string `json:"error"`
+			Description string `json:"error_description"`
+		}
+		_ = json.Unmarshal(body, &failure)
+		return Token{}, fmt.Errorf("%w: %d %s %s", ErrExchange, resp.StatusCode, failure.Error, failure.Description)
+	}
+
+	var token Token
+	if err := json.Unmarshal(body, &token); err != nil {
+		return Token{}, fmt.Errorf("%w: %v", ErrExchange, err)
+	}
+	if token.IdToken == "" {
+		return Token{}, fmt.Errorf("%w: 响应中缺少 id_token", ErrExchange)
+	}
+	return token, nil
+}
+
+// VerifyIDToken 校验ID令牌的签名、颁发者、受众、有效期及 nonce
+func (p *Provider) VerifyIDToken(ctx context.Context, raw, nonce string) (Claims, error) {
+	if _, err := p.Discover(ctx); err != nil {
+		return nil, err
+	}
+
+	claims := gojwt.MapClaims{}
+	_, err := gojwt.ParseWithClaims(raw, claims, func(token *gojwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.publicKey(ctx, kid)
+	},
+		gojwt.WithValidMethods(validMethods),
+		gojwt.WithIssuer(p.cfg.Issuer),
+		gojwt.WithAudience(p.cfg.ClientId),
+		gojwt.WithExpirationRequired(),
+		gojwt.WithIssuedAt(),
+		gojwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrIDToken, err)
+	}
+
+	result := Claims(claims)
+	// 多个受众时，授权方须为当前客户端
+	if aud, ok := claims["aud"].([]any); ok && len(aud) > 1 && result.String("azp") != p.cfg.ClientId {
+		return nil, fmt.Errorf("%w: azp 与客户端不一致", ErrIDToken)
+	}
+	if result.String("nonce") != nonce {
+		return nil, fmt.Errorf("%w: nonce 不一致", ErrIDToken)
+	}
+	if result.String("sub") == "" {
+		return nil, fmt.Errorf("%w: 缺少 sub", ErrIDToken)
+	}
+	return result, nil
+}
+
+// publicKey 按 kid 查找签名公钥，未命中时刷新公钥集合(限制刷新频率)
+func (p *Provider) publicKey(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	p.mu.Lock()
+	keys, fetched := p.keys, p.keysTime
+	p.mu.Unlock()
+
+	if key, ok := lookupKey(keys, kid); ok && time.Since(fetched) < keysCacheTTL {
+		return key, nil
+	}
+	if keys != nil && time.Since(fetched) < keysRefreshInterval {
+		return nil, jwt.ErrKeyNotFound
+	}
+
+	keys, err := p.fetchKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if key, ok := lookupKey(keys, kid); ok {
+		return key, nil
+	}
+	return nil, jwt.ErrKeyNotFound
+}
+
+// fetchKeys 获取身份提供方公钥集合
+func (p *Provider) fetchKeys(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	discovery, err := p.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var set jwt.JWKSet
+	if err := p.getJSON(ctx, discovery.JwksURI, &set); err != nil {
+		return nil, fmt.Errorf("获取身份提供方公钥失败: %w", err)
+	}
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		// 跳过无法识别的密钥，不影响其余密钥
+		key, err := jwk.PublicKey()
+		if err != nil {
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+
+	p.mu.Lock()
+	p.keys, p.keysTime = keys, time.Now()
+	p.mu.Unlock()
+	return keys, nil
+}
+
+// lookupKey 令牌未指定 kid 时，仅在公钥唯一时使用该公钥
+func lookupKey(keys map[string]crypto.PublicKey, kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(keys) == 1 {
+		for _, key := range keys {
+			return key, true
+		}
+	}
+	key, ok := keys[kid]
+	return key, ok && kid != ""
+}
+
+// getJSON 请求并解析 JSON 响应
+func (p *Provider) getJSON(ctx context.Context, endpoint string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s 返回 %d", endpoint, resp.StatusCode)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, maxResponseBodyBytes)).Decode(v)
+}
+
+// RandomString 生成 URL 安全的随机串，用于 state、nonce 及 PKCE verifier
+func RandomString() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// CodeChallenge PKCE S256 challenge
+func CodeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,155 @@
+/**
+ * Description：
+ * FileName：oidc_test.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 06:21:14
+ * Remark：
+ */
+
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"github.com/carefuly/carefuly-admin-go-gin/pkg/utils/oidc/oidctest"
+	gojwt "github.com/golang-jwt/jwt/v5"
+	"testing"
+	"time"
+)
+
+func newTestProvider(t *testing.T) (*oidctest.Server, *Provider) {
+	t.Helper()
+	server, err := oidctest.NewServer("carefuly", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(server.Close)
+	server.SetClaims(map[string]any{"sub": "u-1", "email": "demo@example.com"})
+
+	provider := NewProvider(Config{
+		Issuer:       server.Issuer(),
+		ClientId:     "carefuly",
+		ClientSecret: "secret",
+		RedirectURL:  "http://localhost:3000/sso/callback",
+	})
+	return server, provider
+}
+
+func TestProvider_AuthorizationCodeFlow(t *testing.T) {
+	server, provider := newTestProvider(t)
+	ctx := context.Background()
+
+	verifier, _ := RandomString()
+	authURL, err := provider.AuthCodeURL(ctx, "state-1", "nonce-1", verifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, state, err := server.Authorize(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state != "state-1" {
+		t.Fatalf("state = %q", state)
+	}
+
+	// verifier 不一致时无法换取令牌
+	if _, err := provider.Exchange(ctx, code, "wrong-verifier"); !errors.Is(err, ErrExchange) {
+		t.Fatalf("PKCE 校验失败应返回 ErrExchange，实际: %v", err)
+	}
+
+	code, _, err = server.Authorize(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := provider.Exchange(ctx, code, verifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := provider.VerifyIDToken(ctx, token.IdToken, "other-nonce"); !errors.Is(err, ErrIDToken) {
+		t.Fatalf("nonce 不一致应被拒绝，实际: %v", err)
+	}
+	claims, err := provider.VerifyIDToken(ctx, token.IdToken, "nonce-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.String("sub") != "u-1" || claims.String("email") != "demo@example.com" {
+		t.Fatalf("claims = %v", claims)
+	}
+}
+
+func TestProvider_VerifyIDTokenRejects(t *testing.T) {
+	server, provider := newTestProvider(t)
+	ctx := context.Background()
+	now := time.Now()
+	valid := func() gojwt.MapClaims {
+		return gojwt.MapClaims{
+			"iss": server.Issuer(), "aud": "carefuly", "sub": "u-1", "nonce": "n",
+			"iat": now.Unix(), "exp": now.Add(time.Minute).Unix(),
+		}
+	}
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged := gojwt.NewWithClaims(gojwt.SigningMethodRS256, valid())
+	forged.Header["kid"] = server.Kid
+	forgedToken, _ := forged.SignedString(otherKey)
+
+	hs, _ := gojwt.NewWithClaims(gojwt.SigningMethodHS256, valid()).SignedString([]byte("secret"))
+
+	testCases := []struct {
+		name  string
+		token func() string
+	}{
+		{name: "签名密钥不匹配", token: func() string { return forgedToken }},
+		{name: "共享密钥算法", token: func() string { return hs }},
+		{name: "受众不一致", token: func() string {
+			claims := valid()
+			claims["aud"] = "other"
+			token, _ := server.SignIDToken(claims)
+			return token
+		}},
+		{name: "颁发者不一致", token: func() string {
+			claims := valid()
+			claims["iss"] = "https://evil.example.com"
+			token, _ := server.SignIDToken(claims)
+			return token
+		}},
+		{name: "已过期", token: func() string {
+			claims := valid()
+			claims["exp"] = now.Add(-time.Hour).Unix()
+			token, _ := server.SignIDToken(claims)
+			return token
+		}},
+		{name: "多个受众缺少azp", token: func() string {
+			claims := valid()
+			claims["aud"] = []string{"carefuly", "other"}
+			token, _ := server.SignIDToken(claims)
+			return token
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := provider.VerifyIDToken(ctx, tc.token(), "n"); !errors.Is(err, ErrIDToken) {
+				t.Fatalf("应被拒绝，实际: %v", err)
+			}
+		})
+	}
+
+	token, _ := server.SignIDToken(valid())
+	if _, err := provider.VerifyIDToken(ctx, token, "n"); err != nil {
+		t.Fatalf("有效令牌校验失败: %v", err)
+	}
+}
+
+func TestCodeChallenge(t *testing.T) {
+	// RFC 7636 附录 B 示例
+	if got := CodeChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"); got != "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM" {
+		t.Fatalf("CodeChallenge = %s", got)
+	}
+}
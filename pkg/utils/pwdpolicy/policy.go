@@ -0,0 +1,111 @@
+/**
+ * Description：
+ * FileName：policy.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 01:02:36
+ * Remark：
+ */
+
+package pwdpolicy
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	DefaultMinLength = 6  // 默认最小长度
+	DefaultMaxLength = 72 // 默认最大长度(bcrypt 仅处理前 72 字节)
+)
+
+// ErrPolicy 密码不符合策略，具体原因见错误信息
+var ErrPolicy = errors.New("密码不符合安全策略")
+
+// Error 密码策略校验错误
+type Error struct {
+	msg string
+}
+
+func (e *Error) Error() string {
+	return e.msg
+}
+
+func (e *Error) Is(target error) bool {
+	return target == ErrPolicy
+}
+
+// Policy 密码复杂度策略
+type Policy struct {
+	MinLength        int  // 最小长度
+	MaxLength        int  // 最大长度
+	RequireUpper     bool // 必须包含大写字母
+	RequireLower     bool // 必须包含小写字母
+	RequireDigit     bool // 必须包含数字
+	RequireSymbol    bool // 必须包含特殊字符
+	DisallowUsername bool // 不能包含用户名(忽略大小写)
+}
+
+// Validate 校验密码是否符合策略
+func (p Policy) Validate(password, username string) error {
+	minLength, maxLength := p.MinLength, p.MaxLength
+	if minLength <= 0 {
+		minLength = DefaultMinLength
+	}
+	if maxLength <= 0 || maxLength > DefaultMaxLength {
+		maxLength = DefaultMaxLength
+	}
+
+	if n := utf8.RuneCountInString(password); n < minLength {
+		return &Error{msg: fmt.Sprintf("密码长度不能少于%d位", minLength)}
+	}
+	if len(password) > maxLength {
+		return &Error{msg: fmt.Sprintf("密码长度不能超过%d位", maxLength)}
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsSpace(r):
+		default:
+			symbol = true
+		}
+	}
+
+	var missing []string
+	if p.RequireUpper && !upper {
+		missing = append(missing, "大写字母")
+	}
+	if p.RequireLower && !lower {
+		missing = append(missing, "小写字母")
+	}
+	if p.RequireDigit && !digit {
+		missing = append(missing, "数字")
+	}
+	if p.RequireSymbol && !symbol {
+		missing = append(missing, "特殊字符")
+	}
+	if len(missing) > 0 {
+		return &Error{msg: "密码必须包含" + strings.Join(missing, "、")}
+	}
+
+	if p.DisallowUsername && username != "" &&
+		strings.Contains(strings.ToLower(password), strings.ToLower(username)) {
+		return &Error{msg: "密码不能包含用户名"}
+	}
+
+	return nil
+}
+
+// Reused 密码与近期使用过的密码重复
+func Reused(count int) error {
+	return &Error{msg: fmt.Sprintf("新密码不能与最近%d次使用过的密码相同", count)}
+}
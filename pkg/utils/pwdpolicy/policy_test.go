@@ -0,0 +1,60 @@
+/**
+ * Description：
+ * FileName：policy_test.go
+ * Author：CJiaの用心
+ * Create：2026/10/19 01:10:52
+ * Remark：
+ */
+
+package pwdpolicy
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPolicy_Validate(t *testing.T) {
+	strict := Policy{
+		MinLength:        8,
+		MaxLength:        20,
+		RequireUpper:     true,
+		RequireLower:     true,
+		RequireDigit:     true,
+		RequireSymbol:    true,
+		DisallowUsername: true,
+	}
+
+	testCases := []struct {
+		name     string
+		policy   Policy
+		password string
+		username string
+		wantMsg  string
+	}{
+		{name: "默认策略", policy: Policy{}, password: "123456"},
+		{name: "默认最小长度", policy: Policy{}, password: "12345", wantMsg: "不能少于6位"},
+		{name: "符合全部要求", policy: strict, password: "Carefuly#2026", username: "admin"},
+		{name: "长度不足", policy: strict, password: "Ab#1", wantMsg: "不能少于8位"},
+		{name: "长度超出", policy: strict, password: "Abcdefgh#1234567890ab", wantMsg: "不能超过20位"},
+		{name: "缺少字符类型", policy: strict, password: "abcdefgh", wantMsg: "大写字母、数字、特殊字符"},
+		{name: "包含用户名", policy: strict, password: "xAdmin#2026", username: "admin", wantMsg: "不能包含用户名"},
+		{name: "允许包含用户名", policy: Policy{}, password: "admin123", username: "admin"},
+		{name: "按字符计算长度", policy: Policy{MinLength: 4}, password: "密码安全", username: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.policy.Validate(tc.password, tc.username)
+			if tc.wantMsg == "" {
+				if err != nil {
+					t.Fatalf("期望通过，实际: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrPolicy) || !strings.Contains(err.Error(), tc.wantMsg) {
+				t.Fatalf("期望包含 %q 的策略错误，实际: %v", tc.wantMsg, err)
+			}
+		})
+	}
+}
@@ -9,6 +9,7 @@
 package requestUtils
 
 import (
+	"context"
 	"github.com/gin-gonic/gin"
 	"strings"
 )
@@ -22,6 +23,29 @@ func GetRequestUser(c *gin.Context) string {
 	return username.(string)
 }
 
+// GetActorId 实际操作人ID，模拟登录时为发起模拟的管理员，否则为当前登录用户
+func GetActorId(ctx context.Context) string {
+	if actorId, ok := ctx.Value("actorId").(string); ok && actorId != "" {
+		return actorId
+	}
+	userId, _ := ctx.Value("userId").(string)
+	return userId
+}
+
+// GetActorName 实际操作人用户名，模拟登录时为发起模拟的管理员
+func GetActorName(c *gin.Context) string {
+	if actorName := c.GetString("actorName"); actorName != "" {
+		return actorName
+	}
+	return GetRequestUser(c)
+}
+
+// IsImpersonating 当前请求是否使用模拟登录令牌
+func IsImpersonating(ctx context.Context) bool {
+	actorId, _ := ctx.Value("actorId").(string)
+	return actorId != ""
+}
+
 // NormalizeIP IPv6 本地地址处理
 func NormalizeIP(c *gin.Context) string {
 	ip := c.ClientIP()
@@ -0,0 +1,88 @@
+/**
+ * Description：
+ * FileName：match.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 09:12:40
+ * Remark：
+ */
+
+package routematch
+
+import "strings"
+
+// Normalize 统一接口地址格式
+// 兼容 swagger 风格的 {id} 参数写法，并去掉末尾多余的 /
+func Normalize(api string) string {
+	api = strings.TrimSpace(api)
+	if api == "" {
+		return ""
+	}
+	if !strings.HasPrefix(api, "/") {
+		api = "/" + api
+	}
+	segments := strings.Split(api, "/")
+	for i, seg := range segments {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") && len(seg) > 2 {
+			segments[i] = ":" + seg[1:len(seg)-1]
+		}
+	}
+	api = strings.Join(segments, "/")
+	if len(api) > 1 {
+		api = strings.TrimRight(api, "/")
+	}
+	return api
+}
+
+// prefixes 路由公共前缀，依次去除后再比较
+var prefixes = []string{"/dev-api", "/v1"}
+
+// Match 判断接口配置 pattern 是否匹配 gin 路由模板 route(ctx.FullPath())
+// 两者仅去除公共前缀 /dev-api、/v1 后按完整路径比较，路径段数必须一致
+// pattern 中的参数段(:id、{id})与通配段(*)可匹配任意单个路径段
+// route 中的参数段按字面比较，不作为通配
+func Match(pattern, route string) bool {
+	return Specificity(pattern, route) >= 0
+}
+
+// Specificity 返回 pattern 匹配 route 的精确程度，不匹配时返回 -1
+// 同一路由匹配到多个 pattern 时，数值越大越精确：字面相同的路径段计 2，参数段计 1，通配段计 0
+func Specificity(pattern, route string) int {
+	pattern = trimPrefix(Normalize(pattern))
+	route = trimPrefix(Normalize(route))
+	if pattern == "" || route == "" {
+		return -1
+	}
+
+	ps := strings.Split(pattern, "/")
+	rs := strings.Split(route, "/")
+	if len(ps) != len(rs) {
+		return -1
+	}
+
+	score := 0
+	for i, p := range ps {
+		switch {
+		case p == "*":
+		case strings.HasPrefix(p, ":"):
+			score++
+		case p == rs[i]:
+			score += 2
+		default:
+			return -1
+		}
+	}
+	return score
+}
+
+// trimPrefix 去除路由公共前缀
+func trimPrefix(path string) string {
+	for _, prefix := range prefixes {
+		if path == prefix {
+			return "/"
+		}
+		if strings.HasPrefix(path, prefix+"/") {
+			path = path[len(prefix):]
+		}
+	}
+	return path
+}
@@ -0,0 +1,59 @@
+/**
+ * Description：
+ * FileName：match_test.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 09:31:05
+ * Remark：
+ */
+
+package routematch
+
+import "testing"
+
+func TestMatch(t *testing.T) {
+	testCases := []struct {
+		name    string
+		pattern string
+		route   string
+		want    bool
+	}{
+		{name: "完整路由", pattern: "/dev-api/v1/system/role/listPage", route: "/dev-api/v1/system/role/listPage", want: true},
+		{name: "省略前缀", pattern: "/v1/system/role/listPage", route: "/dev-api/v1/system/role/listPage", want: true},
+		{name: "省略版本前缀", pattern: "system/role/listPage", route: "/dev-api/v1/system/role/listPage", want: true},
+		{name: "gin参数", pattern: "/v1/system/role/delete/:id", route: "/dev-api/v1/system/role/delete/:id", want: true},
+		{name: "swagger参数", pattern: "/v1/system/role/delete/{id}", route: "/dev-api/v1/system/role/delete/:id", want: true},
+		{name: "实际请求路径", pattern: "/v1/system/role/delete/:id", route: "/dev-api/v1/system/role/delete/ADE5E818", want: true},
+		{name: "通配符", pattern: "/v1/system/role/*", route: "/dev-api/v1/system/role/listAll", want: true},
+		{name: "末尾斜杠", pattern: "/v1/system/role/listAll/", route: "/dev-api/v1/system/role/listAll", want: true},
+		{name: "路径不同", pattern: "/v1/system/role/listAll", route: "/dev-api/v1/system/user/listAll", want: false},
+		{name: "部分路径段", pattern: "/v1/system/role/list", route: "/dev-api/v1/system/role/listAll", want: false},
+		{name: "pattern更长", pattern: "/v1/system/role/delete/:id/all", route: "/dev-api/v1/system/role/delete/:id", want: false},
+		{name: "仅末尾路径段", pattern: "listPage", route: "/dev-api/v1/system/user/listPage", want: false},
+		{name: "省略模块", pattern: "/delete/:id", route: "/dev-api/v1/system/user/delete/:id", want: false},
+		{name: "参数段匹配其他路由", pattern: "/:a/:b", route: "/dev-api/v1/system/user/create", want: false},
+		{name: "路由参数不作为通配", pattern: "/v1/system/role/getById/abc", route: "/dev-api/v1/system/role/getById/:id", want: false},
+		{name: "非公共前缀", pattern: "/role/listPage", route: "/dev-api/v1/system/role/listPage", want: false},
+		{name: "空配置", pattern: "", route: "/dev-api/v1/system/role/listAll", want: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Match(tc.pattern, tc.route); got != tc.want {
+				t.Errorf("Match(%q, %q) = %v, want %v", tc.pattern, tc.route, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSpecificity(t *testing.T) {
+	route := "/dev-api/v1/system/user/getById/:id"
+	exact := Specificity("/v1/system/user/getById/:id", route)
+	param := Specificity("/v1/system/:module/getById/:id", route)
+	wildcard := Specificity("/v1/system/*/getById/:id", route)
+	if !(exact > param && param > wildcard && wildcard >= 0) {
+		t.Errorf("Specificity() = %d, %d, %d, want descending", exact, param, wildcard)
+	}
+	if got := Specificity("/v1/system/role/getById/:id", route); got != -1 {
+		t.Errorf("Specificity() = %d, want -1", got)
+	}
+}
@@ -15,6 +15,13 @@ const (
 	DigitIotaCaptcha TypeCaptcha = 1 // 数字字母验证码
 )
 
+// 验证码业务类型
+const (
+	BizCaptchaLogin    = "BizCaptchaLogin"    // 登录
+	BizCaptchaRegister = "BizCaptchaRegister" // 注册
+	BizPasswordReset   = "BizPasswordReset"   // 邮箱找回密码
+)
+
 // Captcha 验证码生成器接口
 type Captcha interface {
 	// Generate 生成验证码，返回验证码ID、问题和答案
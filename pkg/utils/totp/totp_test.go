@@ -0,0 +1,93 @@
+/**
+ * Description：
+ * FileName：totp_test.go
+ * Author：CJiaの用心
+ * Create：2026/10/18 21:38:14
+ * Remark：
+ */
+
+package totp
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCode(t *testing.T) {
+	// RFC 6238 附录B 测试向量(SHA1)，取后 6 位
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+	testCases := []struct {
+		unix int64
+		want string
+	}{
+		{unix: 59, want: "287082"},
+		{unix: 1111111109, want: "081804"},
+		{unix: 1111111111, want: "050471"},
+		{unix: 1234567890, want: "005924"},
+		{unix: 2000000000, want: "279037"},
+	}
+
+	for _, tc := range testCases {
+		got, err := Code(secret, Counter(time.Unix(tc.unix, 0)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tc.want {
+			t.Errorf("Code(%d) = %s, want %s", tc.unix, got, tc.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Unix(1700000000, 0)
+
+	testCases := []struct {
+		name   string
+		offset int64
+		want   bool
+	}{
+		{name: "当前时间步", offset: 0, want: true},
+		{name: "上一时间步", offset: -1, want: true},
+		{name: "下一时间步", offset: 1, want: true},
+		{name: "超出偏移", offset: -2, want: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			code, err := Code(secret, Counter(now)+tc.offset)
+			if err != nil {
+				t.Fatal(err)
+			}
+			counter, ok := Validate(secret, code, now)
+			if ok != tc.want {
+				t.Fatalf("Validate() = %v, want %v", ok, tc.want)
+			}
+			if ok && counter != Counter(now)+tc.offset {
+				t.Errorf("counter = %d, want %d", counter, Counter(now)+tc.offset)
+			}
+		})
+	}
+
+	if _, ok := Validate(secret, "12345", now); ok {
+		t.Error("位数不正确的验证码不应通过")
+	}
+}
+
+func TestURI(t *testing.T) {
+	uri := URI("Carefuly Admin", "careful", "JBSWY3DPEHPK3PXP")
+	if !strings.HasPrefix(uri, "otpauth://totp/Carefuly%20Admin:careful?") {
+		t.Errorf("URI() = %s", uri)
+	}
+	for _, part := range []string{"secret=JBSWY3DPEHPK3PXP", "issuer=Carefuly+Admin", "digits=6", "period=30"} {
+		if !strings.Contains(uri, part) {
+			t.Errorf("URI() = %s, missing %s", uri, part)
+		}
+	}
+}